@@ -39,7 +39,6 @@ func CreateDependencies(ctx context.Context, appName, buildVersion, databaseFile
 
 	if deps.telemetry, _, err = telemetry.NewTelemeter(ctx, deps, appName, buildVersion, host, telemetry.Options{
 		PrometheusNamespace: "clean-static",
-		JaegerURL:           "",
 		TraceProbability:    0.0,
 	}); err != nil {
 		return nil, errors.Wrap(err, "could not create Telemeter")
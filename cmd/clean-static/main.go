@@ -59,7 +59,7 @@ func run() error {
 
 	for _, n := range names {
 		switch n {
-		case "trnTranslations":
+		case "trnTranslations", "dgmTypeAttributes":
 			continue
 		default:
 			level.Debug(deps.Logger()).Message("dropping table", "table_name", n)
@@ -81,6 +81,10 @@ WHERE "tcID" = 8;
 		return errors.Wrap(err, "could not CleanTranslations")
 	}
 
+	if err := repo.CleanTypeAttributes(ctx, nil); err != nil {
+		return errors.Wrap(err, "could not CleanTypeAttributes")
+	}
+
 	level.Debug(deps.Logger()).Message("vacuuming database")
 	if _, err := deps.StaticDB().ExecContext(ctx, "VACUUM"); err != nil {
 		return errors.Wrap(err, "could not vacuum")
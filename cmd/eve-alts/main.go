@@ -13,6 +13,7 @@ import (
 	"github.com/kava-forge/eve-alts/lib/logging/level"
 
 	"github.com/kava-forge/eve-alts/pkg/app"
+	"github.com/kava-forge/eve-alts/pkg/cli"
 )
 
 // build time variables
@@ -23,6 +24,9 @@ var (
 	BuildSHA     string
 )
 
+// errCommandFailed means a subcommand already reported its error on stderr
+var errCommandFailed = errors.New("command failed")
+
 func main() {
 	home := app.GetConfigDir()
 	crashlog := path.Join(home, "eve-alts-crash.log")
@@ -36,6 +40,9 @@ func main() {
 
 	ctx := context.Background()
 	err := run(ctx, os.Args[1:])
+	if errors.Is(err, errCommandFailed) {
+		os.Exit(1)
+	}
 	if err != nil {
 		panic(err)
 	}
@@ -48,6 +55,7 @@ func run(ctx context.Context, args []string) error {
 	fs.Usage = func() {
 		fmt.Fprintf(fs.Output(), "Usage of %s:\n\tVersion=%s (%s)\n\tBuildDate=%s\n", AppName, BuildVersion, BuildSHA, BuildDate)
 		fs.PrintDefaults()
+		cli.Usage(fs.Output(), AppName)
 	}
 	fs.StringVar(&configFile, "config", "", "The config file to use")
 	if err := fs.Parse(args); err != nil {
@@ -69,6 +77,17 @@ func run(ctx context.Context, args []string) error {
 
 	level.Debug(logger).Message("config dump", "config", fmt.Sprintf("%+v", conf))
 
+	if fs.NArg() > 0 && cli.IsCommand(fs.Arg(0)) {
+		if err := cli.Run(ctx, deps, fs.Args(), os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", AppName, err)
+			if errors.Is(err, cli.ErrUsage) {
+				cli.Usage(os.Stderr, AppName)
+			}
+			return errCommandFailed
+		}
+		return nil
+	}
+
 	coreApp, err := app.New(AppName, deps, conf)
 	if err != nil {
 		return errors.Wrap(err, "could not construct core app")
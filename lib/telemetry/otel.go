@@ -96,20 +96,23 @@ func NewTelemeter(serviceName, version, instanceID string, spanExporter SpanExpo
 	return NewTelemeterFromResource(res, spanExporter, meterProvider, spanSample)
 }
 
+// NewTelemeterFromResource builds a Telemeter. A nil spanExporter turns
+// tracing off: nothing is sampled.
 func NewTelemeterFromResource(res *Resource, spanExporter SpanExporter, meterProvider MeterProvider, spanSample float64) *Telemeter {
+	sampler := sdkTrace.ParentBased(sdkTrace.TraceIDRatioBased(spanSample))
+	opts := []sdkTrace.TracerProviderOption{sdkTrace.WithResource(res)}
+	if spanExporter != nil {
+		opts = append(opts, sdkTrace.WithBatcher(spanExporter))
+	} else {
+		sampler = sdkTrace.NeverSample()
+	}
+	opts = append(opts, sdkTrace.WithSampler(sampler))
+
 	return &Telemeter{
-		resource:      res,
-		traceExporter: spanExporter,
-		meterProvider: meterProvider,
-		tracerProvider: sdkTrace.NewTracerProvider(
-			sdkTrace.WithBatcher(spanExporter),
-			sdkTrace.WithResource(res),
-			sdkTrace.WithSampler(
-				sdkTrace.ParentBased(
-					sdkTrace.TraceIDRatioBased(spanSample),
-				),
-			),
-		),
+		resource:       res,
+		traceExporter:  spanExporter,
+		meterProvider:  meterProvider,
+		tracerProvider: sdkTrace.NewTracerProvider(opts...),
 		propagator: propagation.NewCompositeTextMapPropagator(
 			propagation.TraceContext{},
 			b3.New(),
@@ -1,2 +1,2 @@
 ALTER TABLE tags
-DROP COLUMN IF EXISTS "color_a";
\ No newline at end of file
+DROP COLUMN "color_a";
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS tag_includes;
\ No newline at end of file
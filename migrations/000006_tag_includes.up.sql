@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS tag_includes (
+    "tag_id" INTEGER NOT NULL REFERENCES "tags" ("id") ON DELETE CASCADE,
+    "included_tag_id" INTEGER NOT NULL REFERENCES "tags" ("id") ON DELETE CASCADE,
+    PRIMARY KEY ("tag_id", "included_tag_id"),
+    CHECK ("tag_id" != "included_tag_id")
+);
\ No newline at end of file
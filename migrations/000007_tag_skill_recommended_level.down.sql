@@ -0,0 +1,2 @@
+ALTER TABLE tag_skills
+DROP COLUMN "recommended_level";
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE tag_skills
+ADD COLUMN "recommended_level" INTEGER NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1,29 @@
+-- nested groups cannot be represented by the flat schema: each role keeps its
+-- top level operator and every tag referenced anywhere in its expression
+ALTER TABLE roles
+ADD COLUMN "operator" VARCHAR NOT NULL DEFAULT 'all';
+
+UPDATE roles
+SET "operator" = (
+    SELECT role_nodes."operator"
+    FROM role_nodes
+    WHERE role_nodes."role_id" = roles."id" AND role_nodes."parent_id" IS NULL
+)
+WHERE EXISTS (
+    SELECT 1
+    FROM role_nodes
+    WHERE role_nodes."role_id" = roles."id" AND role_nodes."parent_id" IS NULL
+);
+
+CREATE TABLE IF NOT EXISTS role_tags (
+    "role_id" INTEGER NOT NULL REFERENCES "roles" ("id") ON DELETE CASCADE,
+    "tag_id" INTEGER NOT NULL REFERENCES "tags" ("id") ON DELETE CASCADE,
+    PRIMARY KEY ("role_id", "tag_id")
+);
+
+INSERT OR IGNORE INTO role_tags ("role_id", "tag_id")
+SELECT "role_id", "tag_id"
+FROM role_nodes
+WHERE "tag_id" IS NOT NULL;
+
+DROP TABLE IF EXISTS role_nodes;
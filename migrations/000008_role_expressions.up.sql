@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS role_nodes (
+    "id" INTEGER PRIMARY KEY,
+    "role_id" INTEGER NOT NULL REFERENCES "roles" ("id") ON DELETE CASCADE,
+    "parent_id" INTEGER REFERENCES "role_nodes" ("id") ON DELETE CASCADE,
+    "position" INTEGER NOT NULL DEFAULT 0,
+    "operator" VARCHAR,
+    "tag_id" INTEGER REFERENCES "tags" ("id") ON DELETE CASCADE,
+    CHECK (("operator" IS NULL) != ("tag_id" IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS "idx_role_nodes_role_id" ON role_nodes ("role_id");
+
+-- every existing role becomes a single group over its tags
+INSERT INTO role_nodes ("role_id", "operator")
+SELECT "id", "operator"
+FROM roles;
+
+INSERT INTO role_nodes ("role_id", "parent_id", "position", "tag_id")
+SELECT
+    role_tags."role_id",
+    role_nodes."id",
+    ROW_NUMBER() OVER (PARTITION BY role_tags."role_id" ORDER BY tags."name") - 1,
+    role_tags."tag_id"
+FROM role_tags
+JOIN role_nodes ON role_nodes."role_id" = role_tags."role_id" AND role_nodes."parent_id" IS NULL
+JOIN tags ON tags."id" = role_tags."tag_id";
+
+DROP TABLE IF EXISTS role_tags;
+
+ALTER TABLE roles
+DROP COLUMN "operator";
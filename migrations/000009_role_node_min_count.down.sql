@@ -0,0 +1,2 @@
+ALTER TABLE role_nodes
+DROP COLUMN "min_count";
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE role_nodes
+ADD COLUMN "min_count" INTEGER NOT NULL DEFAULT 0;
\ No newline at end of file
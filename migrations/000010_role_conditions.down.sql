@@ -0,0 +1,7 @@
+ALTER TABLE characters
+DROP COLUMN "omega";
+
+ALTER TABLE characters
+DROP COLUMN "total_sp";
+
+DROP TABLE IF EXISTS role_conditions;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS role_conditions (
+    "id" INTEGER PRIMARY KEY,
+    "role_id" INTEGER NOT NULL REFERENCES "roles" ("id") ON DELETE CASCADE,
+    "kind" VARCHAR NOT NULL,
+    "value" INTEGER NOT NULL DEFAULT 0,
+    "label" VARCHAR NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS "idx_role_conditions_role_id" ON role_conditions ("role_id");
+
+ALTER TABLE characters
+ADD COLUMN "total_sp" INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE characters
+ADD COLUMN "omega" BOOLEAN;
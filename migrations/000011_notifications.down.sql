@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS notifications;
+
+ALTER TABLE characters
+DROP COLUMN "skill_queue_length";
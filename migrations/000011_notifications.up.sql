@@ -0,0 +1,13 @@
+ALTER TABLE characters
+ADD COLUMN "skill_queue_length" INTEGER;
+
+CREATE TABLE IF NOT EXISTS notifications (
+    "id" INTEGER PRIMARY KEY,
+    "url" TEXT NOT NULL,
+    "payload" TEXT NOT NULL,
+    "attempts" INTEGER NOT NULL DEFAULT 0,
+    "next_attempt" TIMESTAMP NOT NULL,
+    "last_error" TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS "idx_notifications_next_attempt" ON notifications ("next_attempt");
@@ -0,0 +1,6 @@
+-- sealed tokens cannot be read without the vault, so those characters have to
+-- be added again
+DELETE FROM tokens
+WHERE "access_token" LIKE 'vault1:%' OR "refresh_token" LIKE 'vault1:%';
+
+DROP TABLE IF EXISTS vault_settings;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS vault_settings (
+    "id" INTEGER PRIMARY KEY CHECK ("id" = 1),
+    "mode" VARCHAR NOT NULL,
+    "kdf_salt" BLOB NOT NULL DEFAULT x'',
+    "kdf_time" INTEGER NOT NULL DEFAULT 0,
+    "kdf_memory" INTEGER NOT NULL DEFAULT 0,
+    "kdf_threads" INTEGER NOT NULL DEFAULT 0,
+    "check_value" TEXT NOT NULL
+);
@@ -0,0 +1,11 @@
+-- tag names are unique again, so the editor can refuse a duplicate. Any
+-- existing duplicates after the first get their id added to the name.
+UPDATE tags
+SET "name" = "name" || ' (' || "id" || ')'
+WHERE "id" NOT IN (
+    SELECT min("id")
+    FROM tags
+    GROUP BY "name"
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tag_name_unique" ON tags ("name");
@@ -0,0 +1,328 @@
+// Package api serves characters, tags, roles and match results as JSON on a
+// local port, for fleet tooling and stream overlays. Every request needs the
+// configured token, as an "Authorization: Bearer" header or a token query
+// parameter for browser sources that cannot set headers.
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"net"
+	stdhttp "net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+	"github.com/kava-forge/eve-alts/lib/json"
+	"github.com/kava-forge/eve-alts/lib/logging"
+	"github.com/kava-forge/eve-alts/lib/logging/level"
+
+	"github.com/kava-forge/eve-alts/pkg/app/characters"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/esi"
+	"github.com/kava-forge/eve-alts/pkg/keys"
+	"github.com/kava-forge/eve-alts/pkg/notify"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/telemetry"
+	"github.com/kava-forge/eve-alts/pkg/views"
+)
+
+const TokenKey = "token"
+
+var (
+	ErrNotLoopback    = errors.New("api must listen on a loopback address")
+	ErrMissingToken   = errors.New("api token is required")
+	ErrRefreshRunning = errors.New("a refresh is already running")
+	errMissingRole    = errors.New("missing role parameter")
+)
+
+type dependencies interface {
+	DB() database.Connection
+	StaticDB() database.Connection
+	Logger() logging.Logger
+	ESIClient() esi.Client
+
+	Telemetry() *telemetry.Telemeter
+	Stats() *telemetry.Stats
+
+	AppRepo() repository.AppData
+	StaticRepo() repository.StaticData
+
+	Notifier() *notify.Notifier
+}
+
+type Server struct {
+	*stdhttp.Server
+
+	deps   dependencies
+	logger logging.Logger
+	token  []byte
+
+	// refreshing serialises refresh triggers, so a busy overlay cannot queue
+	// up ESI calls
+	refreshing sync.Mutex
+}
+
+// CheckAddr makes sure the api is only reachable from this machine
+func CheckAddr(hostport string) error {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return errors.Wrap(err, "invalid api address", keys.HostPort, hostport)
+	}
+	if strings.EqualFold(host, "localhost") {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return errors.Wrap(ErrNotLoopback, "invalid api address", keys.HostPort, hostport)
+}
+
+func NewServer(deps dependencies, serveAddr, token string) (*Server, error) {
+	if err := CheckAddr(serveAddr); err != nil {
+		return nil, err
+	}
+	if token == "" {
+		return nil, ErrMissingToken
+	}
+
+	s := &Server{
+		deps:   deps,
+		logger: logging.With(deps.Logger(), keys.Component, "api.Server"),
+		token:  []byte(token),
+	}
+
+	mux := stdhttp.NewServeMux()
+	mux.HandleFunc("GET /api/v1/characters", s.handleCharacters)
+	mux.HandleFunc("GET /api/v1/characters/{ref}", s.handleCharacter)
+	mux.HandleFunc("POST /api/v1/characters/{ref}/refresh", s.handleRefreshCharacter)
+	mux.HandleFunc("POST /api/v1/refresh", s.handleRefreshAll)
+	mux.HandleFunc("GET /api/v1/tags", s.handleTags)
+	mux.HandleFunc("GET /api/v1/tags/{ref}", s.handleTag)
+	mux.HandleFunc("GET /api/v1/roles", s.handleRoles)
+	mux.HandleFunc("GET /api/v1/match", s.handleMatch)
+
+	s.Server = &stdhttp.Server{
+		Addr:        serveAddr,
+		Handler:     s.authorize(mux),
+		ReadTimeout: 5 * time.Second,
+		// refreshing every character waits on ESI
+		WriteTimeout: 2 * time.Minute,
+	}
+
+	return s, nil
+}
+
+func (s *Server) authorize(next stdhttp.Handler) stdhttp.Handler {
+	return stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+
+		tok := r.URL.Query().Get(TokenKey)
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			tok = bearer
+		}
+		if subtle.ConstantTimeCompare([]byte(tok), s.token) != 1 {
+			s.writeError(w, stdhttp.StatusUnauthorized, errors.New("invalid token"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) writeJSON(w stdhttp.ResponseWriter, code int, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		level.Error(s.logger).Err("could not encode response", err)
+		code = stdhttp.StatusInternalServerError
+		b = []byte(`{"error":"could not encode response"}`)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(b)
+}
+
+type errorOut struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) writeError(w stdhttp.ResponseWriter, code int, err error) {
+	s.writeJSON(w, code, errorOut{Error: err.Error()})
+}
+
+// fail maps err to a status code and reports it
+func (s *Server) fail(w stdhttp.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, views.ErrNotFound):
+		s.writeError(w, stdhttp.StatusNotFound, err)
+	case errors.Is(err, errMissingRole):
+		s.writeError(w, stdhttp.StatusBadRequest, err)
+	case errors.Is(err, ErrRefreshRunning):
+		s.writeError(w, stdhttp.StatusConflict, err)
+	default:
+		level.Error(s.logger).Err("api request failed", err)
+		s.writeError(w, stdhttp.StatusInternalServerError, errors.New("internal error"))
+	}
+}
+
+func (s *Server) handleCharacters(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	chars, err := views.LoadCharacters(r.Context(), s.deps.AppRepo())
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.writeJSON(w, stdhttp.StatusOK, views.NewCharacters(chars))
+}
+
+func (s *Server) handleCharacter(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	chars, err := views.LoadCharacters(r.Context(), s.deps.AppRepo())
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	c, err := views.FindCharacter(chars, r.PathValue("ref"))
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.writeJSON(w, stdhttp.StatusOK, views.NewCharacter(c))
+}
+
+type refreshOut struct {
+	Refreshed []views.Character `json:"refreshed"`
+	Errors    []string          `json:"errors"`
+}
+
+// refresh refreshes chars one at a time; failures are reported alongside the
+// characters that did refresh
+func (s *Server) refresh(ctx context.Context, chars []*repository.CharacterDBData) (refreshOut, error) {
+	if !s.refreshing.TryLock() {
+		return refreshOut{}, ErrRefreshRunning
+	}
+	defer s.refreshing.Unlock()
+
+	res := refreshOut{
+		Refreshed: make([]views.Character, 0, len(chars)),
+		Errors:    []string{},
+	}
+	for _, c := range chars {
+		level.Info(s.logger).Message("refreshing character", keys.CharacterID, c.Character.ID, keys.CharacterName, c.Character.Name)
+
+		dbChar, err := characters.RefreshStoredCharacter(ctx, s.deps, c.Character.ID)
+		if err != nil {
+			level.Error(s.logger).Err("could not refresh character", err, keys.CharacterID, c.Character.ID)
+			res.Errors = append(res.Errors, c.Character.Name+": refresh failed")
+			continue
+		}
+		res.Refreshed = append(res.Refreshed, views.NewCharacter(&dbChar))
+	}
+	return res, nil
+}
+
+func (s *Server) handleRefreshCharacter(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	chars, err := views.LoadCharacters(r.Context(), s.deps.AppRepo())
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	c, err := views.FindCharacter(chars, r.PathValue("ref"))
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+
+	res, err := s.refresh(r.Context(), []*repository.CharacterDBData{c})
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.writeJSON(w, stdhttp.StatusOK, res)
+}
+
+func (s *Server) handleRefreshAll(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	chars, err := views.LoadCharacters(r.Context(), s.deps.AppRepo())
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+
+	res, err := s.refresh(r.Context(), chars)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.writeJSON(w, stdhttp.StatusOK, res)
+}
+
+func (s *Server) handleTags(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	tags, err := views.LoadTags(r.Context(), s.deps.AppRepo())
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.writeJSON(w, stdhttp.StatusOK, views.NewTags(tags))
+}
+
+func (s *Server) handleTag(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	tags, err := views.LoadTags(r.Context(), s.deps.AppRepo())
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	tag, err := views.FindTag(tags, r.PathValue("ref"))
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	detail, err := views.LoadTagDetail(r.Context(), s.deps.StaticRepo(), tag, tags)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.writeJSON(w, stdhttp.StatusOK, detail)
+}
+
+func (s *Server) handleRoles(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	roles, err := views.LoadRoles(r.Context(), s.deps.AppRepo())
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.writeJSON(w, stdhttp.StatusOK, views.NewRoles(roles))
+}
+
+// handleMatch answers "which characters qualify for ?role=X"
+func (s *Server) handleMatch(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	ctx := r.Context()
+
+	ref := r.URL.Query().Get("role")
+	if ref == "" {
+		s.fail(w, errMissingRole)
+		return
+	}
+
+	roles, err := views.LoadRoles(ctx, s.deps.AppRepo())
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	role, err := views.FindRole(roles, ref)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	chars, err := views.LoadCharacters(ctx, s.deps.AppRepo())
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	tags, err := views.LoadTags(ctx, s.deps.AppRepo())
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+
+	s.writeJSON(w, stdhttp.StatusOK, views.NewMatch(nil, chars, tags, roles, role))
+}
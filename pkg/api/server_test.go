@@ -0,0 +1,233 @@
+package api_test
+
+import (
+	"database/sql"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kava-forge/eve-alts/pkg/api"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/esi"
+	"github.com/kava-forge/eve-alts/pkg/operators"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/testhelpers"
+)
+
+const testToken = "s3cret"
+
+type testDependencies struct {
+	*testhelpers.TestDependencies
+}
+
+func (testDependencies) ESIClient() esi.Client { return nil }
+
+func newTestServer(t *testing.T) (testDependencies, *api.Server) {
+	t.Helper()
+
+	deps := testDependencies{testhelpers.NewTestDependencies(t)}
+	deps.TestAppRepo.GetAllCharactersReturns([]*repository.CharacterDBData{
+		{
+			Character:   repository.Character{ID: 1, Name: "Alice", TotalSp: 50_000_000, Omega: sql.NullBool{Bool: true, Valid: true}},
+			Corporation: repository.Corporation{ID: 10, Name: "Alice Corp", Ticker: "ALC"},
+			Skills:      []repository.CharacterSkill{{CharacterID: 1, SkillID: 100, SkillLevel: 5}},
+		},
+		{
+			Character:   repository.Character{ID: 2, Name: "Bob"},
+			Corporation: repository.Corporation{ID: 10, Name: "Alice Corp", Ticker: "ALC"},
+		},
+	}, nil)
+	deps.TestAppRepo.GetAllTagsReturns([]*repository.TagDBData{{
+		Tag:    repository.Tag{ID: 5, Name: "Logi"},
+		Skills: []repository.TagSkill{{TagID: 5, SkillID: 100, SkillLevel: 4}},
+	}}, nil)
+	deps.TestAppRepo.GetAllRolesReturns([]*repository.RoleDBData{{
+		Role: repository.Role{ID: 7, Name: "Guardians"},
+		Expr: repository.NewRoleGroup(operators.OperatorAll, repository.NewRoleLeaf(5)),
+		Tags: []repository.Tag{{ID: 5, Name: "Logi"}},
+	}}, nil)
+	deps.TestStaticRepo.BatchGetSkillNamesReturns([]repository.BatchGetSkillNamesRow{{SkillID: 100, SkillName: "Logistics Cruisers"}}, nil)
+
+	srv, err := api.NewServer(deps, "localhost:0", testToken)
+	require.NoError(t, err)
+	return deps, srv
+}
+
+func TestNewServer(t *testing.T) {
+	t.Parallel()
+
+	deps := testDependencies{testhelpers.NewTestDependencies(t)}
+
+	tests := []struct {
+		name    string
+		addr    string
+		token   string
+		wantErr error
+	}{
+		{name: "localhost", addr: "localhost:8093", token: testToken},
+		{name: "ipv4 loopback", addr: "127.0.0.1:8093", token: testToken},
+		{name: "ipv6 loopback", addr: "[::1]:8093", token: testToken},
+		{name: "all interfaces", addr: ":8093", token: testToken, wantErr: api.ErrNotLoopback},
+		{name: "lan", addr: "192.168.1.2:8093", token: testToken, wantErr: api.ErrNotLoopback},
+		{name: "no token", addr: "localhost:8093", wantErr: api.ErrMissingToken},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := api.NewServer(deps, tt.addr, tt.token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		auth     string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "no token",
+			method:   stdhttp.MethodGet,
+			target:   "/api/v1/characters",
+			wantCode: stdhttp.StatusUnauthorized,
+			wantBody: `{"error": "invalid token"}`,
+		},
+		{
+			name:     "wrong token",
+			method:   stdhttp.MethodGet,
+			target:   "/api/v1/characters",
+			auth:     "Bearer nope",
+			wantCode: stdhttp.StatusUnauthorized,
+			wantBody: `{"error": "invalid token"}`,
+		},
+		{
+			name:     "characters",
+			method:   stdhttp.MethodGet,
+			target:   "/api/v1/characters",
+			auth:     "Bearer " + testToken,
+			wantCode: stdhttp.StatusOK,
+			wantBody: `[
+				{"id": 1, "name": "Alice", "corporation": {"id": 10, "name": "Alice Corp", "ticker": "ALC"}, "total_sp": 50000000, "omega": true, "skills": 1},
+				{"id": 2, "name": "Bob", "corporation": {"id": 10, "name": "Alice Corp", "ticker": "ALC"}, "total_sp": 0, "omega": null, "skills": 0}
+			]`,
+		},
+		{
+			name:     "character by name with query token",
+			method:   stdhttp.MethodGet,
+			target:   "/api/v1/characters/bob?token=" + testToken,
+			wantCode: stdhttp.StatusOK,
+			wantBody: `{"id": 2, "name": "Bob", "corporation": {"id": 10, "name": "Alice Corp", "ticker": "ALC"}, "total_sp": 0, "omega": null, "skills": 0}`,
+		},
+		{
+			name:     "unknown character",
+			method:   stdhttp.MethodGet,
+			target:   "/api/v1/characters/99",
+			auth:     "Bearer " + testToken,
+			wantCode: stdhttp.StatusNotFound,
+		},
+		{
+			name:     "tag",
+			method:   stdhttp.MethodGet,
+			target:   "/api/v1/tags/5",
+			auth:     "Bearer " + testToken,
+			wantCode: stdhttp.StatusOK,
+			wantBody: `{"id": 5, "name": "Logi", "color": "#00000000", "includes": [], "skills": [{"id": 100, "name": "Logistics Cruisers", "level": 4}], "inherited": []}`,
+		},
+		{
+			name:     "roles",
+			method:   stdhttp.MethodGet,
+			target:   "/api/v1/roles",
+			auth:     "Bearer " + testToken,
+			wantCode: stdhttp.StatusOK,
+			wantBody: `[{"id": 7, "name": "Guardians", "label": "", "color": "#00000000", "tags": ["Logi"], "conditions": [], "expression": {"operator": "all", "children": [{"tag": "Logi"}]}}]`,
+		},
+		{
+			name:     "match",
+			method:   stdhttp.MethodGet,
+			target:   "/api/v1/match?role=guardians",
+			auth:     "Bearer " + testToken,
+			wantCode: stdhttp.StatusOK,
+			wantBody: `{"role": {"id": 7, "name": "Guardians"}, "characters": [{"id": 1, "name": "Alice"}]}`,
+		},
+		{
+			name:     "match without role",
+			method:   stdhttp.MethodGet,
+			target:   "/api/v1/match",
+			auth:     "Bearer " + testToken,
+			wantCode: stdhttp.StatusBadRequest,
+		},
+		{
+			name:     "match unknown role",
+			method:   stdhttp.MethodGet,
+			target:   "/api/v1/match?role=Tackle",
+			auth:     "Bearer " + testToken,
+			wantCode: stdhttp.StatusNotFound,
+		},
+		{
+			name:     "refresh unknown character",
+			method:   stdhttp.MethodPost,
+			target:   "/api/v1/characters/99/refresh",
+			auth:     "Bearer " + testToken,
+			wantCode: stdhttp.StatusNotFound,
+		},
+		{
+			name:     "refresh is not a GET",
+			method:   stdhttp.MethodGet,
+			target:   "/api/v1/refresh",
+			auth:     "Bearer " + testToken,
+			wantCode: stdhttp.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, srv := newTestServer(t)
+
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			srv.Handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantBody != "" {
+				assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+				assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestServer_RefreshReportsFailures(t *testing.T) {
+	t.Parallel()
+
+	deps, srv := newTestServer(t)
+	deps.TestAppRepo.GetTokenForCharacterReturns(repository.Token{}, database.ErrNoRows)
+
+	req := httptest.NewRequest(stdhttp.MethodPost, "/api/v1/refresh", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	rec := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, stdhttp.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"refreshed": [], "errors": ["Alice: refresh failed", "Bob: refresh failed"]}`, rec.Body.String())
+	assert.Equal(t, 2, deps.TestAppRepo.GetTokenForCharacterCallCount())
+}
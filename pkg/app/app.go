@@ -15,11 +15,13 @@ import (
 	"github.com/kava-forge/eve-alts/lib/logging/level"
 
 	"github.com/kava-forge/eve-alts/migrations"
+	"github.com/kava-forge/eve-alts/pkg/api"
 	"github.com/kava-forge/eve-alts/pkg/app/colors"
 	"github.com/kava-forge/eve-alts/pkg/background"
 	"github.com/kava-forge/eve-alts/pkg/database"
 	"github.com/kava-forge/eve-alts/pkg/esi"
 	"github.com/kava-forge/eve-alts/pkg/keys"
+	"github.com/kava-forge/eve-alts/pkg/notify"
 	"github.com/kava-forge/eve-alts/pkg/panics"
 	"github.com/kava-forge/eve-alts/pkg/repository"
 	"github.com/kava-forge/eve-alts/pkg/telemetry"
@@ -27,6 +29,7 @@ import (
 
 type dependencies interface {
 	DB() database.Connection
+	Snapshots() database.Snapshots
 	StaticDB() database.Connection
 	Logger() logging.Logger
 	HTTPClient() http.Client
@@ -39,6 +42,9 @@ type dependencies interface {
 
 	AppRepo() repository.AppData
 	StaticRepo() repository.StaticData
+	VaultSource() repository.KeySource
+
+	Notifier() *notify.Notifier
 }
 
 type App struct {
@@ -73,11 +79,37 @@ func (a *App) Start() error {
 
 	logger := logging.With(a.deps.Logger(), keys.Component, "App")
 
+	level.Debug(logger).Message("checking database")
+	if err := database.Check(ctx, logger, a.deps.DB(), migrations.Migrations); err != nil {
+		return errors.Wrap(err, "could not check database")
+	}
+
 	level.Debug(logger).Message("running migrations")
-	if err := a.deps.DB().Migrate(ctx, migrations.Migrations); err != nil {
+	if err := a.deps.Snapshots().Migrate(ctx, a.deps.DB(), migrations.Migrations); err != nil {
 		return errors.Wrap(err, "could not run database migrations")
 	}
 
+	level.Debug(logger).Message("unlocking token vault")
+	setUp, err := unlockVault(ctx, a.deps)
+	switch {
+	case keyFileProblem(err):
+		showKeyFileProblem(a.deps, a.window, err)
+	case err != nil:
+		return err
+	case a.deps.VaultSource().Mode == repository.VaultModePassphrase:
+		showUnlock(a.deps, a.window, setUp)
+	}
+
+	if a.conf.Database.WatchInterval > 0 {
+		level.Debug(logger).Message("watching for external changes")
+		go func() {
+			defer panics.Handler(logger)
+			if err := a.deps.AppRepo().WatchExternalChanges(ctx, a.conf.Database.WatchInterval); err != nil {
+				level.Error(logger).Err("could not watch for external changes", err)
+			}
+		}()
+	}
+
 	done := make(chan error)
 	level.Debug(logger).Message("starting background")
 	go a.startBackground(ctx, done)
@@ -98,7 +130,7 @@ func (a *App) startBackground(ctx context.Context, done chan error) {
 	defer panics.Handler(logger)
 	defer close(done)
 
-	servers := make([]background.Serverlike, 0, 3)
+	servers := make([]background.Serverlike, 0, 5)
 	servers = append(servers, a.deps.ESICallbackServer())
 
 	ch := a.deps.StatsHandler()
@@ -134,6 +166,22 @@ func (a *App) startBackground(ctx context.Context, done chan error) {
 		})
 	}
 
+	if a.deps.Notifier().Enabled() {
+		level.Debug(logger).Message("starting notification retries")
+		servers = append(servers, a.deps.Notifier())
+	}
+
+	if a.conf.Serving.APIEnabled {
+		level.Debug(logger).Message("configuring api")
+
+		srv, err := api.NewServer(a.deps, a.conf.Serving.APIHostPort, a.conf.Serving.APIToken)
+		if err != nil {
+			done <- errors.Wrap(err, "could not configure api")
+			return
+		}
+		servers = append(servers, srv)
+	}
+
 	g, _ := background.RunBackground(ctx, logger, servers...)
 
 	done <- g.Wait()
@@ -0,0 +1,74 @@
+package bindings
+
+// MergeByID sets each item of list that has the same id as one in fresh to
+// that one, and appends the rest of fresh. Items keep their indexes, so
+// widgets bound to them stay bound. An id of 0 marks a removed item.
+func MergeByID[T any](list *DataList[T], fresh []T, id func(T) int64) error {
+	cur, err := list.Get()
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[int64]T, len(fresh))
+	for _, v := range fresh {
+		byID[id(v)] = v
+	}
+
+	for i, v := range cur {
+		vid := id(v)
+		if f, ok := byID[vid]; ok && vid != 0 {
+			if err := list.SetValue(i, f); err != nil {
+				return err
+			}
+			delete(byID, vid)
+		}
+	}
+
+	for _, v := range fresh {
+		if _, ok := byID[id(v)]; ok {
+			if err := list.Append(v); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// RemoveWhere replaces each item of list that remove matches with gone(item),
+// which should have an id of 0. Items are not taken out of the list, as that
+// would shift the indexes widgets are bound to.
+func RemoveWhere[T any](list *DataList[T], remove func(T) bool, gone func(T) T) error {
+	cur, err := list.Get()
+	if err != nil {
+		return err
+	}
+
+	for i, v := range cur {
+		if !remove(v) {
+			continue
+		}
+		if err := list.SetValue(i, gone(v)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Replace makes list match fresh, merging it in by id and marking every item
+// that is not in it as gone
+func Replace[T any](list *DataList[T], fresh []T, id func(T) int64, gone func(T) T) error {
+	keep := make(map[int64]bool, len(fresh))
+	for _, v := range fresh {
+		keep[id(v)] = true
+	}
+
+	if err := MergeByID(list, fresh, id); err != nil {
+		return err
+	}
+	return RemoveWhere(list, func(v T) bool {
+		vid := id(v)
+		return vid != 0 && !keep[vid]
+	}, gone)
+}
@@ -0,0 +1,83 @@
+package bindings_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kava-forge/eve-alts/pkg/app/bindings"
+)
+
+type item struct {
+	ID   int64
+	Name string
+}
+
+func itemID(i *item) int64 { return i.ID }
+
+func goneItem(i *item) *item {
+	removed := *i
+	removed.ID = 0
+	return &removed
+}
+
+func TestReplace(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		start []*item
+		fresh []*item
+		want  []*item
+	}{
+		{
+			name:  "updates in place and appends",
+			start: []*item{{1, "a"}, {2, "b"}},
+			fresh: []*item{{3, "c"}, {2, "B"}, {1, "a"}},
+			want:  []*item{{1, "a"}, {2, "B"}, {3, "c"}},
+		},
+		{
+			name:  "marks missing items gone",
+			start: []*item{{1, "a"}, {2, "b"}, {3, "c"}},
+			fresh: []*item{{3, "c"}},
+			want:  []*item{{0, "a"}, {0, "b"}, {3, "c"}},
+		},
+		{
+			name:  "leaves gone items alone",
+			start: []*item{{0, "a"}, {2, "b"}},
+			fresh: []*item{{2, "b"}},
+			want:  []*item{{0, "a"}, {2, "b"}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			list := bindings.NewDataList[*item]()
+			require.NoError(t, list.Set(tt.start))
+
+			require.NoError(t, bindings.Replace(list, tt.fresh, itemID, goneItem))
+
+			got, err := list.Get()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRemoveWhere(t *testing.T) {
+	t.Parallel()
+
+	start := []*item{{1, "a"}, {2, "b"}}
+	list := bindings.NewDataList[*item]()
+	require.NoError(t, list.Set(start))
+
+	require.NoError(t, bindings.RemoveWhere(list, func(i *item) bool { return i.ID == 2 }, goneItem))
+
+	got, err := list.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []*item{{1, "a"}, {0, "b"}}, got)
+	assert.Equal(t, int64(2), start[1].ID, "items are replaced, not changed")
+}
@@ -10,13 +10,11 @@ import (
 
 	"github.com/kava-forge/eve-alts/lib/logging"
 	"github.com/kava-forge/eve-alts/pkg/app/apperrors"
-	"github.com/kava-forge/eve-alts/pkg/app/bindings"
 	"github.com/kava-forge/eve-alts/pkg/keys"
 	"github.com/kava-forge/eve-alts/pkg/panics"
-	"github.com/kava-forge/eve-alts/pkg/repository"
 )
 
-func NewAddCharacterButton(deps dependencies, parent fyne.Window, chars *bindings.DataList[*repository.CharacterDBData]) *widget.Button {
+func NewAddCharacterButton(deps dependencies, parent fyne.Window) *widget.Button {
 	button := widget.NewButtonWithIcon("Add Character", theme.ContentAddIcon(), func() {
 		go func() {
 			logger := logging.With(deps.Logger(), keys.Component, "AddCharacterButton")
@@ -44,23 +42,14 @@ func NewAddCharacterButton(deps dependencies, parent fyne.Window, chars *binding
 				return
 			}
 
-			dbChar, err := RefreshCharacterData(ctx, deps, tok, cdata.RealID)
-			if err != nil {
+			// the characters tab adds the card once the character is stored
+			if _, err := RefreshCharacterData(ctx, deps, tok, cdata.RealID); err != nil {
 				apperrors.Show(logger, parent, apperrors.Error(
 					"Error fetching character data",
 					apperrors.WithCause(err),
 				), nil)
 				return
 			}
-
-			if err := chars.Append(&dbChar); err != nil {
-				apperrors.Show(logger, parent, apperrors.Error(
-					"Error displaying character",
-					apperrors.WithCause(err),
-					apperrors.WithInternalData(keys.CharacterID, dbChar.Character.ID),
-				), nil)
-				return
-			}
 		}()
 	})
 
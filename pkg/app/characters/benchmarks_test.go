@@ -0,0 +1,51 @@
+package characters
+
+import (
+	"testing"
+
+	"github.com/kava-forge/eve-alts/pkg/matching"
+	"github.com/kava-forge/eve-alts/pkg/testhelpers/roster"
+)
+
+var (
+	Match   TagMatch
+	Matched bool
+)
+
+// BenchmarkPerCardMatching is every card evaluating every tag and role on its
+// own, as each redraw used to
+func BenchmarkPerCardMatching(b *testing.B) {
+	chars, tags, roles := roster.Random(1, 150, 80, 30)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, c := range chars {
+			for _, t := range tags {
+				Match, _, _ = CharacterTagMatch(c, t)
+			}
+			for _, r := range roles {
+				Matched, _ = CharacterMatchesRole(c, r, tags)
+			}
+		}
+	}
+}
+
+// BenchmarkEngineMatching is the same lookups from freshly compiled results,
+// the worst case after any change
+func BenchmarkEngineMatching(b *testing.B) {
+	chars, tags, roles := roster.Random(1, 150, 80, 30)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		res := matching.Compile(chars, tags, roles)
+		for _, c := range chars {
+			for _, t := range tags {
+				lvl, _ := res.Tag(c.Character.ID, t.Tag.ID)
+				Match = tagMatchFromLevel(lvl)
+			}
+			for _, r := range roles {
+				Matched, _ = res.Role(c.Character.ID, r.Role.ID)
+			}
+		}
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/kava-forge/eve-alts/pkg/app/minitag"
 	"github.com/kava-forge/eve-alts/pkg/esi"
 	"github.com/kava-forge/eve-alts/pkg/keys"
+	"github.com/kava-forge/eve-alts/pkg/matching"
 	"github.com/kava-forge/eve-alts/pkg/repository"
 )
 
@@ -41,6 +42,8 @@ type CharacterCard struct {
 	tags   *bindings.DataList[*repository.TagDBData]
 	roles  *bindings.DataList[*repository.RoleDBData]
 	parent fyne.Window
+	// matcher is shared by every card in the roster
+	matcher *matcher
 
 	NameLabel         *widget.Label
 	Portrait          *canvas.Image
@@ -52,11 +55,13 @@ type CharacterCard struct {
 	AllianceIcon      *canvas.Image
 	RefreshButton     *widget.Button
 	DeleteButton      *widget.Button
+	ReportButton      *widget.Button
 
 	MiniTags          *minitag.MiniTagSet[string, *CharacterMiniTag]
 	MiniTagsContainer *container.Scroll
 	miniTagLookup     map[int64]bool
 	selectedTags      map[string]bool
+	tagThreshold      TagMatch
 
 	Roles         *minitag.MiniTagSet[string, *RoleMiniTag]
 	roleLookup    map[int64]bool
@@ -107,7 +112,7 @@ func getImagesForChar(logger logging.Logger, char *repository.CharacterDBData) (
 	return im
 }
 
-func NewCharacterCard(deps dependencies, parent fyne.Window, dataChar bindings.DataProxy[*repository.CharacterDBData], tagsData *bindings.DataList[*repository.TagDBData], rolesData *bindings.DataList[*repository.RoleDBData], deleteFunc func(c *CharacterCard)) *CharacterCard {
+func NewCharacterCard(deps dependencies, parent fyne.Window, m *matcher, dataChar bindings.DataProxy[*repository.CharacterDBData], tagsData *bindings.DataList[*repository.TagDBData], rolesData *bindings.DataList[*repository.RoleDBData]) *CharacterCard {
 	logger := logging.With(deps.Logger(), keys.Component, "CharacterCard.NewCharacterCard")
 
 	char, err := dataChar.Get()
@@ -128,11 +133,12 @@ func NewCharacterCard(deps dependencies, parent fyne.Window, dataChar bindings.D
 	}
 
 	cc := &CharacterCard{
-		deps:   deps,
-		char:   dataChar,
-		tags:   tagsData,
-		roles:  rolesData,
-		parent: parent,
+		deps:    deps,
+		char:    dataChar,
+		tags:    tagsData,
+		roles:   rolesData,
+		parent:  parent,
+		matcher: m,
 
 		NameLabel:         widget.NewLabel(char.Character.Name),
 		Portrait:          images.Portrait,
@@ -146,10 +152,12 @@ func NewCharacterCard(deps dependencies, parent fyne.Window, dataChar bindings.D
 		// DeleteButton:      widget.NewButtonWithIcon("delete", theme.DeleteIcon(), nil),
 		RefreshButton: widget.NewButton("refresh", nil),
 		DeleteButton:  widget.NewButton("delete", nil),
+		ReportButton:  widget.NewButton("report", nil),
 
 		MiniTags:      minitag.NewMiniTagSet[string, *CharacterMiniTag](),
 		miniTagLookup: map[int64]bool{},
 		selectedTags:  map[string]bool{},
+		tagThreshold:  TagMatchRequired,
 
 		Roles:         minitag.NewMiniTagSet[string, *RoleMiniTag](),
 		roleLookup:    map[int64]bool{},
@@ -171,8 +179,9 @@ func NewCharacterCard(deps dependencies, parent fyne.Window, dataChar bindings.D
 	cc.CorporationIcon.SetMinSize(fyne.Size{Height: 64, Width: 64})
 
 	cc.RefreshButton.OnTapped = cc.refreshData
-	cc.DeleteButton.OnTapped = cc.deleteCharacter(deleteFunc)
+	cc.DeleteButton.OnTapped = cc.deleteCharacter()
 	cc.DeleteButton.Importance = widget.DangerImportance
+	cc.ReportButton.OnTapped = cc.showReport
 
 	cc.refreshTags()
 	cc.refreshRoles()
@@ -202,11 +211,19 @@ func (c *CharacterCard) UpdateTagSelection(tagID string, selected bool) {
 	c.redraw()
 }
 
+// UpdateTagThreshold sets how well a selected tag must be met for the card to show
+func (c *CharacterCard) UpdateTagThreshold(threshold TagMatch) {
+	c.update.Lock()
+	c.tagThreshold = threshold
+	c.update.Unlock()
+	c.redraw()
+}
+
 func (c *CharacterCard) CreateRenderer() fyne.WidgetRenderer {
 	return c
 }
 
-func (c *CharacterCard) matchesSelectedTags() bool {
+func (c *CharacterCard) matchesSelectedTags(res *matching.Results, charID int64) bool {
 	logger := logging.With(c.deps.Logger(), keys.Component, "CharacterCard.matchesSelectedTags")
 
 	matchedTags := make(map[string]bool, c.MiniTags.Len())
@@ -217,8 +234,10 @@ func (c *CharacterCard) matchesSelectedTags() bool {
 				"Could not load tag data",
 				apperrors.WithCause(err),
 			), nil)
+			continue
 		}
-		matchedTags[tag.StrID()] = mt.isMatch
+		lvl, _ := res.Tag(charID, tag.Tag.ID)
+		matchedTags[tag.StrID()] = tagMatchFromLevel(lvl) >= c.tagThreshold
 	}
 
 	for k, on := range c.selectedTags {
@@ -234,7 +253,7 @@ func (c *CharacterCard) matchesSelectedTags() bool {
 	return true
 }
 
-func (c *CharacterCard) matchesSelectedRoles() bool {
+func (c *CharacterCard) matchesSelectedRoles(res *matching.Results, charID int64) bool {
 	logger := logging.With(c.deps.Logger(), keys.Component, "CharacterCard.matchesSelectedRoles")
 
 	matchedRoles := make(map[string]bool, c.Roles.Len())
@@ -245,8 +264,9 @@ func (c *CharacterCard) matchesSelectedRoles() bool {
 				"Could not load role data",
 				apperrors.WithCause(err),
 			), nil)
+			continue
 		}
-		matchedRoles[role.StrID()] = mt.isMatch
+		matchedRoles[role.StrID()], _ = res.Role(charID, role.Role.ID)
 	}
 
 	for k, on := range c.selectedRoles {
@@ -281,7 +301,16 @@ func (c *CharacterCard) redraw() {
 
 	level.Debug(logger).Message("refreshing character card")
 
-	if c.matchesSelectedTags() && c.matchesSelectedRoles() {
+	res, err := c.matcher.results()
+	if err != nil {
+		apperrors.Show(logger, c.parent, apperrors.Error(
+			"Could not match character",
+			apperrors.WithCause(err),
+		), nil)
+		return
+	}
+
+	if c.matchesSelectedTags(res, char.Character.ID) && c.matchesSelectedRoles(res, char.Character.ID) {
 		if c.Hidden {
 			c.Show()
 		}
@@ -349,7 +378,7 @@ func (c *CharacterCard) refreshTags() {
 		}
 
 		c.miniTagLookup[tag.Tag.ID] = true
-		mt := NewCharacterMiniTag(c.deps, c.parent, c.char, c.tags.Child(i))
+		mt := NewCharacterMiniTag(c.deps, c.parent, c.matcher, c.char, c.tags.Child(i))
 		c.MiniTags.Add(mt)
 		// mt.Refresh()
 	}
@@ -390,7 +419,7 @@ func (c *CharacterCard) refreshRoles() {
 		}
 
 		c.roleLookup[role.Role.ID] = true
-		mt := NewRoleMiniTag(c.deps, c.parent, c.char, c.roles.Child(i), c.tags)
+		mt := NewRoleMiniTag(c.deps, c.parent, c.matcher, c.char, c.roles.Child(i), c.tags)
 		c.Roles.Add(mt)
 		// mt.Refresh()
 	}
@@ -445,25 +474,55 @@ func (c *CharacterCard) refreshData() {
 
 	tok := esi.TokenFromRepository(dbTok)
 
-	newChar, err := RefreshCharacterData(ctx, c.deps, tok, char.Character.ID)
-	if err != nil {
+	// the card picks up the new data from the repository's change event
+	if _, err := RefreshCharacterData(ctx, c.deps, tok, char.Character.ID); err != nil {
 		apperrors.Show(logger, c.parent, apperrors.Error(
 			"Error refreshing character data",
 			apperrors.WithCause(err),
 		), nil)
 		return
 	}
+}
+
+func (c *CharacterCard) showReport() {
+	logger := logging.With(c.deps.Logger(), keys.Component, "CharacterCard.showReport")
+
+	char, err := c.char.Get()
+	if err != nil {
+		apperrors.Show(logger, c.parent, apperrors.Error(
+			"Could not find character data",
+			apperrors.WithCause(err),
+		), nil)
+		return
+	}
 
-	if err := c.char.Set(&newChar); err != nil {
+	roles, err := c.roles.Get()
+	if err != nil {
 		apperrors.Show(logger, c.parent, apperrors.Error(
-			"Could not set character data",
+			"Could not load role list data",
 			apperrors.WithCause(err),
 		), nil)
 		return
 	}
+
+	tags, err := c.tags.Get()
+	if err != nil {
+		apperrors.Show(logger, c.parent, apperrors.Error(
+			"Could not load tag list data",
+			apperrors.WithCause(err),
+		), nil)
+		return
+	}
+
+	if err := showMatchReport(c.deps, c.parent, char, roles, tags); err != nil {
+		apperrors.Show(logger, c.parent, apperrors.Error(
+			"Could not build match report",
+			apperrors.WithCause(err),
+		), nil)
+	}
 }
 
-func (c *CharacterCard) deleteCharacter(callback func(*CharacterCard)) func() {
+func (c *CharacterCard) deleteCharacter() func() {
 	return func() {
 		ctx := context.Background()
 
@@ -493,8 +552,6 @@ func (c *CharacterCard) deleteCharacter(callback func(*CharacterCard)) func() {
 					"Unable to delete character",
 					apperrors.WithCause(err),
 				), nil)
-			} else {
-				callback(c)
 			}
 		}, c.parent)
 		conf.SetConfirmImportance(widget.DangerImportance)
@@ -566,6 +623,12 @@ func (c *CharacterCard) Layout(sz fyne.Size) {
 	c.DeleteButton.Alignment = widget.ButtonAlignCenter
 	dbsz := c.DeleteButton.Size()
 	c.DeleteButton.Move(fyne.Position{X: sz.Width - rbsz.Width - theme.Padding() - dbsz.Width, Y: sz.Height - dbsz.Height})
+
+	reportLabelSz := fyne.MeasureText(c.ReportButton.Text, fontSize, c.NameLabel.TextStyle)
+	c.ReportButton.Resize(fyne.Size{Width: reportLabelSz.Width + 2*theme.InnerPadding(), Height: reportLabelSz.Height + theme.InnerPadding()})
+	c.ReportButton.Alignment = widget.ButtonAlignCenter
+	repsz := c.ReportButton.Size()
+	c.ReportButton.Move(fyne.Position{X: sz.Width - rbsz.Width - theme.Padding() - dbsz.Width - theme.Padding() - repsz.Width, Y: sz.Height - repsz.Height})
 }
 
 func (c *CharacterCard) MinSize() fyne.Size {
@@ -587,6 +650,7 @@ func (c *CharacterCard) Objects() []fyne.CanvasObject {
 		c.AllianceTicker,
 		c.RefreshButton,
 		c.DeleteButton,
+		c.ReportButton,
 		c.MiniTagsContainer,
 		c.Roles,
 	}
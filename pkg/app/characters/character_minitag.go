@@ -13,6 +13,7 @@ import (
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
+	"github.com/kava-forge/eve-alts/lib/errors"
 	"github.com/kava-forge/eve-alts/lib/logging"
 	"github.com/kava-forge/eve-alts/lib/logging/level"
 
@@ -30,13 +31,14 @@ type CharacterMiniTag struct {
 	parent  fyne.Window
 	char    bindings.DataProxy[*repository.CharacterDBData]
 	tag     bindings.DataProxy[*repository.TagDBData]
+	matcher *matcher
 	isMatch bool
-	missing []string
+	match   TagMatch
 
 	update *sync.RWMutex
 }
 
-func NewCharacterMiniTag(deps dependencies, parent fyne.Window, char bindings.DataProxy[*repository.CharacterDBData], tag bindings.DataProxy[*repository.TagDBData]) *CharacterMiniTag {
+func NewCharacterMiniTag(deps dependencies, parent fyne.Window, m *matcher, char bindings.DataProxy[*repository.CharacterDBData], tag bindings.DataProxy[*repository.TagDBData]) *CharacterMiniTag {
 	logger := logging.With(deps.Logger(), keys.Component, "CharacterMiniTag")
 
 	tagData, err := tag.Get()
@@ -54,6 +56,7 @@ func NewCharacterMiniTag(deps dependencies, parent fyne.Window, char bindings.Da
 		parent:  parent,
 		char:    char,
 		tag:     tag,
+		matcher: m,
 
 		update: &sync.RWMutex{},
 	}
@@ -99,27 +102,53 @@ func (c *CharacterMiniTag) redraw() {
 		return
 	}
 
-	isMatch, missing := CharacterMatchesTag(char, tag)
-	level.Debug(logger).Message("tag match?", "match", isMatch, "missing", missing)
+	res, err := c.matcher.results()
+	if err != nil {
+		apperrors.Show(logger, c.parent, apperrors.Error(
+			"Could not match tags",
+			apperrors.WithCause(err),
+		), nil)
+		return
+	}
+
+	lvl, _ := res.Tag(char.Character.ID, tag.Tag.ID)
+	match := tagMatchFromLevel(lvl)
+	level.Debug(logger).Message("tag match?", "match", match)
 
 	c.SetText(tag.Tag.Name)
 	c.ColorSwatch.SetColor(tag.Color())
-	c.isMatch = isMatch
+	c.match = match
+	c.isMatch = match != TagMatchMissing
 	c.MiniTag.Dimmed = !c.isMatch
+	c.MiniTag.Partial = match == TagMatchRequired
 	c.MiniTag.RefreshStyle()
+}
+
+// skillLines names the skills the character is short of, looked up when the
+// tag is tapped rather than on every redraw
+func (c *CharacterMiniTag) skillLines() (missingLines, belowLines []string, err error) {
+	char, err := c.char.Get()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not find character data")
+	}
+	tag, err := c.tag.Get()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not find tag data")
+	}
 
-	ids := make([]int64, 0, len(missing))
+	_, missing, belowRecommended := CharacterTagMatch(char, tag)
+
+	ids := make([]int64, 0, len(missing)+len(belowRecommended))
 	for _, sk := range missing {
 		ids = append(ids, sk.SkillID)
 	}
+	for _, sk := range belowRecommended {
+		ids = append(ids, sk.SkillID)
+	}
 
 	names, err := c.deps.StaticRepo().BatchGetSkillNames(context.Background(), ids, nil)
 	if err != nil {
-		apperrors.Show(logger, c.parent, apperrors.Error(
-			"Could not find load skill names",
-			apperrors.WithCause(err),
-		), nil)
-		return
+		return nil, nil, errors.Wrap(err, "could not load skill names")
 	}
 
 	nameMap := make(map[int64]string, len(names))
@@ -127,10 +156,22 @@ func (c *CharacterMiniTag) redraw() {
 		nameMap[name.SkillID] = name.SkillName
 	}
 
-	c.missing = c.missing[:0]
 	for _, sk := range missing {
-		c.missing = append(c.missing, fmt.Sprintf("%s %d", nameMap[sk.SkillID], sk.SkillLevel))
+		missingLines = append(missingLines, fmt.Sprintf("%s %d", nameMap[sk.SkillID], sk.SkillLevel))
 	}
+	for _, sk := range belowRecommended {
+		belowLines = append(belowLines, fmt.Sprintf("%s %d", nameMap[sk.SkillID], sk.RecommendedLevel))
+	}
+
+	return missingLines, belowLines, nil
+}
+
+// Match is how far the character meets the tag
+func (c *CharacterMiniTag) Match() TagMatch {
+	c.update.RLock()
+	defer c.update.RUnlock()
+
+	return c.match
 }
 
 func (c *CharacterMiniTag) ShouldShow() bool {
@@ -192,15 +233,36 @@ var _ fyne.Tappable = (*CharacterMiniTag)(nil)
 func (c *CharacterMiniTag) Tapped(_ *fyne.PointEvent) {
 	logger := logging.With(c.deps.Logger(), keys.Component, "CharacterMiniTag.Tapped")
 	level.Debug(logger).Message("minitag tap")
-	if !c.isMatch {
-		list := widget.NewRichTextWithText(strings.Join(c.missing, "\n"))
+	if match := c.Match(); match != TagMatchRecommended {
+		missing, belowRecommended, err := c.skillLines()
+		if err != nil {
+			apperrors.Show(logger, c.parent, apperrors.Error(
+				"Could not find missing skills",
+				apperrors.WithCause(err),
+			), nil)
+			return
+		}
+
+		lines := make([]string, 0, len(missing)+len(belowRecommended)+2)
+		lines = append(lines, missing...)
+		if len(belowRecommended) > 0 {
+			if len(missing) > 0 {
+				lines = append(lines, "", "Recommended:")
+			}
+			lines = append(lines, belowRecommended...)
+		}
+		list := widget.NewRichTextWithText(strings.Join(lines, "\n"))
 		// scr := container.NewVScroll(list)
 		estTime := widget.NewLabel("Estimated train time: TBD")
 		copyButton := widget.NewButtonWithIcon("Copy", theme.ContentCopyIcon(), func() {
 			c.parent.Clipboard().SetContent(list.String())
 		})
 		data := container.New(layout.NewVBoxLayout(), list, layout.NewSpacer(), container.New(layout.NewHBoxLayout(), estTime, layout.NewSpacer(), copyButton))
-		d := dialog.NewCustom(fmt.Sprintf("Missing Skills for %s", c.NameLabel.String()), "Close", data, c.parent)
+		title := "Missing Skills for %s"
+		if match == TagMatchRequired {
+			title = "Recommended Skills for %s"
+		}
+		d := dialog.NewCustom(fmt.Sprintf(title, c.NameLabel.String()), "Close", data, c.parent)
 		d.Show()
 	}
 }
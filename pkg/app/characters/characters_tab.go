@@ -1,17 +1,21 @@
 package characters
 
 import (
+	"context"
 	"fmt"
+	"slices"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/layout"
 
+	"github.com/kava-forge/eve-alts/lib/errors"
 	"github.com/kava-forge/eve-alts/lib/logging"
 	"github.com/kava-forge/eve-alts/lib/logging/level"
 	"github.com/kava-forge/eve-alts/pkg/app/apperrors"
 	"github.com/kava-forge/eve-alts/pkg/app/bindings"
+	"github.com/kava-forge/eve-alts/pkg/database"
 	"github.com/kava-forge/eve-alts/pkg/keys"
 	"github.com/kava-forge/eve-alts/pkg/repository"
 )
@@ -26,6 +30,7 @@ func NewCharactersTab(deps dependencies, parent fyne.Window, tags *bindings.Data
 	charContainer := container.New(charLout)
 
 	chars := bindings.NewDataList[*repository.CharacterDBData]()
+	m := newMatcher(deps.Stats(), chars, tags, roles)
 	chars.AddListener(binding.NewDataListener(func() {
 		defer charContainer.Refresh()
 
@@ -70,26 +75,7 @@ func NewCharactersTab(deps dependencies, parent fyne.Window, tags *bindings.Data
 				continue
 			}
 
-			cc := NewCharacterCard(deps, parent, chars.Child(i), tags, roles, func(c *CharacterCard) {
-				level.Debug(logger).Message("removing character card")
-				char, err := chars.GetValue(i)
-				if err != nil {
-					apperrors.Show(logger, c.Parent(), apperrors.Error(
-						"Could not find character data",
-						apperrors.WithCause(err),
-					), nil)
-					return
-				}
-				char.Character.ID = 0
-				if err := chars.SetValue(i, char); err != nil {
-					apperrors.Show(logger, c.Parent(), apperrors.Error(
-						"Could not delete character",
-						apperrors.WithCause(err),
-					), nil)
-					return
-				}
-				charContainer.Remove(c)
-			})
+			cc := NewCharacterCard(deps, parent, m, chars.Child(i), tags, roles)
 			level.Debug(logger).Message("adding new character", "cc", fmt.Sprintf("%#v", cc))
 			charContainer.Add(cc)
 
@@ -103,17 +89,80 @@ func NewCharactersTab(deps dependencies, parent fyne.Window, tags *bindings.Data
 		}
 	}))
 
+	deps.AppRepo().Events().Subscribe(func(ev repository.ChangeEvent) {
+		if ev.Entity != repository.EntityCharacter {
+			return
+		}
+
+		logger := logging.With(logger, keys.CharacterID, ev.ID) //nolint:govet // intentional
+		level.Debug(logger).Message("character changed", "change", ev.Change)
+
+		if err := syncCharacters(context.Background(), deps, chars, ev); err != nil {
+			apperrors.Show(logger, parent, apperrors.Error(
+				"Could not load character data",
+				apperrors.WithCause(err),
+			), nil)
+		}
+
+		for _, o := range slices.Clone(charContainer.Objects) {
+			if c, ok := o.(*CharacterCard); ok && c.CharacterID() == 0 {
+				charContainer.Remove(c)
+			}
+		}
+	})
+
 	buttonLout := layout.NewGridWrapLayout(fyne.Size{Width: 500, Height: 50})
 	buttonContainer := container.New(buttonLout)
 
-	buttonContainer.Add(NewAddCharacterButton(deps, parent, chars))
+	buttonContainer.Add(NewAddCharacterButton(deps, parent))
 	buttonContainer.Add(NewRefreshAllButton(deps, parent, chars))
 
 	vbox := container.New(layout.NewVBoxLayout())
 	vbox.Add(buttonContainer)
 	vbox.Add(container.New(layout.NewPaddedLayout(), roleFilters.RoleSet))
-	vbox.Add(container.New(layout.NewPaddedLayout(), tagFilters.TagSet))
+	vbox.Add(container.New(layout.NewPaddedLayout(), container.NewBorder(nil, nil, tagFilters.Threshold, nil, tagFilters.TagSet)))
 	vbox.Add(charContainer)
 
 	return container.NewVScroll(vbox), chars
 }
+
+func characterID(c *repository.CharacterDBData) int64 {
+	if c == nil {
+		return 0
+	}
+	return c.Character.ID
+}
+
+func removedCharacter(c *repository.CharacterDBData) *repository.CharacterDBData {
+	removed := *c
+	removed.Character.ID = 0
+	return &removed
+}
+
+// syncCharacters brings the character list in line with the database after
+// ev, so the list only ever changes to match what was stored
+func syncCharacters(ctx context.Context, deps dependencies, chars *bindings.DataList[*repository.CharacterDBData], ev repository.ChangeEvent) error {
+	if ev.ID == 0 {
+		all, err := deps.AppRepo().GetAllCharacters(ctx, nil)
+		if err != nil && !errors.Is(err, database.ErrNoRows) {
+			return errors.Wrap(err, "could not GetAllCharacters")
+		}
+		return errors.Wrap(bindings.Replace(chars, all, characterID, removedCharacter), "could not set characters")
+	}
+
+	gone := func(c *repository.CharacterDBData) bool { return characterID(c) == ev.ID }
+
+	if ev.Change == repository.ChangeDeleted {
+		return errors.Wrap(bindings.RemoveWhere(chars, gone, removedCharacter), "could not remove character")
+	}
+
+	char, err := deps.AppRepo().GetCharacter(ctx, ev.ID, nil)
+	if errors.Is(err, database.ErrNoRows) {
+		// deleted again before the event got here
+		return errors.Wrap(bindings.RemoveWhere(chars, gone, removedCharacter), "could not remove character")
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not GetCharacter")
+	}
+	return errors.Wrap(bindings.MergeByID(chars, []*repository.CharacterDBData{char}, characterID), "could not set character")
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/kava-forge/eve-alts/lib/logging"
 	"github.com/kava-forge/eve-alts/pkg/database"
 	"github.com/kava-forge/eve-alts/pkg/esi"
+	"github.com/kava-forge/eve-alts/pkg/notify"
 	"github.com/kava-forge/eve-alts/pkg/repository"
 	"github.com/kava-forge/eve-alts/pkg/telemetry"
 )
@@ -19,4 +20,6 @@ type dependencies interface {
 
 	AppRepo() repository.AppData
 	StaticRepo() repository.StaticData
+
+	Notifier() *notify.Notifier
 }
@@ -0,0 +1,215 @@
+package characters
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/scoring"
+)
+
+// TagReport is a character's standing against one tag
+type TagReport struct {
+	Tag      repository.Tag
+	Match    TagMatch
+	Progress scoring.Progress
+	// BelowRecommended are the skills at their required level but not at the
+	// recommended one
+	BelowRecommended []repository.TagSkill
+}
+
+// RoleReport is a character's standing against one role
+type RoleReport struct {
+	Role   repository.Role
+	Result RoleResult
+}
+
+// MatchReport explains every role and tag match for one character
+type MatchReport struct {
+	Character *repository.CharacterDBData
+	Roles     []RoleReport
+	Tags      []TagReport
+
+	charSkills map[int64]int64
+	skillNames map[int64]string
+}
+
+// BuildMatchReport evaluates the character against every live role and tag,
+// each sorted by name. skillNames is used when rendering the missing skills.
+func BuildMatchReport(char *repository.CharacterDBData, roles []*repository.RoleDBData, tags []*repository.TagDBData, ranks scoring.Ranks, skillNames map[int64]string) MatchReport {
+	r := MatchReport{
+		Character:  char,
+		Roles:      make([]RoleReport, 0, len(roles)),
+		Tags:       make([]TagReport, 0, len(tags)),
+		charSkills: make(map[int64]int64, len(char.Skills)),
+		skillNames: skillNames,
+	}
+	for _, sk := range char.Skills {
+		r.charSkills[sk.SkillID] = sk.SkillLevel
+	}
+
+	for _, role := range roles {
+		if role == nil || role.Role.ID == 0 {
+			continue
+		}
+		_, res := CharacterMatchesRole(char, role, tags)
+		r.Roles = append(r.Roles, RoleReport{Role: role.Role, Result: res})
+	}
+	sort.SliceStable(r.Roles, func(i, j int) bool { return r.Roles[i].Role.Name < r.Roles[j].Role.Name })
+
+	for _, tag := range tags {
+		if tag == nil || tag.Tag.ID == 0 {
+			continue
+		}
+		match, _, below := CharacterTagMatch(char, tag)
+		r.Tags = append(r.Tags, TagReport{
+			Tag:              tag.Tag,
+			Match:            match,
+			Progress:         scoring.TagProgress(char, tag, ranks),
+			BelowRecommended: below,
+		})
+	}
+	sort.SliceStable(r.Tags, func(i, j int) bool { return r.Tags[i].Tag.Name < r.Tags[j].Tag.Name })
+
+	return r
+}
+
+// LoadMatchReport builds the report, looking up skill ranks and names for
+// every skill used by the tags
+func LoadMatchReport(ctx context.Context, static repository.StaticData, char *repository.CharacterDBData, roles []*repository.RoleDBData, tags []*repository.TagDBData) (MatchReport, error) {
+	live := make([]*repository.TagDBData, 0, len(tags))
+	for _, t := range tags {
+		if t != nil && t.Tag.ID != 0 {
+			live = append(live, t)
+		}
+	}
+
+	ranks, err := scoring.LoadRanks(ctx, static, live)
+	if err != nil {
+		return MatchReport{}, errors.Wrap(err, "could not load skill ranks")
+	}
+
+	skillIDs := make([]int64, 0, len(ranks))
+	seen := map[int64]bool{}
+	for _, t := range live {
+		for _, sk := range t.AllSkills() {
+			if !seen[sk.SkillID] {
+				seen[sk.SkillID] = true
+				skillIDs = append(skillIDs, sk.SkillID)
+			}
+		}
+	}
+
+	nameMap := make(map[int64]string, len(skillIDs))
+	if len(skillIDs) > 0 {
+		rows, err := static.BatchGetSkillNames(ctx, skillIDs, nil)
+		if err != nil {
+			return MatchReport{}, errors.Wrap(err, "could not fetch skill names")
+		}
+		for _, row := range rows {
+			nameMap[row.SkillID] = row.SkillName
+		}
+	}
+
+	return BuildMatchReport(char, roles, live, ranks, nameMap), nil
+}
+
+func (r MatchReport) skillName(skillID int64) string {
+	if name, ok := r.skillNames[skillID]; ok {
+		return name
+	}
+	return fmt.Sprintf("skill #%d", skillID)
+}
+
+// String renders the report as plain text, suitable for pasting elsewhere
+func (r MatchReport) String() string {
+	var b strings.Builder
+
+	c := r.Character
+	fmt.Fprintf(&b, "%s [%s]", c.Character.Name, c.Corporation.Ticker)
+	if c.Alliance.Ticker.Valid {
+		fmt.Fprintf(&b, " [%s]", c.Alliance.Ticker.String)
+	}
+	fmt.Fprintf(&b, "\nTotal SP: %s\n", scoring.FormatSP(c.Character.TotalSp))
+
+	b.WriteString("\nRoles\n")
+	if len(r.Roles) == 0 {
+		b.WriteString("(none)\n")
+	}
+	for _, role := range r.Roles {
+		mark := "✗"
+		if role.Result.Matched {
+			mark = "✓"
+		}
+		fmt.Fprintf(&b, "%s %s\n", mark, role.Role.Name)
+		for _, line := range role.Result.Lines() {
+			fmt.Fprintf(&b, "    %s\n", line)
+		}
+	}
+
+	b.WriteString("\nTags\n")
+	if len(r.Tags) == 0 {
+		b.WriteString("(none)\n")
+	}
+	for _, tag := range r.Tags {
+		switch tag.Match {
+		case TagMatchMissing:
+			fmt.Fprintf(&b, "✗ %s (%s SP needed)\n", tag.Tag.Name, scoring.FormatSP(tag.Progress.SPNeeded()))
+		case TagMatchRequired:
+			fmt.Fprintf(&b, "✓ %s (below recommended)\n", tag.Tag.Name)
+		default:
+			fmt.Fprintf(&b, "✓ %s\n", tag.Tag.Name)
+		}
+
+		for _, gap := range tag.Progress.Missing {
+			cur := fmt.Sprintf("%d", gap.CurrentLevel)
+			if gap.CurrentLevel < 0 {
+				cur = "not injected"
+			}
+			fmt.Fprintf(&b, "    %s: %s -> %d (%s SP)\n", r.skillName(gap.SkillID), cur, gap.TargetLevel, scoring.FormatSP(gap.SPNeeded))
+		}
+		for _, sk := range tag.BelowRecommended {
+			cur := "not injected"
+			if lvl, ok := r.charSkills[sk.SkillID]; ok {
+				cur = fmt.Sprintf("%d", lvl)
+			}
+			fmt.Fprintf(&b, "    %s: %s -> %d recommended\n", r.skillName(sk.SkillID), cur, sk.RecommendedLevel)
+		}
+	}
+
+	return b.String()
+}
+
+// showMatchReport shows the character's full match report with a button to
+// copy it to the clipboard
+func showMatchReport(deps dependencies, parent fyne.Window, char *repository.CharacterDBData, roles []*repository.RoleDBData, tags []*repository.TagDBData) error {
+	report, err := LoadMatchReport(context.Background(), deps.StaticRepo(), char, roles, tags)
+	if err != nil {
+		return err
+	}
+
+	text := report.String()
+
+	body := widget.NewLabelWithStyle(text, fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+	copyButton := widget.NewButtonWithIcon("Copy", theme.ContentCopyIcon(), func() {
+		parent.Clipboard().SetContent(text)
+	})
+	content := container.NewBorder(nil, container.New(layout.NewHBoxLayout(), layout.NewSpacer(), copyButton), nil, nil, container.NewVScroll(body))
+
+	d := dialog.NewCustom(fmt.Sprintf("%s Match Report", char.Character.Name), "Close", content, parent)
+	d.Resize(fyne.Size{Width: 600, Height: 600})
+	d.Show()
+
+	return nil
+}
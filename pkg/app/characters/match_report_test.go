@@ -0,0 +1,73 @@
+package characters_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kava-forge/eve-alts/pkg/app/characters"
+	"github.com/kava-forge/eve-alts/pkg/operators"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/scoring"
+)
+
+func TestMatchReport(t *testing.T) {
+	t.Parallel()
+
+	char := &repository.CharacterDBData{
+		Character:   repository.Character{ID: 1, Name: "Pilot", TotalSp: 1_500_000},
+		Corporation: repository.Corporation{ID: 10, Ticker: "CORP"},
+		Alliance:    repository.Alliance{ID: sql.NullInt64{Int64: 20, Valid: true}, Ticker: sql.NullString{String: "ALLY", Valid: true}},
+		Skills: []repository.CharacterSkill{
+			{SkillID: 100, SkillLevel: 3},
+			{SkillID: 102, SkillLevel: 4},
+		},
+	}
+	tags := []*repository.TagDBData{
+		{Tag: repository.Tag{ID: 1, Name: "Logi"}, Skills: []repository.TagSkill{
+			{TagID: 1, SkillID: 100, SkillLevel: 5},
+			{TagID: 1, SkillID: 101, SkillLevel: 1},
+		}},
+		{Tag: repository.Tag{ID: 2, Name: "Cap"}, Skills: []repository.TagSkill{
+			{TagID: 2, SkillID: 102, SkillLevel: 4, RecommendedLevel: 5},
+		}},
+		{Tag: repository.Tag{}}, // deleted
+	}
+	roles := []*repository.RoleDBData{
+		{
+			Role: repository.Role{ID: 1, Name: "Logi Pilot"},
+			Expr: repository.NewRoleGroup(operators.OperatorAll, repository.NewRoleLeaf(1), repository.NewRoleLeaf(2)),
+		},
+	}
+	names := map[int64]string{100: "Logistics Cruisers", 102: "Capacitor Management"}
+
+	r := characters.BuildMatchReport(char, roles, tags, scoring.Ranks{100: 5}, names)
+
+	if assert.Len(t, r.Tags, 2) {
+		assert.Equal(t, "Cap", r.Tags[0].Tag.Name)
+		assert.Equal(t, characters.TagMatchRequired, r.Tags[0].Match)
+		assert.Equal(t, characters.TagMatchMissing, r.Tags[1].Match)
+		assert.Len(t, r.Tags[1].Progress.Missing, 2)
+	}
+	if assert.Len(t, r.Roles, 1) {
+		assert.False(t, r.Roles[0].Result.Matched)
+	}
+
+	assert.Equal(t, `Pilot [CORP] [ALLY]
+Total SP: 1.50M
+
+Roles
+✗ Logi Pilot
+    ✗ all of (1 more needed)
+        ✗ Logi
+        ✓ Cap
+
+Tags
+✓ Cap (below recommended)
+    Capacitor Management: 4 -> 5 recommended
+✗ Logi (1.24M SP needed)
+    Logistics Cruisers: 3 -> 5 (1.24M SP)
+    skill #101: not injected -> 1 (250 SP)
+`, r.String())
+}
@@ -0,0 +1,64 @@
+package characters
+
+import (
+	"context"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/app/bindings"
+	"github.com/kava-forge/eve-alts/pkg/matching"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/telemetry"
+)
+
+// matcher answers every card's match questions from one engine shared by the
+// whole roster, so redraws and filter changes only recompute anything after
+// characters, tags or roles change
+type matcher struct {
+	stats  *telemetry.Stats
+	engine *matching.Engine
+	chars  *bindings.DataList[*repository.CharacterDBData]
+	tags   *bindings.DataList[*repository.TagDBData]
+	roles  *bindings.DataList[*repository.RoleDBData]
+}
+
+func newMatcher(stats *telemetry.Stats, chars *bindings.DataList[*repository.CharacterDBData], tags *bindings.DataList[*repository.TagDBData], roles *bindings.DataList[*repository.RoleDBData]) *matcher {
+	return &matcher{
+		stats:  stats,
+		engine: matching.NewEngine(),
+		chars:  chars,
+		tags:   tags,
+		roles:  roles,
+	}
+}
+
+func (m *matcher) results() (*matching.Results, error) {
+	chars, err := m.chars.Get()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load character list data")
+	}
+	tags, err := m.tags.Get()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load tag list data")
+	}
+	roles, err := m.roles.Get()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load role list data")
+	}
+
+	res, hit := m.engine.Lookup(chars, tags, roles)
+	m.stats.CacheLookup(context.Background(), "matching", hit)
+	return res, nil
+}
+
+// tagMatchFromLevel converts the engine's tag level to the UI's threshold
+func tagMatchFromLevel(l matching.TagLevel) TagMatch {
+	switch l {
+	case matching.TagRecommended:
+		return TagMatchRecommended
+	case matching.TagRequired:
+		return TagMatchRequired
+	default:
+		return TagMatchMissing
+	}
+}
@@ -1,8 +1,26 @@
 package characters
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/kava-forge/eve-alts/pkg/conditions"
+	"github.com/kava-forge/eve-alts/pkg/matching"
 	"github.com/kava-forge/eve-alts/pkg/operators"
 	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/scoring"
+)
+
+// TagMatch is how far a character meets a tag's skill levels
+type TagMatch int
+
+const (
+	// TagMatchMissing means at least one required level is not trained
+	TagMatchMissing TagMatch = iota
+	// TagMatchRequired means every required level is trained, but not every recommended one
+	TagMatchRequired
+	// TagMatchRecommended means every required and recommended level is trained
+	TagMatchRecommended
 )
 
 func CharacterMatchesTag(char *repository.CharacterDBData, tag *repository.TagDBData) (bool, []repository.TagSkill) {
@@ -14,12 +32,41 @@ func CharacterMatchesTag(char *repository.CharacterDBData, tag *repository.TagDB
 	return skillsMatchTag(charSkills, tag)
 }
 
-func skillsMatchTag(charSkills map[int64]int64, tag *repository.TagDBData) (bool, []repository.TagSkill) {
-	missing := make([]repository.TagSkill, 0, len(tag.Skills))
+// CharacterTagMatch grades a character against both tag thresholds. missing
+// lists skills below their required level, and belowRecommended the skills
+// that meet the required level but not the recommended one.
+func CharacterTagMatch(char *repository.CharacterDBData, tag *repository.TagDBData) (match TagMatch, missing, belowRecommended []repository.TagSkill) {
+	charSkills := make(map[int64]int64, len(char.Skills))
+	for _, sk := range char.Skills {
+		charSkills[sk.SkillID] = sk.SkillLevel
+	}
 
-	for _, sk := range tag.Skills {
+	for _, sk := range tag.AllSkills() {
 		lvl, ok := charSkills[sk.SkillID]
-		if !ok || lvl < sk.SkillLevel {
+		switch {
+		case !meetsRequired(charSkills, sk):
+			missing = append(missing, sk)
+		case !ok || lvl < sk.RecommendedLevel:
+			belowRecommended = append(belowRecommended, sk)
+		}
+	}
+
+	switch {
+	case len(missing) > 0:
+		return TagMatchMissing, missing, belowRecommended
+	case len(belowRecommended) > 0:
+		return TagMatchRequired, missing, belowRecommended
+	default:
+		return TagMatchRecommended, missing, belowRecommended
+	}
+}
+
+func skillsMatchTag(charSkills map[int64]int64, tag *repository.TagDBData) (bool, []repository.TagSkill) {
+	skills := tag.AllSkills()
+	missing := make([]repository.TagSkill, 0, len(skills))
+
+	for _, sk := range skills {
+		if !meetsRequired(charSkills, sk) {
 			missing = append(missing, sk)
 		}
 	}
@@ -27,7 +74,207 @@ func skillsMatchTag(charSkills map[int64]int64, tag *repository.TagDBData) (bool
 	return len(missing) == 0, missing
 }
 
-func CharacterMatchesRole(char *repository.CharacterDBData, role *repository.RoleDBData, tags []*repository.TagDBData) (bool, []repository.Tag) {
+// meetsRequired checks a single tag skill's required level. A skill that is
+// only recommended (required level 0 with a recommended level) is always met;
+// otherwise the skill must at least be injected.
+func meetsRequired(charSkills map[int64]int64, sk repository.TagSkill) bool {
+	if sk.SkillLevel == 0 && sk.RecommendedLevel > 0 {
+		return true
+	}
+	lvl, ok := charSkills[sk.SkillID]
+	return ok && lvl >= sk.SkillLevel
+}
+
+// RoleExplanation is how one node of a role's expression evaluated for a
+// character. Leaves carry the tag and the skills it is short of; groups carry
+// their operator and the explanation of each child.
+type RoleExplanation struct {
+	Operator operators.Operator
+	MinCount int64
+	Tag      repository.Tag
+	Matched  bool
+	// Needed is how many more children a failed group needs to match
+	Needed   int64
+	Missing  []repository.TagSkill
+	Children []RoleExplanation
+}
+
+func (e RoleExplanation) IsLeaf() bool {
+	return e.Tag.ID != 0
+}
+
+// Lines renders the explanation as an indented outline, one node per line
+func (e RoleExplanation) Lines() []string {
+	lines := make([]string, 0)
+
+	var walk func(n RoleExplanation, depth int)
+	walk = func(n RoleExplanation, depth int) {
+		mark := "✗"
+		if n.Matched {
+			mark = "✓"
+		}
+
+		text := n.Tag.Name
+		if !n.IsLeaf() {
+			text = operatorDescription(n.Operator, n.MinCount)
+			if n.Needed > 0 {
+				text = fmt.Sprintf("%s (%d more needed)", text, n.Needed)
+			}
+		}
+		lines = append(lines, fmt.Sprintf("%s%s %s", strings.Repeat("    ", depth), mark, text))
+
+		for _, ch := range n.Children {
+			walk(ch, depth+1)
+		}
+	}
+	walk(e, 0)
+
+	return lines
+}
+
+// Failing lists the tags that made the expression fail: unmatched tags under
+// "all", "any" and "atleast" groups, and matched tags under "none" groups
+func (e RoleExplanation) Failing() []repository.Tag {
+	if e.Matched {
+		return nil
+	}
+
+	failing := make([]repository.Tag, 0)
+	if e.IsLeaf() {
+		return append(failing, e.Tag)
+	}
+
+	for _, ch := range e.Children {
+		switch {
+		case e.Operator == operators.OperatorNone && ch.Matched:
+			failing = append(failing, ch.matchedTags()...)
+		case e.Operator != operators.OperatorNone:
+			failing = append(failing, ch.Failing()...)
+		}
+	}
+	return failing
+}
+
+func (e RoleExplanation) matchedTags() []repository.Tag {
+	if e.IsLeaf() {
+		return []repository.Tag{e.Tag}
+	}
+
+	matched := make([]repository.Tag, 0)
+	for _, ch := range e.Children {
+		if ch.Matched {
+			matched = append(matched, ch.matchedTags()...)
+		}
+	}
+	return matched
+}
+
+func operatorDescription(op operators.Operator, minCount int64) string {
+	switch op {
+	case operators.OperatorAll:
+		return "all of"
+	case operators.OperatorAny:
+		return "any of"
+	case operators.OperatorNone:
+		return "none of"
+	case operators.OperatorAtleast:
+		return fmt.Sprintf("at least %d of", minCount)
+	default:
+		return op.String()
+	}
+}
+
+// ConditionResult is one of a role's conditions checked against a character
+type ConditionResult struct {
+	Condition repository.RoleCondition
+	Met       bool
+	// Detail describes the condition, and what the character has instead if
+	// it is not met
+	Detail string
+}
+
+// CharacterMeetsCondition checks a role condition against the character's
+// corporation, alliance, total SP or clone state
+func CharacterMeetsCondition(char *repository.CharacterDBData, cond repository.RoleCondition) ConditionResult {
+	res := ConditionResult{Condition: cond, Met: matching.MeetsCondition(char, cond)}
+
+	switch conditions.Kind(cond.Kind) {
+	case conditions.KindCorporation:
+		res.Detail = fmt.Sprintf("member of %s", cond.Label)
+		if !res.Met {
+			res.Detail = fmt.Sprintf("%s (in %s)", res.Detail, char.Corporation.Name)
+		}
+	case conditions.KindAlliance:
+		res.Detail = fmt.Sprintf("in alliance %s", cond.Label)
+		if !res.Met {
+			cur := "no alliance"
+			if char.Alliance.ID.Valid {
+				cur = char.Alliance.Name.String
+			}
+			res.Detail = fmt.Sprintf("%s (in %s)", res.Detail, cur)
+		}
+	case conditions.KindMinSp:
+		res.Detail = fmt.Sprintf("at least %s SP", scoring.FormatSP(cond.Value))
+		if !res.Met {
+			res.Detail = fmt.Sprintf("%s (has %s)", res.Detail, scoring.FormatSP(char.Character.TotalSp))
+		}
+	case conditions.KindOmega:
+		res.Detail = "omega clone"
+		switch {
+		case !char.Character.Omega.Valid:
+			res.Detail = "omega clone (unknown until the character is refreshed)"
+		case !res.Met:
+			res.Detail = "omega clone (alpha)"
+		}
+	default:
+		res.Detail = fmt.Sprintf("unknown condition %q", cond.Kind)
+	}
+
+	return res
+}
+
+// RoleResult is a character's evaluation against a whole role: every
+// condition must be met and the tag expression must match
+type RoleResult struct {
+	Matched    bool
+	Conditions []ConditionResult
+	Expr       RoleExplanation
+}
+
+// Lines lists the conditions, then the expression outline
+func (r RoleResult) Lines() []string {
+	lines := make([]string, 0, len(r.Conditions))
+	for _, c := range r.Conditions {
+		mark := "✗"
+		if c.Met {
+			mark = "✓"
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", mark, c.Detail))
+	}
+	return append(lines, r.Expr.Lines()...)
+}
+
+// Failing lists the tags that made the expression fail
+func (r RoleResult) Failing() []repository.Tag {
+	return r.Expr.Failing()
+}
+
+// FailingConditions lists the conditions that are not met
+func (r RoleResult) FailingConditions() []ConditionResult {
+	failing := make([]ConditionResult, 0)
+	for _, c := range r.Conditions {
+		if !c.Met {
+			failing = append(failing, c)
+		}
+	}
+	return failing
+}
+
+// CharacterMatchesRole evaluates the role's conditions and expression for the
+// character. Tags that no longer exist are left out of the evaluation, as are
+// groups with nothing left in them. An expression with nothing left at all
+// matches.
+func CharacterMatchesRole(char *repository.CharacterDBData, role *repository.RoleDBData, tags []*repository.TagDBData) (bool, RoleResult) {
 	charSkills := make(map[int64]int64, len(char.Skills))
 	for _, sk := range char.Skills {
 		charSkills[sk.SkillID] = sk.SkillLevel
@@ -35,35 +282,79 @@ func CharacterMatchesRole(char *repository.CharacterDBData, role *repository.Rol
 
 	tLookup := make(map[int64]*repository.TagDBData)
 	for _, tdb := range tags {
-		tLookup[tdb.Tag.ID] = tdb
+		if tdb.Tag.ID != 0 {
+			tLookup[tdb.Tag.ID] = tdb
+		}
 	}
 
-	mismatch := make([]repository.Tag, 0, len(role.Tags))
+	expr := role.Expr
+	if expr == nil {
+		expr = repository.NewRoleGroup(operators.OperatorAll)
+	}
+
+	ex, ok := evalRoleExpr(charSkills, expr, tLookup)
+	if !ok {
+		ex.Matched = true
+	}
 
-	for _, t := range role.Tags {
-		tdb, ok := tLookup[t.ID]
+	res := RoleResult{Matched: ex.Matched, Expr: ex, Conditions: make([]ConditionResult, 0, len(role.Conditions))}
+	for _, cond := range role.Conditions {
+		cr := CharacterMeetsCondition(char, cond)
+		res.Matched = res.Matched && cr.Met
+		res.Conditions = append(res.Conditions, cr)
+	}
+
+	return res.Matched, res
+}
+
+// evalRoleExpr returns false for ok when the node should be ignored: a leaf
+// whose tag is gone, or a group without any remaining children. Matching
+// itself is left to the engine, so a role matches here exactly when it
+// matches in the compiled results.
+func evalRoleExpr(charSkills map[int64]int64, e *repository.RoleExpr, tLookup map[int64]*repository.TagDBData) (RoleExplanation, bool) {
+	missing := make(map[int64][]repository.TagSkill)
+	node, ok := matching.EvalExpr(e, func(tagID int64) (bool, bool) {
+		tdb, ok := tLookup[tagID]
 		if !ok {
-			continue
+			return false, false
 		}
+		match, m := skillsMatchTag(charSkills, tdb)
+		missing[tagID] = m
+		return match, true
+	})
 
-		match, _ := skillsMatchTag(charSkills, tdb)
-		switch role.Operator {
-		case operators.OperatorAny:
-			if match {
-				return true, nil
-			} else {
-				mismatch = append(mismatch, t)
-			}
-		case operators.OperatorAll:
-			if !match {
-				mismatch = append(mismatch, t)
-			}
-		case operators.OperatorNone:
-			if match {
-				mismatch = append(mismatch, t)
-			}
+	return explain(node, tLookup, missing), ok
+}
+
+// explain turns the engine's node into an explanation, counting how many more
+// children each failed group needs
+func explain(node matching.ExprNode, tLookup map[int64]*repository.TagDBData, missing map[int64][]repository.TagSkill) RoleExplanation {
+	e := node.Expr
+	if e.IsLeaf() {
+		var tag repository.Tag
+		if tdb, ok := tLookup[e.TagID]; ok {
+			tag = tdb.Tag
 		}
+		return RoleExplanation{Tag: tag, Matched: node.Matched, Missing: missing[e.TagID]}
+	}
+
+	ex := RoleExplanation{Operator: e.Operator, MinCount: e.MinCount, Matched: node.Matched, Children: make([]RoleExplanation, 0, len(node.Children))}
+	var matched int64
+	for _, ch := range node.Children {
+		if ch.Matched {
+			matched++
+		}
+		ex.Children = append(ex.Children, explain(ch, tLookup, missing))
+	}
+
+	switch e.Operator {
+	case operators.OperatorAll:
+		ex.Needed = int64(len(ex.Children)) - matched
+	case operators.OperatorAny:
+		ex.Needed = max(1-matched, 0)
+	case operators.OperatorAtleast:
+		ex.Needed = max(e.MinCount-matched, 0)
 	}
 
-	return len(mismatch) == 0, mismatch
+	return ex
 }
@@ -0,0 +1,339 @@
+package characters_test
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kava-forge/eve-alts/pkg/app/characters"
+	"github.com/kava-forge/eve-alts/pkg/matching"
+	"github.com/kava-forge/eve-alts/pkg/operators"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/testhelpers/roster"
+)
+
+func TestCharacterTagMatch(t *testing.T) {
+	t.Parallel()
+
+	tag := &repository.TagDBData{
+		Tag: repository.Tag{ID: 1, Name: "Logi"},
+		Skills: []repository.TagSkill{
+			{TagID: 1, SkillID: 100, SkillLevel: 4, RecommendedLevel: 5},
+			{TagID: 1, SkillID: 101, SkillLevel: 3},
+			{TagID: 1, SkillID: 102, SkillLevel: 0, RecommendedLevel: 4}, // recommended only
+		},
+	}
+
+	tests := []struct {
+		name                 string
+		skills               map[int64]int64
+		want                 characters.TagMatch
+		wantMissing          []int64
+		wantBelowRecommended []int64
+	}{
+		{
+			name:                 "missing required",
+			skills:               map[int64]int64{100: 3, 101: 3},
+			want:                 characters.TagMatchMissing,
+			wantMissing:          []int64{100},
+			wantBelowRecommended: []int64{102},
+		},
+		{
+			name:                 "meets required",
+			skills:               map[int64]int64{100: 4, 101: 5, 102: 1},
+			want:                 characters.TagMatchRequired,
+			wantBelowRecommended: []int64{100, 102},
+		},
+		{
+			name:   "meets recommended",
+			skills: map[int64]int64{100: 5, 101: 3, 102: 4},
+			want:   characters.TagMatchRecommended,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			char := &repository.CharacterDBData{}
+			for id, lvl := range tt.skills {
+				char.Skills = append(char.Skills, repository.CharacterSkill{SkillID: id, SkillLevel: lvl})
+			}
+
+			got, missing, below := characters.CharacterTagMatch(char, tag)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantMissing, skillIDs(missing))
+			assert.Equal(t, tt.wantBelowRecommended, skillIDs(below))
+
+			// the binary check used by roles agrees on the required threshold
+			isMatch, _ := characters.CharacterMatchesTag(char, tag)
+			assert.Equal(t, tt.want != characters.TagMatchMissing, isMatch)
+		})
+	}
+}
+
+func skillIDs(skills []repository.TagSkill) []int64 {
+	var ids []int64
+	for _, sk := range skills {
+		ids = append(ids, sk.SkillID)
+	}
+	return ids
+}
+
+func TestCharacterMatchesRole(t *testing.T) {
+	t.Parallel()
+
+	newTag := func(id, skillID int64, name string) *repository.TagDBData {
+		return &repository.TagDBData{
+			Tag:    repository.Tag{ID: id, Name: name},
+			Skills: []repository.TagSkill{{TagID: id, SkillID: skillID, SkillLevel: 1}},
+		}
+	}
+	logiCruiser := newTag(1, 100, "Logi Cruiser")
+	capSkills := newTag(2, 101, "Cap Skills")
+	logiFrigate := newTag(3, 102, "Logi Frigate")
+	alpha := newTag(4, 103, "Alpha")
+	tags := []*repository.TagDBData{logiCruiser, capSkills, logiFrigate, alpha}
+
+	// (Logi Cruiser AND Cap Skills) OR Logi Frigate, AND NOT Alpha
+	role := &repository.RoleDBData{
+		Role: repository.Role{ID: 1, Name: "Logi"},
+		Expr: repository.NewRoleGroup(operators.OperatorAll,
+			repository.NewRoleGroup(operators.OperatorAny,
+				repository.NewRoleGroup(operators.OperatorAll, repository.NewRoleLeaf(1), repository.NewRoleLeaf(2)),
+				repository.NewRoleLeaf(3),
+			),
+			repository.NewRoleGroup(operators.OperatorNone, repository.NewRoleLeaf(4)),
+			repository.NewRoleLeaf(99), // deleted tag, ignored
+		),
+	}
+
+	tests := []struct {
+		name        string
+		skills      []int64
+		want        bool
+		wantFailing []string
+	}{
+		{
+			name:   "cruiser and cap",
+			skills: []int64{100, 101},
+			want:   true,
+		},
+		{
+			name:   "frigate",
+			skills: []int64{102},
+			want:   true,
+		},
+		{
+			name:        "cruiser without cap",
+			skills:      []int64{100},
+			want:        false,
+			wantFailing: []string{"Cap Skills", "Logi Frigate"},
+		},
+		{
+			name:        "alpha",
+			skills:      []int64{102, 103},
+			want:        false,
+			wantFailing: []string{"Alpha"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			char := &repository.CharacterDBData{}
+			for _, id := range tt.skills {
+				char.Skills = append(char.Skills, repository.CharacterSkill{SkillID: id, SkillLevel: 5})
+			}
+
+			got, ex := characters.CharacterMatchesRole(char, role, tags)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want, ex.Matched)
+
+			var failing []string
+			for _, tag := range ex.Failing() {
+				failing = append(failing, tag.Name)
+			}
+			assert.Equal(t, tt.wantFailing, failing)
+		})
+	}
+}
+
+func TestCharacterMatchesRole_Atleast(t *testing.T) {
+	t.Parallel()
+
+	tags := make([]*repository.TagDBData, 0, 5)
+	leaves := make([]*repository.RoleExpr, 0, 5)
+	for i := int64(1); i <= 5; i++ {
+		tags = append(tags, &repository.TagDBData{
+			Tag:    repository.Tag{ID: i, Name: fmt.Sprintf("Weapon %d", i)},
+			Skills: []repository.TagSkill{{TagID: i, SkillID: 100 + i, SkillLevel: 1}},
+		})
+		leaves = append(leaves, repository.NewRoleLeaf(i))
+	}
+	role := &repository.RoleDBData{Expr: repository.NewRoleAtleast(3, leaves...)}
+
+	tests := []struct {
+		name       string
+		skills     []int64
+		want       bool
+		wantNeeded int64
+	}{
+		{name: "three", skills: []int64{101, 103, 105}, want: true},
+		{name: "all five", skills: []int64{101, 102, 103, 104, 105}, want: true},
+		{name: "one", skills: []int64{102}, want: false, wantNeeded: 2},
+		{name: "none", want: false, wantNeeded: 3},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			char := &repository.CharacterDBData{}
+			for _, id := range tt.skills {
+				char.Skills = append(char.Skills, repository.CharacterSkill{SkillID: id, SkillLevel: 1})
+			}
+
+			got, ex := characters.CharacterMatchesRole(char, role, tags)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantNeeded, ex.Expr.Needed)
+			if !tt.want {
+				assert.Equal(t, fmt.Sprintf("✗ at least 3 of (%d more needed)", tt.wantNeeded), ex.Lines()[0])
+				assert.Len(t, ex.Failing(), 5-len(tt.skills))
+			}
+		})
+	}
+}
+
+func TestRoleExplanation_Lines(t *testing.T) {
+	t.Parallel()
+
+	tags := []*repository.TagDBData{{
+		Tag:    repository.Tag{ID: 1, Name: "Logi Cruiser"},
+		Skills: []repository.TagSkill{{TagID: 1, SkillID: 100, SkillLevel: 1}},
+	}}
+	role := &repository.RoleDBData{
+		Expr: repository.NewRoleGroup(operators.OperatorAny,
+			repository.NewRoleGroup(operators.OperatorNone, repository.NewRoleLeaf(1)),
+		),
+	}
+
+	_, ex := characters.CharacterMatchesRole(&repository.CharacterDBData{}, role, tags)
+	assert.Equal(t, []string{
+		"✓ any of",
+		"    ✓ none of",
+		"        ✗ Logi Cruiser",
+	}, ex.Lines())
+
+	// a role whose tags are all gone matches
+	match, _ := characters.CharacterMatchesRole(&repository.CharacterDBData{}, role, nil)
+	assert.True(t, match)
+}
+
+func TestCharacterMeetsCondition(t *testing.T) {
+	t.Parallel()
+
+	char := &repository.CharacterDBData{
+		Character:   repository.Character{ID: 1, TotalSp: 5_000_000, Omega: sql.NullBool{Bool: true, Valid: true}},
+		Corporation: repository.Corporation{ID: 10, Name: "Corp"},
+		Alliance:    repository.Alliance{ID: sql.NullInt64{Int64: 20, Valid: true}, Name: sql.NullString{String: "Alliance", Valid: true}},
+	}
+	alpha := &repository.CharacterDBData{
+		Character:   repository.Character{ID: 2, Omega: sql.NullBool{Valid: true}},
+		Corporation: repository.Corporation{ID: 11, Name: "Other"},
+	}
+	unknown := &repository.CharacterDBData{Character: repository.Character{ID: 3}}
+
+	tests := []struct {
+		name string
+		char *repository.CharacterDBData
+		cond repository.RoleCondition
+		want bool
+	}{
+		{"corp match", char, repository.RoleCondition{Kind: "corporation", Value: 10}, true},
+		{"corp mismatch", alpha, repository.RoleCondition{Kind: "corporation", Value: 10}, false},
+		{"alliance match", char, repository.RoleCondition{Kind: "alliance", Value: 20}, true},
+		{"no alliance", alpha, repository.RoleCondition{Kind: "alliance", Value: 20}, false},
+		{"enough sp", char, repository.RoleCondition{Kind: "min_sp", Value: 5_000_000}, true},
+		{"too little sp", char, repository.RoleCondition{Kind: "min_sp", Value: 5_000_001}, false},
+		{"omega", char, repository.RoleCondition{Kind: "omega"}, true},
+		{"alpha", alpha, repository.RoleCondition{Kind: "omega"}, false},
+		{"clone state unknown", unknown, repository.RoleCondition{Kind: "omega"}, false},
+		{"unknown kind", char, repository.RoleCondition{Kind: "bogus"}, false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res := characters.CharacterMeetsCondition(tt.char, tt.cond)
+			assert.Equal(t, tt.want, res.Met)
+			assert.NotEmpty(t, res.Detail)
+		})
+	}
+}
+
+func TestCharacterMatchesRole_Conditions(t *testing.T) {
+	t.Parallel()
+
+	tags := []*repository.TagDBData{
+		{Tag: repository.Tag{ID: 1, Name: "A"}, Skills: []repository.TagSkill{{TagID: 1, SkillID: 100, SkillLevel: 1}}},
+	}
+	role := &repository.RoleDBData{
+		Role: repository.Role{ID: 1, Name: "R"},
+		Expr: repository.NewRoleGroup(operators.OperatorAll, repository.NewRoleLeaf(1)),
+		Conditions: []repository.RoleCondition{
+			{Kind: "corporation", Value: 10, Label: "Corp [C]"},
+		},
+	}
+
+	char := &repository.CharacterDBData{
+		Corporation: repository.Corporation{ID: 10, Name: "Corp"},
+		Skills:      []repository.CharacterSkill{{SkillID: 100, SkillLevel: 1}},
+	}
+	match, res := characters.CharacterMatchesRole(char, role, tags)
+	assert.True(t, match)
+	assert.Empty(t, res.FailingConditions())
+	assert.Equal(t, "✓ member of Corp [C]", res.Lines()[0])
+
+	char.Corporation = repository.Corporation{ID: 11, Name: "Other"}
+	match, res = characters.CharacterMatchesRole(char, role, tags)
+	assert.False(t, match)
+	assert.True(t, res.Expr.Matched)
+	assert.Len(t, res.FailingConditions(), 1)
+	assert.Equal(t, "✗ member of Corp [C] (in Other)", res.Lines()[0])
+}
+
+func TestEngineAgreesWithPerCharacterMatching(t *testing.T) {
+	t.Parallel()
+
+	chars, tags, roles := roster.Random(1, 60, 40, 30)
+	res := matching.Compile(chars, tags, roles)
+
+	for _, c := range chars {
+		for _, tag := range tags {
+			if tag.Tag.ID == 0 {
+				continue
+			}
+			want, _, _ := characters.CharacterTagMatch(c, tag)
+			got, ok := res.Tag(c.Character.ID, tag.Tag.ID)
+			if assert.True(t, ok) {
+				assert.Equal(t, int(want), int(got), "character %d, tag %d", c.Character.ID, tag.Tag.ID)
+			}
+		}
+		for _, role := range roles {
+			if role.Role.ID == 0 {
+				continue
+			}
+			want, _ := characters.CharacterMatchesRole(c, role, tags)
+			got, ok := res.Role(c.Character.ID, role.Role.ID)
+			if assert.True(t, ok) {
+				assert.Equal(t, want, got, "character %d, role %d", c.Character.ID, role.Role.ID)
+			}
+		}
+	}
+}
@@ -10,11 +10,9 @@ import (
 	"fyne.io/fyne/v2/widget"
 	"github.com/hashicorp/go-multierror"
 
-	"github.com/kava-forge/eve-alts/lib/errors"
 	"github.com/kava-forge/eve-alts/lib/logging"
 	"github.com/kava-forge/eve-alts/pkg/app/apperrors"
 	"github.com/kava-forge/eve-alts/pkg/app/bindings"
-	"github.com/kava-forge/eve-alts/pkg/esi"
 	"github.com/kava-forge/eve-alts/pkg/keys"
 	"github.com/kava-forge/eve-alts/pkg/panics"
 	"github.com/kava-forge/eve-alts/pkg/repository"
@@ -56,7 +54,7 @@ func NewRefreshAllButton(deps dependencies, parent fyne.Window, chars *bindings.
 
 		wg := &sync.WaitGroup{}
 
-		for i, char := range charList {
+		for _, char := range charList {
 			if char == nil || char.Character.ID == 0 {
 				continue
 			}
@@ -67,22 +65,8 @@ func NewRefreshAllButton(deps dependencies, parent fyne.Window, chars *bindings.
 				defer panics.Handler(logger)
 				defer wg.Done()
 
-				dbTok, err := deps.AppRepo().GetTokenForCharacter(ctx, char.Character.ID, nil)
-				if err != nil {
-					errs <- errors.Wrap(err, "unable to find db token", keys.CharacterID, char.Character.ID)
-					return
-				}
-
-				tok := esi.TokenFromRepository(dbTok)
-
-				dbChar, err := RefreshCharacterData(ctx, deps, tok, char.Character.ID)
-				if err != nil {
-					errs <- errors.Wrap(err, "could not RefreshCharacterData", keys.CharacterID, char.Character.ID)
-					return
-				}
-
-				if err := chars.SetValue(i, &dbChar); err != nil {
-					errs <- errors.Wrap(err, "could not append new character", keys.CharacterID, char.Character.ID)
+				if _, err := RefreshStoredCharacter(ctx, deps, char.Character.ID); err != nil {
+					errs <- err
 				}
 			}()
 		}
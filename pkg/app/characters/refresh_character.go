@@ -3,11 +3,14 @@ package characters
 import (
 	"context"
 	"database/sql"
+	"strconv"
+	"time"
 
 	"golang.org/x/oauth2"
 
 	"github.com/kava-forge/eve-alts/lib/errors"
 	"github.com/kava-forge/eve-alts/lib/logging"
+	"github.com/kava-forge/eve-alts/lib/logging/level"
 
 	"github.com/kava-forge/eve-alts/pkg/database"
 	"github.com/kava-forge/eve-alts/pkg/esi"
@@ -15,15 +18,32 @@ import (
 	"github.com/kava-forge/eve-alts/pkg/repository"
 )
 
-func RefreshCharacterData(ctx context.Context, deps dependencies, tok *oauth2.Token, charID int64) (repository.CharacterDBData, error) {
+// RefreshStoredCharacter refreshes a character using the token saved for it
+func RefreshStoredCharacter(ctx context.Context, deps dependencies, charID int64) (repository.CharacterDBData, error) {
+	dbTok, err := deps.AppRepo().GetTokenForCharacter(ctx, charID, nil)
+	if err != nil {
+		return repository.CharacterDBData{}, errors.Wrap(err, "unable to find db token", keys.CharacterID, charID)
+	}
+
+	dbChar, err := RefreshCharacterData(ctx, deps, esi.TokenFromRepository(dbTok), charID)
+	if err != nil {
+		return dbChar, errors.Wrap(err, "could not RefreshCharacterData", keys.CharacterID, charID)
+	}
+	return dbChar, nil
+}
+
+func RefreshCharacterData(ctx context.Context, deps dependencies, tok *oauth2.Token, charID int64) (data repository.CharacterDBData, err error) {
 	logger := logging.With(deps.Logger(), keys.Component, "RefreshCharacterData")
 
-	var data repository.CharacterDBData
+	start := time.Now()
+	name := strconv.FormatInt(charID, 10)
+	defer func() { deps.Stats().CharacterRefresh(ctx, name, time.Since(start), err) }()
 
 	pubData, err := deps.ESIClient().GetCharacterPublicData(ctx, tok, charID)
 	if err != nil {
 		return data, errors.Wrap(err, "could not GetCharacterPublicData")
 	}
+	name = pubData.Name
 
 	portraitData, err := deps.ESIClient().GetCharacterPortrait(ctx, tok, charID)
 	if err != nil {
@@ -44,21 +64,23 @@ func RefreshCharacterData(ctx context.Context, deps dependencies, tok *oauth2.To
 	if err != nil {
 		return data, errors.Wrap(err, "could not GetSkills")
 	}
-	skillIDMap := make(map[int64]bool, len(skillList.Skills))
-	for _, skill := range skillList.Skills {
-		skillIDMap[skill.SkillID] = true
+	// an unreadable queue only loses the empty queue notification, so it
+	// does not stop the refresh
+	queueLength := int64(-1)
+	if queue, err := deps.ESIClient().GetSkillQueue(ctx, tok, charID); err != nil {
+		level.Error(logger).Err("could not GetSkillQueue", err, keys.CharacterID, charID)
+	} else {
+		queueLength = queue.Remaining(time.Now())
 	}
 
-	seenSkills, err := deps.AppRepo().GetAllCharacterSkills(ctx, charID, nil)
-	if err != nil && !errors.Is(err, database.ErrNoRows) {
-		return data, errors.Wrap(err, "could not GetAllCharacterSkills")
+	skillLevels := make(map[int64]int64, len(skillList.Skills))
+	for _, skill := range skillList.Skills {
+		skillLevels[skill.SkillID] = skill.TrainedLevel
 	}
 
-	toDelete := make([]int64, 0, 10)
-	for _, skill := range seenSkills {
-		if !skillIDMap[skill.SkillID] {
-			toDelete = append(toDelete, skill.SkillID)
-		}
+	before, err := deps.AppRepo().GetCharacter(ctx, charID, nil)
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return data, errors.Wrap(err, "could not GetCharacter")
 	}
 
 	var allianceData esi.AllianceData
@@ -83,12 +105,6 @@ func RefreshCharacterData(ctx context.Context, deps dependencies, tok *oauth2.To
 	if err := database.TransactWithRetries(ctx, deps.Telemetry(), logger, deps.DB(), &sql.TxOptions{}, func(ctx context.Context, tx database.Tx) error {
 		var err error
 
-		if dbSkills == nil {
-			dbSkills = make([]repository.CharacterSkill, 0, len(skillList.Skills))
-		} else {
-			dbSkills = dbSkills[:0]
-		}
-
 		if corpData.AllianceID != 0 {
 			if dbAlliance, err = deps.AppRepo().UpsertAlliance(ctx, corpData.AllianceID, allianceData.Name, allianceData.Ticker, allianceIcons.Small, tx); err != nil {
 				return errors.Wrap(err, "could not UpsertAlliance")
@@ -99,26 +115,23 @@ func RefreshCharacterData(ctx context.Context, deps dependencies, tok *oauth2.To
 			return errors.Wrap(err, "could not UpsertCorporation")
 		}
 
-		if dbChar, err = deps.AppRepo().UpsertCharacter(ctx, charID, pubData.Name, portraitData.Medium, pubData.CorporationID, tx); err != nil {
+		if dbChar, err = deps.AppRepo().UpsertCharacter(ctx, charID, pubData.Name, portraitData.Medium, pubData.CorporationID, skillList.TotalSP, skillList.Omega(), tx); err != nil {
 			return errors.Wrap(err, "could not UpsertCharacter")
 		}
 
-		if _, err = deps.AppRepo().UpsertToken(ctx, dbChar.ID, tok.AccessToken, tok.RefreshToken, tok.TokenType, tok.Expiry, tx); err != nil {
-			return errors.Wrap(err, "could not UpsertToken")
+		if queueLength >= 0 {
+			if err := deps.AppRepo().UpdateCharacterSkillQueue(ctx, dbChar.ID, queueLength, tx); err != nil {
+				return errors.Wrap(err, "could not UpdateCharacterSkillQueue")
+			}
+			dbChar.SkillQueueLength = sql.NullInt64{Int64: queueLength, Valid: true}
 		}
 
-		for _, skill := range skillList.Skills {
-			dbSkill, err := deps.AppRepo().UpsertCharacterSkill(ctx, dbChar.ID, skill.SkillID, skill.TrainedLevel, tx)
-			if err != nil {
-				return errors.Wrap(err, "could not UpsertCharacterSkill")
-			}
-			dbSkills = append(dbSkills, dbSkill)
+		if _, err = deps.AppRepo().UpsertToken(ctx, dbChar.ID, tok.AccessToken, tok.RefreshToken, tok.TokenType, tok.Expiry, tx); err != nil {
+			return errors.Wrap(err, "could not UpsertToken")
 		}
 
-		if len(toDelete) > 0 {
-			if err := deps.AppRepo().DeleteCharacterSkills(ctx, dbChar.ID, toDelete, tx); err != nil {
-				return errors.Wrap(err, "could not DeleteCharacterSkills")
-			}
+		if dbSkills, err = deps.AppRepo().ReplaceCharacterSkills(ctx, dbChar.ID, skillLevels, tx); err != nil {
+			return errors.Wrap(err, "could not ReplaceCharacterSkills")
 		}
 
 		return nil
@@ -131,5 +144,9 @@ func RefreshCharacterData(ctx context.Context, deps dependencies, tok *oauth2.To
 	data.Alliance = dbAlliance
 	data.Skills = dbSkills
 
+	if err := deps.Notifier().CharacterRefreshed(ctx, before, &data); err != nil {
+		level.Error(logger).Err("could not queue refresh notifications", err, keys.CharacterID, charID)
+	}
+
 	return data, nil
 }
@@ -2,11 +2,17 @@ package characters
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
 
+	"github.com/kava-forge/eve-alts/lib/errors"
 	"github.com/kava-forge/eve-alts/lib/logging"
 	"github.com/kava-forge/eve-alts/lib/logging/level"
 
@@ -25,14 +31,14 @@ type RoleMiniTag struct {
 	char      bindings.DataProxy[*repository.CharacterDBData]
 	role      bindings.DataProxy[*repository.RoleDBData]
 	tags      *bindings.DataList[*repository.TagDBData]
+	matcher   *matcher
 	knownTags map[int64]bool
 	isMatch   bool
-	missing   []string
 
 	update *sync.RWMutex
 }
 
-func NewRoleMiniTag(deps dependencies, parent fyne.Window, char bindings.DataProxy[*repository.CharacterDBData], role bindings.DataProxy[*repository.RoleDBData], tags *bindings.DataList[*repository.TagDBData]) *RoleMiniTag {
+func NewRoleMiniTag(deps dependencies, parent fyne.Window, m *matcher, char bindings.DataProxy[*repository.CharacterDBData], role bindings.DataProxy[*repository.RoleDBData], tags *bindings.DataList[*repository.TagDBData]) *RoleMiniTag {
 	logger := logging.With(deps.Logger(), keys.Component, "RoleMiniTag")
 
 	roleData, err := role.Get()
@@ -51,6 +57,7 @@ func NewRoleMiniTag(deps dependencies, parent fyne.Window, char bindings.DataPro
 		char:      char,
 		role:      role,
 		tags:      tags,
+		matcher:   m,
 		knownTags: map[int64]bool{},
 
 		update: &sync.RWMutex{},
@@ -125,28 +132,23 @@ func (c *RoleMiniTag) redraw() {
 		return
 	}
 
-	tags, err := c.tags.Get()
+	res, err := c.matcher.results()
 	if err != nil {
 		apperrors.Show(logger, c.parent, apperrors.Error(
-			"Could not find tag list data",
+			"Could not match roles",
 			apperrors.WithCause(err),
 		), nil)
 		return
 	}
 
-	isMatch, missing := CharacterMatchesRole(char, role, tags)
-	level.Debug(logger).Message("role match?", "match", isMatch, "missing", missing)
+	isMatch, _ := res.Role(char.Character.ID, role.Role.ID)
+	level.Debug(logger).Message("role match?", "match", isMatch)
 
 	c.SetText(role.Role.Label)
 	c.ColorSwatch.SetColor(role.Color())
 	c.isMatch = isMatch
 	c.MiniTag.Dimmed = !c.isMatch
 	c.MiniTag.RefreshStyle()
-
-	c.missing = c.missing[:0]
-	for _, m := range missing {
-		c.missing = append(c.missing, m.Name)
-	}
 }
 
 func (c *RoleMiniTag) ShouldShow() bool {
@@ -196,3 +198,55 @@ func (c *RoleMiniTag) SortKey() string {
 	}
 	return role.Role.Label
 }
+
+// explain evaluates the role in full for the explanation dialog, one line
+// per condition and expression node
+func (c *RoleMiniTag) explain() ([]string, error) {
+	char, err := c.char.Get()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find character data")
+	}
+	role, err := c.role.Get()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find role data")
+	}
+	tags, err := c.tags.Get()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find tag list data")
+	}
+
+	_, res := CharacterMatchesRole(char, role, tags)
+	return res.Lines(), nil
+}
+
+var _ fyne.Tappable = (*RoleMiniTag)(nil)
+
+func (c *RoleMiniTag) Tapped(_ *fyne.PointEvent) {
+	c.update.RLock()
+	defer c.update.RUnlock()
+
+	logger := logging.With(c.deps.Logger(), keys.Component, "RoleMiniTag.Tapped")
+	level.Debug(logger).Message("minitag tap")
+
+	explanation, err := c.explain()
+	if err != nil {
+		apperrors.Show(logger, c.parent, apperrors.Error(
+			"Could not explain role match",
+			apperrors.WithCause(err),
+		), nil)
+		return
+	}
+
+	list := widget.NewLabel(strings.Join(explanation, "\n"))
+	copyButton := widget.NewButtonWithIcon("Copy", theme.ContentCopyIcon(), func() {
+		c.parent.Clipboard().SetContent(list.Text)
+	})
+	data := container.New(layout.NewVBoxLayout(), list, layout.NewSpacer(), container.New(layout.NewHBoxLayout(), layout.NewSpacer(), copyButton))
+
+	title := "Why %s does not match"
+	if c.isMatch {
+		title = "Why %s matches"
+	}
+	d := dialog.NewCustom(fmt.Sprintf(title, c.NameLabel.String()), "Close", data, c.parent)
+	d.Show()
+}
@@ -3,6 +3,7 @@ package characters
 import (
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/widget"
 
 	"github.com/kava-forge/eve-alts/lib/logging"
 	"github.com/kava-forge/eve-alts/lib/logging/level"
@@ -21,8 +22,17 @@ type TagFilter struct {
 	TagSet             *minitag.MiniTagSet[string, *tags.TagMiniTag]
 	tagState           *bindings.DataMap[bool]
 	attachedCharacters []*CharacterCard
+
+	// Threshold picks whether selected tags need their required or recommended levels met
+	Threshold *widget.Select
+	threshold TagMatch
 }
 
+const (
+	thresholdRequired    = "Required"
+	thresholdRecommended = "Recommended"
+)
+
 func NewTagFilter(deps dependencies, parent fyne.Window, tagsData *bindings.DataList[*repository.TagDBData]) *TagFilter {
 	tf := &TagFilter{
 		deps:   deps,
@@ -31,8 +41,13 @@ func NewTagFilter(deps dependencies, parent fyne.Window, tagsData *bindings.Data
 
 		TagSet:   minitag.NewMiniTagSet[string, *tags.TagMiniTag](),
 		tagState: bindings.NewDataMap[bool](),
+
+		threshold: TagMatchRequired,
 	}
 
+	tf.Threshold = widget.NewSelect([]string{thresholdRequired, thresholdRecommended}, tf.setThreshold)
+	tf.Threshold.SetSelected(thresholdRequired)
+
 	tf.update()
 
 	tagsData.AddListener(binding.NewDataListener(tf.update))
@@ -69,6 +84,21 @@ func (tf *TagFilter) update() {
 	tf.TagSet.Refresh()
 }
 
+func (tf *TagFilter) setThreshold(sel string) {
+	threshold := TagMatchRequired
+	if sel == thresholdRecommended {
+		threshold = TagMatchRecommended
+	}
+	if threshold == tf.threshold {
+		return
+	}
+	tf.threshold = threshold
+
+	for _, cc := range tf.attachedCharacters {
+		cc.UpdateTagThreshold(threshold)
+	}
+}
+
 func (tf *TagFilter) add(tagData bindings.DataProxy[*repository.TagDBData]) {
 	logger := logging.With(tf.deps.Logger(), keys.Component, "TagFilter.Add")
 
@@ -110,6 +140,7 @@ func (tf *TagFilter) AttachAllToCharacter(cc *CharacterCard) {
 		tf.AttachToCharacter(cc, tagID, tagSelected)
 	}
 
+	cc.UpdateTagThreshold(tf.threshold)
 	tf.attachedCharacters = append(tf.attachedCharacters, cc)
 }
 
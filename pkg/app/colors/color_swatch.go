@@ -14,14 +14,17 @@ import (
 type ColorSwatch struct {
 	widget.BaseWidget
 
-	logger logging.Logger
-	rect   *canvas.Rectangle
+	logger   logging.Logger
+	rect     *canvas.Rectangle
+	color    color.Color
+	outlined bool
 }
 
 func NewColorSwatch(logger logging.Logger, c color.Color) *ColorSwatch {
 	s := &ColorSwatch{
 		logger: logger,
 		rect:   canvas.NewRectangle(c),
+		color:  c,
 	}
 	s.ExtendBaseWidget(s)
 
@@ -33,13 +36,37 @@ func (s *ColorSwatch) SetCornerRadius(cr float32) {
 }
 
 func (s *ColorSwatch) Color() color.Color {
-	return s.rect.FillColor
+	return s.color
 }
 
 func (s *ColorSwatch) SetColor(c color.Color) {
 	defer s.Refresh()
 
-	s.rect.FillColor = c
+	s.color = c
+	s.paint()
+}
+
+// SetOutlined draws just the border of the swatch instead of filling it
+func (s *ColorSwatch) SetOutlined(outlined bool) {
+	if s.outlined == outlined {
+		return
+	}
+	defer s.Refresh()
+
+	s.outlined = outlined
+	s.paint()
+}
+
+func (s *ColorSwatch) paint() {
+	if s.outlined {
+		s.rect.FillColor = color.Transparent
+		s.rect.StrokeColor = s.color
+		s.rect.StrokeWidth = 2
+	} else {
+		s.rect.FillColor = s.color
+		s.rect.StrokeColor = nil
+		s.rect.StrokeWidth = 0
+	}
 }
 
 func (s *ColorSwatch) CreateRenderer() fyne.WidgetRenderer {
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/kava-forge/eve-alts/lib/deferutil"
@@ -15,7 +16,12 @@ import (
 	"github.com/kirsle/configdir"
 	"github.com/spf13/viper"
 
+	"github.com/kava-forge/eve-alts/pkg/api"
+	"github.com/kava-forge/eve-alts/pkg/database"
 	"github.com/kava-forge/eve-alts/pkg/keys"
+	"github.com/kava-forge/eve-alts/pkg/notify"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/telemetry"
 )
 
 const (
@@ -26,6 +32,7 @@ const (
 	DefaultCallbackScheme   = "http"
 	DefaultPProfHostport    = "localhost:8089"
 	DefaultStatsHostport    = "localhost:8091"
+	DefaultAPIHostport      = "localhost:8093"
 )
 
 var ensureConfDir sync.Once
@@ -48,16 +55,34 @@ var defaultConfig []byte
 var ErrMissingSecret = errors.New("missing secret")
 
 type TelemeterConf struct {
-	JaegerHostPort      string  `mapstructure:"jaeger_hostport"`
 	PrometheusNamespace string  `mapstructure:"prometheus_namespace"`
+	TraceExporter       string  `mapstructure:"trace_exporter"`
+	OTLPEndpoint        string  `mapstructure:"otlp_endpoint"`
+	OTLPTLS             bool    `mapstructure:"otlp_tls"`
 	TraceProbability    float64 `mapstructure:"trace_probability"`
 }
 
+var ErrBadProbability = errors.New("trace probability must be between 0 and 1")
+
 func (c *TelemeterConf) FillDefaults() error {
 	if c.PrometheusNamespace == "" {
 		c.PrometheusNamespace = PromNamespace
 	}
 
+	exp, err := telemetry.ParseExporter(c.TraceExporter)
+	if err != nil {
+		return err
+	}
+	c.TraceExporter = string(exp)
+
+	if c.OTLPEndpoint == "" {
+		c.OTLPEndpoint = exp.DefaultEndpoint()
+	}
+
+	if c.TraceProbability < 0 || c.TraceProbability > 1 {
+		return errors.Wrap(ErrBadProbability, "invalid telemetry configuration", "trace_probability", c.TraceProbability)
+	}
+
 	return nil
 }
 
@@ -83,10 +108,40 @@ func (c *LoggingConf) FillDefaults() error {
 	return nil
 }
 
+var ErrBadWatchInterval = errors.New("watch interval cannot be negative")
+
 type DatabaseConf struct {
 	Location       string `mapstructure:"location"`
 	StaticLocation string `mapstructure:"static_location"`
 	Database       string `mapstructure:"database"`
+
+	// snapshots of the app database, taken before migrations and on request
+	BackupDirectory string `mapstructure:"backup_directory"`
+	BackupKeep      int    `mapstructure:"backup_keep"`
+
+	// connection settings for the app database
+	ForeignKeys    bool          `mapstructure:"foreign_keys"`
+	JournalMode    string        `mapstructure:"journal_mode"`
+	BusyTimeout    time.Duration `mapstructure:"busy_timeout"`
+	Synchronous    string        `mapstructure:"synchronous"`
+	TxLock         string        `mapstructure:"tx_lock"`
+	IntegrityCheck string        `mapstructure:"integrity_check"`
+
+	// how often the app looks for changes made by another process, such as
+	// the CLI; 0 does not look
+	WatchInterval time.Duration `mapstructure:"watch_interval"`
+}
+
+// Pragmas are the connection settings for the app database
+func (c DatabaseConf) Pragmas() database.Pragmas {
+	return database.Pragmas{
+		ForeignKeys: c.ForeignKeys,
+		JournalMode: c.JournalMode,
+		BusyTimeout: c.BusyTimeout,
+		Synchronous: c.Synchronous,
+		TxLock:      c.TxLock,
+		Integrity:   database.IntegrityCheck(c.IntegrityCheck),
+	}
 }
 
 func (c *DatabaseConf) FillDefaults() error {
@@ -98,6 +153,27 @@ func (c *DatabaseConf) FillDefaults() error {
 		c.StaticLocation = filepath.Join(GetConfigDir(), "staticdata.db")
 	}
 
+	if c.BackupDirectory == "" {
+		c.BackupDirectory = filepath.Join(GetConfigDir(), "backups")
+	}
+
+	if c.BackupKeep <= 0 {
+		c.BackupKeep = database.DefaultSnapshotKeep
+	}
+
+	pragmas := c.Pragmas()
+	if err := pragmas.FillDefaults(); err != nil {
+		return errors.Wrap(err, "invalid database configuration")
+	}
+	c.JournalMode = pragmas.JournalMode
+	c.Synchronous = pragmas.Synchronous
+	c.TxLock = pragmas.TxLock
+	c.IntegrityCheck = string(pragmas.Integrity)
+
+	if c.WatchInterval < 0 {
+		return errors.Wrap(ErrBadWatchInterval, "invalid database configuration", "watch_interval", c.WatchInterval)
+	}
+
 	return nil
 }
 
@@ -111,6 +187,11 @@ type ServingConf struct {
 	CallbackScheme     string `mapstructure:"callback_scheme"`
 	PProfHostPort      string `mapstructure:"pprof_hostport"`
 	PrometheusHostPort string `mapstructure:"prometheus_hostport"`
+
+	// the local JSON api is off unless enabled, and then needs a token
+	APIEnabled  bool   `mapstructure:"api_enabled"`
+	APIHostPort string `mapstructure:"api_hostport"`
+	APIToken    string `mapstructure:"api_token"`
 }
 
 func (c *ServingConf) FillDefaults() error {
@@ -134,15 +215,81 @@ func (c *ServingConf) FillDefaults() error {
 		c.PrometheusHostPort = DefaultStatsHostport
 	}
 
+	if c.APIHostPort == "" {
+		c.APIHostPort = DefaultAPIHostport
+	}
+
+	if c.APIEnabled {
+		if err := api.CheckAddr(c.APIHostPort); err != nil {
+			return err
+		}
+		if c.APIToken == "" {
+			return errors.Wrap(api.ErrMissingToken, "set serving.api_token to enable the api")
+		}
+	}
+
+	return nil
+}
+
+type NotificationsConf struct {
+	Webhooks    []string `mapstructure:"webhooks"`
+	Events      []string `mapstructure:"events"`
+	Username    string   `mapstructure:"username"`
+	MaxAttempts int64    `mapstructure:"max_attempts"`
+}
+
+func (c *NotificationsConf) FillDefaults() error {
+	if c.Username == "" {
+		c.Username = notify.DefaultUsername
+	}
+
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = notify.DefaultMaxAttempts
+	}
+
+	_, err := c.EventKinds()
+	return err
+}
+
+func (c NotificationsConf) EventKinds() ([]notify.EventKind, error) {
+	kinds := make([]notify.EventKind, 0, len(c.Events))
+	for _, e := range c.Events {
+		k, err := notify.ParseEventKind(e)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid notification event", "event", e)
+		}
+		kinds = append(kinds, k)
+	}
+	return kinds, nil
+}
+
+type VaultConf struct {
+	Mode    string `mapstructure:"mode"`
+	KeyFile string `mapstructure:"keyfile"`
+}
+
+func (c *VaultConf) FillDefaults() error {
+	mode, err := repository.ParseVaultMode(c.Mode)
+	if err != nil {
+		return err
+	}
+	c.Mode = string(mode)
+
+	if c.KeyFile == "" {
+		c.KeyFile = filepath.Join(GetConfigDir(), "token.key")
+	}
+
 	return nil
 }
 
 type Config struct {
-	Database  DatabaseConf  `mapstructure:"database"`
-	Logging   LoggingConf   `mapstructure:"logging"`
-	PProf     PProfConf     `mapstructure:"pprof"`
-	Serving   ServingConf   `mapstructure:"serving"`
-	Telemetry TelemeterConf `mapstructure:"telemetry"`
+	Database      DatabaseConf      `mapstructure:"database"`
+	Logging       LoggingConf       `mapstructure:"logging"`
+	Notifications NotificationsConf `mapstructure:"notifications"`
+	PProf         PProfConf         `mapstructure:"pprof"`
+	Serving       ServingConf       `mapstructure:"serving"`
+	Telemetry     TelemeterConf     `mapstructure:"telemetry"`
+	Vault         VaultConf         `mapstructure:"vault"`
 
 	ConfigFile string `mapstructure:"-"`
 }
@@ -158,6 +305,10 @@ func (c *Config) FillDefaults() error {
 		errs = multierror.Append(errs, err)
 	}
 
+	if err := c.Notifications.FillDefaults(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+
 	if err := c.Serving.FillDefaults(); err != nil {
 		errs = multierror.Append(errs, err)
 	}
@@ -166,6 +317,10 @@ func (c *Config) FillDefaults() error {
 		errs = multierror.Append(errs, err)
 	}
 
+	if err := c.Vault.FillDefaults(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+
 	return errs
 }
 
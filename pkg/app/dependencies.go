@@ -22,6 +22,7 @@ import (
 	"github.com/kava-forge/eve-alts/pkg/database"
 	"github.com/kava-forge/eve-alts/pkg/esi"
 	"github.com/kava-forge/eve-alts/pkg/keys"
+	"github.com/kava-forge/eve-alts/pkg/notify"
 	"github.com/kava-forge/eve-alts/pkg/panics"
 	"github.com/kava-forge/eve-alts/pkg/repository"
 	"github.com/kava-forge/eve-alts/pkg/telemetry"
@@ -43,6 +44,11 @@ type Dependencies struct {
 
 	appRepo    *repository.AppSqliteRepository
 	staticRepo *repository.StaticSqliteRepository
+	vault      *repository.TokenVault
+	vaultConf  VaultConf
+	snapshots  database.Snapshots
+
+	notifier *notify.Notifier
 }
 
 var _ dependencies = (*Dependencies)(nil)
@@ -90,7 +96,10 @@ func CreateDependencies(ctx context.Context, appName, buildVersion string, conf
 
 	if deps.telemetry, deps.statsHandler, err = telemetry.NewTelemeter(ctx, deps, appName, buildVersion, host, telemetry.Options{
 		PrometheusNamespace: conf.Telemetry.PrometheusNamespace,
-		JaegerURL:           conf.Telemetry.JaegerHostPort,
+		TraceExporter:       telemetry.Exporter(conf.Telemetry.TraceExporter),
+		OTLPEndpoint:        conf.Telemetry.OTLPEndpoint,
+		OTLPInsecure:        !conf.Telemetry.OTLPTLS,
+		TraceFile:           path.Join(conf.Logging.Directory, "traces.json"),
 		TraceProbability:    conf.Telemetry.TraceProbability,
 	}); err != nil {
 		return nil, errors.Wrap(err, "could not create Telemeter")
@@ -100,6 +109,7 @@ func CreateDependencies(ctx context.Context, appName, buildVersion string, conf
 	if deps.stats, err = telemetry.NewStats(appName, deps.telemetry); err != nil {
 		return nil, errors.Wrap(err, "could not create Stats")
 	}
+	deps.stats.MakeDefault()
 
 	deps.httpClient = http.NewTelemeterClient(deps.Logger(), deps.Telemetry())
 
@@ -121,14 +131,19 @@ func CreateDependencies(ctx context.Context, appName, buildVersion string, conf
 	deps.staticDB = &database.WrappedConnection{DB: staticdb}
 	deps.staticRepo = repository.NewStaticData(deps)
 
-	appdb, err := sql.Open("sqlite3", conf.Database.Location)
-	if err != nil {
+	if deps.db, err = database.Open(ctx, conf.Database.Location, conf.Database.Pragmas()); err != nil {
 		return nil, errors.Wrap(err, "could not open app database", keys.Path, conf.Database.Location)
 	}
 
-	deps.db = &database.WrappedConnection{DB: appdb}
+	deps.snapshots = database.Snapshots{Dir: conf.Database.BackupDirectory, Keep: conf.Database.BackupKeep}
+	deps.vault = repository.NewTokenVault()
+	deps.vaultConf = conf.Vault
 	deps.appRepo = repository.NewAppData(deps)
 
+	if err := deps.stats.ObserveRoster(loadRoster(deps.appRepo)); err != nil {
+		return nil, errors.Wrap(err, "could not observe roster metrics")
+	}
+
 	callbackURL := &url.URL{
 		Scheme: conf.Serving.CallbackScheme,
 		Host:   conf.Serving.HostPort,
@@ -141,10 +156,22 @@ func CreateDependencies(ctx context.Context, appName, buildVersion string, conf
 
 	deps.callbackServer = esi.NewCallbackServer(deps.Logger(), conf.Serving.HostPort, conf.Serving.CallbackPath)
 
+	events, err := conf.Notifications.EventKinds()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not configure notifications")
+	}
+	deps.notifier = notify.New(deps, notify.Options{
+		Webhooks:    conf.Notifications.Webhooks,
+		Events:      events,
+		Username:    conf.Notifications.Username,
+		MaxAttempts: conf.Notifications.MaxAttempts,
+	})
+
 	return deps, nil
 }
 
 func (d *Dependencies) DB() database.Connection                { return d.db }
+func (d *Dependencies) Snapshots() database.Snapshots          { return d.snapshots }
 func (d *Dependencies) StaticDB() database.Connection          { return d.staticDB }
 func (d *Dependencies) Logger() logging.Logger                 { return d.logger }
 func (d *Dependencies) HTTPClient() http.Client                { return d.httpClient }
@@ -157,6 +184,17 @@ func (d *Dependencies) StatsHandler() stdhttp.Handler   { return d.statsHandler
 
 func (d *Dependencies) AppRepo() repository.AppData       { return d.appRepo }
 func (d *Dependencies) StaticRepo() repository.StaticData { return d.staticRepo }
+func (d *Dependencies) Vault() *repository.TokenVault     { return d.vault }
+
+// VaultSource is the configured key source, without a passphrase
+func (d *Dependencies) VaultSource() repository.KeySource {
+	return repository.KeySource{
+		Mode:    repository.VaultMode(d.vaultConf.Mode),
+		KeyFile: d.vaultConf.KeyFile,
+	}
+}
+
+func (d *Dependencies) Notifier() *notify.Notifier { return d.notifier }
 
 func (d *Dependencies) Close(ctx context.Context, timeout time.Duration) error {
 	defer level.Debug(d.Logger()).Message("done deps close")
@@ -0,0 +1,258 @@
+package library
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/kava-forge/eve-alts/lib/deferutil"
+	"github.com/kava-forge/eve-alts/lib/logging"
+	"github.com/kava-forge/eve-alts/lib/logging/level"
+
+	"github.com/kava-forge/eve-alts/pkg/app/apperrors"
+	"github.com/kava-forge/eve-alts/pkg/app/bindings"
+	"github.com/kava-forge/eve-alts/pkg/bundles"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/keys"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/telemetry"
+)
+
+type dependencies interface {
+	DB() database.Connection
+	Snapshots() database.Snapshots
+	Logger() logging.Logger
+	Telemetry() *telemetry.Telemeter
+
+	AppRepo() repository.AppData
+	StaticRepo() repository.StaticData
+}
+
+var bundleFilter = storage.NewExtensionFileFilter([]string{".json", ".toml"})
+
+// NewMenu builds the "Library" menu, for sharing tag and role definitions as
+// bundle files, exporting the character matrix and managing database snapshots
+func NewMenu(deps dependencies, parent fyne.Window, chars *bindings.DataList[*repository.CharacterDBData], tags *bindings.DataList[*repository.TagDBData], roles *bindings.DataList[*repository.RoleDBData]) *fyne.Menu {
+	return fyne.NewMenu("Library",
+		fyne.NewMenuItem("Export Tags & Roles...", func() { showExport(deps, parent, tags, roles) }),
+		fyne.NewMenuItem("Import Tags & Roles...", func() { showImport(deps, parent) }),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Export Character Matrix...", func() { showMatrixExport(deps, parent, chars, tags, roles) }),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Database Snapshots...", func() { showSnapshots(deps, parent) }),
+	)
+}
+
+func liveTags(tags *bindings.DataList[*repository.TagDBData]) ([]*repository.TagDBData, error) {
+	list, err := tags.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	live := make([]*repository.TagDBData, 0, len(list))
+	for _, t := range list {
+		if t != nil && t.Tag.ID != 0 {
+			live = append(live, t)
+		}
+	}
+	sort.Slice(live, func(i, j int) bool { return live[i].Tag.Name < live[j].Tag.Name })
+	return live, nil
+}
+
+func liveRoles(roles *bindings.DataList[*repository.RoleDBData]) ([]*repository.RoleDBData, error) {
+	list, err := roles.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	live := make([]*repository.RoleDBData, 0, len(list))
+	for _, r := range list {
+		if r != nil && r.Role.ID != 0 {
+			live = append(live, r)
+		}
+	}
+	sort.Slice(live, func(i, j int) bool { return live[i].Role.Name < live[j].Role.Name })
+	return live, nil
+}
+
+func showExport(deps dependencies, parent fyne.Window, tagsData *bindings.DataList[*repository.TagDBData], rolesData *bindings.DataList[*repository.RoleDBData]) {
+	logger := logging.With(deps.Logger(), keys.Component, "Library.Export")
+
+	allTags, err := liveTags(tagsData)
+	if err != nil {
+		apperrors.Show(logger, parent, apperrors.Error(
+			"Could not load tag list data",
+			apperrors.WithCause(err),
+		), nil)
+		return
+	}
+	allRoles, err := liveRoles(rolesData)
+	if err != nil {
+		apperrors.Show(logger, parent, apperrors.Error(
+			"Could not load role list data",
+			apperrors.WithCause(err),
+		), nil)
+		return
+	}
+
+	tagNames := make([]string, 0, len(allTags))
+	tagsByName := make(map[string]*repository.TagDBData, len(allTags))
+	for _, t := range allTags {
+		tagNames = append(tagNames, t.Tag.Name)
+		tagsByName[t.Tag.Name] = t
+	}
+	roleNames := make([]string, 0, len(allRoles))
+	rolesByName := make(map[string]*repository.RoleDBData, len(allRoles))
+	for _, r := range allRoles {
+		roleNames = append(roleNames, r.Role.Name)
+		rolesByName[r.Role.Name] = r
+	}
+
+	tagChecks := widget.NewCheckGroup(tagNames, nil)
+	tagChecks.SetSelected(tagNames)
+	roleChecks := widget.NewCheckGroup(roleNames, nil)
+	roleChecks.SetSelected(roleNames)
+
+	content := container.NewGridWithColumns(2,
+		container.NewBorder(widget.NewLabel("Tags"), nil, nil, nil, container.NewVScroll(tagChecks)),
+		container.NewBorder(widget.NewLabel("Roles (their tags are always included)"), nil, nil, nil, container.NewVScroll(roleChecks)),
+	)
+
+	d := dialog.NewCustomConfirm("Export Tags & Roles", "Export", "Cancel", content, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		selTags := make([]*repository.TagDBData, 0, len(tagChecks.Selected))
+		for _, n := range tagChecks.Selected {
+			selTags = append(selTags, tagsByName[n])
+		}
+		selRoles := make([]*repository.RoleDBData, 0, len(roleChecks.Selected))
+		for _, n := range roleChecks.Selected {
+			selRoles = append(selRoles, rolesByName[n])
+		}
+
+		b, err := bundles.Export(context.Background(), deps.StaticRepo(), allTags, selTags, selRoles)
+		if err != nil {
+			apperrors.Show(logger, parent, apperrors.Error(
+				"Could not build bundle",
+				apperrors.WithCause(err),
+			), nil)
+			return
+		}
+
+		save := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+			if err != nil {
+				apperrors.Show(logger, parent, apperrors.Error(
+					"Could not open file",
+					apperrors.WithCause(err),
+				), nil)
+				return
+			}
+			if w == nil {
+				return
+			}
+			defer deferutil.CheckDefer(w.Close)
+
+			f, err := bundles.FormatFromPath(w.URI().Path())
+			if err != nil {
+				apperrors.Show(logger, parent, apperrors.Error(
+					"Bundle files must end in .json or .toml",
+					apperrors.WithCause(err),
+				), nil)
+				return
+			}
+
+			if err := bundles.Encode(w, b, f); err != nil {
+				apperrors.Show(logger, parent, apperrors.Error(
+					"Could not write bundle",
+					apperrors.WithCause(err),
+				), nil)
+				return
+			}
+
+			level.Info(logger).Message("exported bundle", "path", w.URI().Path(), "tags", len(b.Tags), "roles", len(b.Roles))
+		}, parent)
+		save.SetFileName("eve-alts-library.json")
+		save.SetFilter(bundleFilter)
+		save.Show()
+	}, parent)
+	d.Resize(fyne.Size{Width: 600, Height: 500})
+	d.Show()
+}
+
+func showImport(deps dependencies, parent fyne.Window) {
+	logger := logging.With(deps.Logger(), keys.Component, "Library.Import")
+
+	open := dialog.NewFileOpen(func(r fyne.URIReadCloser, err error) {
+		if err != nil {
+			apperrors.Show(logger, parent, apperrors.Error(
+				"Could not open file",
+				apperrors.WithCause(err),
+			), nil)
+			return
+		}
+		if r == nil {
+			return
+		}
+		defer deferutil.CheckDefer(r.Close)
+
+		f, err := bundles.FormatFromPath(r.URI().Path())
+		if err != nil {
+			apperrors.Show(logger, parent, apperrors.Error(
+				"Bundle files must end in .json or .toml",
+				apperrors.WithCause(err),
+			), nil)
+			return
+		}
+
+		b, err := bundles.Decode(r, f)
+		if err != nil {
+			apperrors.Show(logger, parent, apperrors.Error(
+				"Could not read bundle",
+				apperrors.WithCause(err),
+			), nil)
+			return
+		}
+
+		policies := bundles.ConflictPolicies()
+		policyNames := make([]string, 0, len(policies))
+		for _, p := range policies {
+			policyNames = append(policyNames, string(p))
+		}
+		policySel := widget.NewSelect(policyNames, nil)
+		policySel.SetSelected(string(bundles.ConflictSkip))
+
+		dialog.ShowForm("Import Tags & Roles", "Import", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("Contents", widget.NewLabel(fmt.Sprintf("%d tags, %d roles", len(b.Tags), len(b.Roles)))),
+			widget.NewFormItem("When a name exists", policySel),
+		}, func(ok bool) {
+			if !ok {
+				return
+			}
+
+			ctx := context.Background()
+
+			res, err := bundles.Import(ctx, deps, b, bundles.ConflictPolicy(policySel.Selected))
+			if err != nil {
+				apperrors.Show(logger, parent, apperrors.Error(
+					"Could not import bundle",
+					apperrors.WithCause(err),
+				), nil)
+				return
+			}
+
+			// the tags and roles tabs pick up what was imported from the
+			// repository's change events
+			dialog.ShowInformation("Import Complete", res.String(), parent)
+		}, parent)
+	}, parent)
+	open.SetFilter(bundleFilter)
+	open.Show()
+}
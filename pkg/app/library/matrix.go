@@ -0,0 +1,85 @@
+package library
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+
+	"github.com/kava-forge/eve-alts/lib/deferutil"
+	"github.com/kava-forge/eve-alts/lib/logging"
+	"github.com/kava-forge/eve-alts/lib/logging/level"
+
+	"github.com/kava-forge/eve-alts/pkg/app/apperrors"
+	"github.com/kava-forge/eve-alts/pkg/app/bindings"
+	"github.com/kava-forge/eve-alts/pkg/keys"
+	"github.com/kava-forge/eve-alts/pkg/matrix"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+var matrixFilter = storage.NewExtensionFileFilter([]string{".csv", ".html", ".xlsx"})
+
+func showMatrixExport(deps dependencies, parent fyne.Window, charsData *bindings.DataList[*repository.CharacterDBData], tagsData *bindings.DataList[*repository.TagDBData], rolesData *bindings.DataList[*repository.RoleDBData]) {
+	logger := logging.With(deps.Logger(), keys.Component, "Library.MatrixExport")
+
+	chars, err := charsData.Get()
+	if err != nil {
+		apperrors.Show(logger, parent, apperrors.Error(
+			"Could not load character list data",
+			apperrors.WithCause(err),
+		), nil)
+		return
+	}
+	tags, err := liveTags(tagsData)
+	if err != nil {
+		apperrors.Show(logger, parent, apperrors.Error(
+			"Could not load tag list data",
+			apperrors.WithCause(err),
+		), nil)
+		return
+	}
+	roles, err := liveRoles(rolesData)
+	if err != nil {
+		apperrors.Show(logger, parent, apperrors.Error(
+			"Could not load role list data",
+			apperrors.WithCause(err),
+		), nil)
+		return
+	}
+
+	save := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+		if err != nil {
+			apperrors.Show(logger, parent, apperrors.Error(
+				"Could not open file",
+				apperrors.WithCause(err),
+			), nil)
+			return
+		}
+		if w == nil {
+			return
+		}
+		defer deferutil.CheckDefer(w.Close)
+
+		f, err := matrix.FormatFromPath(w.URI().Path())
+		if err != nil {
+			apperrors.Show(logger, parent, apperrors.Error(
+				"Matrix files must end in .csv, .html or .xlsx",
+				apperrors.WithCause(err),
+			), nil)
+			return
+		}
+
+		m := matrix.Build(chars, tags, roles)
+		if err := matrix.Write(w, m, f); err != nil {
+			apperrors.Show(logger, parent, apperrors.Error(
+				"Could not write matrix",
+				apperrors.WithCause(err),
+			), nil)
+			return
+		}
+
+		level.Info(logger).Message("exported matrix", "path", w.URI().Path(), "characters", len(m.Rows), "columns", len(m.Columns))
+	}, parent)
+	save.SetFileName("eve-alts-matrix.html")
+	save.SetFilter(matrixFilter)
+	save.Show()
+}
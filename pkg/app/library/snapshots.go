@@ -0,0 +1,117 @@
+package library
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/kava-forge/eve-alts/lib/logging"
+	"github.com/kava-forge/eve-alts/lib/logging/level"
+
+	"github.com/kava-forge/eve-alts/pkg/app/apperrors"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/keys"
+)
+
+func snapshotLabel(s database.Snapshot) string {
+	return fmt.Sprintf("%s  —  schema v%d, %s, %.1f MB", s.Taken.Local().Format(time.DateTime), s.Version, s.Reason, float64(s.Size)/(1<<20))
+}
+
+// showSnapshots lists the database snapshots, newest first, and can take a
+// new one or restore one. The app closes after a restore, as everything it
+// has loaded is out of date.
+func showSnapshots(deps dependencies, parent fyne.Window) {
+	logger := logging.With(deps.Logger(), keys.Component, "Library.Snapshots")
+
+	snaps, err := deps.Snapshots().List()
+	if err != nil {
+		apperrors.Show(logger, parent, apperrors.Error(
+			"Could not list snapshots",
+			apperrors.WithCause(err),
+		), nil)
+		return
+	}
+
+	selected := -1
+	list := widget.NewList(
+		func() int { return len(snaps) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(snapshotLabel(snaps[id]))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) { selected = id }
+	list.OnUnselected = func(widget.ListItemID) { selected = -1 }
+
+	var d dialog.Dialog
+
+	takeBtn := widget.NewButton("Take Snapshot", func() {
+		snap, err := deps.Snapshots().Take(context.Background(), deps.DB(), database.ReasonManual)
+		if err != nil {
+			apperrors.Show(logger, parent, apperrors.Error(
+				"Could not take snapshot",
+				apperrors.WithCause(err),
+			), nil)
+			return
+		}
+		level.Info(logger).Message("took snapshot", "name", snap.Name)
+
+		if snaps, err = deps.Snapshots().List(); err != nil {
+			apperrors.Show(logger, parent, apperrors.Error(
+				"Could not list snapshots",
+				apperrors.WithCause(err),
+			), nil)
+			return
+		}
+		list.UnselectAll()
+		list.Refresh()
+	})
+
+	restoreBtn := widget.NewButton("Restore Selected...", func() {
+		if selected < 0 || selected >= len(snaps) {
+			return
+		}
+		snap := snaps[selected]
+
+		conf := dialog.NewConfirm("Restore Snapshot?", fmt.Sprintf(
+			"Replace the database with the snapshot from %s?\nThe database as it is now is kept as a snapshot first, and the app will close once the restore is done.",
+			snap.Taken.Local().Format(time.DateTime),
+		), func(ok bool) {
+			if !ok {
+				return
+			}
+
+			undo, err := deps.Snapshots().Restore(context.Background(), deps.DB(), snap.Name)
+			if err != nil {
+				apperrors.Show(logger, parent, apperrors.Error(
+					"Could not restore snapshot",
+					apperrors.WithCause(err),
+				), nil)
+				return
+			}
+			level.Info(logger).Message("restored snapshot", "name", snap.Name, "undo", undo.Name)
+
+			d.Hide()
+			info := dialog.NewInformation("Snapshot Restored", "Start the app again to load the restored data.", parent)
+			info.SetOnClosed(func() { fyne.CurrentApp().Quit() })
+			info.Show()
+		}, parent)
+		conf.Show()
+	})
+
+	var top fyne.CanvasObject = widget.NewLabel(fmt.Sprintf("Snapshots are kept in %s", deps.Snapshots().Dir))
+	if len(snaps) == 0 {
+		top = container.NewVBox(top, widget.NewLabel("There are no snapshots yet."))
+	}
+
+	content := container.NewBorder(top, container.NewHBox(takeBtn, restoreBtn), nil, nil, list)
+
+	d = dialog.NewCustom("Database Snapshots", "Close", content, parent)
+	d.Resize(fyne.Size{Width: 640, Height: 420})
+	d.Show()
+}
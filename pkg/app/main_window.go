@@ -13,6 +13,7 @@ import (
 	"github.com/kava-forge/eve-alts/lib/logging/level"
 	"github.com/kava-forge/eve-alts/pkg/app/apperrors"
 	"github.com/kava-forge/eve-alts/pkg/app/characters"
+	"github.com/kava-forge/eve-alts/pkg/app/library"
 	"github.com/kava-forge/eve-alts/pkg/app/roles"
 	"github.com/kava-forge/eve-alts/pkg/app/tags"
 	"github.com/kava-forge/eve-alts/pkg/database"
@@ -38,6 +39,9 @@ func NewMainWindow(deps dependencies, a fyne.App) fyne.Window {
 	)
 
 	w.SetContent(tabs)
+	w.SetMainMenu(fyne.NewMainMenu(
+		library.NewMenu(deps, w, chars, tags, roles),
+	))
 
 	a.Lifecycle().SetOnStarted(func() {
 		ctx := context.Background()
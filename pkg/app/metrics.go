@@ -0,0 +1,44 @@
+package app
+
+import (
+	"context"
+
+	"github.com/kava-forge/eve-alts/pkg/matching"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/telemetry"
+	"github.com/kava-forge/eve-alts/pkg/views"
+)
+
+// loadRoster reads the stored characters, tags and roles for the roster
+// gauges on each metrics scrape
+func loadRoster(repo repository.AppData) func(ctx context.Context) (telemetry.Roster, error) {
+	return func(ctx context.Context) (telemetry.Roster, error) {
+		chars, err := views.LoadCharacters(ctx, repo)
+		if err != nil {
+			return telemetry.Roster{}, err
+		}
+		tags, err := views.LoadTags(ctx, repo)
+		if err != nil {
+			return telemetry.Roster{}, err
+		}
+		roles, err := views.LoadRoles(ctx, repo)
+		if err != nil {
+			return telemetry.Roster{}, err
+		}
+
+		r := telemetry.Roster{
+			TotalSP:   make(map[string]int64, len(chars)),
+			Qualified: make(map[string]int64, len(roles)),
+		}
+		for _, c := range chars {
+			r.TotalSP[c.Character.Name] = c.Character.TotalSp
+		}
+
+		res := matching.Compile(chars, tags, roles)
+		for _, role := range roles {
+			r.Qualified[role.Role.Name] = int64(len(res.Filter(nil, matching.TagMissing, []int64{role.Role.ID})))
+		}
+
+		return r, nil
+	}
+}
@@ -20,6 +20,9 @@ type MiniTag struct {
 	ColorSwatch  *colors.ColorSwatch
 	Dimmed       bool
 	UnDimmedBold bool
+	// Partial outlines the swatch instead of filling it, for a tag that is only
+	// partly met. Dimmed takes precedence.
+	Partial bool
 
 	size fyne.ThemeSizeName
 }
@@ -58,7 +61,7 @@ func (c *MiniTag) RefreshStyle() {
 
 	textColorName := theme.ColorNameForeground
 
-	if !c.Dimmed {
+	if !c.Dimmed && !c.Partial {
 		var darkText, lightText fyne.ThemeColorName
 		if fyne.CurrentApp().Settings().ThemeVariant() == theme.VariantLight {
 			darkText = theme.ColorNameForeground
@@ -86,6 +89,8 @@ func (c *MiniTag) RefreshStyle() {
 			}
 			if c.Dimmed {
 				seg.Style.TextStyle.Bold = false
+			} else if c.Partial {
+				seg.Style.TextStyle.Italic = true
 			} else {
 				seg.Style.TextStyle.Bold = c.UnDimmedBold
 			}
@@ -112,6 +117,7 @@ func (c *MiniTag) Layout(sz fyne.Size) {
 	if c.Dimmed {
 		c.ColorSwatch.Hide()
 	} else {
+		c.ColorSwatch.SetOutlined(c.Partial)
 		c.ColorSwatch.Show()
 	}
 }
@@ -9,11 +9,11 @@ import (
 	"github.com/kava-forge/eve-alts/pkg/repository"
 )
 
-func NewAddRoleButton(deps dependencies, roles *bindings.DataList[*repository.RoleDBData], tags *bindings.DataList[*repository.TagDBData]) *widget.Button {
+func NewAddRoleButton(deps dependencies, tags *bindings.DataList[*repository.TagDBData]) *widget.Button {
 	button := widget.NewButtonWithIcon("Add Role", theme.ContentAddIcon(), func() {
 		a := fyne.CurrentApp()
 
-		w := NewRoleEditor(deps, a, "Add Role", roles, tags, nil, nil)
+		w := NewRoleEditor(deps, a, "Add Role", tags, nil, nil)
 		w.Show()
 	})
 
@@ -0,0 +1,206 @@
+package roles
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+	"github.com/kava-forge/eve-alts/lib/logging"
+
+	"github.com/kava-forge/eve-alts/pkg/app/apperrors"
+	"github.com/kava-forge/eve-alts/pkg/conditions"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/keys"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+// ConditionsEditor edits the non-skill conditions of a role. Corporations and
+// alliances are offered from the ones the known characters belong to.
+type ConditionsEditor struct {
+	deps   dependencies
+	parent fyne.Window
+	conds  []repository.RoleCondition
+
+	corps     map[string]int64
+	alliances map[string]int64
+
+	Content *fyne.Container
+}
+
+func NewConditionsEditor(deps dependencies, parent fyne.Window) *ConditionsEditor {
+	logger := logging.With(deps.Logger(), keys.Component, "ConditionsEditor")
+
+	ce := &ConditionsEditor{
+		deps:      deps,
+		parent:    parent,
+		conds:     make([]repository.RoleCondition, 0),
+		corps:     map[string]int64{},
+		alliances: map[string]int64{},
+
+		Content: container.NewVBox(),
+	}
+
+	chars, err := deps.AppRepo().GetAllCharacters(context.Background(), nil)
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		apperrors.Show(logger, parent, apperrors.Error(
+			"Could not load characters",
+			apperrors.WithCause(err),
+		), nil)
+	}
+	for _, c := range chars {
+		if c.Corporation.ID != 0 {
+			ce.corps[fmt.Sprintf("%s [%s]", c.Corporation.Name, c.Corporation.Ticker)] = c.Corporation.ID
+		}
+		if c.Alliance.ID.Valid {
+			ce.alliances[fmt.Sprintf("%s [%s]", c.Alliance.Name.String, c.Alliance.Ticker.String)] = c.Alliance.ID.Int64
+		}
+	}
+
+	ce.rebuild()
+
+	return ce
+}
+
+// SetConditions loads a copy of conds into the editor
+func (ce *ConditionsEditor) SetConditions(conds []repository.RoleCondition) {
+	ce.conds = append(make([]repository.RoleCondition, 0, len(conds)), conds...)
+	ce.rebuild()
+}
+
+// Conditions is a copy of the edited conditions
+func (ce *ConditionsEditor) Conditions() []repository.RoleCondition {
+	return append(make([]repository.RoleCondition, 0, len(ce.conds)), ce.conds...)
+}
+
+// Validate checks that every condition has a value picked
+func (ce *ConditionsEditor) Validate() error {
+	for _, c := range ce.conds {
+		switch conditions.Kind(c.Kind) {
+		case conditions.KindCorporation, conditions.KindAlliance:
+			if c.Value == 0 {
+				return errors.New("pick a corporation or alliance for every condition")
+			}
+		case conditions.KindMinSp:
+			if c.Value <= 0 {
+				return errors.New("minimum SP must be a positive number")
+			}
+		}
+	}
+	return nil
+}
+
+func (ce *ConditionsEditor) rebuild() {
+	logger := logging.With(ce.deps.Logger(), keys.Component, "ConditionsEditor.rebuild")
+
+	objs := make([]fyne.CanvasObject, 0, len(ce.conds)+1)
+	for i := range ce.conds {
+		objs = append(objs, ce.conditionRow(logger, i))
+	}
+	objs = append(objs, container.NewHBox(widget.NewButton("add condition", func() {
+		ce.conds = append(ce.conds, repository.RoleCondition{Kind: conditions.KindOmega.String()})
+		ce.rebuild()
+	})))
+
+	ce.Content.Objects = objs
+	ce.Content.Refresh()
+}
+
+func (ce *ConditionsEditor) conditionRow(logger logging.Logger, i int) fyne.CanvasObject {
+	cond := &ce.conds[i]
+
+	kindInp := widget.NewSelect(conditions.KindNames(), nil)
+	kindInp.Selected = cond.Kind
+	kindInp.OnChanged = func(s string) {
+		kind, err := conditions.ParseKind(s)
+		if err != nil {
+			apperrors.Show(logger, ce.parent, apperrors.Error(
+				"Unrecognized condition",
+				apperrors.WithCause(err),
+			), nil)
+			return
+		}
+		ce.conds[i] = repository.RoleCondition{Kind: kind.String()}
+		ce.rebuild() // the value widget depends on the kind
+	}
+
+	row := container.NewHBox(kindInp)
+
+	switch conditions.Kind(cond.Kind) {
+	case conditions.KindCorporation:
+		row.Add(ce.orgSelect(cond, ce.corps))
+	case conditions.KindAlliance:
+		row.Add(ce.orgSelect(cond, ce.alliances))
+	case conditions.KindMinSp:
+		row.Add(minSPEntry(cond))
+	}
+
+	remove := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		ce.conds = append(ce.conds[:i], ce.conds[i+1:]...)
+		ce.rebuild()
+	})
+	remove.Importance = widget.DangerImportance
+
+	row.Add(layout.NewSpacer())
+	row.Add(remove)
+
+	return row
+}
+
+// orgSelect picks a corporation or alliance, keeping the stored one as an
+// option even when no character belongs to it any more
+func (ce *ConditionsEditor) orgSelect(cond *repository.RoleCondition, options map[string]int64) *widget.Select {
+	ids := make(map[string]int64, len(options)+1)
+	for label, id := range options {
+		ids[label] = id
+	}
+	if cond.Value != 0 && cond.Label != "" {
+		ids[cond.Label] = cond.Value
+	}
+
+	labels := make([]string, 0, len(ids))
+	for label := range ids {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	inp := widget.NewSelect(labels, nil)
+	inp.Selected = cond.Label
+	inp.OnChanged = func(s string) {
+		cond.Value = ids[s]
+		cond.Label = s
+	}
+	return inp
+}
+
+// minSPEntry edits the SP a character needs for a min_sp condition
+func minSPEntry(cond *repository.RoleCondition) *widget.Entry {
+	inp := widget.NewEntry()
+	inp.PlaceHolder = "skill points"
+	if cond.Value > 0 {
+		inp.SetText(strconv.FormatInt(cond.Value, 10))
+	}
+	inp.Validator = func(s string) error {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return errors.Wrap(err, "skill points must be a number")
+		}
+		if n <= 0 {
+			return errors.New("skill points must be positive")
+		}
+		return nil
+	}
+	inp.OnChanged = func(s string) {
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			cond.Value = n
+		}
+	}
+	return inp
+}
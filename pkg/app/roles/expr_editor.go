@@ -0,0 +1,235 @@
+package roles
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+	"github.com/kava-forge/eve-alts/lib/logging"
+
+	"github.com/kava-forge/eve-alts/pkg/app/apperrors"
+	"github.com/kava-forge/eve-alts/pkg/app/bindings"
+	"github.com/kava-forge/eve-alts/pkg/app/minitag"
+	"github.com/kava-forge/eve-alts/pkg/keys"
+	"github.com/kava-forge/eve-alts/pkg/operators"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+// ExprEditor edits a role's expression tree. Groups pick an operator and can
+// hold tags and further groups; the whole tree is redrawn on every structural
+// change.
+type ExprEditor struct {
+	deps   dependencies
+	parent fyne.Window
+	tags   *bindings.DataList[*repository.TagDBData]
+	root   *repository.RoleExpr
+
+	Content *fyne.Container
+}
+
+func NewExprEditor(deps dependencies, parent fyne.Window, tagsData *bindings.DataList[*repository.TagDBData]) *ExprEditor {
+	logger := logging.With(deps.Logger(), keys.Component, "ExprEditor")
+
+	ee := &ExprEditor{
+		deps:   deps,
+		parent: parent,
+		tags:   tagsData,
+		root:   repository.NewRoleGroup(operators.OperatorAll),
+
+		Content: container.NewVBox(),
+	}
+
+	ee.rebuild()
+
+	tagsData.AddListener(bindings.NewListener(logger, ee.rebuild))
+
+	return ee
+}
+
+// SetExpr loads a copy of e into the editor
+func (ee *ExprEditor) SetExpr(e *repository.RoleExpr) {
+	if e == nil {
+		e = repository.NewRoleGroup(operators.OperatorAll)
+	}
+	ee.root = e.Clone()
+	ee.rebuild()
+}
+
+// Expr is a copy of the edited expression
+func (ee *ExprEditor) Expr() *repository.RoleExpr {
+	return ee.root.Clone()
+}
+
+func (ee *ExprEditor) rebuild() {
+	logger := logging.With(ee.deps.Logger(), keys.Component, "ExprEditor.rebuild")
+
+	tagsList, err := ee.tags.Get()
+	if err != nil {
+		apperrors.Show(logger, ee.parent, apperrors.Error(
+			"Could not load tag list data",
+			apperrors.WithCause(err),
+		), nil)
+		return
+	}
+
+	live := make(map[int64]*repository.TagDBData, len(tagsList))
+	for _, t := range tagsList {
+		if t.Tag.ID != 0 {
+			live[t.Tag.ID] = t
+		}
+	}
+
+	ee.Content.Objects = []fyne.CanvasObject{ee.groupRow(logger, ee.root, nil, live)}
+	ee.Content.Refresh()
+}
+
+func (ee *ExprEditor) groupRow(logger logging.Logger, node, parentNode *repository.RoleExpr, live map[int64]*repository.TagDBData) fyne.CanvasObject {
+	opInp := widget.NewSelect(operators.OperatorNames(), nil)
+	opInp.Selected = node.Operator.String()
+	opInp.OnChanged = func(s string) {
+		op, err := operators.ParseOperator(s)
+		if err != nil {
+			apperrors.Show(logger, ee.parent, apperrors.Error(
+				"Unrecognized role operator",
+				apperrors.WithCause(err),
+			), nil)
+			return
+		}
+		node.Operator = op
+		if op == operators.OperatorAtleast && node.MinCount < 1 {
+			node.MinCount = 1
+		}
+		ee.rebuild() // show or hide the count
+	}
+
+	labels, ids := tagOptions(live, node)
+	addTag := widget.NewSelect(labels, nil)
+	addTag.PlaceHolder = "add tag"
+	addTag.OnChanged = func(s string) {
+		node.Children = append(node.Children, repository.NewRoleLeaf(ids[s]))
+		ee.rebuild()
+	}
+
+	addGroup := widget.NewButton("add group", func() {
+		node.Children = append(node.Children, repository.NewRoleGroup(operators.OperatorAll))
+		ee.rebuild()
+	})
+
+	header := container.NewHBox(opInp)
+	if node.Operator == operators.OperatorAtleast {
+		header.Add(minCountEntry(node))
+	}
+	header.Add(addTag)
+	header.Add(addGroup)
+	if parentNode != nil {
+		header.Add(layout.NewSpacer())
+		header.Add(ee.removeButton(node, parentNode))
+	}
+
+	children := container.NewVBox()
+	for _, ch := range node.Children {
+		if ch.IsLeaf() {
+			children.Add(ee.leafRow(logger, ch, node, live))
+		} else {
+			children.Add(ee.groupRow(logger, ch, node, live))
+		}
+	}
+	if len(node.Children) == 0 {
+		children.Add(widget.NewLabelWithStyle("(empty group)", fyne.TextAlignLeading, fyne.TextStyle{Italic: true}))
+	}
+
+	// a thin rule down the left shows which group the children belong to
+	rule := canvas.NewRectangle(theme.Color(theme.ColorNameSeparator))
+	rule.SetMinSize(fyne.Size{Width: 2})
+
+	return container.NewBorder(header, nil, container.New(layout.NewCustomPaddedLayout(0, 0, 2*theme.Padding(), theme.Padding()), rule), nil, children)
+}
+
+// minCountEntry edits how many children an "atleast" group needs
+func minCountEntry(node *repository.RoleExpr) *widget.Entry {
+	inp := widget.NewEntry()
+	inp.SetText(strconv.FormatInt(node.MinCount, 10))
+	inp.Validator = func(s string) error {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return errors.Wrap(err, "count must be a number")
+		}
+		if n < 1 || n > int64(len(node.Children)) {
+			return errors.New("count must be between 1 and the number of tags and groups")
+		}
+		return nil
+	}
+	inp.OnChanged = func(s string) {
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			node.MinCount = n
+		}
+	}
+	return inp
+}
+
+func (ee *ExprEditor) leafRow(logger logging.Logger, node, parentNode *repository.RoleExpr, live map[int64]*repository.TagDBData) fyne.CanvasObject {
+	var label fyne.CanvasObject
+	if t, ok := live[node.TagID]; ok {
+		label = minitag.New(logger, t.Tag.Name, t.Color(), theme.SizeNameText)
+	} else {
+		label = widget.NewLabelWithStyle("(deleted tag)", fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+	}
+
+	return container.NewHBox(label, layout.NewSpacer(), ee.removeButton(node, parentNode))
+}
+
+func (ee *ExprEditor) removeButton(node, parentNode *repository.RoleExpr) *widget.Button {
+	b := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		for i, ch := range parentNode.Children {
+			if ch == node {
+				parentNode.Children = append(parentNode.Children[:i], parentNode.Children[i+1:]...)
+				break
+			}
+		}
+		ee.rebuild()
+	})
+	b.Importance = widget.DangerImportance
+	return b
+}
+
+// tagOptions lists the live tags that are not already directly in the group,
+// labelled by name with the ID appended to any duplicate names
+func tagOptions(live map[int64]*repository.TagDBData, group *repository.RoleExpr) ([]string, map[string]int64) {
+	present := make(map[int64]bool, len(group.Children))
+	for _, ch := range group.Children {
+		if ch.IsLeaf() {
+			present[ch.TagID] = true
+		}
+	}
+
+	nameCount := make(map[string]int, len(live))
+	for _, t := range live {
+		nameCount[t.Tag.Name]++
+	}
+
+	labels := make([]string, 0, len(live))
+	ids := make(map[string]int64, len(live))
+	for id, t := range live {
+		if present[id] {
+			continue
+		}
+
+		label := t.Tag.Name
+		if nameCount[label] > 1 {
+			label = fmt.Sprintf("%s (#%d)", label, id)
+		}
+		labels = append(labels, label)
+		ids[label] = id
+	}
+	sort.Strings(labels)
+
+	return labels, ids
+}
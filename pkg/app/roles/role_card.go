@@ -35,7 +35,7 @@ type RoleCard struct {
 	update *sync.RWMutex
 }
 
-func NewRoleCard(deps dependencies, parent fyne.Window, dataRole bindings.DataProxy[*repository.RoleDBData], deleteFunc func(c *RoleCard), editFunc func(bindings.DataProxy[*repository.RoleDBData], func())) *RoleCard {
+func NewRoleCard(deps dependencies, parent fyne.Window, dataRole bindings.DataProxy[*repository.RoleDBData], editFunc func(bindings.DataProxy[*repository.RoleDBData], func())) *RoleCard {
 	logger := logging.With(deps.Logger(), keys.Component, "RoleCard")
 
 	role, err := dataRole.Get()
@@ -69,7 +69,7 @@ func NewRoleCard(deps dependencies, parent fyne.Window, dataRole bindings.DataPr
 	cc.ColorSwatch.SetCornerRadius(theme.InnerPadding() / 2)
 
 	cc.EditButton.OnTapped = cc.editRole(editFunc)
-	cc.DeleteButton.OnTapped = cc.deleteRole(deleteFunc)
+	cc.DeleteButton.OnTapped = cc.deleteRole()
 	cc.DeleteButton.Importance = widget.DangerImportance
 
 	dataRole.AddListener(bindings.NewListener(logger, cc.redraw))
@@ -185,7 +185,7 @@ func (c *RoleCard) editRole(editFunc func(bindings.DataProxy[*repository.RoleDBD
 	}
 }
 
-func (c *RoleCard) deleteRole(callback func(*RoleCard)) func() {
+func (c *RoleCard) deleteRole() func() {
 	logger := logging.With(c.deps.Logger(), keys.Component, "RoleCard.deleteRole")
 	return func() {
 		ctx := context.Background()
@@ -214,8 +214,6 @@ func (c *RoleCard) deleteRole(callback func(*RoleCard)) func() {
 					"Could not delete role",
 					apperrors.WithCause(err),
 				), nil)
-			} else {
-				callback(c)
 			}
 		}, c.parent)
 		conf.SetConfirmImportance(widget.DangerImportance)
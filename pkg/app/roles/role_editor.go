@@ -8,6 +8,7 @@ import (
 	"math/rand"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
 
@@ -19,7 +20,6 @@ import (
 	"github.com/kava-forge/eve-alts/pkg/app/colors"
 	"github.com/kava-forge/eve-alts/pkg/database"
 	"github.com/kava-forge/eve-alts/pkg/keys"
-	"github.com/kava-forge/eve-alts/pkg/operators"
 	"github.com/kava-forge/eve-alts/pkg/repository"
 )
 
@@ -28,8 +28,8 @@ func populateRoleData(
 	colorSwatch *colors.TappableColorSwatch,
 	colorInp *dialog.ColorPickerDialog,
 	labelInp *widget.Entry,
-	operatorInp *widget.Select,
-	tagSel *TagSelector,
+	exprEd *ExprEditor,
+	condEd *ConditionsEditor,
 	roleData bindings.DataProxy[*repository.RoleDBData],
 ) error {
 	role, err := roleData.Get()
@@ -47,18 +47,13 @@ func populateRoleData(
 	labelInp.Text = role.Role.Label
 	labelInp.Refresh()
 
-	operatorInp.Selected = role.Role.Operator
-	operatorInp.Refresh()
-
-	tagSel.Clear()
-	for _, t := range role.Tags {
-		tagSel.Select(t.ID)
-	}
+	exprEd.SetExpr(role.Expr)
+	condEd.SetConditions(role.Conditions)
 
 	return nil
 }
 
-func NewRoleEditor(deps dependencies, a fyne.App, title string, roles *bindings.DataList[*repository.RoleDBData], tags *bindings.DataList[*repository.TagDBData], roleData bindings.DataProxy[*repository.RoleDBData], onClose func()) fyne.Window {
+func NewRoleEditor(deps dependencies, a fyne.App, title string, tags *bindings.DataList[*repository.TagDBData], roleData bindings.DataProxy[*repository.RoleDBData], onClose func()) fyne.Window {
 	logger := logging.With(deps.Logger(), keys.Component, "RoleEditor")
 
 	w := a.NewWindow(fmt.Sprintf("EVE Alts - %s", title))
@@ -72,7 +67,6 @@ func NewRoleEditor(deps dependencies, a fyne.App, title string, roles *bindings.
 
 	nameInp := widget.NewEntry()
 	labelInp := widget.NewEntry()
-	operatorInp := widget.NewSelect(operators.OperatorNames(), func(string) {})
 
 	colorSwatch := colors.NewTappableColorSwatch(deps.Logger(), color.RGBA{R: uint8(rand.Intn(256)), G: uint8(rand.Intn(256)), B: uint8(rand.Intn(256)), A: 255}) //nolint:gosec // not security related
 	colorInp := dialog.NewColorPicker("Tag Color", "Pick a color for the role", func(c color.Color) {
@@ -81,10 +75,11 @@ func NewRoleEditor(deps dependencies, a fyne.App, title string, roles *bindings.
 	colorInp.Advanced = true
 	colorSwatch.OnTapped = func(pe *fyne.PointEvent) { colorInp.Show() }
 
-	tagSel := NewTagSelector(deps, w, tags)
+	exprEd := NewExprEditor(deps, w, tags)
+	condEd := NewConditionsEditor(deps, w)
 
 	if roleData != nil {
-		if err := populateRoleData(nameInp, colorSwatch, colorInp, labelInp, operatorInp, tagSel, roleData); err != nil {
+		if err := populateRoleData(nameInp, colorSwatch, colorInp, labelInp, exprEd, condEd, roleData); err != nil {
 			apperrors.Show(logger, w, apperrors.Error(
 				"Could not load role data",
 				apperrors.WithCause(err),
@@ -96,45 +91,45 @@ func NewRoleEditor(deps dependencies, a fyne.App, title string, roles *bindings.
 		widget.NewFormItem("Role Name", nameInp),
 		widget.NewFormItem("Role Color", colorSwatch),
 		widget.NewFormItem("Role Label", labelInp),
-		widget.NewFormItem("Operator", operatorInp),
-		widget.NewFormItem("Tags", tagSel.TagSet),
+		widget.NewFormItem("Requirements", exprEd.Content),
+		widget.NewFormItem("Conditions", condEd.Content),
 	)
 	form.OnCancel = w.Close
 	form.OnSubmit = func() {
 		ctx := context.Background()
 
-		tagIDs := tagSel.Selected()
+		expr := exprEd.Expr()
+		if err := expr.Validate(); err != nil {
+			apperrors.Show(logger, w, apperrors.Error(
+				"Invalid role requirements",
+				apperrors.WithCause(err),
+			), nil)
+			return
+		}
 
-		op, err := operators.ParseOperator(operatorInp.Selected)
-		if err != nil {
+		conds := condEd.Conditions()
+		if err := condEd.Validate(); err != nil {
 			apperrors.Show(logger, w, apperrors.Error(
-				"Unrecognized role operator",
+				"Invalid role conditions",
 				apperrors.WithCause(err),
 			), nil)
+			return
 		}
 
+		// the roles tab shows the saved role once the change is committed
 		if roleData == nil {
-			var dbRole repository.Role
-			var dbTags []repository.Tag
 			if err := database.TransactWithRetries(ctx, deps.Telemetry(), logger, deps.DB(), &sql.TxOptions{}, func(ctx context.Context, tx database.Tx) error {
-				var err error
-
-				if dbTags == nil {
-					dbTags = make([]repository.Tag, 0, 0) // TODO
-				} else {
-					dbTags = dbTags[:0]
-				}
-
-				dbRole, err = deps.AppRepo().InsertRole(ctx, nameInp.Text, labelInp.Text, op, colorSwatch.Color(), tx)
+				dbRole, err := deps.AppRepo().InsertRole(ctx, nameInp.Text, labelInp.Text, colorSwatch.Color(), tx)
 				if err != nil {
 					return errors.Wrap(err, "could not InsertRole")
 				}
 
-				for _, tid := range tagIDs {
-					_, err := deps.AppRepo().UpsertRoleTag(ctx, dbRole.ID, tid, tx)
-					if err != nil && !errors.Is(err, database.ErrNoRows) {
-						return errors.Wrap(err, "could not UpsertRoleTag", keys.TagID, tid)
-					}
+				if err := deps.AppRepo().SetRoleExpr(ctx, dbRole.ID, expr, tx); err != nil {
+					return errors.Wrap(err, "could not SetRoleExpr", keys.RoleID, dbRole.ID)
+				}
+
+				if err := deps.AppRepo().SetRoleConditions(ctx, dbRole.ID, conds, tx); err != nil {
+					return errors.Wrap(err, "could not SetRoleConditions", keys.RoleID, dbRole.ID)
 				}
 
 				return nil
@@ -145,25 +140,6 @@ func NewRoleEditor(deps dependencies, a fyne.App, title string, roles *bindings.
 				), nil)
 				return
 			}
-
-			dbTags, err := deps.AppRepo().GetAllRoleTags(ctx, dbRole.ID, nil)
-			if err != nil {
-				apperrors.Show(logger, w, apperrors.Error(
-					"Could not fetch role tags",
-					apperrors.WithCause(err),
-				), nil)
-			}
-
-			if err := roles.Append(&repository.RoleDBData{
-				Role:     dbRole,
-				Operator: operators.Operator(dbRole.Operator),
-				Tags:     dbTags,
-			}); err != nil {
-				apperrors.Show(logger, w, apperrors.Error(
-					"Could not append role",
-					apperrors.WithCause(err),
-				), nil)
-			}
 			w.Close()
 		} else { // edit existing
 			roleP, err := roleData.Get()
@@ -177,49 +153,19 @@ func NewRoleEditor(deps dependencies, a fyne.App, title string, roles *bindings.
 
 			logger := logging.With(logger, keys.RoleID, roleP.Role.ID) //nolint:govet // intentional
 
-			var dbRole repository.Role
-			var dbTags []repository.Tag
 			if err := database.TransactWithRetries(ctx, deps.Telemetry(), logger, deps.DB(), &sql.TxOptions{}, func(ctx context.Context, tx database.Tx) error {
-				role := *roleP
-
-				tagIDMap := make(map[int64]bool, len(tagIDs))
-				for _, tid := range tagIDs {
-					tagIDMap[tid] = true
-				}
-				toDelete := make([]int64, 0, 10)
-				for _, t := range role.Tags {
-					if !tagIDMap[t.ID] {
-						toDelete = append(toDelete, t.ID)
-					}
-				}
-
-				c := colorSwatch.Color()
-				err = deps.AppRepo().UpdateRole(ctx, role.Role.ID, nameInp.Text, labelInp.Text, op, c, tx)
-				if err != nil {
+				if err := deps.AppRepo().UpdateRole(ctx, roleP.Role.ID, nameInp.Text, labelInp.Text, colorSwatch.Color(), tx); err != nil {
 					return errors.Wrap(err, "could not UpdateRole")
 				}
-				role.Role.Name = nameInp.Text
-				cr, cg, cb, ca := c.RGBA()
-				role.Role.ColorR = int64(cr)
-				role.Role.ColorG = int64(cg)
-				role.Role.ColorB = int64(cb)
-				role.Role.ColorA = int64(ca)
 
-				for _, tid := range tagIDs {
-					_, err := deps.AppRepo().UpsertRoleTag(ctx, role.Role.ID, tid, tx)
-					if err != nil && !errors.Is(err, database.ErrNoRows) {
-						return errors.Wrap(err, "could not UpsertRoleTag", keys.TagID, tid, keys.RoleID, role.Role.ID)
-					}
+				if err := deps.AppRepo().SetRoleExpr(ctx, roleP.Role.ID, expr, tx); err != nil {
+					return errors.Wrap(err, "could not SetRoleExpr")
 				}
 
-				if len(toDelete) > 0 {
-					if err := deps.AppRepo().DeleteRoleTags(ctx, role.Role.ID, toDelete, tx); err != nil {
-						return errors.Wrap(err, "could not DeleteRoleTags")
-					}
+				if err := deps.AppRepo().SetRoleConditions(ctx, roleP.Role.ID, conds, tx); err != nil {
+					return errors.Wrap(err, "could not SetRoleConditions")
 				}
 
-				dbRole = role.Role
-
 				return nil
 			}); err != nil {
 				apperrors.Show(logger, w, apperrors.Error(
@@ -228,30 +174,11 @@ func NewRoleEditor(deps dependencies, a fyne.App, title string, roles *bindings.
 				), nil)
 				return
 			}
-
-			dbTags, err = deps.AppRepo().GetAllRoleTags(ctx, dbRole.ID, nil)
-			if err != nil {
-				apperrors.Show(logger, w, apperrors.Error(
-					"Could not fetch role tags",
-					apperrors.WithCause(err),
-				), nil)
-			}
-
-			if err := roleData.Set(&repository.RoleDBData{
-				Role:     dbRole,
-				Operator: operators.Operator(dbRole.Operator),
-				Tags:     dbTags,
-			}); err != nil {
-				apperrors.Show(logger, w, apperrors.Error(
-					"Could not set role",
-					apperrors.WithCause(err),
-				), nil)
-			}
 			w.Close()
 		}
 	}
 
-	w.SetContent(form)
+	w.SetContent(container.NewVScroll(form))
 
 	return w
 }
@@ -1,18 +1,22 @@
 package roles
 
 import (
+	"context"
 	"fmt"
+	"slices"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/layout"
 
+	"github.com/kava-forge/eve-alts/lib/errors"
 	"github.com/kava-forge/eve-alts/lib/logging"
 	"github.com/kava-forge/eve-alts/lib/logging/level"
 
 	"github.com/kava-forge/eve-alts/pkg/app/apperrors"
 	"github.com/kava-forge/eve-alts/pkg/app/bindings"
+	"github.com/kava-forge/eve-alts/pkg/database"
 	"github.com/kava-forge/eve-alts/pkg/keys"
 	"github.com/kava-forge/eve-alts/pkg/repository"
 )
@@ -73,26 +77,8 @@ func NewRolesTab(deps dependencies, parent fyne.Window, tagsData *bindings.DataL
 				continue
 			}
 
-			cc := NewRoleCard(deps, parent, rolesData.Child(i), func(c *RoleCard) {
-				level.Debug(logger).Message("removing role card")
-				role, err := rolesData.GetValue(i) //nolint:govet // intentional
-				if err != nil {
-					apperrors.Show(logger, c.Parent(), apperrors.Error(
-						"Could not find role data",
-						apperrors.WithCause(err),
-					), nil)
-					return
-				}
-				role.Role.ID = 0
-				if err := rolesData.SetValue(i, role); err != nil {
-					apperrors.Show(logger, c.Parent(), apperrors.Error(
-						"Could not delete role",
-						apperrors.WithCause(err),
-					), nil)
-				}
-				ctn.Remove(c)
-			}, func(roleData bindings.DataProxy[*repository.RoleDBData], onClose func()) {
-				w := NewRoleEditor(deps, fyne.CurrentApp(), "Edit Role", rolesData, tagsData, roleData, onClose)
+			cc := NewRoleCard(deps, parent, rolesData.Child(i), func(roleData bindings.DataProxy[*repository.RoleDBData], onClose func()) {
+				w := NewRoleEditor(deps, fyne.CurrentApp(), "Edit Role", tagsData, roleData, onClose)
 				w.Show()
 			})
 			level.Debug(logger).Message("adding new role", keys.RoleID, role.Role.ID, "cc", fmt.Sprintf("%#v", cc))
@@ -105,7 +91,51 @@ func NewRolesTab(deps dependencies, parent fyne.Window, tagsData *bindings.DataL
 		}
 	}))
 
-	ctn.Add(NewAddRoleButton(deps, rolesData, tagsData))
+	// roles show the tags they are made of, and lose them with the tag, so
+	// tag changes load the roles again too
+	deps.AppRepo().Events().Subscribe(func(ev repository.ChangeEvent) {
+		if ev.Entity != repository.EntityRole && ev.Entity != repository.EntityTag {
+			return
+		}
+
+		level.Debug(logger).Message("role changed", "entity", ev.Entity, "id", ev.ID, "change", ev.Change)
+
+		if err := syncRoles(context.Background(), deps, rolesData); err != nil {
+			apperrors.Show(logger, parent, apperrors.Error(
+				"Could not load roles data",
+				apperrors.WithCause(err),
+			), nil)
+		}
+
+		for _, o := range slices.Clone(ctn.Objects) {
+			if c, ok := o.(*RoleCard); ok && c.RoleID() == 0 {
+				ctn.Remove(c)
+			}
+		}
+	})
+
+	ctn.Add(NewAddRoleButton(deps, tagsData))
 
 	return container.NewVScroll(ctn), rolesData
 }
+
+func roleID(r *repository.RoleDBData) int64 {
+	if r == nil {
+		return 0
+	}
+	return r.Role.ID
+}
+
+// syncRoles makes the role list match the database
+func syncRoles(ctx context.Context, deps dependencies, rolesData *bindings.DataList[*repository.RoleDBData]) error {
+	dbRoles, err := deps.AppRepo().GetAllRoles(ctx, nil)
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return errors.Wrap(err, "could not GetAllRoles")
+	}
+
+	return errors.Wrap(bindings.Replace(rolesData, dbRoles, roleID, func(r *repository.RoleDBData) *repository.RoleDBData {
+		removed := *r
+		removed.Role.ID = 0
+		return &removed
+	}), "could not set roles")
+}
@@ -0,0 +1,149 @@
+package tags
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+	"github.com/kava-forge/eve-alts/lib/logging"
+	"github.com/kava-forge/eve-alts/lib/logging/level"
+
+	"github.com/kava-forge/eve-alts/pkg/app/apperrors"
+	"github.com/kava-forge/eve-alts/pkg/fittings"
+	"github.com/kava-forge/eve-alts/pkg/keys"
+)
+
+// NewImportFitButton opens a dialog to paste an EFT fit, and adds the skills
+// required to fly it to the skill list, replacing those from an earlier import
+// of the same fit. If the tag has no name yet, the fit name is used.
+func NewImportFitButton(deps dependencies, parent fyne.Window, nameInp, textArea *widget.Entry) *widget.Button {
+	logger := logging.With(deps.Logger(), keys.Component, "ImportFit")
+
+	return widget.NewButton("Import EFT Fit", func() {
+		fitInp := widget.NewMultiLineEntry()
+		fitInp.Wrapping = fyne.TextWrapOff
+		fitInp.SetPlaceHolder("[Hull, Fit Name]\nModule\nModule, Charge\n\nDrone x5")
+		fitInp.SetMinRowsVisible(15)
+
+		d := dialog.NewForm("Import EFT Fit", "Import", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("Fit", fitInp),
+		}, func(ok bool) {
+			if !ok {
+				return
+			}
+
+			ctx := context.Background()
+
+			fit, err := fittings.ParseEFT(fitInp.Text)
+			if err != nil {
+				apperrors.Show(logger, parent, apperrors.Error(
+					"Could not parse fit",
+					apperrors.WithCause(err),
+				), nil)
+				return
+			}
+
+			skills, resolveErr := fittings.RequiredSkills(ctx, deps.StaticRepo(), fit)
+			if resolveErr != nil && skills == nil {
+				apperrors.Show(logger, parent, apperrors.Error(
+					"Could not find required skills",
+					apperrors.WithCause(resolveErr),
+				), nil)
+				return
+			}
+			level.Debug(logger).Message("resolved fit skills", "fit", fit.Name, "skills", skills)
+
+			if err := appendFitSkills(ctx, deps, fit, skills, textArea); err != nil {
+				apperrors.Show(logger, parent, apperrors.Error(
+					"Could not add fit skills",
+					apperrors.WithCause(err),
+				), nil)
+				return
+			}
+
+			if strings.TrimSpace(nameInp.Text) == "" {
+				nameInp.SetText(fit.Name)
+			}
+
+			if resolveErr != nil {
+				apperrors.Show(logger, parent, apperrors.Error(
+					fmt.Sprintf("Some items were not recognized and were skipped:\n%s", resolveErr),
+					apperrors.WithCause(resolveErr),
+				), nil)
+			}
+		}, parent)
+		d.Resize(fyne.Size{Width: 600, Height: 500})
+		d.Show()
+	})
+}
+
+func appendFitSkills(ctx context.Context, deps dependencies, fit fittings.Fit, skills []fittings.SkillRequirement, textArea *widget.Entry) error {
+	if len(skills) == 0 {
+		return nil
+	}
+
+	skillIDs := make([]int64, 0, len(skills))
+	for _, sk := range skills {
+		skillIDs = append(skillIDs, sk.SkillID)
+	}
+	rows, err := deps.StaticRepo().BatchGetSkillNames(ctx, skillIDs, nil)
+	if err != nil {
+		return errors.Wrap(err, "could not fetch skill names")
+	}
+	nameMap := make(map[int64]string, len(rows))
+	for _, row := range rows {
+		nameMap[row.SkillID] = row.SkillName
+	}
+
+	lines := make([]string, 0, len(skills))
+	for _, sk := range skills {
+		lines = append(lines, fmt.Sprintf("%s %d", nameMap[sk.SkillID], sk.SkillLevel))
+	}
+	sort.Strings(lines)
+
+	textArea.SetText(SetFitBlock(textArea.Text, fit, lines))
+
+	return nil
+}
+
+// SetFitBlock puts a fit's skill lines into a skill list under a "# Fit Name
+// (Hull)" header. A block already there for the same fit, up to the next blank
+// line or header, is replaced, so importing a fit again updates it. Otherwise
+// the block is appended.
+func SetFitBlock(text string, fit fittings.Fit, lines []string) string {
+	header := fmt.Sprintf("# %s (%s)", fit.Name, fit.Hull)
+	block := append([]string{header}, lines...)
+
+	existing := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, l := range existing {
+		if strings.TrimSpace(l) != header {
+			continue
+		}
+
+		end := i + 1
+		for end < len(existing) {
+			next := strings.TrimSpace(existing[end])
+			if next == "" || strings.HasPrefix(next, "#") {
+				break
+			}
+			end++
+		}
+
+		merged := append(append(append([]string{}, existing[:i]...), block...), existing[end:]...)
+		return strings.Join(merged, "\n")
+	}
+
+	// ParseSkills keeps the highest level for duplicate skills, so other
+	// blocks listing the same skills do no harm
+	text = strings.TrimRight(text, "\n")
+	if text != "" {
+		text += "\n\n"
+	}
+	return text + strings.Join(block, "\n")
+}
@@ -0,0 +1,39 @@
+package tags_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kava-forge/eve-alts/pkg/app/tags"
+	"github.com/kava-forge/eve-alts/pkg/fittings"
+)
+
+func TestSetFitBlock(t *testing.T) {
+	t.Parallel()
+
+	rifter := fittings.Fit{Hull: "Rifter", Name: "Tackle"}
+	slasher := fittings.Fit{Hull: "Slasher", Name: "Tackle"}
+
+	text := "Navigation 3 # by hand\n"
+	text = tags.SetFitBlock(text, rifter, []string{"Minmatar Frigate 1", "Small Projectile Turret 1"})
+	text = tags.SetFitBlock(text, slasher, []string{"Minmatar Frigate 3"})
+	assert.Equal(t, "Navigation 3 # by hand\n\n"+
+		"# Tackle (Rifter)\nMinmatar Frigate 1\nSmall Projectile Turret 1\n\n"+
+		"# Tackle (Slasher)\nMinmatar Frigate 3", text)
+
+	// importing the same fit again updates its block in place
+	text = tags.SetFitBlock(text, rifter, []string{"Minmatar Frigate 2"})
+	assert.Equal(t, "Navigation 3 # by hand\n\n"+
+		"# Tackle (Rifter)\nMinmatar Frigate 2\n\n"+
+		"# Tackle (Slasher)\nMinmatar Frigate 3", text)
+
+	again := tags.SetFitBlock(text, rifter, []string{"Minmatar Frigate 2"})
+	assert.Equal(t, text, again, "a repeated import changes nothing")
+
+	lines, err := tags.ParseSkillLines(text)
+	assert.NoError(t, err)
+	assert.Len(t, lines, 3)
+
+	assert.Equal(t, "# Tackle (Rifter)\nMinmatar Frigate 1", tags.SetFitBlock("", rifter, []string{"Minmatar Frigate 1"}))
+}
@@ -0,0 +1,383 @@
+package tags
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+var (
+	ErrMissingSkillName   = errors.New("missing skill name")
+	ErrInvalidSkillName   = errors.New("invalid skill name")
+	ErrInvalidSkillLevel  = errors.New("invalid skill level")
+	ErrUnknownAnnotation  = errors.New("unknown annotation")
+	ErrUnbalancedBrackets = errors.New("unbalanced brackets")
+)
+
+const (
+	AnnotationRequired    = "required"
+	AnnotationRecommended = "recommended"
+)
+
+var knownAnnotations = map[string]bool{
+	AnnotationRequired:    true,
+	AnnotationRecommended: true,
+}
+
+// LineError ties a skill list error to the line that caused it
+type LineError struct {
+	Line int
+	Text string
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d (%q): %s", e.Line, e.Text, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// SkillLine is one parsed line of a skill list, before the skill name is resolved
+type SkillLine struct {
+	Line        int
+	Name        string
+	Level       int64
+	Annotations []string
+}
+
+func (l SkillLine) HasAnnotation(a string) bool {
+	for _, la := range l.Annotations {
+		if la == a {
+			return true
+		}
+	}
+	return false
+}
+
+type SkillData struct {
+	SkillID     int64
+	SkillLevel  int64
+	Recommended bool
+}
+
+// ParseSkillLines parses a skill list, one skill per line. Each line is one of
+//
+//	Skill Name                  level 1
+//	Skill Name 4                Arabic level, 0-5
+//	Skill Name IV               Roman level, as EVE copies skills
+//	Skill Name Level 4
+//	Skill Name<TAB>4<TAB>...    in-game copy; extra columns are ignored
+//
+// optionally followed by annotations in parentheses or brackets, e.g.
+// "Gunnery V (recommended)". Anything after a # is a comment.
+func ParseSkillLines(text string) ([]SkillLine, error) {
+	scanner := bufio.NewScanner(strings.NewReader(text))
+
+	var errs error
+	var lines []SkillLine
+
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		raw := scanner.Text()
+
+		sl, ok, err := parseSkillLine(raw)
+		if err != nil {
+			errs = multierror.Append(errs, &LineError{Line: lineNo, Text: strings.TrimSpace(raw), Err: err})
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		sl.Line = lineNo
+		lines = append(lines, sl)
+	}
+
+	if err := scanner.Err(); err != nil {
+		errs = multierror.Append(errs, errors.Wrap(err, "could not read skill list"))
+	}
+
+	return lines, errs
+}
+
+func parseSkillLine(raw string) (sl SkillLine, ok bool, err error) {
+	if idx := strings.Index(raw, "#"); idx >= 0 {
+		raw = raw[:idx]
+	}
+
+	line := strings.TrimSpace(raw)
+	if line == "" {
+		return sl, false, nil
+	}
+
+	if strings.Contains(line, "\t") {
+		sl, err = parseTabbedLine(line)
+	} else {
+		sl, err = parseSpacedLine(line)
+	}
+	if err != nil {
+		return sl, false, err
+	}
+
+	if sl.Name == "" {
+		return sl, false, ErrMissingSkillName
+	}
+
+	for _, a := range sl.Annotations {
+		if !knownAnnotations[a] {
+			return sl, false, errors.Wrap(ErrUnknownAnnotation, "could not parse annotation", "annotation", a)
+		}
+	}
+
+	return sl, true, nil
+}
+
+// parseTabbedLine handles the tab separated format from copying skills out of the game
+func parseTabbedLine(line string) (sl SkillLine, err error) {
+	fields := strings.Split(line, "\t")
+
+	sl.Name = strings.Join(strings.Fields(fields[0]), " ")
+	sl.Level = 1
+
+	foundLevel := false
+	for _, f := range fields[1:] {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+
+		if annotations, ok := parseAnnotationGroup(f); ok {
+			sl.Annotations = append(sl.Annotations, annotations...)
+			continue
+		}
+
+		if knownAnnotations[strings.ToLower(f)] {
+			sl.Annotations = append(sl.Annotations, strings.ToLower(f))
+			continue
+		}
+
+		if foundLevel {
+			continue
+		}
+
+		tokens := strings.Fields(f)
+		if len(tokens) == 2 && strings.EqualFold(tokens[0], "level") {
+			tokens = tokens[1:]
+		}
+		if len(tokens) != 1 {
+			continue
+		}
+
+		lvl, isLevel, err := parseLevel(tokens[0])
+		if err != nil {
+			return sl, err
+		}
+		if isLevel {
+			sl.Level = lvl
+			foundLevel = true
+		}
+	}
+
+	return sl, nil
+}
+
+func parseSpacedLine(line string) (sl SkillLine, err error) {
+	// peel annotation groups off of the end
+	for {
+		var group string
+		switch {
+		case strings.HasSuffix(line, ")"):
+			idx := strings.LastIndex(line, "(")
+			if idx < 0 {
+				return sl, errors.Wrap(ErrUnbalancedBrackets, "missing opening parenthesis")
+			}
+			group = line[idx:]
+			line = strings.TrimSpace(line[:idx])
+		case strings.HasSuffix(line, "]"):
+			idx := strings.LastIndex(line, "[")
+			if idx < 0 {
+				return sl, errors.Wrap(ErrUnbalancedBrackets, "missing opening bracket")
+			}
+			group = line[idx:]
+			line = strings.TrimSpace(line[:idx])
+		}
+		if group == "" {
+			break
+		}
+
+		annotations, _ := parseAnnotationGroup(group)
+		sl.Annotations = append(annotations, sl.Annotations...)
+	}
+
+	// every group that was closed has been peeled off, so any opening left
+	// was never closed
+	if strings.ContainsAny(line, "([") {
+		return sl, errors.Wrap(ErrUnbalancedBrackets, "missing closing parenthesis or bracket")
+	}
+
+	tokens := strings.Fields(line)
+	sl.Level = 1
+
+	if n := len(tokens); n > 1 {
+		lvl, isLevel, err := parseLevel(tokens[n-1])
+		if err != nil {
+			return sl, err
+		}
+		if isLevel {
+			sl.Level = lvl
+			tokens = tokens[:n-1]
+			if n := len(tokens); n > 1 && strings.EqualFold(tokens[n-1], "level") {
+				tokens = tokens[:n-1]
+			}
+		}
+	}
+
+	sl.Name = strings.Join(tokens, " ")
+
+	return sl, nil
+}
+
+// parseAnnotationGroup parses "(a, b)" or "[a, b]" into its lowercased annotations
+func parseAnnotationGroup(s string) ([]string, bool) {
+	if len(s) < 2 {
+		return nil, false
+	}
+	if !(s[0] == '(' && s[len(s)-1] == ')') && !(s[0] == '[' && s[len(s)-1] == ']') {
+		return nil, false
+	}
+
+	var annotations []string
+	for _, a := range strings.Split(s[1:len(s)-1], ",") {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if a != "" {
+			annotations = append(annotations, a)
+		}
+	}
+
+	return annotations, true
+}
+
+var romanLevels = map[string]int64{
+	"I":   1,
+	"II":  2,
+	"III": 3,
+	"IV":  4,
+	"V":   5,
+}
+
+// parseLevel reports whether tok looks like a skill level, and an error if it
+// does but is out of range
+func parseLevel(tok string) (int64, bool, error) {
+	if lvl, ok := romanLevels[strings.ToUpper(tok)]; ok {
+		return lvl, true, nil
+	}
+
+	for _, r := range tok {
+		if r < '0' || r > '9' {
+			return 0, false, nil
+		}
+	}
+
+	lvl, err := strconv.ParseInt(tok, 10, 64)
+	if err != nil || lvl > 5 {
+		return 0, true, errors.Wrap(ErrInvalidSkillLevel, "skill level must be between 0 and 5", "level", tok)
+	}
+
+	return lvl, true, nil
+}
+
+// ParseSkills parses a skill list (see ParseSkillLines) and resolves the skill names.
+// Skills listed more than once keep their highest level, separately for
+// required and recommended lines.
+func ParseSkills(ctx context.Context, static repository.StaticData, text string) (skills []SkillData, err error) {
+	lines, errs := ParseSkillLines(text)
+
+	for _, sl := range lines {
+		skillID, err := static.GetSkillIDByName(ctx, sl.Name, nil)
+		if err != nil {
+			errs = multierror.Append(errs, &LineError{
+				Line: sl.Line,
+				Text: sl.Name,
+				Err:  errors.Wrap(ErrInvalidSkillName, "could not find skill", "skill_name", sl.Name),
+			})
+			continue
+		}
+
+		skills = append(skills, SkillData{
+			SkillID:     skillID,
+			SkillLevel:  sl.Level,
+			Recommended: sl.HasAnnotation(AnnotationRecommended),
+		})
+	}
+
+	type skillKey struct {
+		id          int64
+		recommended bool
+	}
+
+	maxLevels := make(map[skillKey]SkillData, len(skills))
+	for _, sk := range skills {
+		key := skillKey{sk.SkillID, sk.Recommended}
+		if cur, ok := maxLevels[key]; !ok || sk.SkillLevel >= cur.SkillLevel {
+			maxLevels[key] = sk
+		}
+	}
+
+	skills = skills[:0]
+	for _, sk := range maxLevels {
+		skills = append(skills, sk)
+	}
+	sort.Slice(skills, func(i, j int) bool {
+		if skills[i].SkillID != skills[j].SkillID {
+			return skills[i].SkillID < skills[j].SkillID
+		}
+		return !skills[i].Recommended && skills[j].Recommended
+	})
+
+	return skills, errs
+}
+
+// TagSkillLevels folds parsed skills into one row per skill, with the required
+// level from plain lines and the recommended level from recommended lines.
+// A recommended level at or below the required level adds nothing and is dropped.
+func TagSkillLevels(skills []SkillData) []repository.TagSkill {
+	byID := make(map[int64]*repository.TagSkill, len(skills))
+	ordered := make([]*repository.TagSkill, 0, len(skills))
+
+	for _, sd := range skills {
+		ts, ok := byID[sd.SkillID]
+		if !ok {
+			ts = &repository.TagSkill{SkillID: sd.SkillID}
+			byID[sd.SkillID] = ts
+			ordered = append(ordered, ts)
+		}
+
+		if sd.Recommended {
+			ts.RecommendedLevel = max(ts.RecommendedLevel, sd.SkillLevel)
+		} else {
+			ts.SkillLevel = max(ts.SkillLevel, sd.SkillLevel)
+		}
+	}
+
+	levels := make([]repository.TagSkill, 0, len(ordered))
+	for _, ts := range ordered {
+		if ts.RecommendedLevel <= ts.SkillLevel {
+			ts.RecommendedLevel = 0
+		}
+		levels = append(levels, *ts)
+	}
+
+	return levels
+}
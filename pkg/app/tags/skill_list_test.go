@@ -0,0 +1,208 @@
+package tags_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kava-forge/eve-alts/pkg/app/tags"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/repository/repositoryfakes"
+)
+
+func TestParseSkillLines(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		text    string
+		want    []tags.SkillLine
+		wantErr error
+	}{
+		{
+			name: "no level",
+			text: "Gunnery",
+			want: []tags.SkillLine{{Line: 1, Name: "Gunnery", Level: 1}},
+		},
+		{
+			name: "arabic level",
+			text: "Small Hybrid Turret 4",
+			want: []tags.SkillLine{{Line: 1, Name: "Small Hybrid Turret", Level: 4}},
+		},
+		{
+			name: "roman level",
+			text: "Gunnery V",
+			want: []tags.SkillLine{{Line: 1, Name: "Gunnery", Level: 5}},
+		},
+		{
+			name: "lowercase roman level",
+			text: "Gunnery iv",
+			want: []tags.SkillLine{{Line: 1, Name: "Gunnery", Level: 4}},
+		},
+		{
+			name: "level word",
+			text: "Gunnery Level 3",
+			want: []tags.SkillLine{{Line: 1, Name: "Gunnery", Level: 3}},
+		},
+		{
+			name: "extra whitespace",
+			text: "   Spaceship   Command   II   ",
+			want: []tags.SkillLine{{Line: 1, Name: "Spaceship Command", Level: 2}},
+		},
+		{
+			name: "tab separated",
+			text: "Gunnery\t5\t256000\nMotion Prediction\tLevel IV",
+			want: []tags.SkillLine{
+				{Line: 1, Name: "Gunnery", Level: 5},
+				{Line: 2, Name: "Motion Prediction", Level: 4},
+			},
+		},
+		{
+			name: "tab separated with annotation",
+			text: "Gunnery\t5\trecommended",
+			want: []tags.SkillLine{{Line: 1, Name: "Gunnery", Level: 5, Annotations: []string{"recommended"}}},
+		},
+		{
+			name: "comments and blank lines",
+			text: "# logi skills\n\nLogistics Cruisers IV # needed for T2\n  # indented comment\n",
+			want: []tags.SkillLine{{Line: 3, Name: "Logistics Cruisers", Level: 4}},
+		},
+		{
+			name: "annotations",
+			text: "Gunnery V (recommended)\nSurgical Strike 4 [Recommended]\nMechanics (required, recommended)",
+			want: []tags.SkillLine{
+				{Line: 1, Name: "Gunnery", Level: 5, Annotations: []string{"recommended"}},
+				{Line: 2, Name: "Surgical Strike", Level: 4, Annotations: []string{"recommended"}},
+				{Line: 3, Name: "Mechanics", Level: 1, Annotations: []string{"required", "recommended"}},
+			},
+		},
+		{
+			name: "name with digits",
+			text: "Cybernetics 5",
+			want: []tags.SkillLine{{Line: 1, Name: "Cybernetics", Level: 5}},
+		},
+		{
+			name:    "level too high",
+			text:    "Gunnery 7",
+			wantErr: tags.ErrInvalidSkillLevel,
+		},
+		{
+			name:    "unknown annotation",
+			text:    "Gunnery V (someday)",
+			wantErr: tags.ErrUnknownAnnotation,
+		},
+		{
+			name:    "missing name",
+			text:    "(recommended)",
+			wantErr: tags.ErrMissingSkillName,
+		},
+		{
+			name:    "unbalanced brackets",
+			text:    "Gunnery V recommended]",
+			wantErr: tags.ErrUnbalancedBrackets,
+		},
+		{
+			name:    "unclosed parenthesis",
+			text:    "Gunnery V (recommended",
+			wantErr: tags.ErrUnbalancedBrackets,
+		},
+		{
+			name:    "unclosed bracket",
+			text:    "Gunnery V [recommended] [required",
+			wantErr: tags.ErrUnbalancedBrackets,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tags.ParseSkillLines(tt.text)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseSkillLines_LineNumbers(t *testing.T) {
+	t.Parallel()
+
+	got, err := tags.ParseSkillLines("Gunnery V\nGunnery 9\n\nMechanics (oops)\nSpaceship Command")
+
+	assert.Equal(t, []tags.SkillLine{
+		{Line: 1, Name: "Gunnery", Level: 5},
+		{Line: 5, Name: "Spaceship Command", Level: 1},
+	}, got)
+
+	var lineErr *tags.LineError
+	if assert.ErrorAs(t, err, &lineErr) {
+		assert.Equal(t, 2, lineErr.Line)
+	}
+	assert.Contains(t, err.Error(), "line 2")
+	assert.Contains(t, err.Error(), "line 4")
+}
+
+func TestParseSkills(t *testing.T) {
+	t.Parallel()
+
+	skillIDs := map[string]int64{
+		"gunnery":           3300,
+		"spaceship command": 3327,
+	}
+
+	static := &repositoryfakes.FakeStaticData{}
+	static.GetSkillIDByNameCalls(func(ctx context.Context, name string, tx database.Tx) (int64, error) {
+		id, ok := skillIDs[strings.ToLower(name)]
+		if !ok {
+			return 0, sql.ErrNoRows
+		}
+		return id, nil
+	})
+
+	got, err := tags.ParseSkills(context.Background(), static, `
+Gunnery III
+Gunnery 2
+Gunnery V (recommended)
+Spaceship Command IV [recommended]
+Spaceship Command
+Not A Skill IV
+`)
+
+	assert.ErrorIs(t, err, tags.ErrInvalidSkillName)
+	assert.Contains(t, err.Error(), "line 7")
+
+	// required before recommended for each skill, whatever the input order
+	assert.Equal(t, []tags.SkillData{
+		{SkillID: 3300, SkillLevel: 3},
+		{SkillID: 3300, SkillLevel: 5, Recommended: true},
+		{SkillID: 3327, SkillLevel: 1},
+		{SkillID: 3327, SkillLevel: 4, Recommended: true},
+	}, got)
+}
+
+func TestTagSkillLevels(t *testing.T) {
+	t.Parallel()
+
+	got := tags.TagSkillLevels([]tags.SkillData{
+		{SkillID: 3300, SkillLevel: 4},
+		{SkillID: 3300, SkillLevel: 5, Recommended: true},
+		{SkillID: 3301, SkillLevel: 3, Recommended: true},
+		{SkillID: 3302, SkillLevel: 4},
+		{SkillID: 3302, SkillLevel: 2, Recommended: true}, // below required, dropped
+	})
+
+	assert.Equal(t, []repository.TagSkill{
+		{SkillID: 3300, SkillLevel: 4, RecommendedLevel: 5},
+		{SkillID: 3301, SkillLevel: 0, RecommendedLevel: 3},
+		{SkillID: 3302, SkillLevel: 4, RecommendedLevel: 0},
+	}, got)
+}
@@ -24,18 +24,20 @@ type TagCard struct {
 	widget.BaseWidget
 
 	deps   dependencies
+	tags   *bindings.DataList[*repository.TagDBData]
 	tag    bindings.DataProxy[*repository.TagDBData]
 	parent fyne.Window
 
-	NameLabel    *widget.RichText
-	ColorSwatch  *colors.ColorSwatch
-	EditButton   *widget.Button
-	DeleteButton *widget.Button
+	NameLabel     *widget.RichText
+	ColorSwatch   *colors.ColorSwatch
+	DetailsButton *widget.Button
+	EditButton    *widget.Button
+	DeleteButton  *widget.Button
 
 	update *sync.RWMutex
 }
 
-func NewTagCard(deps dependencies, parent fyne.Window, dataTag bindings.DataProxy[*repository.TagDBData], deleteFunc func(c *TagCard), editFunc func(bindings.DataProxy[*repository.TagDBData], func())) *TagCard {
+func NewTagCard(deps dependencies, parent fyne.Window, tags *bindings.DataList[*repository.TagDBData], dataTag bindings.DataProxy[*repository.TagDBData], editFunc func(bindings.DataProxy[*repository.TagDBData], func())) *TagCard {
 	logger := logging.With(deps.Logger(), keys.Component, "TagCard")
 
 	tag, err := dataTag.Get()
@@ -51,15 +53,20 @@ func NewTagCard(deps dependencies, parent fyne.Window, dataTag bindings.DataProx
 
 	cc := &TagCard{
 		deps:   deps,
+		tags:   tags,
 		tag:    dataTag,
 		parent: parent,
 
-		NameLabel:   widget.NewRichTextWithText(tag.Tag.Name),
+		NameLabel: widget.NewRichText(
+			&widget.TextSegment{Text: tag.Tag.Name},
+			&widget.TextSegment{Text: skillCountText(tag)},
+		),
 		ColorSwatch: colors.NewColorSwatch(deps.Logger(), tag.Color()),
 		// EditButton:   widget.NewButtonWithIcon("edit", theme.SettingsIcon(), nil),
 		// DeleteButton: widget.NewButtonWithIcon("delete", theme.DeleteIcon(), nil),
-		EditButton:   widget.NewButton("edit", nil),
-		DeleteButton: widget.NewButton("delete", nil),
+		DetailsButton: widget.NewButton("details", nil),
+		EditButton:    widget.NewButton("edit", nil),
+		DeleteButton:  widget.NewButton("delete", nil),
 
 		update: &sync.RWMutex{},
 	}
@@ -68,8 +75,9 @@ func NewTagCard(deps dependencies, parent fyne.Window, dataTag bindings.DataProx
 	cc.refreshStyle()
 	cc.ColorSwatch.SetCornerRadius(theme.InnerPadding() / 2)
 
+	cc.DetailsButton.OnTapped = cc.showDetails
 	cc.EditButton.OnTapped = cc.editTag(editFunc)
-	cc.DeleteButton.OnTapped = cc.deleteTag(deleteFunc)
+	cc.DeleteButton.OnTapped = cc.deleteTag()
 	cc.DeleteButton.Importance = widget.DangerImportance
 
 	dataTag.AddListener(bindings.NewListener(logger, cc.redraw))
@@ -103,7 +111,7 @@ func (c *TagCard) refreshStyle() {
 		textColorName = lightText
 	}
 
-	for _, segi := range c.NameLabel.Segments {
+	for i, segi := range c.NameLabel.Segments {
 		if seg, ok := segi.(*widget.TextSegment); ok {
 			seg.Style = widget.RichTextStyle{
 				Alignment: fyne.TextAlignLeading,
@@ -112,11 +120,23 @@ func (c *TagCard) refreshStyle() {
 				SizeName:  theme.SizeNameText,
 				TextStyle: fyne.TextStyle{Bold: true},
 			}
+			if i > 0 { // skill counts
+				seg.Style.SizeName = theme.SizeNameCaptionText
+				seg.Style.TextStyle = fyne.TextStyle{Italic: true}
+			}
 		}
 		segi.Visual().Refresh()
 	}
 }
 
+// skillCountText summarizes the tag's own and inherited skills, e.g. " 4 + 6 inherited"
+func skillCountText(tag *repository.TagDBData) string {
+	if len(tag.Inherited) == 0 {
+		return fmt.Sprintf(" %d", len(tag.Skills))
+	}
+	return fmt.Sprintf(" %d + %d inherited", len(tag.Skills), len(tag.Inherited))
+}
+
 func (c *TagCard) CreateRenderer() fyne.WidgetRenderer {
 	return c
 }
@@ -151,6 +171,7 @@ func (c *TagCard) redraw() {
 	level.Debug(logger).Message("refreshing tag card", "color", fmt.Sprintf("%#v", tag.Color()))
 
 	c.setText(tag.Tag.Name)
+	c.setTextAt(1, skillCountText(tag))
 	c.ColorSwatch.SetColor(tag.Color())
 	c.refreshStyle()
 }
@@ -176,6 +197,26 @@ func (c *TagCard) RefreshDataWith(tag *repository.TagDBData) error {
 	return c.tag.Set(tag)
 }
 
+func (c *TagCard) showDetails() {
+	logger := logging.With(c.deps.Logger(), keys.Component, "TagCard.showDetails")
+
+	tag, err := c.tag.Get()
+	if err != nil {
+		apperrors.Show(logger, c.parent, apperrors.Error(
+			"Could not find tag data",
+			apperrors.WithCause(err),
+		), nil)
+		return
+	}
+
+	if err := showDetails(c.deps, c.parent, c.tags, tag); err != nil {
+		apperrors.Show(logger, c.parent, apperrors.Error(
+			"Could not show tag details",
+			apperrors.WithCause(err),
+		), nil)
+	}
+}
+
 func (c *TagCard) editTag(editFunc func(bindings.DataProxy[*repository.TagDBData], func())) func() {
 	return func() {
 		c.EditButton.Disable()
@@ -186,7 +227,7 @@ func (c *TagCard) editTag(editFunc func(bindings.DataProxy[*repository.TagDBData
 	}
 }
 
-func (c *TagCard) deleteTag(callback func(*TagCard)) func() {
+func (c *TagCard) deleteTag() func() {
 	logger := logging.With(c.deps.Logger(), keys.Component, "TagCard.deleteTag")
 	return func() {
 		ctx := context.Background()
@@ -215,8 +256,6 @@ func (c *TagCard) deleteTag(callback func(*TagCard)) func() {
 					"Could not delete tag",
 					apperrors.WithCause(err),
 				), nil)
-			} else {
-				callback(c)
 			}
 		}, c.parent)
 		conf.SetConfirmImportance(widget.DangerImportance)
@@ -251,6 +290,12 @@ func (c *TagCard) Layout(sz fyne.Size) {
 	c.DeleteButton.Alignment = widget.ButtonAlignCenter
 	dbsz := c.DeleteButton.Size()
 	c.DeleteButton.Move(fyne.Position{X: sz.Width - rbsz.Width - theme.Padding() - dbsz.Width - theme.Padding(), Y: sz.Height - dbsz.Height - theme.Padding()})
+
+	detailsLabelSz := fyne.MeasureText(c.DetailsButton.Text, fontSize, fyne.TextStyle{})
+	c.DetailsButton.Resize(fyne.Size{Width: detailsLabelSz.Width + 2*theme.InnerPadding(), Height: detailsLabelSz.Height + theme.InnerPadding()})
+	c.DetailsButton.Alignment = widget.ButtonAlignCenter
+	sbsz := c.DetailsButton.Size()
+	c.DetailsButton.Move(fyne.Position{X: sz.Width - rbsz.Width - theme.Padding() - dbsz.Width - theme.Padding() - sbsz.Width - theme.Padding(), Y: sz.Height - sbsz.Height - theme.Padding()})
 }
 
 func (c *TagCard) MinSize() fyne.Size {
@@ -264,6 +309,7 @@ func (c *TagCard) Objects() []fyne.CanvasObject {
 	return []fyne.CanvasObject{
 		c.ColorSwatch,
 		c.NameLabel,
+		c.DetailsButton,
 		c.EditButton,
 		c.DeleteButton,
 	}
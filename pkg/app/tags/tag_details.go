@@ -0,0 +1,159 @@
+package tags
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/app/bindings"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/scoring"
+)
+
+// closestLimit caps the "closest characters" ranking
+const closestLimit = 10
+
+// showDetails shows the tag's own and inherited skills, and which characters
+// are closest to meeting it
+func showDetails(deps dependencies, parent fyne.Window, tags *bindings.DataList[*repository.TagDBData], tag *repository.TagDBData) error {
+	ctx := context.Background()
+
+	chars, err := deps.AppRepo().GetAllCharacters(ctx, nil)
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return errors.Wrap(err, "could not GetAllCharacters")
+	}
+
+	ranks, err := scoring.LoadRanks(ctx, deps.StaticRepo(), []*repository.TagDBData{tag})
+	if err != nil {
+		return errors.Wrap(err, "could not load skill ranks")
+	}
+
+	ranked := scoring.RankCharacters(chars, tag, ranks, false)
+	qualified := 0
+	for _, c := range chars {
+		if c.Character.ID != 0 {
+			qualified++
+		}
+	}
+	qualified -= len(ranked)
+	if len(ranked) > closestLimit {
+		ranked = ranked[:closestLimit]
+	}
+
+	skillIDs := make([]int64, 0, len(tag.Skills)+len(tag.Inherited))
+	for _, sk := range tag.Skills {
+		skillIDs = append(skillIDs, sk.SkillID)
+	}
+	for _, sk := range tag.Inherited {
+		skillIDs = append(skillIDs, sk.SkillID)
+	}
+
+	nameMap := map[int64]string{}
+	if len(skillIDs) > 0 {
+		rows, err := deps.StaticRepo().BatchGetSkillNames(ctx, skillIDs, nil)
+		if err != nil {
+			return errors.Wrap(err, "could not fetch skill names")
+		}
+		for _, row := range rows {
+			nameMap[row.SkillID] = row.SkillName
+		}
+	}
+
+	tagNames := map[int64]string{}
+	list, err := tags.Get()
+	if err != nil {
+		return errors.Wrap(err, "could not load tag list data")
+	}
+	for _, t := range list {
+		if t != nil && t.Tag.ID != 0 {
+			tagNames[t.Tag.ID] = t.Tag.Name
+		}
+	}
+
+	tabs := container.NewAppTabs(
+		container.NewTabItem("Skills", container.NewVScroll(skillsContent(tag, nameMap, tagNames))),
+		container.NewTabItem("Closest Characters", container.NewVScroll(closestContent(ranked, qualified, nameMap))),
+	)
+
+	d := dialog.NewCustom(fmt.Sprintf("%s Details", tag.Tag.Name), "Close", tabs, parent)
+	d.Resize(fyne.Size{Width: 500, Height: 500})
+	d.Show()
+
+	return nil
+}
+
+func skillsContent(tag *repository.TagDBData, nameMap, tagNames map[int64]string) fyne.CanvasObject {
+	skillLines := func(skills []repository.TagSkill) string {
+		lines := make([]string, 0, len(skills))
+		for _, sk := range skills {
+			line := fmt.Sprintf("%s %d", nameMap[sk.SkillID], sk.SkillLevel)
+			if sk.RecommendedLevel > 0 {
+				line = fmt.Sprintf("%s (%d %s)", line, sk.RecommendedLevel, AnnotationRecommended)
+			}
+			lines = append(lines, line)
+		}
+		sort.Strings(lines)
+		return strings.Join(lines, "\n")
+	}
+
+	box := container.NewVBox()
+
+	own := widget.NewCard("", "Own skills", widget.NewLabel(skillLines(tag.Skills)))
+	if len(tag.Skills) == 0 {
+		own.SetContent(widget.NewLabel("(none)"))
+	}
+	box.Add(own)
+
+	bySource := make(map[int64][]repository.TagSkill)
+	sources := make([]int64, 0)
+	for _, sk := range tag.Inherited {
+		if _, ok := bySource[sk.TagID]; !ok {
+			sources = append(sources, sk.TagID)
+		}
+		bySource[sk.TagID] = append(bySource[sk.TagID], sk)
+	}
+	sort.Slice(sources, func(i, j int) bool { return tagNames[sources[i]] < tagNames[sources[j]] })
+
+	for _, src := range sources {
+		box.Add(widget.NewCard("", fmt.Sprintf("Inherited from %s", tagNames[src]), widget.NewLabel(skillLines(bySource[src]))))
+	}
+
+	return box
+}
+
+func closestContent(ranked []scoring.CharacterProgress, qualified int, nameMap map[int64]string) fyne.CanvasObject {
+	box := container.NewVBox(widget.NewLabel(fmt.Sprintf("%d characters already qualify", qualified)))
+
+	if len(ranked) == 0 {
+		return box
+	}
+
+	acc := widget.NewAccordion()
+	for _, cp := range ranked {
+		p := cp.Progress
+
+		lines := make([]string, 0, len(p.Missing))
+		for _, gap := range p.Missing {
+			cur := fmt.Sprintf("%d", gap.CurrentLevel)
+			if gap.CurrentLevel < 0 {
+				cur = "not injected"
+			}
+			lines = append(lines, fmt.Sprintf("%s: %s -> %d (%s SP)", nameMap[gap.SkillID], cur, gap.TargetLevel, scoring.FormatSP(gap.SPNeeded)))
+		}
+
+		title := fmt.Sprintf("%s - %.0f%%, %s SP to go", cp.Character.Character.Name, p.Percent(), scoring.FormatSP(p.SPNeeded()))
+		acc.Append(widget.NewAccordionItem(title, widget.NewLabel(strings.Join(lines, "\n"))))
+	}
+	box.Add(acc)
+
+	return box
+}
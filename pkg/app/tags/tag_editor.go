@@ -1,21 +1,18 @@
 package tags
 
 import (
-	"bufio"
 	"context"
 	"database/sql"
 	"fmt"
 	"image/color"
 	"math/rand"
-	"regexp"
+	"slices"
 	"sort"
-	"strconv"
 	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
-	"github.com/hashicorp/go-multierror"
 
 	"github.com/kava-forge/eve-alts/lib/errors"
 	"github.com/kava-forge/eve-alts/lib/logging"
@@ -29,7 +26,7 @@ import (
 	"github.com/kava-forge/eve-alts/pkg/repository"
 )
 
-func populateTagData(deps dependencies, nameInp *widget.Entry, colorSwatch *colors.TappableColorSwatch, colorInp *dialog.ColorPickerDialog, textArea *widget.Entry, tagData bindings.DataProxy[*repository.TagDBData]) error {
+func populateTagData(deps dependencies, nameInp *widget.Entry, colorSwatch *colors.TappableColorSwatch, colorInp *dialog.ColorPickerDialog, textArea *widget.Entry, includeInp *widget.CheckGroup, includeIDs map[string]int64, tagData bindings.DataProxy[*repository.TagDBData]) error {
 	tag, err := tagData.Get()
 	if err != nil {
 		return errors.Wrap(err, "unable to load tag data")
@@ -57,13 +54,31 @@ func populateTagData(deps dependencies, nameInp *widget.Entry, colorSwatch *colo
 	}
 
 	for _, sk := range tag.Skills {
-		lines = append(lines, fmt.Sprintf("%s %d", nameMap[sk.SkillID], sk.SkillLevel))
+		recommendedOnly := sk.SkillLevel == 0 && sk.RecommendedLevel > 0
+		if !recommendedOnly {
+			lines = append(lines, fmt.Sprintf("%s %d", nameMap[sk.SkillID], sk.SkillLevel))
+		}
+		if sk.RecommendedLevel > 0 {
+			lines = append(lines, fmt.Sprintf("%s %d (%s)", nameMap[sk.SkillID], sk.RecommendedLevel, AnnotationRecommended))
+		}
 	}
 	sort.Strings(lines)
 
 	textArea.Text = strings.Join(lines, "\n")
 	textArea.Refresh()
 
+	included := make(map[int64]bool, len(tag.Includes))
+	for _, inc := range tag.Includes {
+		included[inc.ID] = true
+	}
+	sel := make([]string, 0, len(tag.Includes))
+	for _, label := range includeInp.Options {
+		if included[includeIDs[label]] {
+			sel = append(sel, label)
+		}
+	}
+	includeInp.SetSelected(sel)
+
 	return nil
 }
 
@@ -92,8 +107,24 @@ func NewTagEditor(deps dependencies, a fyne.App, title string, tags *bindings.Da
 	textArea.Wrapping = fyne.TextWrapOff
 	textArea.SetMinRowsVisible(10)
 
+	var editingID int64
 	if tagData != nil {
-		if err := populateTagData(deps, nameInp, colorSwatch, colorInp, textArea, tagData); err != nil {
+		if tag, err := tagData.Get(); err == nil {
+			editingID = tag.Tag.ID
+		}
+	}
+	includeLabels, includeIDs, err := includeOptions(tags, editingID)
+	if err != nil {
+		apperrors.Show(logger, w, apperrors.Error(
+			"Could not load tag list data",
+			apperrors.WithCause(err),
+		), nil)
+	}
+	includeInp := widget.NewCheckGroup(includeLabels, nil)
+	includeInp.Horizontal = true
+
+	if tagData != nil {
+		if err := populateTagData(deps, nameInp, colorSwatch, colorInp, textArea, includeInp, includeIDs, tagData); err != nil {
 			apperrors.Show(logger, w, apperrors.Error(
 				"Could not load tag data",
 				apperrors.WithCause(err),
@@ -105,62 +136,59 @@ func NewTagEditor(deps dependencies, a fyne.App, title string, tags *bindings.Da
 		widget.NewFormItem("Tag Name", nameInp),
 		widget.NewFormItem("Tag Color", colorSwatch),
 		widget.NewFormItem("Skill List", textArea),
+		widget.NewFormItem("", NewImportFitButton(deps, w, nameInp, textArea)),
+		widget.NewFormItem("Includes Tags", includeInp),
 	)
 	form.OnCancel = w.Close
 	form.OnSubmit = func() {
 		ctx := context.Background()
 
-		skills, err := ParseSkills(ctx, deps.StaticRepo(), textArea.Text)
+		parsed, err := ParseSkills(ctx, deps.StaticRepo(), textArea.Text)
 		if err != nil {
 			apperrors.Show(logger, w, apperrors.Error(
-				"Could not parse skill list",
+				fmt.Sprintf("Could not parse skill list:\n%s", err),
 				apperrors.WithCause(err),
 			), nil)
 			return
 		}
-		level.Debug(logger).Message("parsed skills", "skills", skills)
+		level.Debug(logger).Message("parsed skills", "skills", parsed)
+
+		skills := TagSkillLevels(parsed)
+
+		includes := make([]int64, 0, len(includeInp.Selected))
+		for _, label := range includeInp.Selected {
+			includes = append(includes, includeIDs[label])
+		}
+		slices.Sort(includes)
 
+		// the tags tab shows the saved tag once the change is committed
 		if tagData == nil {
-			var dbTag repository.Tag
-			var dbSkills []repository.TagSkill
 			if err := database.TransactWithRetries(ctx, deps.Telemetry(), logger, deps.DB(), &sql.TxOptions{}, func(ctx context.Context, tx database.Tx) error {
-				if dbSkills == nil {
-					dbSkills = make([]repository.TagSkill, 0, len(skills))
-				} else {
-					dbSkills = dbSkills[:0]
-				}
-
-				dbTag, err = deps.AppRepo().InsertTag(ctx, nameInp.Text, colorSwatch.Color(), tx)
+				dbTag, err := deps.AppRepo().InsertTag(ctx, nameInp.Text, colorSwatch.Color(), tx)
 				if err != nil {
 					return errors.Wrap(err, "could not InsertTag")
 				}
 
 				for _, sd := range skills {
-					sk, err := deps.AppRepo().UpsertTagSkill(ctx, dbTag.ID, sd.SkillID, sd.SkillLevel, tx)
-					if err != nil {
+					if _, err := deps.AppRepo().UpsertTagSkill(ctx, dbTag.ID, sd.SkillID, sd.SkillLevel, sd.RecommendedLevel, tx); err != nil {
 						return errors.Wrap(err, "could not UpsertTagSkill", keys.SkillID, sd.SkillID, keys.SkillLevel, sd.SkillLevel)
 					}
-					dbSkills = append(dbSkills, sk)
+				}
+
+				for _, tid := range includes {
+					if err := deps.AppRepo().UpsertTagInclude(ctx, dbTag.ID, tid, tx); err != nil {
+						return errors.Wrap(err, "could not UpsertTagInclude", keys.IncludedTagID, tid)
+					}
 				}
 
 				return nil
 			}); err != nil {
 				apperrors.Show(logger, w, apperrors.Error(
-					"Could not create tag",
+					saveErrorMessage("Could not create tag", err),
 					apperrors.WithCause(err),
 				), nil)
 				return
 			}
-
-			if err := tags.Append(&repository.TagDBData{
-				Tag:    dbTag,
-				Skills: dbSkills,
-			}); err != nil {
-				apperrors.Show(logger, w, apperrors.Error(
-					"Could not append tag",
-					apperrors.WithCause(err),
-				), nil)
-			}
 			w.Close()
 		} else { // edit existing
 			tagP, err := tagData.Get()
@@ -174,8 +202,6 @@ func NewTagEditor(deps dependencies, a fyne.App, title string, tags *bindings.Da
 
 			logger := logging.With(logger, keys.TagID, tagP.Tag.ID) //nolint:govet // intentional
 
-			var dbTag repository.Tag
-			var dbSkills []repository.TagSkill
 			if err := database.TransactWithRetries(ctx, deps.Telemetry(), logger, deps.DB(), &sql.TxOptions{}, func(ctx context.Context, tx database.Tx) error {
 				tag := *tagP
 
@@ -190,29 +216,25 @@ func NewTagEditor(deps dependencies, a fyne.App, title string, tags *bindings.Da
 					}
 				}
 
-				if dbSkills == nil {
-					dbSkills = make([]repository.TagSkill, 0, len(skills))
-				} else {
-					dbSkills = dbSkills[:0]
+				includeIDMap := make(map[int64]bool, len(includes))
+				for _, tid := range includes {
+					includeIDMap[tid] = true
+				}
+				includesToDelete := make([]int64, 0, len(tag.Includes))
+				for _, inc := range tag.Includes {
+					if !includeIDMap[inc.ID] {
+						includesToDelete = append(includesToDelete, inc.ID)
+					}
 				}
 
-				c := colorSwatch.Color()
-				err = deps.AppRepo().UpdateTag(ctx, tag.Tag.ID, nameInp.Text, c, tx)
-				if err != nil {
+				if err := deps.AppRepo().UpdateTag(ctx, tag.Tag.ID, nameInp.Text, colorSwatch.Color(), tx); err != nil {
 					return errors.Wrap(err, "could not UpdateTag")
 				}
-				tag.Tag.Name = nameInp.Text
-				cr, cg, cb, _ := c.RGBA()
-				tag.Tag.ColorR = int64(cr)
-				tag.Tag.ColorG = int64(cg)
-				tag.Tag.ColorB = int64(cb)
 
 				for _, sd := range skills {
-					sk, err := deps.AppRepo().UpsertTagSkill(ctx, tag.Tag.ID, sd.SkillID, sd.SkillLevel, tx)
-					if err != nil {
+					if _, err := deps.AppRepo().UpsertTagSkill(ctx, tag.Tag.ID, sd.SkillID, sd.SkillLevel, sd.RecommendedLevel, tx); err != nil {
 						return errors.Wrap(err, "could not UpsertTagSkill", keys.SkillID, sd.SkillID, keys.SkillLevel, sd.SkillLevel)
 					}
-					dbSkills = append(dbSkills, sk)
 				}
 
 				if len(toDelete) > 0 {
@@ -221,26 +243,28 @@ func NewTagEditor(deps dependencies, a fyne.App, title string, tags *bindings.Da
 					}
 				}
 
-				dbTag = tag.Tag
+				// removals first, so dropping an include can make room for one that
+				// would otherwise look like a cycle
+				if len(includesToDelete) > 0 {
+					if err := deps.AppRepo().DeleteTagIncludes(ctx, tag.Tag.ID, includesToDelete, tx); err != nil {
+						return errors.Wrap(err, "could not DeleteTagIncludes")
+					}
+				}
+
+				for _, tid := range includes {
+					if err := deps.AppRepo().UpsertTagInclude(ctx, tag.Tag.ID, tid, tx); err != nil {
+						return errors.Wrap(err, "could not UpsertTagInclude", keys.IncludedTagID, tid)
+					}
+				}
 
 				return nil
 			}); err != nil {
 				apperrors.Show(logger, w, apperrors.Error(
-					"Could not save tag",
+					saveErrorMessage("Could not save tag", err),
 					apperrors.WithCause(err),
 				), nil)
 				return
 			}
-
-			if err := tagData.Set(&repository.TagDBData{
-				Tag:    dbTag,
-				Skills: dbSkills,
-			}); err != nil {
-				apperrors.Show(logger, w, apperrors.Error(
-					"Could not set tag",
-					apperrors.WithCause(err),
-				), nil)
-			}
 			w.Close()
 		}
 	}
@@ -249,73 +273,3 @@ func NewTagEditor(deps dependencies, a fyne.App, title string, tags *bindings.Da
 
 	return w
 }
-
-type SkillData struct {
-	SkillID    int64
-	SkillLevel int64
-}
-
-var (
-	skillRegexp         = regexp.MustCompile(`^(.*\D)(\s\d)?$`)
-	ErrMalformedLine    = errors.New("malformed line")
-	ErrInvalidSkillName = errors.New("invalid skill name")
-)
-
-func ParseSkills(ctx context.Context, static repository.StaticData, text string) (skills []SkillData, err error) {
-	scanner := bufio.NewScanner(strings.NewReader(text))
-
-	var errs error
-
-	var line string
-	for scanner.Scan() {
-		line = strings.TrimSpace(scanner.Text())
-		if strings.HasPrefix(line, "#") || line == "" {
-			continue
-		}
-
-		m := skillRegexp.FindStringSubmatch(line)
-		if m == nil {
-			errs = multierror.Append(errs, errors.Wrap(ErrMalformedLine, "could not parse line", "line", line))
-			continue
-		}
-
-		skillName := strings.TrimSpace(m[1])
-		skillLevelStr := strings.TrimSpace(m[2])
-
-		var skillLevel int64
-		if skillLevelStr == "" {
-			skillLevel = 1
-		} else {
-			skillLevel, err = strconv.ParseInt(skillLevelStr, 10, 64)
-			if err != nil {
-				errs = multierror.Append(errs, errors.Wrap(err, "could not convert skill level to int64", "level", skillLevelStr))
-				continue
-			}
-		}
-
-		skillID, err := static.GetSkillIDByName(ctx, skillName, nil)
-		if err != nil {
-			errs = multierror.Append(errs, errors.Wrap(ErrInvalidSkillName, "could not find skill", "skill_name", skillName))
-			continue
-		}
-
-		skills = append(skills, SkillData{
-			SkillID:    skillID,
-			SkillLevel: skillLevel,
-		})
-	}
-
-	maxLevels := make(map[int64]SkillData, len(skills))
-	for _, sk := range skills {
-		if sk.SkillLevel >= maxLevels[sk.SkillID].SkillLevel {
-			maxLevels[sk.SkillID] = sk
-		}
-	}
-
-	skills = skills[:0]
-	for _, sk := range maxLevels {
-		skills = append(skills, sk)
-	}
-
-	return skills, errs
-}
@@ -0,0 +1,56 @@
+package tags
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/app/bindings"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+func saveErrorMessage(msg string, err error) string {
+	switch {
+	case errors.Is(err, repository.ErrTagCycle):
+		return "A tag cannot include itself, directly or through the tags it includes"
+	case errors.Is(err, database.ErrUniqueViolation):
+		return "A tag with that name already exists"
+	}
+	return msg
+}
+
+// includeOptions lists the tags that tag could include, keyed by a label that
+// is unique even if two tags share a name
+func includeOptions(tags *bindings.DataList[*repository.TagDBData], tagID int64) ([]string, map[string]int64, error) {
+	list, err := tags.Get()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not load tag list data")
+	}
+
+	nameCount := make(map[string]int, len(list))
+	for _, t := range list {
+		if t != nil && t.Tag.ID != 0 {
+			nameCount[t.Tag.Name]++
+		}
+	}
+
+	labels := make([]string, 0, len(list))
+	ids := make(map[string]int64, len(list))
+	for _, t := range list {
+		if t == nil || t.Tag.ID == 0 || t.Tag.ID == tagID {
+			continue
+		}
+
+		label := t.Tag.Name
+		if nameCount[label] > 1 {
+			label = fmt.Sprintf("%s (#%d)", t.Tag.Name, t.Tag.ID)
+		}
+		labels = append(labels, label)
+		ids[label] = t.Tag.ID
+	}
+	sort.Strings(labels)
+
+	return labels, ids, nil
+}
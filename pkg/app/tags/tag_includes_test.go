@@ -0,0 +1,49 @@
+package tags
+
+import (
+	"context"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kava-forge/eve-alts/migrations"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/testhelpers"
+)
+
+type dbDependencies struct {
+	*testhelpers.TestDependencies
+	db database.Connection
+}
+
+func (d dbDependencies) DB() database.Connection { return d.db }
+
+func TestSaveErrorMessage_DuplicateName(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db, err := database.Open(ctx, filepath.Join(t.TempDir(), "database.db"), database.DefaultPragmas())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close(ctx) })
+	require.NoError(t, db.Migrate(ctx, migrations.Migrations))
+
+	repo := repository.NewAppData(dbDependencies{TestDependencies: testhelpers.NewTestDependencies(t), db: db})
+
+	_, err = repo.InsertTag(ctx, "Logi", color.Black, nil)
+	require.NoError(t, err)
+	other, err := repo.InsertTag(ctx, "Tackle", color.Black, nil)
+	require.NoError(t, err)
+
+	_, err = repo.InsertTag(ctx, "Logi", color.White, nil)
+	assert.Equal(t, "A tag with that name already exists", saveErrorMessage("Could not create tag", err))
+
+	err = repo.UpdateTag(ctx, other.ID, "Logi", color.Black, nil)
+	assert.Equal(t, "A tag with that name already exists", saveErrorMessage("Could not save tag", err))
+
+	err = repo.UpdateTag(ctx, other.ID, "Scout", color.Black, nil)
+	assert.NoError(t, err)
+}
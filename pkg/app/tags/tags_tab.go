@@ -1,18 +1,22 @@
 package tags
 
 import (
+	"context"
 	"fmt"
+	"slices"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/layout"
 
+	"github.com/kava-forge/eve-alts/lib/errors"
 	"github.com/kava-forge/eve-alts/lib/logging"
 	"github.com/kava-forge/eve-alts/lib/logging/level"
 
 	"github.com/kava-forge/eve-alts/pkg/app/apperrors"
 	"github.com/kava-forge/eve-alts/pkg/app/bindings"
+	"github.com/kava-forge/eve-alts/pkg/database"
 	"github.com/kava-forge/eve-alts/pkg/keys"
 	"github.com/kava-forge/eve-alts/pkg/repository"
 )
@@ -73,25 +77,7 @@ func NewTagsTab(deps dependencies, parent fyne.Window) (fyne.CanvasObject, *bind
 				continue
 			}
 
-			cc := NewTagCard(deps, parent, tagsData.Child(i), func(c *TagCard) {
-				level.Debug(logger).Message("removing tag card")
-				tag, err := tagsData.GetValue(i) //nolint:govet // intentional
-				if err != nil {
-					apperrors.Show(logger, c.Parent(), apperrors.Error(
-						"Could not find tag data",
-						apperrors.WithCause(err),
-					), nil)
-					return
-				}
-				tag.Tag.ID = 0
-				if err := tagsData.SetValue(i, tag); err != nil {
-					apperrors.Show(logger, c.Parent(), apperrors.Error(
-						"Could not delete tag",
-						apperrors.WithCause(err),
-					), nil)
-				}
-				ctn.Remove(c)
-			}, func(tagData bindings.DataProxy[*repository.TagDBData], onClose func()) {
+			cc := NewTagCard(deps, parent, tagsData, tagsData.Child(i), func(tagData bindings.DataProxy[*repository.TagDBData], onClose func()) {
 				w := NewTagEditor(deps, fyne.CurrentApp(), "Edit Tag", tagsData, tagData, onClose)
 				w.Show()
 			})
@@ -105,7 +91,51 @@ func NewTagsTab(deps dependencies, parent fyne.Window) (fyne.CanvasObject, *bind
 		}
 	}))
 
+	// saving or deleting one tag changes the includes and inherited skills
+	// of every tag that includes it, so the whole list is loaded again
+	deps.AppRepo().Events().Subscribe(func(ev repository.ChangeEvent) {
+		if ev.Entity != repository.EntityTag {
+			return
+		}
+
+		level.Debug(logger).Message("tag changed", keys.TagID, ev.ID, "change", ev.Change)
+
+		if err := syncTags(context.Background(), deps, tagsData); err != nil {
+			apperrors.Show(logger, parent, apperrors.Error(
+				"Could not load tags data",
+				apperrors.WithCause(err),
+			), nil)
+		}
+
+		for _, o := range slices.Clone(ctn.Objects) {
+			if c, ok := o.(*TagCard); ok && c.TagID() == 0 {
+				ctn.Remove(c)
+			}
+		}
+	})
+
 	ctn.Add(NewAddTagButton(deps, tagsData))
 
 	return container.NewVScroll(ctn), tagsData
 }
+
+func tagID(t *repository.TagDBData) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.Tag.ID
+}
+
+// syncTags makes the tag list match the database
+func syncTags(ctx context.Context, deps dependencies, tagsData *bindings.DataList[*repository.TagDBData]) error {
+	dbTags, err := deps.AppRepo().GetAllTags(ctx, nil)
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return errors.Wrap(err, "could not GetAllTags")
+	}
+
+	return errors.Wrap(bindings.Replace(tagsData, dbTags, tagID, func(t *repository.TagDBData) *repository.TagDBData {
+		removed := *t
+		removed.Tag.ID = 0
+		return &removed
+	}), "could not set tags")
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"context"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+	"github.com/kava-forge/eve-alts/lib/logging"
+	"github.com/kava-forge/eve-alts/lib/logging/level"
+
+	"github.com/kava-forge/eve-alts/pkg/app/apperrors"
+	"github.com/kava-forge/eve-alts/pkg/keys"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+// unlockVault unlocks the token vault in key file mode. In passphrase mode it
+// only reports whether the vault has been set up, and showUnlock asks for
+// the passphrase once the window is up. Like a skipped passphrase, a key
+// file that is missing or wrong leaves the vault locked rather than keeping
+// the app from starting; showKeyFileProblem says why.
+func unlockVault(ctx context.Context, deps dependencies) (setUp bool, err error) {
+	src := deps.VaultSource()
+	if src.Mode != repository.VaultModePassphrase {
+		return true, errors.Wrap(deps.AppRepo().UnlockVault(ctx, src), "could not unlock the token vault")
+	}
+
+	status, err := deps.AppRepo().VaultStatus(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "could not check the token vault")
+	}
+	return status.Mode != "", nil
+}
+
+// keyFileProblem reports whether err means the key file cannot unlock the
+// vault, which the app can run without
+func keyFileProblem(err error) bool {
+	return errors.Is(err, repository.ErrKeyFileMissing) ||
+		errors.Is(err, repository.ErrBadKeyFile) ||
+		errors.Is(err, repository.ErrWrongVaultKey)
+}
+
+// showKeyFileProblem explains why the vault stayed locked in key file mode
+func showKeyFileProblem(deps dependencies, parent fyne.Window, err error) {
+	logger := logging.With(deps.Logger(), keys.Component, "UnlockVault")
+
+	msg := "The token vault key file does not match the stored tokens."
+	if errors.Is(err, repository.ErrKeyFileMissing) {
+		msg = "The token vault key file is missing."
+	}
+	msg += " Characters cannot be refreshed or added until it is restored, or until vault reset deletes the stored tokens."
+	apperrors.Show(logger, parent, apperrors.Error(msg, apperrors.WithCause(err)), nil)
+}
+
+// showUnlock asks for the vault passphrase until it is right or the user
+// skips it. A vault that is not set up yet asks for a new passphrase twice.
+// Characters cannot be refreshed or added while the vault is locked.
+func showUnlock(deps dependencies, parent fyne.Window, setUp bool) {
+	logger := logging.With(deps.Logger(), keys.Component, "UnlockVault")
+
+	passInp := widget.NewPasswordEntry()
+	items := []*widget.FormItem{widget.NewFormItem("Passphrase", passInp)}
+
+	title := "Unlock Token Vault"
+	confirmInp := widget.NewPasswordEntry()
+	if !setUp {
+		title = "Choose a Token Vault Passphrase"
+		items = append(items, widget.NewFormItem("Repeat", confirmInp))
+	}
+
+	d := dialog.NewForm(title, "Unlock", "Skip", items, func(ok bool) {
+		if !ok {
+			level.Info(logger).Message("token vault left locked")
+			return
+		}
+
+		retry := func() { showUnlock(deps, parent, setUp) }
+
+		if !setUp && passInp.Text != confirmInp.Text {
+			apperrors.Show(logger, parent, apperrors.Error("The passphrases do not match"), retry)
+			return
+		}
+
+		src := deps.VaultSource()
+		src.Passphrase = passInp.Text
+		if err := deps.AppRepo().UnlockVault(context.Background(), src); err != nil {
+			msg := "Could not unlock the token vault"
+			if errors.Is(err, repository.ErrWrongVaultKey) {
+				msg = "Wrong passphrase"
+			}
+			apperrors.Show(logger, parent, apperrors.Error(msg, apperrors.WithCause(err)), retry)
+			return
+		}
+		level.Debug(logger).Message("token vault unlocked")
+	}, parent)
+	d.Resize(fyne.Size{Width: 400, Height: 200})
+	d.Show()
+}
@@ -0,0 +1,179 @@
+package bundles
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+	"github.com/kava-forge/eve-alts/lib/json"
+
+	"github.com/kava-forge/eve-alts/pkg/conditions"
+	"github.com/kava-forge/eve-alts/pkg/operators"
+)
+
+// Version is the bundle format version written by this build. Bundles with a
+// newer version are rejected, older versions are upgraded on decode.
+const Version = 1
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported bundle version")
+	ErrUnknownFormat      = errors.New("unknown bundle format")
+	ErrInvalidColor       = errors.New("invalid color")
+)
+
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatTOML Format = "toml"
+)
+
+// FormatFromPath picks the bundle format from a file extension
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".toml":
+		return FormatTOML, nil
+	default:
+		return "", errors.Wrap(ErrUnknownFormat, "bundle files must end in .json or .toml", "path", path)
+	}
+}
+
+// Bundle is a portable collection of tag and role definitions. Skills are
+// referenced by type ID; names are only there for people reading the file.
+// Roles reference tags by name.
+type Bundle struct {
+	Version int    `json:"version" toml:"version"`
+	Tags    []Tag  `json:"tags" toml:"tags"`
+	Roles   []Role `json:"roles,omitempty" toml:"roles,omitempty"`
+}
+
+type Tag struct {
+	Name   string  `json:"name" toml:"name"`
+	Color  string  `json:"color" toml:"color"`
+	Skills []Skill `json:"skills" toml:"skills"`
+	// Includes names other tags whose skills this tag also requires
+	Includes []string `json:"includes,omitempty" toml:"includes,omitempty"`
+}
+
+type Skill struct {
+	ID    int64  `json:"id" toml:"id"`
+	Name  string `json:"name,omitempty" toml:"name,omitempty"`
+	Level int64  `json:"level" toml:"level"`
+	// Recommended is an optional level above Level; 0 means none
+	Recommended int64 `json:"recommended,omitempty" toml:"recommended,omitempty"`
+}
+
+// Role is also the top level group of the role's requirements: Operator
+// applies to Tags and Groups together
+type Role struct {
+	Name     string             `json:"name" toml:"name"`
+	Label    string             `json:"label" toml:"label"`
+	Operator operators.Operator `json:"operator" toml:"operator"`
+	// Count is how many tags and groups an "atleast" role needs
+	Count  int64    `json:"count,omitempty" toml:"count,omitempty"`
+	Color  string   `json:"color" toml:"color"`
+	Tags   []string `json:"tags" toml:"tags"`
+	Groups []Group  `json:"groups,omitempty" toml:"groups,omitempty"`
+	// Conditions must all hold alongside the tag requirements
+	Conditions []Condition `json:"conditions,omitempty" toml:"conditions,omitempty"`
+}
+
+// Condition is a non-skill requirement of a role. Value is the corporation or
+// alliance ID, or the SP for min_sp; Label names the corporation or alliance.
+type Condition struct {
+	Kind  conditions.Kind `json:"kind" toml:"kind"`
+	Value int64           `json:"value,omitempty" toml:"value,omitempty"`
+	Label string          `json:"label,omitempty" toml:"label,omitempty"`
+}
+
+// Group is a nested group of role requirements
+type Group struct {
+	Operator operators.Operator `json:"operator" toml:"operator"`
+	Count    int64              `json:"count,omitempty" toml:"count,omitempty"`
+	Tags     []string           `json:"tags,omitempty" toml:"tags,omitempty"`
+	Groups   []Group            `json:"groups,omitempty" toml:"groups,omitempty"`
+}
+
+func Encode(w io.Writer, b Bundle, f Format) error {
+	var data []byte
+	var err error
+
+	switch f {
+	case FormatJSON:
+		data, err = json.MarshalIndent(b, "", "  ")
+	case FormatTOML:
+		data, err = toml.Marshal(b)
+	default:
+		return errors.Wrap(ErrUnknownFormat, "could not encode bundle", "format", f)
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not encode bundle", "format", f)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return errors.Wrap(err, "could not write bundle")
+	}
+
+	return nil
+}
+
+func Decode(r io.Reader, f Format) (b Bundle, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return b, errors.Wrap(err, "could not read bundle")
+	}
+
+	switch f {
+	case FormatJSON:
+		err = json.Unmarshal(data, &b)
+	case FormatTOML:
+		err = toml.NewDecoder(bytes.NewReader(data)).DisallowUnknownFields().Decode(&b)
+	default:
+		return b, errors.Wrap(ErrUnknownFormat, "could not decode bundle", "format", f)
+	}
+	if err != nil {
+		return b, errors.Wrap(err, "could not decode bundle", "format", f)
+	}
+
+	if b.Version < 1 || b.Version > Version {
+		return b, errors.Wrap(ErrUnsupportedVersion, "could not decode bundle", "version", b.Version, "supported", Version)
+	}
+	b.Version = Version
+
+	return b, nil
+}
+
+// EncodeColor formats a color as #rrggbbaa
+func EncodeColor(c color.Color) string {
+	nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x%02x", nc.R, nc.G, nc.B, nc.A)
+}
+
+// DecodeColor parses #rrggbb or #rrggbbaa
+func DecodeColor(s string) (color.Color, error) {
+	var c color.NRGBA
+
+	switch len(s) {
+	case 7:
+		c.A = 0xff
+		if _, err := fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B); err != nil {
+			return nil, errors.Wrap(ErrInvalidColor, "could not parse color", "color", s)
+		}
+	case 9:
+		if _, err := fmt.Sscanf(s, "#%02x%02x%02x%02x", &c.R, &c.G, &c.B, &c.A); err != nil {
+			return nil, errors.Wrap(ErrInvalidColor, "could not parse color", "color", s)
+		}
+	default:
+		return nil, errors.Wrap(ErrInvalidColor, "could not parse color", "color", s)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,332 @@
+package bundles_test
+
+import (
+	"bytes"
+	"context"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kava-forge/eve-alts/pkg/bundles"
+	"github.com/kava-forge/eve-alts/pkg/conditions"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/operators"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/testhelpers"
+)
+
+var testBundle = bundles.Bundle{
+	Version: bundles.Version,
+	Tags: []bundles.Tag{
+		{
+			Name:  "Logi Cruiser",
+			Color: "#00ff00ff",
+			Skills: []bundles.Skill{
+				{ID: 12096, Name: "Logistics Cruisers", Level: 4},
+				{ID: 3416, Name: "Shield Emission Systems", Level: 4, Recommended: 5},
+			},
+			Includes: []string{"Cap"},
+		},
+		{
+			Name:   "Cap",
+			Color:  "#0000ff80",
+			Skills: []bundles.Skill{{ID: 3418, Level: 5}},
+		},
+	},
+	Roles: []bundles.Role{
+		{
+			Name:     "Logi",
+			Label:    "L",
+			Operator: operators.OperatorAll,
+			Color:    "#ffffffff",
+			Tags:     []string{"Logi Cruiser", "Cap"},
+			Groups: []bundles.Group{{
+				Operator: operators.OperatorNone,
+				Groups:   []bundles.Group{{Operator: operators.OperatorAtleast, Count: 1, Tags: []string{"Cap"}}},
+			}},
+			Conditions: []bundles.Condition{{Kind: conditions.KindMinSp, Value: 20_000_000}},
+		},
+	},
+}
+
+func TestEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []bundles.Format{bundles.FormatJSON, bundles.FormatTOML} {
+		f := f
+		t.Run(string(f), func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			if !assert.NoError(t, bundles.Encode(buf, testBundle, f)) {
+				return
+			}
+
+			got, err := bundles.Decode(buf, f)
+			assert.NoError(t, err)
+			assert.Equal(t, testBundle, got)
+		})
+	}
+}
+
+func TestDecode_Version(t *testing.T) {
+	t.Parallel()
+
+	_, err := bundles.Decode(bytes.NewBufferString(`{"version": 99, "tags": []}`), bundles.FormatJSON)
+	assert.ErrorIs(t, err, bundles.ErrUnsupportedVersion)
+
+	_, err = bundles.Decode(bytes.NewBufferString(`{"tags": []}`), bundles.FormatJSON)
+	assert.ErrorIs(t, err, bundles.ErrUnsupportedVersion)
+}
+
+func TestColorRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	c, err := bundles.DecodeColor("#12345678")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "#12345678", bundles.EncodeColor(c))
+	}
+
+	c, err = bundles.DecodeColor("#123456")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "#123456ff", bundles.EncodeColor(c))
+	}
+
+	_, err = bundles.DecodeColor("red")
+	assert.ErrorIs(t, err, bundles.ErrInvalidColor)
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	deps := testhelpers.NewTestDependencies(t)
+	deps.TestStaticRepo.BatchGetSkillNamesReturns([]repository.BatchGetSkillNamesRow{
+		{SkillID: 3300, SkillName: "Gunnery"},
+	}, nil)
+
+	gunnery := &repository.TagDBData{
+		Tag:    repository.Tag{ID: 1, Name: "Gunnery", ColorR: 0xffff, ColorA: 0xffff},
+		Skills: []repository.TagSkill{{TagID: 1, SkillID: 3300, SkillLevel: 5}},
+	}
+	other := &repository.TagDBData{
+		Tag: repository.Tag{ID: 2, Name: "Other", ColorA: 0xffff},
+	}
+	base := &repository.TagDBData{
+		Tag: repository.Tag{ID: 3, Name: "Base", ColorA: 0xffff},
+	}
+	gunnery.Includes = []repository.Tag{base.Tag}
+	role := &repository.RoleDBData{
+		Role: repository.Role{ID: 1, Name: "DPS", Label: "D", ColorA: 0xffff},
+		Expr: repository.NewRoleGroup(operators.OperatorAny,
+			repository.NewRoleLeaf(gunnery.Tag.ID),
+			repository.NewRoleGroup(operators.OperatorNone, repository.NewRoleLeaf(other.Tag.ID)),
+		),
+		Tags:       []repository.Tag{gunnery.Tag, other.Tag},
+		Conditions: []repository.RoleCondition{{Kind: "corporation", Value: 98000001, Label: "Corp [C]"}},
+	}
+
+	// only the role is selected, but its tags and what they include come along
+	b, err := bundles.Export(context.Background(), deps.StaticRepo(), []*repository.TagDBData{gunnery, other, base}, nil, []*repository.RoleDBData{role})
+	assert.NoError(t, err)
+	assert.Equal(t, bundles.Bundle{
+		Version: bundles.Version,
+		Tags: []bundles.Tag{
+			{
+				Name:     "Gunnery",
+				Color:    "#ff0000ff",
+				Skills:   []bundles.Skill{{ID: 3300, Name: "Gunnery", Level: 5}},
+				Includes: []string{"Base"},
+			},
+			{
+				Name:   "Base",
+				Color:  "#000000ff",
+				Skills: []bundles.Skill{},
+			},
+			{
+				Name:   "Other",
+				Color:  "#000000ff",
+				Skills: []bundles.Skill{},
+			},
+		},
+		Roles: []bundles.Role{{
+			Name:     "DPS",
+			Label:    "D",
+			Operator: operators.OperatorAny,
+			Color:    "#000000ff",
+			Tags:     []string{"Gunnery"},
+			Groups:   []bundles.Group{{Operator: operators.OperatorNone, Tags: []string{"Other"}}},
+			Conditions: []bundles.Condition{
+				{Kind: conditions.KindCorporation, Value: 98000001, Label: "Corp [C]"},
+			},
+		}},
+	}, b)
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		policy        bundles.ConflictPolicy
+		want          bundles.ImportResult
+		wantInserted  []string
+		wantSkillsSet int
+		wantIncludes  int
+	}{
+		{
+			name:   "skip",
+			policy: bundles.ConflictSkip,
+			want: bundles.ImportResult{
+				TagsCreated:  []string{"Cap"},
+				TagsSkipped:  []string{"Logi Cruiser"},
+				RolesCreated: []string{"Logi"},
+			},
+			wantInserted:  []string{"Cap"},
+			wantSkillsSet: 1,
+			// the existing Logi Cruiser is left alone
+			wantIncludes: 0,
+		},
+		{
+			name:   "rename",
+			policy: bundles.ConflictRename,
+			want: bundles.ImportResult{
+				TagsCreated:  []string{"Logi Cruiser (2)", "Cap"},
+				RolesCreated: []string{"Logi"},
+			},
+			wantInserted:  []string{"Logi Cruiser (2)", "Cap"},
+			wantSkillsSet: 3,
+			wantIncludes:  1,
+		},
+		{
+			name:   "merge",
+			policy: bundles.ConflictMerge,
+			want: bundles.ImportResult{
+				TagsCreated:  []string{"Cap"},
+				TagsMerged:   []string{"Logi Cruiser"},
+				RolesCreated: []string{"Logi"},
+			},
+			wantInserted: []string{"Cap"},
+			// Logistics Cruisers is already at 5, only Shield Emission Systems is added
+			wantSkillsSet: 2,
+			wantIncludes:  1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			deps := testhelpers.NewTestDependencies(t)
+			deps.TestAppRepo.GetAllTagsReturns([]*repository.TagDBData{{
+				Tag:    repository.Tag{ID: 1, Name: "Logi Cruiser"},
+				Skills: []repository.TagSkill{{TagID: 1, SkillID: 12096, SkillLevel: 5}},
+			}}, nil)
+			deps.TestAppRepo.GetAllRolesReturns(nil, database.ErrNoRows)
+
+			nextID := int64(10)
+			var inserted []string
+			deps.TestAppRepo.InsertTagCalls(func(ctx context.Context, name string, c color.Color, tx database.Tx) (repository.Tag, error) {
+				nextID++
+				inserted = append(inserted, name)
+				return repository.Tag{ID: nextID, Name: name}, nil
+			})
+
+			res, err := bundles.Import(context.Background(), deps, testBundle, tt.policy)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, res)
+			assert.Equal(t, tt.wantInserted, inserted)
+			assert.Equal(t, tt.wantSkillsSet, deps.TestAppRepo.UpsertTagSkillCallCount())
+			assert.Equal(t, tt.wantIncludes, deps.TestAppRepo.UpsertTagIncludeCallCount())
+			if assert.Equal(t, 1, deps.TestAppRepo.SetRoleExprCallCount()) {
+				_, _, expr, _ := deps.TestAppRepo.SetRoleExprArgsForCall(0)
+				assert.Equal(t, operators.OperatorAll, expr.Operator)
+				assert.Len(t, expr.Children, 3)
+				assert.Len(t, expr.TagIDs(), 2)
+			}
+			if assert.Equal(t, 1, deps.TestAppRepo.SetRoleConditionsCallCount()) {
+				_, _, conds, _ := deps.TestAppRepo.SetRoleConditionsArgsForCall(0)
+				assert.Equal(t, []repository.RoleCondition{{Kind: "min_sp", Value: 20_000_000}}, conds)
+			}
+			assert.Equal(t, 1, deps.TestDB.BeginTxCallCount())
+		})
+	}
+}
+
+func TestImport_InvalidCount(t *testing.T) {
+	t.Parallel()
+
+	deps := testhelpers.NewTestDependencies(t)
+
+	b := bundles.Bundle{
+		Version: bundles.Version,
+		Roles: []bundles.Role{{
+			Name:     "Guns",
+			Operator: operators.OperatorAtleast,
+			Count:    3,
+			Color:    "#ffffffff",
+			Tags:     []string{"Hybrids", "Projectiles"},
+		}},
+	}
+
+	_, err := bundles.Import(context.Background(), deps, b, bundles.ConflictSkip)
+	assert.ErrorIs(t, err, repository.ErrInvalidRoleExpr)
+	// rejected before touching the database
+	assert.Equal(t, 0, deps.TestDB.BeginTxCallCount())
+}
+
+func TestImport_InvalidCondition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cond bundles.Condition
+	}{
+		{"unknown kind", bundles.Condition{Kind: "standing"}},
+		{"corporation without id", bundles.Condition{Kind: conditions.KindCorporation, Label: "Corp"}},
+		{"negative sp", bundles.Condition{Kind: conditions.KindMinSp, Value: -1}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			deps := testhelpers.NewTestDependencies(t)
+
+			b := bundles.Bundle{
+				Version: bundles.Version,
+				Roles: []bundles.Role{{
+					Name:       "Guns",
+					Operator:   operators.OperatorAll,
+					Color:      "#ffffffff",
+					Conditions: []bundles.Condition{tt.cond},
+				}},
+			}
+
+			_, err := bundles.Import(context.Background(), deps, b, bundles.ConflictSkip)
+			assert.Error(t, err)
+			assert.Equal(t, 0, deps.TestDB.BeginTxCallCount())
+		})
+	}
+}
+
+func TestImport_UnknownTag(t *testing.T) {
+	t.Parallel()
+
+	deps := testhelpers.NewTestDependencies(t)
+
+	b := bundles.Bundle{
+		Version: bundles.Version,
+		Roles: []bundles.Role{{
+			Name:     "Logi",
+			Operator: operators.OperatorAll,
+			Color:    "#ffffffff",
+			Tags:     []string{"Nope"},
+		}},
+	}
+
+	_, err := bundles.Import(context.Background(), deps, b, bundles.ConflictSkip)
+	assert.ErrorIs(t, err, bundles.ErrUnknownTag)
+	// not worth retrying
+	assert.Equal(t, 1, deps.TestDB.BeginTxCallCount())
+}
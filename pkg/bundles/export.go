@@ -0,0 +1,158 @@
+package bundles
+
+import (
+	"context"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/conditions"
+	"github.com/kava-forge/eve-alts/pkg/operators"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+// Export builds a bundle from the given tags and roles. Tags used by the roles,
+// and tags included by other tags, are always exported, even if they were not
+// selected, so the bundle is self-contained.
+func Export(ctx context.Context, static repository.StaticData, allTags []*repository.TagDBData, tags []*repository.TagDBData, roles []*repository.RoleDBData) (Bundle, error) {
+	b := Bundle{
+		Version: Version,
+		Tags:    make([]Tag, 0, len(tags)),
+		Roles:   make([]Role, 0, len(roles)),
+	}
+
+	tagsByID := make(map[int64]*repository.TagDBData, len(allTags))
+	for _, t := range allTags {
+		tagsByID[t.Tag.ID] = t
+	}
+
+	selected := make(map[int64]bool, len(tags))
+	toExport := make([]*repository.TagDBData, 0, len(tags))
+	var addTag func(t *repository.TagDBData) error
+	addTag = func(t *repository.TagDBData) error {
+		if selected[t.Tag.ID] {
+			return nil
+		}
+		selected[t.Tag.ID] = true
+		toExport = append(toExport, t)
+
+		for _, inc := range t.Includes {
+			it, ok := tagsByID[inc.ID]
+			if !ok {
+				return errors.Wrap(ErrUnknownTag, "tag includes a tag that does not exist", "tag", t.Tag.Name, "tag_id", inc.ID)
+			}
+			if err := addTag(it); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, t := range tags {
+		if err := addTag(t); err != nil {
+			return b, err
+		}
+	}
+
+	for _, r := range roles {
+		g, err := exportGroup(r.Expr, func(tagID int64) (string, error) {
+			t, ok := tagsByID[tagID]
+			if !ok {
+				return "", errors.Wrap(ErrUnknownTag, "role references a tag that does not exist", "role", r.Role.Name, "tag_id", tagID)
+			}
+			if err := addTag(t); err != nil {
+				return "", err
+			}
+			return t.Tag.Name, nil
+		})
+		if err != nil {
+			return b, err
+		}
+
+		var conds []Condition
+		for _, c := range r.Conditions {
+			conds = append(conds, Condition{Kind: conditions.Kind(c.Kind), Value: c.Value, Label: c.Label})
+		}
+
+		b.Roles = append(b.Roles, Role{
+			Name:       r.Role.Name,
+			Label:      r.Role.Label,
+			Operator:   g.Operator,
+			Count:      g.Count,
+			Color:      EncodeColor(r.Color()),
+			Tags:       g.Tags,
+			Groups:     g.Groups,
+			Conditions: conds,
+		})
+	}
+
+	var skillIDs []int64
+	for _, t := range toExport {
+		for _, sk := range t.Skills {
+			skillIDs = append(skillIDs, sk.SkillID)
+		}
+	}
+
+	nameMap := map[int64]string{}
+	if len(skillIDs) > 0 {
+		rows, err := static.BatchGetSkillNames(ctx, skillIDs, nil)
+		if err != nil {
+			return b, errors.Wrap(err, "could not fetch skill names")
+		}
+		for _, row := range rows {
+			nameMap[row.SkillID] = row.SkillName
+		}
+	}
+
+	for _, t := range toExport {
+		tag := Tag{
+			Name:   t.Tag.Name,
+			Color:  EncodeColor(t.Color()),
+			Skills: make([]Skill, 0, len(t.Skills)),
+		}
+		for _, sk := range t.Skills {
+			tag.Skills = append(tag.Skills, Skill{
+				ID:          sk.SkillID,
+				Name:        nameMap[sk.SkillID],
+				Level:       sk.SkillLevel,
+				Recommended: sk.RecommendedLevel,
+			})
+		}
+		for _, inc := range t.Includes {
+			tag.Includes = append(tag.Includes, inc.Name)
+		}
+		b.Tags = append(b.Tags, tag)
+	}
+
+	return b, nil
+}
+
+// exportGroup converts a role expression to a bundle group, naming each tag
+// through tagName
+func exportGroup(e *repository.RoleExpr, tagName func(int64) (string, error)) (Group, error) {
+	g := Group{Tags: make([]string, 0)}
+	if e == nil {
+		g.Operator = operators.OperatorAll
+		return g, nil
+	}
+
+	g.Operator = e.Operator
+	g.Count = e.MinCount
+	for _, ch := range e.Children {
+		if ch.IsLeaf() {
+			name, err := tagName(ch.TagID)
+			if err != nil {
+				return g, err
+			}
+			g.Tags = append(g.Tags, name)
+			continue
+		}
+
+		sub, err := exportGroup(ch, tagName)
+		if err != nil {
+			return g, err
+		}
+		g.Groups = append(g.Groups, sub)
+	}
+
+	return g, nil
+}
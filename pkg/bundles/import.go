@@ -0,0 +1,469 @@
+package bundles
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+	"github.com/kava-forge/eve-alts/lib/logging"
+	"github.com/kava-forge/eve-alts/lib/logging/level"
+
+	"github.com/kava-forge/eve-alts/pkg/conditions"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/keys"
+	"github.com/kava-forge/eve-alts/pkg/operators"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/telemetry"
+)
+
+var (
+	ErrUnknownTag        = errors.New("unknown tag")
+	ErrUnknownConflict   = errors.New("unknown conflict policy")
+	ErrInvalidSkillLevel = errors.New("invalid skill level")
+	ErrInvalidCondition  = errors.New("invalid role condition")
+)
+
+// ConflictPolicy decides what happens when a bundle tag or role has the same
+// name as one that already exists
+type ConflictPolicy string
+
+const (
+	// ConflictSkip keeps the existing definition; roles in the bundle use the existing tag
+	ConflictSkip ConflictPolicy = "skip"
+	// ConflictRename imports the bundle definition under a new name, e.g. "Logi (2)"
+	ConflictRename ConflictPolicy = "rename"
+	// ConflictMerge adds the bundle's skills (or tags, for roles) to the existing
+	// definition, keeping the higher level where both list a skill
+	ConflictMerge ConflictPolicy = "merge"
+)
+
+func ConflictPolicies() []ConflictPolicy {
+	return []ConflictPolicy{ConflictSkip, ConflictRename, ConflictMerge}
+}
+
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	for _, p := range ConflictPolicies() {
+		if string(p) == strings.ToLower(s) {
+			return p, nil
+		}
+	}
+	return "", errors.Wrap(ErrUnknownConflict, "could not parse conflict policy", "policy", s)
+}
+
+// ImportResult lists the names (as stored) of what happened to each bundle entry
+type ImportResult struct {
+	TagsCreated  []string
+	TagsMerged   []string
+	TagsSkipped  []string
+	RolesCreated []string
+	RolesMerged  []string
+	RolesSkipped []string
+}
+
+func (r ImportResult) String() string {
+	var sb strings.Builder
+	write := func(kind string, names []string) {
+		if len(names) > 0 {
+			fmt.Fprintf(&sb, "%s: %s\n", kind, strings.Join(names, ", "))
+		}
+	}
+	write("Tags created", r.TagsCreated)
+	write("Tags merged", r.TagsMerged)
+	write("Tags skipped", r.TagsSkipped)
+	write("Roles created", r.RolesCreated)
+	write("Roles merged", r.RolesMerged)
+	write("Roles skipped", r.RolesSkipped)
+	return strings.TrimSpace(sb.String())
+}
+
+type dependencies interface {
+	DB() database.Connection
+	Logger() logging.Logger
+	Telemetry() *telemetry.Telemeter
+	AppRepo() repository.AppData
+}
+
+// Import writes the bundle to the app database in a single transaction.
+// Nothing is written if any part of the bundle fails.
+func Import(ctx context.Context, deps dependencies, b Bundle, policy ConflictPolicy) (res ImportResult, err error) {
+	ctx, span := telemetry.StartSpan(ctx, deps.Telemetry(), "bundles", "Import")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := logging.With(deps.Logger(), keys.Component, "bundles.Import")
+
+	if _, err := ParseConflictPolicy(string(policy)); err != nil {
+		return res, err
+	}
+
+	if err := validate(b); err != nil {
+		return res, err
+	}
+
+	err = database.TransactWithRetries(ctx, deps.Telemetry(), logger, deps.DB(), &sql.TxOptions{}, func(ctx context.Context, tx database.Tx) error {
+		res = ImportResult{}
+
+		imp := importer{
+			repo:   deps.AppRepo(),
+			logger: logger,
+			policy: policy,
+			res:    &res,
+		}
+
+		tagIDs, err := imp.importTags(ctx, b.Tags, tx)
+		if err != nil {
+			return err
+		}
+
+		if err := imp.importTagIncludes(ctx, b.Tags, tagIDs, tx); err != nil {
+			return err
+		}
+
+		return imp.importRoles(ctx, b.Roles, tagIDs, tx)
+	})
+	if err != nil {
+		return ImportResult{}, errors.Wrap(err, "could not import bundle")
+	}
+
+	return res, nil
+}
+
+// validate checks everything that doesn't need the database, so that bad
+// bundles fail before a transaction is started
+func validate(b Bundle) error {
+	for _, t := range b.Tags {
+		if strings.TrimSpace(t.Name) == "" {
+			return errors.New("tag with no name")
+		}
+		if _, err := DecodeColor(t.Color); err != nil {
+			return errors.Wrap(err, "invalid tag color", keys.TagName, t.Name)
+		}
+		for _, sk := range t.Skills {
+			if sk.Level < 0 || sk.Level > 5 {
+				return errors.Wrap(ErrInvalidSkillLevel, "skill level must be between 0 and 5", keys.TagName, t.Name, keys.SkillID, sk.ID, keys.SkillLevel, sk.Level)
+			}
+			if sk.Recommended < 0 || sk.Recommended > 5 {
+				return errors.Wrap(ErrInvalidSkillLevel, "recommended level must be between 0 and 5", keys.TagName, t.Name, keys.SkillID, sk.ID, keys.SkillLevel, sk.Recommended)
+			}
+		}
+	}
+
+	for _, r := range b.Roles {
+		if strings.TrimSpace(r.Name) == "" {
+			return errors.New("role with no name")
+		}
+		if _, err := DecodeColor(r.Color); err != nil {
+			return errors.Wrap(err, "invalid role color", keys.RoleName, r.Name)
+		}
+		if err := validateGroup(Group{Operator: r.Operator, Count: r.Count, Tags: r.Tags, Groups: r.Groups}); err != nil {
+			return errors.Wrap(err, "invalid role requirements", keys.RoleName, r.Name)
+		}
+		for _, c := range r.Conditions {
+			if err := validateCondition(c); err != nil {
+				return errors.Wrap(err, "invalid role conditions", keys.RoleName, r.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+type importer struct {
+	repo   repository.AppData
+	logger logging.Logger
+	policy ConflictPolicy
+	res    *ImportResult
+}
+
+// importTags returns the database ID for each bundle tag name
+func (imp *importer) importTags(ctx context.Context, tags []Tag, tx database.Tx) (map[string]int64, error) {
+	existing, err := imp.repo.GetAllTags(ctx, tx)
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return nil, errors.Wrap(err, "could not GetAllTags")
+	}
+
+	byName := make(map[string]*repository.TagDBData, len(existing))
+	for _, t := range existing {
+		byName[t.Tag.Name] = t
+	}
+
+	tagIDs := make(map[string]int64, len(tags))
+
+	for _, t := range tags {
+		c, _ := DecodeColor(t.Color) // checked in validate
+
+		cur, clash := byName[t.Name]
+		switch {
+		case clash && imp.policy == ConflictSkip:
+			level.Debug(imp.logger).Message("skipping existing tag", keys.TagName, t.Name)
+			tagIDs[t.Name] = cur.Tag.ID
+			imp.res.TagsSkipped = append(imp.res.TagsSkipped, t.Name)
+
+		case clash && imp.policy == ConflictMerge:
+			level.Debug(imp.logger).Message("merging into existing tag", keys.TagName, t.Name)
+			curSkills := make(map[int64]repository.TagSkill, len(cur.Skills))
+			for _, sk := range cur.Skills {
+				curSkills[sk.SkillID] = sk
+			}
+			for _, sk := range t.Skills {
+				lvl, rec := sk.Level, sk.Recommended
+				if cs, ok := curSkills[sk.ID]; ok {
+					if cs.SkillLevel >= lvl && cs.RecommendedLevel >= rec {
+						continue
+					}
+					lvl, rec = max(lvl, cs.SkillLevel), max(rec, cs.RecommendedLevel)
+				}
+				if _, err := imp.repo.UpsertTagSkill(ctx, cur.Tag.ID, sk.ID, lvl, rec, tx); err != nil {
+					return nil, errors.Wrap(err, "could not UpsertTagSkill", keys.TagName, t.Name, keys.SkillID, sk.ID)
+				}
+			}
+			tagIDs[t.Name] = cur.Tag.ID
+			imp.res.TagsMerged = append(imp.res.TagsMerged, t.Name)
+
+		default:
+			name := t.Name
+			if clash {
+				name = freeName(t.Name, func(n string) bool { _, ok := byName[n]; return ok })
+			}
+
+			level.Debug(imp.logger).Message("creating tag", keys.TagName, name)
+			dbTag, err := imp.repo.InsertTag(ctx, name, c, tx)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not InsertTag", keys.TagName, name)
+			}
+			for _, sk := range t.Skills {
+				if _, err := imp.repo.UpsertTagSkill(ctx, dbTag.ID, sk.ID, sk.Level, sk.Recommended, tx); err != nil {
+					return nil, errors.Wrap(err, "could not UpsertTagSkill", keys.TagName, name, keys.SkillID, sk.ID)
+				}
+			}
+
+			byName[name] = &repository.TagDBData{Tag: dbTag}
+			tagIDs[t.Name] = dbTag.ID
+			imp.res.TagsCreated = append(imp.res.TagsCreated, name)
+		}
+	}
+
+	// roles may also reference tags that are already in the database
+	for name, t := range byName {
+		if _, ok := tagIDs[name]; !ok {
+			tagIDs[name] = t.Tag.ID
+		}
+	}
+
+	return tagIDs, nil
+}
+
+// importTagIncludes runs once every bundle tag exists, so includes can refer
+// to tags later in the bundle. Skipped tags are left as they are.
+func (imp *importer) importTagIncludes(ctx context.Context, tags []Tag, tagIDs map[string]int64, tx database.Tx) error {
+	skipped := make(map[string]bool, len(imp.res.TagsSkipped))
+	for _, n := range imp.res.TagsSkipped {
+		skipped[n] = true
+	}
+
+	for _, t := range tags {
+		if skipped[t.Name] {
+			continue
+		}
+
+		for _, inc := range t.Includes {
+			incID, ok := tagIDs[inc]
+			if !ok {
+				return database.NonRetryableError(errors.Wrap(ErrUnknownTag, "tag includes a tag that is not in the bundle or database", keys.TagName, t.Name, "included_tag_name", inc))
+			}
+			if err := imp.repo.UpsertTagInclude(ctx, tagIDs[t.Name], incID, tx); err != nil {
+				return errors.Wrap(err, "could not UpsertTagInclude", keys.TagName, t.Name, keys.IncludedTagID, incID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (imp *importer) importRoles(ctx context.Context, roles []Role, tagIDs map[string]int64, tx database.Tx) error {
+	if len(roles) == 0 {
+		return nil
+	}
+
+	existing, err := imp.repo.GetAllRoles(ctx, tx)
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return errors.Wrap(err, "could not GetAllRoles")
+	}
+
+	byName := make(map[string]*repository.RoleDBData, len(existing))
+	for _, r := range existing {
+		byName[r.Role.Name] = r
+	}
+
+	for _, r := range roles {
+		c, _ := DecodeColor(r.Color) // checked in validate
+
+		expr, err := importGroup(Group{Operator: r.Operator, Count: r.Count, Tags: r.Tags, Groups: r.Groups}, tagIDs)
+		if err != nil {
+			return database.NonRetryableError(errors.Wrap(err, "could not build role expression", keys.RoleName, r.Name))
+		}
+
+		conds := make([]repository.RoleCondition, 0, len(r.Conditions))
+		for _, c := range r.Conditions {
+			conds = append(conds, repository.RoleCondition{Kind: c.Kind.String(), Value: c.Value, Label: c.Label})
+		}
+
+		cur, clash := byName[r.Name]
+		var roleID int64
+		switch {
+		case clash && imp.policy == ConflictSkip:
+			level.Debug(imp.logger).Message("skipping existing role", keys.RoleName, r.Name)
+			imp.res.RolesSkipped = append(imp.res.RolesSkipped, r.Name)
+			continue
+
+		case clash && imp.policy == ConflictMerge:
+			level.Debug(imp.logger).Message("merging into existing role", keys.RoleName, r.Name)
+			roleID = cur.Role.ID
+			expr = mergeRoleExpr(cur.Expr, expr)
+			conds = mergeRoleConditions(cur.Conditions, conds)
+			imp.res.RolesMerged = append(imp.res.RolesMerged, r.Name)
+
+		default:
+			name := r.Name
+			if clash {
+				name = freeName(r.Name, func(n string) bool { _, ok := byName[n]; return ok })
+			}
+
+			level.Debug(imp.logger).Message("creating role", keys.RoleName, name)
+			dbRole, err := imp.repo.InsertRole(ctx, name, r.Label, c, tx)
+			if err != nil {
+				return errors.Wrap(err, "could not InsertRole", keys.RoleName, name)
+			}
+
+			byName[name] = &repository.RoleDBData{Role: dbRole, Expr: expr}
+			roleID = dbRole.ID
+			imp.res.RolesCreated = append(imp.res.RolesCreated, name)
+		}
+
+		if err := imp.repo.SetRoleExpr(ctx, roleID, expr, tx); err != nil {
+			return errors.Wrap(err, "could not SetRoleExpr", keys.RoleID, roleID)
+		}
+
+		if err := imp.repo.SetRoleConditions(ctx, roleID, conds, tx); err != nil {
+			return errors.Wrap(err, "could not SetRoleConditions", keys.RoleID, roleID)
+		}
+	}
+
+	return nil
+}
+
+func validateGroup(g Group) error {
+	if _, err := operators.ParseOperator(string(g.Operator)); err != nil {
+		return err
+	}
+	if g.Operator == operators.OperatorAtleast && (g.Count < 1 || g.Count > int64(len(g.Tags)+len(g.Groups))) {
+		return errors.Wrap(repository.ErrInvalidRoleExpr, "atleast count must be between 1 and the number of tags and groups", "count", g.Count)
+	}
+	for _, sub := range g.Groups {
+		if err := validateGroup(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateCondition(c Condition) error {
+	kind, err := conditions.ParseKind(string(c.Kind))
+	if err != nil {
+		return err
+	}
+	switch kind {
+	case conditions.KindCorporation, conditions.KindAlliance:
+		if c.Value <= 0 {
+			return errors.Wrap(ErrInvalidCondition, "corporation and alliance conditions need an ID", keys.ConditionKind, kind)
+		}
+	case conditions.KindMinSp:
+		if c.Value <= 0 {
+			return errors.Wrap(ErrInvalidCondition, "min_sp conditions need a positive value", keys.ConditionKind, kind)
+		}
+	}
+	return nil
+}
+
+// importGroup converts a bundle group to a role expression, tags first
+func importGroup(g Group, tagIDs map[string]int64) (*repository.RoleExpr, error) {
+	e := repository.NewRoleGroup(g.Operator)
+	e.MinCount = g.Count
+	for _, tn := range g.Tags {
+		id, ok := tagIDs[tn]
+		if !ok {
+			return nil, errors.Wrap(ErrUnknownTag, "role references a tag that is not in the bundle or database", keys.TagName, tn)
+		}
+		e.Children = append(e.Children, repository.NewRoleLeaf(id))
+	}
+	for _, sub := range g.Groups {
+		se, err := importGroup(sub, tagIDs)
+		if err != nil {
+			return nil, err
+		}
+		e.Children = append(e.Children, se)
+	}
+	return e, nil
+}
+
+// mergeRoleConditions adds the incoming conditions that the existing ones do
+// not already have
+func mergeRoleConditions(existing, in []repository.RoleCondition) []repository.RoleCondition {
+	type key struct {
+		kind  string
+		value int64
+	}
+
+	merged := make([]repository.RoleCondition, 0, len(existing)+len(in))
+	present := make(map[key]bool, len(existing))
+	for _, c := range existing {
+		present[key{c.Kind, c.Value}] = true
+		merged = append(merged, c)
+	}
+	for _, c := range in {
+		if present[key{c.Kind, c.Value}] {
+			continue
+		}
+		present[key{c.Kind, c.Value}] = true
+		merged = append(merged, c)
+	}
+
+	return merged
+}
+
+// mergeRoleExpr adds the top level tags and groups of in to the existing
+// expression, keeping its operator. Tags already at the top level are not
+// added twice.
+func mergeRoleExpr(existing, in *repository.RoleExpr) *repository.RoleExpr {
+	if existing == nil {
+		return in
+	}
+
+	merged := existing.Clone()
+	present := make(map[int64]bool, len(merged.Children))
+	for _, ch := range merged.Children {
+		if ch.IsLeaf() {
+			present[ch.TagID] = true
+		}
+	}
+
+	for _, ch := range in.Children {
+		if ch.IsLeaf() && present[ch.TagID] {
+			continue
+		}
+		merged.Children = append(merged.Children, ch)
+	}
+
+	return merged
+}
+
+// freeName appends " (n)" to name until taken reports false
+func freeName(name string, taken func(string) bool) string {
+	for i := 2; ; i++ {
+		n := fmt.Sprintf("%s (%d)", name, i)
+		if !taken(n) {
+			return n
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/database"
+)
+
+func init() {
+	register(command{"backup list", "[--json]", backupList})
+	register(command{"backup create", "[--json]", backupCreate})
+	register(command{"backup restore", "[--json] <snapshot name>", backupRestore})
+}
+
+type snapshotView struct {
+	Name    string    `json:"name"`
+	Taken   time.Time `json:"taken"`
+	Version uint      `json:"schema_version"`
+	Reason  string    `json:"reason"`
+	Size    int64     `json:"size"`
+}
+
+func newSnapshotView(s database.Snapshot) snapshotView {
+	return snapshotView{
+		Name:    s.Name,
+		Taken:   s.Taken,
+		Version: s.Version,
+		Reason:  string(s.Reason),
+		Size:    s.Size,
+	}
+}
+
+func snapshotRow(s snapshotView) string {
+	return fmt.Sprintf("%s\t%s\t%d\t%s\t%d", s.Name, s.Taken.Local().Format(time.DateTime), s.Version, s.Reason, s.Size)
+}
+
+const snapshotHeader = "NAME\tTAKEN\tSCHEMA\tREASON\tBYTES"
+
+func backupList(_ context.Context, deps dependencies, args []string, out io.Writer) error {
+	var asJSON bool
+	if err := parseFlags(newFlags("backup list", &asJSON), args); err != nil {
+		return err
+	}
+
+	snaps, err := deps.Snapshots().List()
+	if err != nil {
+		return err
+	}
+
+	views := make([]snapshotView, 0, len(snaps))
+	for _, s := range snaps {
+		views = append(views, newSnapshotView(s))
+	}
+
+	if asJSON {
+		return writeJSON(out, views)
+	}
+
+	rows := make([]string, 0, len(views))
+	for _, v := range views {
+		rows = append(rows, snapshotRow(v))
+	}
+	return writeTable(out, snapshotHeader, rows)
+}
+
+func backupCreate(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	var asJSON bool
+	if err := parseFlags(newFlags("backup create", &asJSON), args); err != nil {
+		return err
+	}
+
+	snap, err := deps.Snapshots().Take(ctx, deps.DB(), database.ReasonManual)
+	if err != nil {
+		return errors.Wrap(err, "could not take snapshot")
+	}
+
+	if asJSON {
+		return writeJSON(out, newSnapshotView(snap))
+	}
+	return writeTable(out, snapshotHeader, []string{snapshotRow(newSnapshotView(snap))})
+}
+
+// backupRestore replaces the app database with a snapshot. The GUI should
+// not be running at the same time.
+func backupRestore(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	var asJSON bool
+	fs := newFlags("backup restore", &asJSON)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.Wrap(ErrUsage, "give the name of one snapshot, from backup list")
+	}
+
+	undo, err := deps.Snapshots().Restore(ctx, deps.DB(), fs.Arg(0))
+	if err != nil {
+		return errors.Wrap(err, "could not restore snapshot")
+	}
+
+	if asJSON {
+		return writeJSON(out, newSnapshotView(undo))
+	}
+	fmt.Fprintf(out, "restored %s; the database as it was is saved as %s\n", fs.Arg(0), undo.Name)
+	return nil
+}
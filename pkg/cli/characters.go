@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	//nolint:depguard,staticcheck // collects per-character refresh errors
+	"github.com/hashicorp/go-multierror"
+	"github.com/kava-forge/eve-alts/lib/errors"
+	"github.com/kava-forge/eve-alts/lib/logging"
+	"github.com/kava-forge/eve-alts/lib/logging/level"
+
+	"github.com/kava-forge/eve-alts/pkg/app/characters"
+	"github.com/kava-forge/eve-alts/pkg/keys"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/scoring"
+	"github.com/kava-forge/eve-alts/pkg/views"
+)
+
+func writeCharacters(out io.Writer, chars []*repository.CharacterDBData, asJSON bool) error {
+	list := views.NewCharacters(chars)
+	if asJSON {
+		return writeJSON(out, list)
+	}
+
+	rows := make([]string, 0, len(list))
+	for _, co := range list {
+		ally := ""
+		if co.Alliance != nil {
+			ally = co.Alliance.Ticker
+		}
+		clone := "?"
+		if co.Omega != nil {
+			clone = "alpha"
+			if *co.Omega {
+				clone = "omega"
+			}
+		}
+		rows = append(rows, fmt.Sprintf("%d\t%s\t%s\t%s\t%s\t%s", co.ID, co.Name, co.Corporation.Ticker, ally, scoring.FormatSP(co.TotalSP), clone))
+	}
+	return writeTable(out, "ID\tNAME\tCORP\tALLIANCE\tSP\tCLONE", rows)
+}
+
+func charactersList(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	var asJSON bool
+	fs := newFlags("characters list", &asJSON)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	chars, err := views.LoadCharacters(ctx, deps.AppRepo())
+	if err != nil {
+		return err
+	}
+
+	return writeCharacters(out, chars, asJSON)
+}
+
+// charactersRefresh refreshes the chosen characters from ESI one at a time.
+// Failures do not stop the others; they are all returned at the end. Any
+// notifications the refresh queues are sent before it returns.
+func charactersRefresh(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	logger := logging.With(deps.Logger(), keys.Component, "cli.charactersRefresh")
+
+	var asJSON, all bool
+	fs := newFlags("characters refresh", &asJSON)
+	fs.BoolVar(&all, "all", false, "refresh every character")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if all == (fs.NArg() > 0) {
+		return errors.Wrap(ErrUsage, "give either --all or character IDs or names")
+	}
+
+	chars, err := views.LoadCharacters(ctx, deps.AppRepo())
+	if err != nil {
+		return err
+	}
+
+	toRefresh := chars
+	if !all {
+		toRefresh = make([]*repository.CharacterDBData, 0, fs.NArg())
+		for _, ref := range fs.Args() {
+			c, err := views.FindCharacter(chars, ref)
+			if err != nil {
+				return err
+			}
+			toRefresh = append(toRefresh, c)
+		}
+	}
+
+	var me error
+	refreshed := make([]*repository.CharacterDBData, 0, len(toRefresh))
+	for _, c := range toRefresh {
+		level.Info(logger).Message("refreshing character", keys.CharacterID, c.Character.ID, keys.CharacterName, c.Character.Name)
+
+		dbChar, err := characters.RefreshStoredCharacter(ctx, deps, c.Character.ID)
+		if err != nil {
+			me = multierror.Append(me, err)
+			continue
+		}
+		refreshed = append(refreshed, &dbChar)
+	}
+
+	// there is no background loop to send what the refresh queued, so send it
+	// before exiting rather than leaving it until the app is next opened
+	if deps.Notifier().Enabled() {
+		if err := deps.Notifier().Flush(ctx); err != nil {
+			me = multierror.Append(me, errors.Wrap(err, "could not send notifications"))
+		}
+	}
+
+	if err := writeCharacters(out, refreshed, asJSON); err != nil {
+		return err
+	}
+
+	return me
+}
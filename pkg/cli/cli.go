@@ -0,0 +1,214 @@
+// Package cli runs the headless subcommands, printing the same data the GUI
+// shows as aligned text or, with --json, as JSON for other tools.
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+	"github.com/kava-forge/eve-alts/lib/json"
+	"github.com/kava-forge/eve-alts/lib/logging"
+	"github.com/kava-forge/eve-alts/lib/logging/level"
+
+	"github.com/kava-forge/eve-alts/migrations"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/esi"
+	"github.com/kava-forge/eve-alts/pkg/keys"
+	"github.com/kava-forge/eve-alts/pkg/notify"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/telemetry"
+	"github.com/kava-forge/eve-alts/pkg/views"
+)
+
+var (
+	ErrUsage    = errors.New("usage")
+	ErrNotFound = views.ErrNotFound
+)
+
+type dependencies interface {
+	DB() database.Connection
+	Snapshots() database.Snapshots
+	StaticDB() database.Connection
+	Logger() logging.Logger
+	ESIClient() esi.Client
+
+	Telemetry() *telemetry.Telemeter
+	Stats() *telemetry.Stats
+
+	AppRepo() repository.AppData
+	StaticRepo() repository.StaticData
+	VaultSource() repository.KeySource
+
+	Notifier() *notify.Notifier
+}
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, deps dependencies, args []string, out io.Writer) error
+}
+
+// commands are keyed by their full name, e.g. "tags show"
+var commands = map[string]command{}
+
+// lockedCommands run without unlocking the token vault, so a lost passphrase
+// can still be looked into and reset. Snapshots copy sealed tokens as they
+// are, so need no key either.
+var lockedCommands = map[string]bool{
+	"vault status":   true,
+	"vault reset":    true,
+	"backup list":    true,
+	"backup create":  true,
+	"backup restore": true,
+	"migrate status": true,
+	"migrate up":     true,
+	"migrate down":   true,
+	"migrate force":  true,
+}
+
+// schemaCommands run before migrations, so they still work on a database
+// that is dirty or was migrated by a newer build
+var schemaCommands = map[string]bool{
+	"backup list":    true,
+	"backup create":  true,
+	"backup restore": true,
+	"migrate status": true,
+	"migrate up":     true,
+	"migrate down":   true,
+	"migrate force":  true,
+}
+
+func register(cmd command) {
+	commands[cmd.name] = cmd
+}
+
+func init() {
+	register(command{"characters list", "[--json]", charactersList})
+	register(command{"characters refresh", "[--json] (--all | <id or name>...)", charactersRefresh})
+	register(command{"tags list", "[--json]", tagsList})
+	register(command{"tags show", "[--json] <id or name>", tagsShow})
+	register(command{"tags import", "[--json] [--conflict skip|rename|merge] <file.json|file.toml>", tagsImport})
+	register(command{"tags export", "[--tag <name>]... [--role <name>]... [--format json|toml] <file or ->", tagsExport})
+	register(command{"roles list", "[--json]", rolesList})
+	register(command{"match", "[--json] --role <id or name>", match})
+	register(command{"matrix", "[--format csv|html|xlsx] <file or ->", matrixExport})
+}
+
+// IsCommand reports whether name starts a subcommand, rather than being a
+// leftover argument for the GUI
+func IsCommand(name string) bool {
+	for full := range commands {
+		if strings.SplitN(full, " ", 2)[0] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Usage lists every subcommand
+func Usage(w io.Writer, prog string) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "\t%s %s %s\n", prog, name, commands[name].usage)
+	}
+}
+
+// Run checks the app database and migrates it, taking a snapshot first if
+// it is behind, and unlocks the token vault, as the GUI does on start. It
+// refuses a database from a newer build. Then it runs the subcommand named
+// by the leading args.
+func Run(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	logger := logging.With(deps.Logger(), keys.Component, "cli.Run")
+
+	var cmd command
+	var ok bool
+	for n := min(2, len(args)); n > 0 && !ok; n-- {
+		cmd, ok = commands[strings.Join(args[:n], " ")]
+		if ok {
+			args = args[n:]
+		}
+	}
+	if !ok {
+		return errors.Wrap(ErrUsage, "unknown command", "args", strings.Join(args, " "))
+	}
+
+	level.Debug(logger).Message("running command", "command", cmd.name, "args", args)
+
+	if !schemaCommands[cmd.name] {
+		if err := database.Check(ctx, logger, deps.DB(), migrations.Migrations); err != nil {
+			return errors.Wrap(err, "could not check database")
+		}
+		if err := deps.Snapshots().Migrate(ctx, deps.DB(), migrations.Migrations); err != nil {
+			return errors.Wrap(err, "could not run database migrations")
+		}
+	}
+
+	if !lockedCommands[cmd.name] {
+		if err := unlockVault(ctx, deps); err != nil {
+			return err
+		}
+	}
+
+	return cmd.run(ctx, deps, args, out)
+}
+
+// newFlags makes a flag set for a command, with the shared --json flag
+func newFlags(name string, asJSON *bool) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.BoolVar(asJSON, "json", false, "print JSON instead of text")
+	return fs
+}
+
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return errors.Wrap(ErrUsage, err.Error(), "command", fs.Name())
+	}
+	return nil
+}
+
+// stringsFlag collects a repeatable string flag
+type stringsFlag []string
+
+func (s *stringsFlag) String() string { return strings.Join(*s, ",") }
+
+func (s *stringsFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+func writeJSON(out io.Writer, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not encode output")
+	}
+	if _, err := fmt.Fprintln(out, string(b)); err != nil {
+		return errors.Wrap(err, "could not write output")
+	}
+	return nil
+}
+
+// writeTable prints tab separated rows as aligned columns
+func writeTable(out io.Writer, header string, rows []string) error {
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, header)
+	for _, row := range rows {
+		fmt.Fprintln(tw, row)
+	}
+	if err := tw.Flush(); err != nil {
+		return errors.Wrap(err, "could not write output")
+	}
+	return nil
+}
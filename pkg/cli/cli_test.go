@@ -0,0 +1,445 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/kava-forge/eve-alts/lib/json"
+
+	"github.com/kava-forge/eve-alts/migrations"
+	"github.com/kava-forge/eve-alts/pkg/cli"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/esi"
+	"github.com/kava-forge/eve-alts/pkg/notify"
+	"github.com/kava-forge/eve-alts/pkg/operators"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/testhelpers"
+)
+
+type testDependencies struct {
+	*testhelpers.TestDependencies
+	esi esi.Client
+}
+
+func (d testDependencies) ESIClient() esi.Client { return d.esi }
+
+// emptyQueueESI answers a refresh for a character whose skill queue has run
+// out; it panics on anything a refresh does not call
+type emptyQueueESI struct {
+	esi.Client
+}
+
+func (emptyQueueESI) GetCharacterPublicData(context.Context, *oauth2.Token, int64) (esi.CharacterPublicData, error) {
+	return esi.CharacterPublicData{CorporationID: 20, Name: "Zed"}, nil
+}
+
+func (emptyQueueESI) GetCharacterPortrait(context.Context, *oauth2.Token, int64) (esi.CharacterPortait, error) {
+	return esi.CharacterPortait{}, nil
+}
+
+func (emptyQueueESI) GetCorporationData(context.Context, *oauth2.Token, int64) (esi.CorporationData, error) {
+	return esi.CorporationData{Name: "Zed Corp", Ticker: "ZED"}, nil
+}
+
+func (emptyQueueESI) GetCorporationIcons(context.Context, *oauth2.Token, int64) (esi.CorporationIcons, error) {
+	return esi.CorporationIcons{}, nil
+}
+
+func (emptyQueueESI) GetSkills(context.Context, *oauth2.Token, int64) (esi.SkillList, error) {
+	return esi.SkillList{TotalSP: 1_000_000}, nil
+}
+
+func (emptyQueueESI) GetSkillQueue(context.Context, *oauth2.Token, int64) (esi.SkillQueue, error) {
+	return nil, nil
+}
+
+func newTestDependencies(t *testing.T) testDependencies {
+	t.Helper()
+
+	deps := testDependencies{TestDependencies: testhelpers.NewTestDependencies(t)}
+
+	deps.TestAppRepo.GetAllCharactersReturns([]*repository.CharacterDBData{
+		{
+			Character:   repository.Character{ID: 2, Name: "Zed", TotalSp: 1_000_000},
+			Corporation: repository.Corporation{ID: 20, Name: "Zed Corp", Ticker: "ZED"},
+			Skills:      []repository.CharacterSkill{{CharacterID: 2, SkillID: 100, SkillLevel: 2}},
+		},
+		{
+			Character:   repository.Character{ID: 1, Name: "Alice", TotalSp: 50_000_000, Omega: sql.NullBool{Bool: true, Valid: true}},
+			Corporation: repository.Corporation{ID: 10, Name: "Alice Corp", Ticker: "ALC"},
+			Alliance:    repository.Alliance{ID: sql.NullInt64{Int64: 99, Valid: true}, Name: sql.NullString{String: "Big Alliance", Valid: true}, Ticker: sql.NullString{String: "BIG", Valid: true}},
+			Skills:      []repository.CharacterSkill{{CharacterID: 1, SkillID: 100, SkillLevel: 5}},
+		},
+	}, nil)
+	deps.TestAppRepo.GetAllTagsReturns([]*repository.TagDBData{{
+		Tag:    repository.Tag{ID: 5, Name: "Gunnery"},
+		Skills: []repository.TagSkill{{TagID: 5, SkillID: 100, SkillLevel: 4}},
+	}}, nil)
+	deps.TestAppRepo.GetAllRolesReturns([]*repository.RoleDBData{{
+		Role: repository.Role{ID: 7, Name: "DPS", Label: "D"},
+		Expr: repository.NewRoleGroup(operators.OperatorAll, repository.NewRoleLeaf(5)),
+		Tags: []repository.Tag{{ID: 5, Name: "Gunnery"}},
+	}}, nil)
+	deps.TestStaticRepo.BatchGetSkillNamesReturns([]repository.BatchGetSkillNamesRow{{SkillID: 100, SkillName: "Gunnery"}}, nil)
+
+	return deps
+}
+
+func TestRun_Usage(t *testing.T) {
+	t.Parallel()
+
+	tests := [][]string{
+		{},
+		{"characters"},
+		{"nope"},
+		{"characters", "list", "--bogus"},
+		{"characters", "refresh"},
+		{"tags", "show"},
+		{"match"},
+	}
+	for _, args := range tests {
+		args := args
+		t.Run(filepath.Join(args...), func(t *testing.T) {
+			t.Parallel()
+
+			deps := newTestDependencies(t)
+			err := cli.Run(context.Background(), deps, args, &bytes.Buffer{})
+			assert.ErrorIs(t, err, cli.ErrUsage)
+		})
+	}
+}
+
+func TestIsCommand(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, cli.IsCommand("characters"))
+	assert.True(t, cli.IsCommand("match"))
+	assert.False(t, cli.IsCommand("list"))
+	assert.False(t, cli.IsCommand("-debug"))
+}
+
+func TestRun_CharactersList(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDependencies(t)
+	out := &bytes.Buffer{}
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"characters", "list"}, out))
+	assert.Equal(t, `ID  NAME   CORP  ALLIANCE  SP      CLONE
+1   Alice  ALC   BIG       50.00M  omega
+2   Zed    ZED             1.00M   ?
+`, out.String())
+	assert.Equal(t, 1, deps.TestDB.MigrateCallCount())
+
+	out.Reset()
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"characters", "list", "--json"}, out))
+	var got []map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	require.Len(t, got, 2)
+	assert.Equal(t, "Alice", got[0]["name"])
+	assert.Equal(t, true, got[0]["omega"])
+	assert.Equal(t, "BIG", got[0]["alliance"].(map[string]interface{})["ticker"])
+	assert.Nil(t, got[1]["omega"])
+	assert.NotContains(t, got[1], "alliance")
+}
+
+func TestRun_CharactersRefreshNotifies(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var received []notify.Payload
+	srv := httptest.NewServer(stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		b, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		var p notify.Payload
+		assert.NoError(t, json.Unmarshal(b, &p))
+		received = append(received, p)
+		w.WriteHeader(stdhttp.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	deps := newTestDependencies(t)
+	deps.esi = emptyQueueESI{}
+	deps.TestNotifier = notify.New(deps, notify.Options{Webhooks: []string{srv.URL}})
+
+	deps.TestAppRepo.GetCharacterReturns(&repository.CharacterDBData{
+		Character: repository.Character{ID: 2, Name: "Zed", SkillQueueLength: sql.NullInt64{Int64: 3, Valid: true}},
+	}, nil)
+	deps.TestAppRepo.UpsertCharacterReturns(repository.Character{ID: 2, Name: "Zed"}, nil)
+
+	var queued []repository.Notification
+	deps.TestAppRepo.InsertNotificationCalls(func(_ context.Context, url, payload string, next time.Time, _ database.Tx) (repository.Notification, error) {
+		n := repository.Notification{ID: int64(len(queued) + 1), Url: url, Payload: payload, NextAttempt: next}
+		queued = append(queued, n)
+		return n, nil
+	})
+	deps.TestAppRepo.GetDueNotificationsCalls(func(context.Context, time.Time, int64, database.Tx) ([]repository.Notification, error) {
+		return queued, nil
+	})
+
+	out := &bytes.Buffer{}
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"characters", "refresh", "zed"}, out))
+
+	// sent before the command returned, with no background loop running
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 1)
+	assert.Contains(t, received[0].Content, "Zed")
+	assert.Equal(t, 1, deps.TestAppRepo.DeleteNotificationCallCount())
+}
+
+func TestRun_TagsShow(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDependencies(t)
+	out := &bytes.Buffer{}
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"tags", "show", "--json", "gunnery"}, out))
+	assert.JSONEq(t, `{
+		"id": 5, "name": "Gunnery", "color": "#00000000", "includes": [],
+		"skills": [{"id": 100, "name": "Gunnery", "level": 4}],
+		"inherited": []
+	}`, out.String())
+
+	err := cli.Run(context.Background(), deps, []string{"tags", "show", "Missiles"}, out)
+	assert.ErrorIs(t, err, cli.ErrNotFound)
+}
+
+func TestRun_RolesList(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDependencies(t)
+	out := &bytes.Buffer{}
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"roles", "list", "--json"}, out))
+	assert.JSONEq(t, `[{
+		"id": 7, "name": "DPS", "label": "D", "color": "#00000000",
+		"tags": ["Gunnery"], "conditions": [],
+		"expression": {"operator": "all", "children": [{"tag": "Gunnery"}]}
+	}]`, out.String())
+}
+
+func TestRun_Match(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDependencies(t)
+	out := &bytes.Buffer{}
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"match", "--json", "--role", "dps"}, out))
+	assert.JSONEq(t, `{"role": {"id": 7, "name": "DPS"}, "characters": [{"id": 1, "name": "Alice"}]}`, out.String())
+
+	out.Reset()
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"match", "--role", "7"}, out))
+	assert.Equal(t, "ID  NAME\n1   Alice\n", out.String())
+
+	err := cli.Run(context.Background(), deps, []string{"match", "--role", "Tackle"}, out)
+	assert.ErrorIs(t, err, cli.ErrNotFound)
+}
+
+func TestRun_TagsExportImport(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDependencies(t)
+	path := filepath.Join(t.TempDir(), "tags.toml")
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"tags", "export", "--role", "DPS", path}, &bytes.Buffer{}))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `name = 'Gunnery'`)
+	assert.Contains(t, string(b), `name = 'DPS'`)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"tags", "import", path}, out))
+	assert.Equal(t, "Tags skipped: Gunnery\nRoles skipped: DPS\n", out.String())
+	assert.Equal(t, 0, deps.TestAppRepo.InsertTagCallCount())
+
+	deps.TestAppRepo.GetAllRolesReturns(nil, database.ErrNoRows)
+	out.Reset()
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"tags", "import", "--json", path}, out))
+	var got map[string][]string
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, []string{"DPS"}, got["roles_created"])
+	assert.Equal(t, []string{"Gunnery"}, got["tags_skipped"])
+}
+
+func TestRun_Matrix(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDependencies(t)
+	out := &bytes.Buffer{}
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"matrix", "-"}, out))
+	assert.Equal(t, "Character ID,Character,Gunnery (tag),DPS (role)\n1,Alice,recommended,qualified\n2,Zed,missing 1,missing 1\n", out.String())
+
+	path := filepath.Join(t.TempDir(), "matrix.html")
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"matrix", path}, &bytes.Buffer{}))
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `<td class="recommended">recommended</td>`)
+
+	err = cli.Run(context.Background(), deps, []string{"matrix", "--format", "pdf", "-"}, out)
+	assert.ErrorIs(t, err, cli.ErrUsage)
+}
+
+func TestRun_Vault(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDependencies(t)
+	deps.TestAppRepo.VaultStatusReturns(repository.VaultStatus{Mode: repository.VaultModeKeyFile, Sealed: 2, Plain: 1}, nil)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"vault", "status"}, out))
+	assert.Equal(t, `MODE     SEALED  PLAINTEXT
+keyfile  2       1
+`, out.String())
+	assert.Equal(t, 0, deps.TestAppRepo.UnlockVaultCallCount(), "status works without the key")
+
+	out.Reset()
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"characters", "list"}, out))
+	require.Equal(t, 1, deps.TestAppRepo.UnlockVaultCallCount())
+	_, src := deps.TestAppRepo.UnlockVaultArgsForCall(0)
+	assert.Equal(t, deps.TestVaultSource, src)
+
+	out.Reset()
+	newKey := filepath.Join(t.TempDir(), "new.key")
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"vault", "rotate", "--keyfile", newKey}, out))
+	require.Equal(t, 1, deps.TestAppRepo.RotateVaultCallCount())
+	_, to := deps.TestAppRepo.RotateVaultArgsForCall(0)
+	assert.Equal(t, repository.KeySource{Mode: repository.VaultModeKeyFile, KeyFile: newKey}, to)
+	assert.Contains(t, out.String(), `keyfile = "`+newKey+`"`)
+
+	err := cli.Run(context.Background(), deps, []string{"vault", "rotate", "--mode", "plaintext"}, out)
+	assert.ErrorIs(t, err, cli.ErrUsage)
+
+	err = cli.Run(context.Background(), deps, []string{"vault", "reset"}, out)
+	assert.ErrorIs(t, err, cli.ErrUsage)
+	assert.Equal(t, 0, deps.TestAppRepo.ResetVaultCallCount())
+
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"vault", "reset", "--yes"}, out))
+	assert.Equal(t, 1, deps.TestAppRepo.ResetVaultCallCount())
+
+	deps.TestAppRepo.UnlockVaultReturns(repository.ErrWrongVaultKey)
+	err = cli.Run(context.Background(), deps, []string{"characters", "list"}, out)
+	assert.ErrorIs(t, err, repository.ErrWrongVaultKey)
+}
+
+func TestRun_Backup(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDependencies(t)
+	deps.TestDB.SchemaVersionReturns(12, false, nil)
+	deps.TestDB.BackupCalls(func(_ context.Context, path string) error {
+		return os.WriteFile(path, []byte("snapshot"), 0o600)
+	})
+
+	out := &bytes.Buffer{}
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"backup", "create", "--json"}, out))
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &created))
+	assert.Equal(t, "manual", created["reason"])
+	assert.EqualValues(t, 12, created["schema_version"])
+	assert.EqualValues(t, len("snapshot"), created["size"])
+	assert.Equal(t, 0, deps.TestAppRepo.UnlockVaultCallCount())
+
+	out.Reset()
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"backup", "list", "--json"}, out))
+	var listed []map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &listed))
+	require.Len(t, listed, 1)
+	assert.Equal(t, created["name"], listed[0]["name"])
+
+	out.Reset()
+	name := created["name"].(string)
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"backup", "restore", name}, out))
+	require.Equal(t, 1, deps.TestDB.RestoreCallCount())
+	_, path := deps.TestDB.RestoreArgsForCall(0)
+	assert.Equal(t, filepath.Join(deps.TestSnapshots.Dir, name), path)
+	assert.Contains(t, out.String(), "prerestore")
+
+	out.Reset()
+	require.NoError(t, cli.Run(context.Background(), deps, []string{"backup", "restore", "--json", name}, out))
+	var undo map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &undo))
+	assert.Equal(t, "prerestore", undo["reason"])
+
+	err := cli.Run(context.Background(), deps, []string{"backup", "restore", "database-nope.db"}, out)
+	assert.ErrorIs(t, err, database.ErrNoSnapshot)
+
+	err = cli.Run(context.Background(), deps, []string{"backup", "restore"}, out)
+	assert.ErrorIs(t, err, cli.ErrUsage)
+}
+
+func TestRun_Migrate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	latest, err := database.LatestVersion(migrations.Migrations)
+	require.NoError(t, err)
+
+	deps := newTestDependencies(t)
+	deps.TestDB.SchemaVersionReturns(latest-1, false, nil)
+	deps.TestDB.BackupCalls(func(_ context.Context, path string) error {
+		return os.WriteFile(path, []byte("snapshot"), 0o600)
+	})
+
+	out := &bytes.Buffer{}
+	require.NoError(t, cli.Run(ctx, deps, []string{"migrate", "status", "--json"}, out))
+	var status map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &status))
+	assert.EqualValues(t, latest-1, status["current"])
+	assert.EqualValues(t, latest, status["latest"])
+	assert.Equal(t, []interface{}{float64(latest)}, status["pending"])
+	assert.Equal(t, 0, deps.TestDB.MigrateCallCount(), "migrate commands do not migrate first")
+
+	out.Reset()
+	require.NoError(t, cli.Run(ctx, deps, []string{"migrate", "up"}, out))
+	require.Equal(t, 1, deps.TestDB.MigrateToCallCount())
+	_, _, to := deps.TestDB.MigrateToArgsForCall(0)
+	assert.Equal(t, latest, to)
+	assert.Equal(t, 1, deps.TestDB.BackupCallCount(), "snapshotted first")
+
+	require.NoError(t, cli.Run(ctx, deps, []string{"migrate", "down", "3"}, out))
+	require.Equal(t, 2, deps.TestDB.MigrateToCallCount())
+	_, _, to = deps.TestDB.MigrateToArgsForCall(1)
+	assert.EqualValues(t, 3, to)
+
+	for _, args := range [][]string{
+		{"migrate", "up", "3"},
+		{"migrate", "down", fmt.Sprint(latest)},
+		{"migrate", "down"},
+		{"migrate", "force", "three"},
+	} {
+		err = cli.Run(ctx, deps, args, out)
+		assert.ErrorIs(t, err, cli.ErrUsage, "%v", args)
+	}
+
+	require.NoError(t, cli.Run(ctx, deps, []string{"migrate", "force", "5"}, out))
+	require.Equal(t, 1, deps.TestDB.ForceVersionCallCount())
+	_, _, to = deps.TestDB.ForceVersionArgsForCall(0)
+	assert.EqualValues(t, 5, to)
+
+	err = cli.Run(ctx, deps, []string{"migrate", "force", fmt.Sprint(latest + 1)}, out)
+	assert.ErrorIs(t, err, database.ErrUnknownVersion)
+
+	deps.TestDB.SchemaVersionReturns(latest+1, false, nil)
+	cleaned := deps.TestDB.CleanOrphansCallCount()
+	err = cli.Run(ctx, deps, []string{"characters", "list"}, out)
+	assert.ErrorIs(t, err, database.ErrSchemaTooNew)
+	assert.Equal(t, cleaned, deps.TestDB.CleanOrphansCallCount(), "nothing is deleted from a newer schema")
+	assert.Equal(t, 1, deps.TestDB.CheckIntegrityCallCount(), "the read only check still runs first")
+
+	out.Reset()
+	require.NoError(t, cli.Run(ctx, deps, []string{"migrate", "status"}, out))
+	assert.Regexp(t, fmt.Sprintf(`(?m)^%d +unknown, from a newer version$`, latest+1), out.String())
+}
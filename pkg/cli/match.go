@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/views"
+)
+
+// match lists the characters that qualify for a role
+func match(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	var asJSON bool
+	var roleRef string
+	fs := newFlags("match", &asJSON)
+	fs.StringVar(&roleRef, "role", "", "the role to match, by ID or name")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if roleRef == "" || fs.NArg() > 0 {
+		return errors.Wrap(ErrUsage, "give one --role")
+	}
+
+	roles, err := views.LoadRoles(ctx, deps.AppRepo())
+	if err != nil {
+		return err
+	}
+	role, err := views.FindRole(roles, roleRef)
+	if err != nil {
+		return err
+	}
+
+	chars, err := views.LoadCharacters(ctx, deps.AppRepo())
+	if err != nil {
+		return err
+	}
+	tags, err := views.LoadTags(ctx, deps.AppRepo())
+	if err != nil {
+		return err
+	}
+
+	res := views.NewMatch(nil, chars, tags, roles, role)
+	if asJSON {
+		return writeJSON(out, res)
+	}
+
+	rows := make([]string, 0, len(res.Characters))
+	for _, c := range res.Characters {
+		rows = append(rows, fmt.Sprintf("%d\t%s", c.ID, c.Name))
+	}
+	return writeTable(out, "ID\tNAME", rows)
+}
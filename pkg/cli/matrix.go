@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/matrix"
+	"github.com/kava-forge/eve-alts/pkg/views"
+)
+
+// matrixExport writes every character graded against every tag and role.
+// "-" writes to out.
+func matrixExport(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	var ignored bool
+	var format string
+	fs := newFlags("matrix", &ignored)
+	fs.StringVar(&format, "format", "", "csv, html or xlsx; taken from the file extension if not given")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.Wrap(ErrUsage, "give one output file, or - for standard output")
+	}
+
+	path := fs.Arg(0)
+	var f matrix.Format
+	var err error
+	switch {
+	case format != "":
+		if f, err = matrix.ParseFormat(format); err != nil {
+			return errors.Wrap(ErrUsage, err.Error())
+		}
+	case path == "-":
+		f = matrix.FormatCSV
+	default:
+		if f, err = matrix.FormatFromPath(path); err != nil {
+			return err
+		}
+	}
+
+	chars, err := views.LoadCharacters(ctx, deps.AppRepo())
+	if err != nil {
+		return err
+	}
+	tags, err := views.LoadTags(ctx, deps.AppRepo())
+	if err != nil {
+		return err
+	}
+	roles, err := views.LoadRoles(ctx, deps.AppRepo())
+	if err != nil {
+		return err
+	}
+
+	m := matrix.Build(chars, tags, roles)
+
+	if path == "-" {
+		return matrix.Write(out, m, f)
+	}
+
+	fh, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "could not create matrix file", "path", path)
+	}
+	if err := matrix.Write(fh, m, f); err != nil {
+		_ = fh.Close()
+		return err
+	}
+	return errors.Wrap(fh.Close(), "could not write matrix file", "path", path)
+}
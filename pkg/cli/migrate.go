@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/migrations"
+	"github.com/kava-forge/eve-alts/pkg/database"
+)
+
+func init() {
+	register(command{"migrate status", "[--json]", migrateStatus})
+	register(command{"migrate up", "[<version>]", migrateUp})
+	register(command{"migrate down", "<version>", migrateDown})
+	register(command{"migrate force", "<version>", migrateForce})
+}
+
+type migrationStatusView struct {
+	Current uint   `json:"current"`
+	Dirty   bool   `json:"dirty"`
+	Latest  uint   `json:"latest"`
+	Pending []uint `json:"pending"`
+}
+
+func migrateStatus(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	var asJSON bool
+	if err := parseFlags(newFlags("migrate status", &asJSON), args); err != nil {
+		return err
+	}
+
+	status, err := database.Status(ctx, deps.DB(), migrations.Migrations)
+	if err != nil {
+		return errors.Wrap(err, "could not read schema status")
+	}
+
+	if asJSON {
+		pending := status.Pending
+		if pending == nil {
+			pending = []uint{}
+		}
+		return writeJSON(out, migrationStatusView{
+			Current: status.Current,
+			Dirty:   status.Dirty,
+			Latest:  status.Latest,
+			Pending: pending,
+		})
+	}
+
+	versions, err := database.MigrationVersions(migrations.Migrations)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]string, 0, len(versions)+1)
+	for _, v := range versions {
+		state := "applied"
+		switch {
+		case v > status.Current:
+			state = "pending"
+		case v == status.Current && status.Dirty:
+			state = "dirty"
+		}
+		rows = append(rows, fmt.Sprintf("%d\t%s", v, state))
+	}
+	if status.TooNew() {
+		state := "unknown, from a newer version"
+		if status.Dirty {
+			state += ", dirty"
+		}
+		rows = append(rows, fmt.Sprintf("%d\t%s", status.Current, state))
+	}
+	return writeTable(out, "VERSION\tSTATE", rows)
+}
+
+// versionArg reads the one version argument of a migrate command. Without
+// one it returns def, unless def is nil.
+func versionArg(name string, args []string, def *uint) (uint, error) {
+	var ignored bool
+	fs := newFlags(name, &ignored)
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+
+	switch {
+	case fs.NArg() == 0 && def != nil:
+		return *def, nil
+	case fs.NArg() != 1:
+		return 0, errors.Wrap(ErrUsage, "give one schema version, from migrate status", "command", name)
+	}
+
+	v, err := strconv.ParseUint(fs.Arg(0), 10, 0)
+	if err != nil {
+		return 0, errors.Wrap(ErrUsage, "schema versions are whole numbers", "command", name, "version", fs.Arg(0))
+	}
+	return uint(v), nil
+}
+
+// migrateTo moves the schema to version, which must not be on the wrong side
+// of the current one for the command
+func migrateTo(ctx context.Context, deps dependencies, name string, version uint, up bool, out io.Writer) error {
+	current, _, err := deps.DB().SchemaVersion(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not read schema version")
+	}
+
+	if up && version < current {
+		return errors.Wrap(ErrUsage, "version is older than the database, use migrate down", "command", name, "version", version, "current", current)
+	}
+	if !up && version > current {
+		return errors.Wrap(ErrUsage, "version is newer than the database, use migrate up", "command", name, "version", version, "current", current)
+	}
+
+	if err := deps.Snapshots().MigrateTo(ctx, deps.DB(), migrations.Migrations, version); err != nil {
+		return errors.Wrap(err, "could not migrate database")
+	}
+
+	fmt.Fprintf(out, "schema migrated from version %d to %d\n", current, version)
+	return nil
+}
+
+func migrateUp(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	latest, err := database.LatestVersion(migrations.Migrations)
+	if err != nil {
+		return err
+	}
+
+	version, err := versionArg("migrate up", args, &latest)
+	if err != nil {
+		return err
+	}
+	return migrateTo(ctx, deps, "migrate up", version, true, out)
+}
+
+func migrateDown(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	version, err := versionArg("migrate down", args, nil)
+	if err != nil {
+		return err
+	}
+	return migrateTo(ctx, deps, "migrate down", version, false, out)
+}
+
+// migrateForce clears a failed migration's dirty flag once the schema has
+// been repaired by hand, recording version as the one applied
+func migrateForce(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	version, err := versionArg("migrate force", args, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := database.ForceVersion(ctx, deps.DB(), migrations.Migrations, version); err != nil {
+		return errors.Wrap(err, "could not force schema version")
+	}
+
+	fmt.Fprintf(out, "schema marked clean at version %d\n", version)
+	return nil
+}
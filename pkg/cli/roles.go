@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kava-forge/eve-alts/pkg/views"
+)
+
+func rolesList(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	var asJSON bool
+	fs := newFlags("roles list", &asJSON)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	roles, err := views.LoadRoles(ctx, deps.AppRepo())
+	if err != nil {
+		return err
+	}
+
+	list := views.NewRoles(roles)
+	if asJSON {
+		return writeJSON(out, list)
+	}
+
+	rows := make([]string, 0, len(list))
+	for _, r := range list {
+		conds := make([]string, 0, len(r.Conditions))
+		for _, c := range r.Conditions {
+			switch {
+			case c.Label != "":
+				conds = append(conds, c.Kind+" "+c.Label)
+			case c.Value != 0:
+				conds = append(conds, fmt.Sprintf("%s %d", c.Kind, c.Value))
+			default:
+				conds = append(conds, c.Kind)
+			}
+		}
+		rows = append(rows, fmt.Sprintf("%d\t%s\t%s\t%s\t%s", r.ID, r.Name, r.Label, strings.Join(r.Tags, ", "), strings.Join(conds, ", ")))
+	}
+	return writeTable(out, "ID\tNAME\tLABEL\tTAGS\tCONDITIONS", rows)
+}
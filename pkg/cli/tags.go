@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/bundles"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/views"
+)
+
+func tagsList(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	var asJSON bool
+	fs := newFlags("tags list", &asJSON)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	tags, err := views.LoadTags(ctx, deps.AppRepo())
+	if err != nil {
+		return err
+	}
+
+	list := views.NewTags(tags)
+
+	if asJSON {
+		return writeJSON(out, list)
+	}
+
+	rows := make([]string, 0, len(list))
+	for _, t := range list {
+		rows = append(rows, fmt.Sprintf("%d\t%s\t%d\t%s", t.ID, t.Name, t.Skills, strings.Join(t.Includes, ", ")))
+	}
+	return writeTable(out, "ID\tNAME\tSKILLS\tINCLUDES", rows)
+}
+
+func tagsShow(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	var asJSON bool
+	fs := newFlags("tags show", &asJSON)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.Wrap(ErrUsage, "give one tag ID or name")
+	}
+
+	tags, err := views.LoadTags(ctx, deps.AppRepo())
+	if err != nil {
+		return err
+	}
+	tag, err := views.FindTag(tags, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	detail, err := views.LoadTagDetail(ctx, deps.StaticRepo(), tag, tags)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		return writeJSON(out, detail)
+	}
+
+	fmt.Fprintf(out, "%s (#%d) %s\n", detail.Name, detail.ID, detail.Color)
+	if len(detail.Includes) > 0 {
+		fmt.Fprintf(out, "Includes: %s\n", strings.Join(detail.Includes, ", "))
+	}
+	fmt.Fprintln(out)
+
+	rows := make([]string, 0, len(detail.Skills)+len(detail.Inherited))
+	for _, s := range append(detail.Skills, detail.Inherited...) {
+		rec := ""
+		if s.Recommended > 0 {
+			rec = fmt.Sprintf("%d", s.Recommended)
+		}
+		rows = append(rows, fmt.Sprintf("%d\t%s\t%d\t%s\t%s", s.ID, s.Name, s.Level, rec, s.From))
+	}
+	return writeTable(out, "ID\tSKILL\tLEVEL\tRECOMMENDED\tFROM", rows)
+}
+
+type importOut struct {
+	TagsCreated  []string `json:"tags_created"`
+	TagsMerged   []string `json:"tags_merged"`
+	TagsSkipped  []string `json:"tags_skipped"`
+	RolesCreated []string `json:"roles_created"`
+	RolesMerged  []string `json:"roles_merged"`
+	RolesSkipped []string `json:"roles_skipped"`
+}
+
+func tagsImport(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	var asJSON bool
+	var conflict string
+	fs := newFlags("tags import", &asJSON)
+	fs.StringVar(&conflict, "conflict", string(bundles.ConflictSkip), "what to do with existing tags and roles of the same name")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.Wrap(ErrUsage, "give one bundle file")
+	}
+
+	policy, err := bundles.ParseConflictPolicy(conflict)
+	if err != nil {
+		return errors.Wrap(ErrUsage, err.Error())
+	}
+
+	path := fs.Arg(0)
+	f, err := bundles.FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+
+	fh, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "could not open bundle", "path", path)
+	}
+	defer fh.Close()
+
+	b, err := bundles.Decode(fh, f)
+	if err != nil {
+		return err
+	}
+
+	res, err := bundles.Import(ctx, deps, b, policy)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		nonNil := func(s []string) []string {
+			if s == nil {
+				return []string{}
+			}
+			return s
+		}
+		return writeJSON(out, importOut{
+			TagsCreated:  nonNil(res.TagsCreated),
+			TagsMerged:   nonNil(res.TagsMerged),
+			TagsSkipped:  nonNil(res.TagsSkipped),
+			RolesCreated: nonNil(res.RolesCreated),
+			RolesMerged:  nonNil(res.RolesMerged),
+			RolesSkipped: nonNil(res.RolesSkipped),
+		})
+	}
+
+	summary := res.String()
+	if summary == "" {
+		summary = "Nothing to import"
+	}
+	_, err = fmt.Fprintln(out, summary)
+	return err
+}
+
+// tagsExport writes a bundle of the chosen tags and roles, or of every tag
+// when none are chosen. "-" writes to out.
+func tagsExport(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	var ignored bool
+	var tagRefs, roleRefs stringsFlag
+	var format string
+	fs := newFlags("tags export", &ignored)
+	fs.Var(&tagRefs, "tag", "a tag to export, by ID or name; repeatable")
+	fs.Var(&roleRefs, "role", "a role to export, by ID or name; repeatable")
+	fs.StringVar(&format, "format", "", "json or toml; taken from the file extension if not given")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.Wrap(ErrUsage, "give one output file, or - for standard output")
+	}
+
+	path := fs.Arg(0)
+	f := bundles.Format(format)
+	switch {
+	case format != "":
+		if f != bundles.FormatJSON && f != bundles.FormatTOML {
+			return errors.Wrap(ErrUsage, "format must be json or toml", "format", format)
+		}
+	case path == "-":
+		f = bundles.FormatJSON
+	default:
+		var err error
+		if f, err = bundles.FormatFromPath(path); err != nil {
+			return err
+		}
+	}
+
+	allTags, err := views.LoadTags(ctx, deps.AppRepo())
+	if err != nil {
+		return err
+	}
+	roles, err := views.LoadRoles(ctx, deps.AppRepo())
+	if err != nil {
+		return err
+	}
+
+	selTags := make([]*repository.TagDBData, 0, len(tagRefs))
+	for _, ref := range tagRefs {
+		t, err := views.FindTag(allTags, ref)
+		if err != nil {
+			return err
+		}
+		selTags = append(selTags, t)
+	}
+	selRoles := make([]*repository.RoleDBData, 0, len(roleRefs))
+	for _, ref := range roleRefs {
+		r, err := views.FindRole(roles, ref)
+		if err != nil {
+			return err
+		}
+		selRoles = append(selRoles, r)
+	}
+	if len(selTags) == 0 && len(selRoles) == 0 {
+		selTags = allTags
+	}
+
+	b, err := bundles.Export(ctx, deps.StaticRepo(), allTags, selTags, selRoles)
+	if err != nil {
+		return err
+	}
+
+	if path == "-" {
+		return bundles.Encode(out, b, f)
+	}
+
+	fh, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "could not create bundle file", "path", path)
+	}
+	if err := bundles.Encode(fh, b, f); err != nil {
+		_ = fh.Close()
+		return err
+	}
+	return errors.Wrap(fh.Close(), "could not write bundle file", "path", path)
+}
@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+const (
+	// PassphraseEnv holds the vault passphrase, so scripts are not prompted
+	PassphraseEnv = "EVE_ALTS_PASSPHRASE"
+	// NewPassphraseEnv holds the passphrase for vault rotate --mode passphrase
+	NewPassphraseEnv = "EVE_ALTS_NEW_PASSPHRASE"
+)
+
+var ErrPassphraseMismatch = errors.New("passphrases do not match")
+
+func init() {
+	register(command{"vault status", "[--json]", vaultStatus})
+	register(command{"vault rotate", "[--mode keyfile|passphrase] [--keyfile <path>]", vaultRotate})
+	register(command{"vault reset", "--yes", vaultReset})
+}
+
+// readPassphrase takes the passphrase from env, or else asks for it on the
+// terminal. With confirm, it is asked for twice.
+func readPassphrase(env, prompt string, confirm bool) (string, error) {
+	if p := os.Getenv(env); p != "" {
+		return p, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errors.Wrap(repository.ErrEmptyPassphrase, "set "+env+" when not running in a terminal")
+	}
+
+	ask := func(prompt string) (string, error) {
+		fmt.Fprint(os.Stderr, prompt)
+		b, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return string(b), errors.Wrap(err, "could not read passphrase")
+	}
+
+	p, err := ask(prompt + ": ")
+	if err != nil || !confirm {
+		return p, err
+	}
+	again, err := ask("Repeat " + prompt + ": ")
+	if err != nil {
+		return "", err
+	}
+	if again != p {
+		return "", ErrPassphraseMismatch
+	}
+	return p, nil
+}
+
+// unlockVault unlocks the token vault with the configured key source,
+// asking for a passphrase if needed
+func unlockVault(ctx context.Context, deps dependencies) error {
+	src := deps.VaultSource()
+	if src.Mode == repository.VaultModePassphrase {
+		status, err := deps.AppRepo().VaultStatus(ctx)
+		if err != nil {
+			return err
+		}
+
+		// a vault that was never set up gets a new passphrase, so confirm it
+		if src.Passphrase, err = readPassphrase(PassphraseEnv, "Vault passphrase", status.Mode == ""); err != nil {
+			return err
+		}
+	}
+
+	return errors.Wrap(deps.AppRepo().UnlockVault(ctx, src), "could not unlock the token vault")
+}
+
+type vaultStatusView struct {
+	Mode   repository.VaultMode `json:"mode"`
+	Sealed int                  `json:"sealed_tokens"`
+	Plain  int                  `json:"plaintext_tokens"`
+}
+
+func vaultStatus(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	var asJSON bool
+	if err := parseFlags(newFlags("vault status", &asJSON), args); err != nil {
+		return err
+	}
+
+	status, err := deps.AppRepo().VaultStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	v := vaultStatusView{Mode: status.Mode, Sealed: status.Sealed, Plain: status.Plain}
+	if asJSON {
+		return writeJSON(out, v)
+	}
+
+	mode := string(v.Mode)
+	if mode == "" {
+		mode = "not set up"
+	}
+	return writeTable(out, "MODE\tSEALED\tPLAINTEXT", []string{
+		fmt.Sprintf("%s\t%d\t%d", mode, v.Sealed, v.Plain),
+	})
+}
+
+// vaultRotate seals every token with a new key. Moving to another mode needs
+// the config file updated to match afterwards. A running app notices the new
+// key and locks its vault until it is restarted.
+func vaultRotate(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	current := deps.VaultSource()
+
+	var ignored bool
+	var mode string
+	to := repository.KeySource{KeyFile: current.KeyFile}
+	fs := newFlags("vault rotate", &ignored)
+	fs.StringVar(&mode, "mode", string(current.Mode), "keyfile or passphrase")
+	fs.StringVar(&to.KeyFile, "keyfile", current.KeyFile, "where to write the new key in keyfile mode")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return errors.Wrap(ErrUsage, "vault rotate takes no arguments")
+	}
+
+	var err error
+	if to.Mode, err = repository.ParseVaultMode(mode); err != nil {
+		return errors.Wrap(ErrUsage, err.Error())
+	}
+
+	if to.Mode == repository.VaultModePassphrase {
+		if to.Passphrase, err = readPassphrase(NewPassphraseEnv, "New vault passphrase", true); err != nil {
+			return err
+		}
+	}
+
+	if err := deps.AppRepo().RotateVault(ctx, to); err != nil {
+		return errors.Wrap(err, "could not rotate the token vault")
+	}
+
+	fmt.Fprintf(out, "tokens are now sealed with a new %s key; restart the app if it is running\n", to.Mode)
+	if to.Mode != current.Mode || (to.Mode == repository.VaultModeKeyFile && to.KeyFile != current.KeyFile) {
+		fmt.Fprintf(out, "set [vault] mode = %q and keyfile = %q in the config file before the next start\n", to.Mode, to.KeyFile)
+	}
+	return nil
+}
+
+// vaultReset forgets every stored token, for a lost passphrase or key file
+func vaultReset(ctx context.Context, deps dependencies, args []string, out io.Writer) error {
+	var ignored, yes bool
+	fs := newFlags("vault reset", &ignored)
+	fs.BoolVar(&yes, "yes", false, "confirm that every character must be added again")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if !yes {
+		return errors.Wrap(ErrUsage, "vault reset deletes every stored token; pass --yes to confirm")
+	}
+
+	if err := deps.AppRepo().ResetVault(ctx); err != nil {
+		return errors.Wrap(err, "could not reset the token vault")
+	}
+
+	fmt.Fprintln(out, "stored tokens deleted; add each character again to refresh it")
+	return nil
+}
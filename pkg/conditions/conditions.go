@@ -0,0 +1,6 @@
+package conditions
+
+//go:generate go-enum --sql --marshal --names --values
+
+// ENUM(corporation, alliance, min_sp, omega)
+type Kind string
@@ -0,0 +1,133 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version:
+// Revision:
+// Build Date:
+// Built By:
+
+package conditions
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// KindCorporation is a Kind of type corporation.
+	KindCorporation Kind = "corporation"
+	// KindAlliance is a Kind of type alliance.
+	KindAlliance Kind = "alliance"
+	// KindMinSp is a Kind of type min_sp.
+	KindMinSp Kind = "min_sp"
+	// KindOmega is a Kind of type omega.
+	KindOmega Kind = "omega"
+)
+
+var ErrInvalidKind = fmt.Errorf("not a valid Kind, try [%s]", strings.Join(_KindNames, ", "))
+
+var _KindNames = []string{
+	string(KindCorporation),
+	string(KindAlliance),
+	string(KindMinSp),
+	string(KindOmega),
+}
+
+// KindNames returns a list of possible string values of Kind.
+func KindNames() []string {
+	tmp := make([]string, len(_KindNames))
+	copy(tmp, _KindNames)
+	return tmp
+}
+
+// KindValues returns a list of the values for Kind
+func KindValues() []Kind {
+	return []Kind{
+		KindCorporation,
+		KindAlliance,
+		KindMinSp,
+		KindOmega,
+	}
+}
+
+// String implements the Stringer interface.
+func (x Kind) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x Kind) IsValid() bool {
+	_, err := ParseKind(string(x))
+	return err == nil
+}
+
+var _KindValue = map[string]Kind{
+	"corporation": KindCorporation,
+	"alliance":    KindAlliance,
+	"min_sp":      KindMinSp,
+	"omega":       KindOmega,
+}
+
+// ParseKind attempts to convert a string to a Kind.
+func ParseKind(name string) (Kind, error) {
+	if x, ok := _KindValue[name]; ok {
+		return x, nil
+	}
+	return Kind(""), fmt.Errorf("%s is %w", name, ErrInvalidKind)
+}
+
+// MarshalText implements the text marshaller method.
+func (x Kind) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *Kind) UnmarshalText(text []byte) error {
+	tmp, err := ParseKind(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+var errKindNilPtr = errors.New("value pointer is nil") // one per type for package clashes
+
+// Scan implements the Scanner interface.
+func (x *Kind) Scan(value interface{}) (err error) {
+	if value == nil {
+		*x = Kind("")
+		return
+	}
+
+	// A wider range of scannable types.
+	// driver.Value values at the top of the list for expediency
+	switch v := value.(type) {
+	case string:
+		*x, err = ParseKind(v)
+	case []byte:
+		*x, err = ParseKind(string(v))
+	case Kind:
+		*x = v
+	case *Kind:
+		if v == nil {
+			return errKindNilPtr
+		}
+		*x = *v
+	case *string:
+		if v == nil {
+			return errKindNilPtr
+		}
+		*x, err = ParseKind(*v)
+	default:
+		return errors.New("invalid type for Kind")
+	}
+
+	return
+}
+
+// Value implements the driver Valuer interface.
+func (x Kind) Value() (driver.Value, error) {
+	return x.String(), nil
+}
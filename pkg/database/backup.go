@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"os"
+
+	gosqlite3 "github.com/mattn/go-sqlite3"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+)
+
+var ErrBadBackup = errors.New("backup is not a usable database")
+
+// Backup writes a consistent copy of the database to path with VACUUM INTO,
+// while other connections go on reading and writing. The copy is written
+// beside path first, so path never holds a partial backup.
+func (c *WrappedConnection) Backup(ctx context.Context, path string) error {
+	tmp := path + ".tmp"
+	_ = os.Remove(tmp)
+
+	if _, err := c.DB.ExecContext(ctx, "VACUUM INTO ?", tmp); err != nil {
+		_ = os.Remove(tmp)
+		return errors.Wrap(err, "could not back up database", "path", path)
+	}
+
+	return errors.Wrap(os.Rename(tmp, path), "could not move backup into place", "path", path)
+}
+
+// Restore replaces the whole database with a backup, using SQLite's online
+// backup API so the open connections see the restored data
+func (c *WrappedConnection) Restore(ctx context.Context, path string) (err error) {
+	if _, err := os.Stat(path); err != nil {
+		return errors.Wrap(err, "could not find backup", "path", path)
+	}
+
+	srcDB, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return errors.Wrap(err, "could not open backup", "path", path)
+	}
+	defer func() {
+		if cerr := srcDB.Close(); cerr != nil && err == nil {
+			err = errors.Wrap(cerr, "could not close backup", "path", path)
+		}
+	}()
+
+	var check string
+	if err := srcDB.QueryRowContext(ctx, "PRAGMA quick_check").Scan(&check); err != nil || check != "ok" {
+		return errors.Wrap(ErrBadBackup, "backup failed its integrity check", "path", path, "result", check)
+	}
+
+	src, err := srcDB.Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not connect to backup", "path", path)
+	}
+	defer src.Close()
+
+	dst, err := c.DB.Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not connect to database")
+	}
+	defer dst.Close()
+
+	return dst.Raw(func(dstRaw interface{}) error {
+		return src.Raw(func(srcRaw interface{}) error {
+			dstConn, ok := dstRaw.(*gosqlite3.SQLiteConn)
+			if !ok {
+				return errors.New("database is not sqlite")
+			}
+			srcConn, ok := srcRaw.(*gosqlite3.SQLiteConn)
+			if !ok {
+				return errors.New("backup is not sqlite")
+			}
+
+			b, err := dstConn.Backup("main", srcConn, "main")
+			if err != nil {
+				return errors.Wrap(err, "could not start restore")
+			}
+
+			if _, err := b.Step(-1); err != nil {
+				_ = b.Finish()
+				return errors.Wrap(err, "could not restore backup", "path", path)
+			}
+			return errors.Wrap(b.Finish(), "could not finish restore", "path", path)
+		})
+	})
+}
+
+// SchemaVersion is the last migration applied, or 0 for a database that
+// was never migrated. dirty means that migration failed part way.
+func (c *WrappedConnection) SchemaVersion(ctx context.Context) (version uint, dirty bool, err error) {
+	var n int
+	if err := c.DB.QueryRowContext(ctx, `SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'`).Scan(&n); err != nil {
+		return 0, false, errors.Wrap(err, "could not look for schema_migrations")
+	}
+	if n == 0 {
+		return 0, false, nil
+	}
+
+	err = c.DB.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	return version, dirty, errors.Wrap(err, "could not read schema version")
+}
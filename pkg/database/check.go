@@ -0,0 +1,145 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"strings"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+	"github.com/kava-forge/eve-alts/lib/logging"
+	"github.com/kava-forge/eve-alts/lib/logging/level"
+)
+
+var ErrCorrupt = errors.New("database failed its integrity check")
+
+// CheckIntegrity runs the integrity check chosen when the database was
+// opened, quick_check unless Open was told otherwise
+func (c *WrappedConnection) CheckIntegrity(ctx context.Context) error {
+	pragma := "PRAGMA quick_check"
+	switch c.integrity {
+	case IntegrityOff:
+		return nil
+	case IntegrityFull:
+		pragma = "PRAGMA integrity_check"
+	}
+
+	rows, err := c.DB.QueryContext(ctx, pragma)
+	if err != nil {
+		return errors.Wrap(err, "could not check database integrity")
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return errors.Wrap(err, "could not read integrity check")
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "could not read integrity check")
+	}
+
+	if len(problems) > 0 {
+		return errors.Wrap(ErrCorrupt, "restore a snapshot with backup restore", "problems", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// orphan is a row whose parent is gone, from PRAGMA foreign_key_check
+type orphan struct {
+	table string
+	rowid int64
+}
+
+// CleanOrphans deletes rows whose parent row no longer exists, as ON DELETE
+// CASCADE would have done had foreign keys been enforced when the parent was
+// deleted. It returns how many rows went from each table.
+func (c *WrappedConnection) CleanOrphans(ctx context.Context) (map[string]int64, error) {
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not start transaction")
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	removed := map[string]int64{}
+	// deleting an orphan may orphan its own children when foreign keys are
+	// off, so look again until nothing is left
+	for {
+		rows, err := tx.QueryContext(ctx, "PRAGMA foreign_key_check")
+		if err != nil {
+			return nil, errors.Wrap(err, "could not check foreign keys")
+		}
+
+		var orphans []orphan
+		for rows.Next() {
+			var o orphan
+			var parent string
+			var fkid int64
+			if err := rows.Scan(&o.table, &o.rowid, &parent, &fkid); err != nil {
+				rows.Close()
+				return nil, errors.Wrap(err, "could not read foreign key check")
+			}
+			orphans = append(orphans, o)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, errors.Wrap(err, "could not read foreign key check")
+		}
+
+		if len(orphans) == 0 {
+			break
+		}
+
+		for _, o := range orphans {
+			res, err := tx.ExecContext(ctx, `DELETE FROM "`+strings.ReplaceAll(o.table, `"`, `""`)+`" WHERE rowid = ?`, o.rowid)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not delete orphaned row", "table", o.table, "rowid", o.rowid)
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return nil, errors.Wrap(err, "could not count deleted rows", "table", o.table)
+			}
+			removed[o.table] += n
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "could not commit orphan cleanup")
+	}
+	return removed, nil
+}
+
+// Check runs the startup checks on conn: its integrity check, then removal
+// of rows orphaned by deletes made while foreign keys were off. A schema from
+// a newer build than migrations is refused before anything is deleted, since
+// this build cannot know what its rows mean; a dirty one is left for Migrate
+// to report.
+func Check(ctx context.Context, logger logging.Logger, conn Connection, migrations embed.FS) error {
+	if err := conn.CheckIntegrity(ctx); err != nil {
+		return err
+	}
+
+	status, err := Status(ctx, conn, migrations)
+	if err != nil {
+		return err
+	}
+	if status.TooNew() {
+		return errors.Wrap(ErrSchemaTooNew, "cannot use this database", "version", status.Current, "latest", status.Latest)
+	}
+	if status.Dirty {
+		return nil
+	}
+
+	removed, err := conn.CleanOrphans(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not clean up orphaned rows")
+	}
+	for table, n := range removed {
+		level.Info(logger).Message("removed orphaned rows", "table", table, "rows", n)
+	}
+	return nil
+}
@@ -11,6 +11,18 @@ import (
 )
 
 type FakeConnection struct {
+	BackupStub        func(context.Context, string) error
+	backupMutex       sync.RWMutex
+	backupArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	backupReturns struct {
+		result1 error
+	}
+	backupReturnsOnCall map[int]struct {
+		result1 error
+	}
 	BeginTxStub        func(context.Context, *sql.TxOptions) (database.Tx, error)
 	beginTxMutex       sync.RWMutex
 	beginTxArgsForCall []struct {
@@ -25,6 +37,30 @@ type FakeConnection struct {
 		result1 database.Tx
 		result2 error
 	}
+	CheckIntegrityStub        func(context.Context) error
+	checkIntegrityMutex       sync.RWMutex
+	checkIntegrityArgsForCall []struct {
+		arg1 context.Context
+	}
+	checkIntegrityReturns struct {
+		result1 error
+	}
+	checkIntegrityReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CleanOrphansStub        func(context.Context) (map[string]int64, error)
+	cleanOrphansMutex       sync.RWMutex
+	cleanOrphansArgsForCall []struct {
+		arg1 context.Context
+	}
+	cleanOrphansReturns struct {
+		result1 map[string]int64
+		result2 error
+	}
+	cleanOrphansReturnsOnCall map[int]struct {
+		result1 map[string]int64
+		result2 error
+	}
 	CloseStub        func(context.Context) error
 	closeMutex       sync.RWMutex
 	closeArgsForCall []struct {
@@ -36,6 +72,19 @@ type FakeConnection struct {
 	closeReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ConnStub        func(context.Context) (*sql.Conn, error)
+	connMutex       sync.RWMutex
+	connArgsForCall []struct {
+		arg1 context.Context
+	}
+	connReturns struct {
+		result1 *sql.Conn
+		result2 error
+	}
+	connReturnsOnCall map[int]struct {
+		result1 *sql.Conn
+		result2 error
+	}
 	ExecContextStub        func(context.Context, string, ...interface{}) (sql.Result, error)
 	execContextMutex       sync.RWMutex
 	execContextArgsForCall []struct {
@@ -51,6 +100,19 @@ type FakeConnection struct {
 		result1 sql.Result
 		result2 error
 	}
+	ForceVersionStub        func(context.Context, embed.FS, uint) error
+	forceVersionMutex       sync.RWMutex
+	forceVersionArgsForCall []struct {
+		arg1 context.Context
+		arg2 embed.FS
+		arg3 uint
+	}
+	forceVersionReturns struct {
+		result1 error
+	}
+	forceVersionReturnsOnCall map[int]struct {
+		result1 error
+	}
 	MigrateStub        func(context.Context, embed.FS) error
 	migrateMutex       sync.RWMutex
 	migrateArgsForCall []struct {
@@ -63,6 +125,19 @@ type FakeConnection struct {
 	migrateReturnsOnCall map[int]struct {
 		result1 error
 	}
+	MigrateToStub        func(context.Context, embed.FS, uint) error
+	migrateToMutex       sync.RWMutex
+	migrateToArgsForCall []struct {
+		arg1 context.Context
+		arg2 embed.FS
+		arg3 uint
+	}
+	migrateToReturns struct {
+		result1 error
+	}
+	migrateToReturnsOnCall map[int]struct {
+		result1 error
+	}
 	PrepareContextStub        func(context.Context, string) (*sql.Stmt, error)
 	prepareContextMutex       sync.RWMutex
 	prepareContextArgsForCall []struct {
@@ -105,10 +180,99 @@ type FakeConnection struct {
 	queryRowContextReturnsOnCall map[int]struct {
 		result1 *sql.Row
 	}
+	RestoreStub        func(context.Context, string) error
+	restoreMutex       sync.RWMutex
+	restoreArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	restoreReturns struct {
+		result1 error
+	}
+	restoreReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SchemaVersionStub        func(context.Context) (uint, bool, error)
+	schemaVersionMutex       sync.RWMutex
+	schemaVersionArgsForCall []struct {
+		arg1 context.Context
+	}
+	schemaVersionReturns struct {
+		result1 uint
+		result2 bool
+		result3 error
+	}
+	schemaVersionReturnsOnCall map[int]struct {
+		result1 uint
+		result2 bool
+		result3 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeConnection) Backup(arg1 context.Context, arg2 string) error {
+	fake.backupMutex.Lock()
+	ret, specificReturn := fake.backupReturnsOnCall[len(fake.backupArgsForCall)]
+	fake.backupArgsForCall = append(fake.backupArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.BackupStub
+	fakeReturns := fake.backupReturns
+	fake.recordInvocation("Backup", []interface{}{arg1, arg2})
+	fake.backupMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeConnection) BackupCallCount() int {
+	fake.backupMutex.RLock()
+	defer fake.backupMutex.RUnlock()
+	return len(fake.backupArgsForCall)
+}
+
+func (fake *FakeConnection) BackupCalls(stub func(context.Context, string) error) {
+	fake.backupMutex.Lock()
+	defer fake.backupMutex.Unlock()
+	fake.BackupStub = stub
+}
+
+func (fake *FakeConnection) BackupArgsForCall(i int) (context.Context, string) {
+	fake.backupMutex.RLock()
+	defer fake.backupMutex.RUnlock()
+	argsForCall := fake.backupArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeConnection) BackupReturns(result1 error) {
+	fake.backupMutex.Lock()
+	defer fake.backupMutex.Unlock()
+	fake.BackupStub = nil
+	fake.backupReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) BackupReturnsOnCall(i int, result1 error) {
+	fake.backupMutex.Lock()
+	defer fake.backupMutex.Unlock()
+	fake.BackupStub = nil
+	if fake.backupReturnsOnCall == nil {
+		fake.backupReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.backupReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeConnection) BeginTx(arg1 context.Context, arg2 *sql.TxOptions) (database.Tx, error) {
 	fake.beginTxMutex.Lock()
 	ret, specificReturn := fake.beginTxReturnsOnCall[len(fake.beginTxArgsForCall)]
@@ -174,6 +338,131 @@ func (fake *FakeConnection) BeginTxReturnsOnCall(i int, result1 database.Tx, res
 	}{result1, result2}
 }
 
+func (fake *FakeConnection) CheckIntegrity(arg1 context.Context) error {
+	fake.checkIntegrityMutex.Lock()
+	ret, specificReturn := fake.checkIntegrityReturnsOnCall[len(fake.checkIntegrityArgsForCall)]
+	fake.checkIntegrityArgsForCall = append(fake.checkIntegrityArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.CheckIntegrityStub
+	fakeReturns := fake.checkIntegrityReturns
+	fake.recordInvocation("CheckIntegrity", []interface{}{arg1})
+	fake.checkIntegrityMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeConnection) CheckIntegrityCallCount() int {
+	fake.checkIntegrityMutex.RLock()
+	defer fake.checkIntegrityMutex.RUnlock()
+	return len(fake.checkIntegrityArgsForCall)
+}
+
+func (fake *FakeConnection) CheckIntegrityCalls(stub func(context.Context) error) {
+	fake.checkIntegrityMutex.Lock()
+	defer fake.checkIntegrityMutex.Unlock()
+	fake.CheckIntegrityStub = stub
+}
+
+func (fake *FakeConnection) CheckIntegrityArgsForCall(i int) context.Context {
+	fake.checkIntegrityMutex.RLock()
+	defer fake.checkIntegrityMutex.RUnlock()
+	argsForCall := fake.checkIntegrityArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConnection) CheckIntegrityReturns(result1 error) {
+	fake.checkIntegrityMutex.Lock()
+	defer fake.checkIntegrityMutex.Unlock()
+	fake.CheckIntegrityStub = nil
+	fake.checkIntegrityReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) CheckIntegrityReturnsOnCall(i int, result1 error) {
+	fake.checkIntegrityMutex.Lock()
+	defer fake.checkIntegrityMutex.Unlock()
+	fake.CheckIntegrityStub = nil
+	if fake.checkIntegrityReturnsOnCall == nil {
+		fake.checkIntegrityReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.checkIntegrityReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) CleanOrphans(arg1 context.Context) (map[string]int64, error) {
+	fake.cleanOrphansMutex.Lock()
+	ret, specificReturn := fake.cleanOrphansReturnsOnCall[len(fake.cleanOrphansArgsForCall)]
+	fake.cleanOrphansArgsForCall = append(fake.cleanOrphansArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.CleanOrphansStub
+	fakeReturns := fake.cleanOrphansReturns
+	fake.recordInvocation("CleanOrphans", []interface{}{arg1})
+	fake.cleanOrphansMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeConnection) CleanOrphansCallCount() int {
+	fake.cleanOrphansMutex.RLock()
+	defer fake.cleanOrphansMutex.RUnlock()
+	return len(fake.cleanOrphansArgsForCall)
+}
+
+func (fake *FakeConnection) CleanOrphansCalls(stub func(context.Context) (map[string]int64, error)) {
+	fake.cleanOrphansMutex.Lock()
+	defer fake.cleanOrphansMutex.Unlock()
+	fake.CleanOrphansStub = stub
+}
+
+func (fake *FakeConnection) CleanOrphansArgsForCall(i int) context.Context {
+	fake.cleanOrphansMutex.RLock()
+	defer fake.cleanOrphansMutex.RUnlock()
+	argsForCall := fake.cleanOrphansArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConnection) CleanOrphansReturns(result1 map[string]int64, result2 error) {
+	fake.cleanOrphansMutex.Lock()
+	defer fake.cleanOrphansMutex.Unlock()
+	fake.CleanOrphansStub = nil
+	fake.cleanOrphansReturns = struct {
+		result1 map[string]int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) CleanOrphansReturnsOnCall(i int, result1 map[string]int64, result2 error) {
+	fake.cleanOrphansMutex.Lock()
+	defer fake.cleanOrphansMutex.Unlock()
+	fake.CleanOrphansStub = nil
+	if fake.cleanOrphansReturnsOnCall == nil {
+		fake.cleanOrphansReturnsOnCall = make(map[int]struct {
+			result1 map[string]int64
+			result2 error
+		})
+	}
+	fake.cleanOrphansReturnsOnCall[i] = struct {
+		result1 map[string]int64
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeConnection) Close(arg1 context.Context) error {
 	fake.closeMutex.Lock()
 	ret, specificReturn := fake.closeReturnsOnCall[len(fake.closeArgsForCall)]
@@ -235,6 +524,70 @@ func (fake *FakeConnection) CloseReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeConnection) Conn(arg1 context.Context) (*sql.Conn, error) {
+	fake.connMutex.Lock()
+	ret, specificReturn := fake.connReturnsOnCall[len(fake.connArgsForCall)]
+	fake.connArgsForCall = append(fake.connArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.ConnStub
+	fakeReturns := fake.connReturns
+	fake.recordInvocation("Conn", []interface{}{arg1})
+	fake.connMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeConnection) ConnCallCount() int {
+	fake.connMutex.RLock()
+	defer fake.connMutex.RUnlock()
+	return len(fake.connArgsForCall)
+}
+
+func (fake *FakeConnection) ConnCalls(stub func(context.Context) (*sql.Conn, error)) {
+	fake.connMutex.Lock()
+	defer fake.connMutex.Unlock()
+	fake.ConnStub = stub
+}
+
+func (fake *FakeConnection) ConnArgsForCall(i int) context.Context {
+	fake.connMutex.RLock()
+	defer fake.connMutex.RUnlock()
+	argsForCall := fake.connArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConnection) ConnReturns(result1 *sql.Conn, result2 error) {
+	fake.connMutex.Lock()
+	defer fake.connMutex.Unlock()
+	fake.ConnStub = nil
+	fake.connReturns = struct {
+		result1 *sql.Conn
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConnection) ConnReturnsOnCall(i int, result1 *sql.Conn, result2 error) {
+	fake.connMutex.Lock()
+	defer fake.connMutex.Unlock()
+	fake.ConnStub = nil
+	if fake.connReturnsOnCall == nil {
+		fake.connReturnsOnCall = make(map[int]struct {
+			result1 *sql.Conn
+			result2 error
+		})
+	}
+	fake.connReturnsOnCall[i] = struct {
+		result1 *sql.Conn
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeConnection) ExecContext(arg1 context.Context, arg2 string, arg3 ...interface{}) (sql.Result, error) {
 	fake.execContextMutex.Lock()
 	ret, specificReturn := fake.execContextReturnsOnCall[len(fake.execContextArgsForCall)]
@@ -301,6 +654,69 @@ func (fake *FakeConnection) ExecContextReturnsOnCall(i int, result1 sql.Result,
 	}{result1, result2}
 }
 
+func (fake *FakeConnection) ForceVersion(arg1 context.Context, arg2 embed.FS, arg3 uint) error {
+	fake.forceVersionMutex.Lock()
+	ret, specificReturn := fake.forceVersionReturnsOnCall[len(fake.forceVersionArgsForCall)]
+	fake.forceVersionArgsForCall = append(fake.forceVersionArgsForCall, struct {
+		arg1 context.Context
+		arg2 embed.FS
+		arg3 uint
+	}{arg1, arg2, arg3})
+	stub := fake.ForceVersionStub
+	fakeReturns := fake.forceVersionReturns
+	fake.recordInvocation("ForceVersion", []interface{}{arg1, arg2, arg3})
+	fake.forceVersionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeConnection) ForceVersionCallCount() int {
+	fake.forceVersionMutex.RLock()
+	defer fake.forceVersionMutex.RUnlock()
+	return len(fake.forceVersionArgsForCall)
+}
+
+func (fake *FakeConnection) ForceVersionCalls(stub func(context.Context, embed.FS, uint) error) {
+	fake.forceVersionMutex.Lock()
+	defer fake.forceVersionMutex.Unlock()
+	fake.ForceVersionStub = stub
+}
+
+func (fake *FakeConnection) ForceVersionArgsForCall(i int) (context.Context, embed.FS, uint) {
+	fake.forceVersionMutex.RLock()
+	defer fake.forceVersionMutex.RUnlock()
+	argsForCall := fake.forceVersionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeConnection) ForceVersionReturns(result1 error) {
+	fake.forceVersionMutex.Lock()
+	defer fake.forceVersionMutex.Unlock()
+	fake.ForceVersionStub = nil
+	fake.forceVersionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) ForceVersionReturnsOnCall(i int, result1 error) {
+	fake.forceVersionMutex.Lock()
+	defer fake.forceVersionMutex.Unlock()
+	fake.ForceVersionStub = nil
+	if fake.forceVersionReturnsOnCall == nil {
+		fake.forceVersionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.forceVersionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeConnection) Migrate(arg1 context.Context, arg2 embed.FS) error {
 	fake.migrateMutex.Lock()
 	ret, specificReturn := fake.migrateReturnsOnCall[len(fake.migrateArgsForCall)]
@@ -363,6 +779,69 @@ func (fake *FakeConnection) MigrateReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeConnection) MigrateTo(arg1 context.Context, arg2 embed.FS, arg3 uint) error {
+	fake.migrateToMutex.Lock()
+	ret, specificReturn := fake.migrateToReturnsOnCall[len(fake.migrateToArgsForCall)]
+	fake.migrateToArgsForCall = append(fake.migrateToArgsForCall, struct {
+		arg1 context.Context
+		arg2 embed.FS
+		arg3 uint
+	}{arg1, arg2, arg3})
+	stub := fake.MigrateToStub
+	fakeReturns := fake.migrateToReturns
+	fake.recordInvocation("MigrateTo", []interface{}{arg1, arg2, arg3})
+	fake.migrateToMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeConnection) MigrateToCallCount() int {
+	fake.migrateToMutex.RLock()
+	defer fake.migrateToMutex.RUnlock()
+	return len(fake.migrateToArgsForCall)
+}
+
+func (fake *FakeConnection) MigrateToCalls(stub func(context.Context, embed.FS, uint) error) {
+	fake.migrateToMutex.Lock()
+	defer fake.migrateToMutex.Unlock()
+	fake.MigrateToStub = stub
+}
+
+func (fake *FakeConnection) MigrateToArgsForCall(i int) (context.Context, embed.FS, uint) {
+	fake.migrateToMutex.RLock()
+	defer fake.migrateToMutex.RUnlock()
+	argsForCall := fake.migrateToArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeConnection) MigrateToReturns(result1 error) {
+	fake.migrateToMutex.Lock()
+	defer fake.migrateToMutex.Unlock()
+	fake.MigrateToStub = nil
+	fake.migrateToReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) MigrateToReturnsOnCall(i int, result1 error) {
+	fake.migrateToMutex.Lock()
+	defer fake.migrateToMutex.Unlock()
+	fake.MigrateToStub = nil
+	if fake.migrateToReturnsOnCall == nil {
+		fake.migrateToReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.migrateToReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeConnection) PrepareContext(arg1 context.Context, arg2 string) (*sql.Stmt, error) {
 	fake.prepareContextMutex.Lock()
 	ret, specificReturn := fake.prepareContextReturnsOnCall[len(fake.prepareContextArgsForCall)]
@@ -557,23 +1036,168 @@ func (fake *FakeConnection) QueryRowContextReturnsOnCall(i int, result1 *sql.Row
 	}{result1}
 }
 
+func (fake *FakeConnection) Restore(arg1 context.Context, arg2 string) error {
+	fake.restoreMutex.Lock()
+	ret, specificReturn := fake.restoreReturnsOnCall[len(fake.restoreArgsForCall)]
+	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RestoreStub
+	fakeReturns := fake.restoreReturns
+	fake.recordInvocation("Restore", []interface{}{arg1, arg2})
+	fake.restoreMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeConnection) RestoreCallCount() int {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return len(fake.restoreArgsForCall)
+}
+
+func (fake *FakeConnection) RestoreCalls(stub func(context.Context, string) error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = stub
+}
+
+func (fake *FakeConnection) RestoreArgsForCall(i int) (context.Context, string) {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	argsForCall := fake.restoreArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeConnection) RestoreReturns(result1 error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = nil
+	fake.restoreReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) RestoreReturnsOnCall(i int, result1 error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = nil
+	if fake.restoreReturnsOnCall == nil {
+		fake.restoreReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.restoreReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConnection) SchemaVersion(arg1 context.Context) (uint, bool, error) {
+	fake.schemaVersionMutex.Lock()
+	ret, specificReturn := fake.schemaVersionReturnsOnCall[len(fake.schemaVersionArgsForCall)]
+	fake.schemaVersionArgsForCall = append(fake.schemaVersionArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.SchemaVersionStub
+	fakeReturns := fake.schemaVersionReturns
+	fake.recordInvocation("SchemaVersion", []interface{}{arg1})
+	fake.schemaVersionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeConnection) SchemaVersionCallCount() int {
+	fake.schemaVersionMutex.RLock()
+	defer fake.schemaVersionMutex.RUnlock()
+	return len(fake.schemaVersionArgsForCall)
+}
+
+func (fake *FakeConnection) SchemaVersionCalls(stub func(context.Context) (uint, bool, error)) {
+	fake.schemaVersionMutex.Lock()
+	defer fake.schemaVersionMutex.Unlock()
+	fake.SchemaVersionStub = stub
+}
+
+func (fake *FakeConnection) SchemaVersionArgsForCall(i int) context.Context {
+	fake.schemaVersionMutex.RLock()
+	defer fake.schemaVersionMutex.RUnlock()
+	argsForCall := fake.schemaVersionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConnection) SchemaVersionReturns(result1 uint, result2 bool, result3 error) {
+	fake.schemaVersionMutex.Lock()
+	defer fake.schemaVersionMutex.Unlock()
+	fake.SchemaVersionStub = nil
+	fake.schemaVersionReturns = struct {
+		result1 uint
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeConnection) SchemaVersionReturnsOnCall(i int, result1 uint, result2 bool, result3 error) {
+	fake.schemaVersionMutex.Lock()
+	defer fake.schemaVersionMutex.Unlock()
+	fake.SchemaVersionStub = nil
+	if fake.schemaVersionReturnsOnCall == nil {
+		fake.schemaVersionReturnsOnCall = make(map[int]struct {
+			result1 uint
+			result2 bool
+			result3 error
+		})
+	}
+	fake.schemaVersionReturnsOnCall[i] = struct {
+		result1 uint
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeConnection) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.backupMutex.RLock()
+	defer fake.backupMutex.RUnlock()
 	fake.beginTxMutex.RLock()
 	defer fake.beginTxMutex.RUnlock()
+	fake.checkIntegrityMutex.RLock()
+	defer fake.checkIntegrityMutex.RUnlock()
+	fake.cleanOrphansMutex.RLock()
+	defer fake.cleanOrphansMutex.RUnlock()
 	fake.closeMutex.RLock()
 	defer fake.closeMutex.RUnlock()
+	fake.connMutex.RLock()
+	defer fake.connMutex.RUnlock()
 	fake.execContextMutex.RLock()
 	defer fake.execContextMutex.RUnlock()
+	fake.forceVersionMutex.RLock()
+	defer fake.forceVersionMutex.RUnlock()
 	fake.migrateMutex.RLock()
 	defer fake.migrateMutex.RUnlock()
+	fake.migrateToMutex.RLock()
+	defer fake.migrateToMutex.RUnlock()
 	fake.prepareContextMutex.RLock()
 	defer fake.prepareContextMutex.RUnlock()
 	fake.queryContextMutex.RLock()
 	defer fake.queryContextMutex.RUnlock()
 	fake.queryRowContextMutex.RLock()
 	defer fake.queryRowContextMutex.RUnlock()
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	fake.schemaVersionMutex.RLock()
+	defer fake.schemaVersionMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,28 @@
+package database
+
+// hookedTx is the Tx that TransactWithPolicy hands to its txFunc. It holds
+// the functions registered with AfterCommit until the attempt ends.
+type hookedTx struct {
+	Tx
+	afterCommit []func()
+}
+
+// AfterCommit runs fn once tx has committed. If the attempt rolls back,
+// including to be retried, fn is dropped; a retried txFunc registers it
+// again. A Tx that did not come from TransactWithPolicy cannot say when it
+// commits, so fn runs straight away.
+func AfterCommit(tx Tx, fn func()) {
+	if htx, ok := tx.(*hookedTx); ok {
+		htx.afterCommit = append(htx.afterCommit, fn)
+		return
+	}
+	fn()
+}
+
+func (t *hookedTx) committed() {
+	hooks := t.afterCommit
+	t.afterCommit = nil
+	for _, fn := range hooks {
+		fn()
+	}
+}
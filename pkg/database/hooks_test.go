@@ -0,0 +1,72 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	gosqlite3 "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/database/databasefakes"
+	"github.com/kava-forge/eve-alts/pkg/testhelpers"
+)
+
+func TestAfterCommit(t *testing.T) {
+	t.Parallel()
+
+	deps := testhelpers.NewTestDependencies(t)
+	busy := gosqlite3.Error{Code: gosqlite3.ErrBusy}
+
+	tests := []struct {
+		name      string
+		failures  []error
+		commitErr error
+		wantErr   bool
+		wantRuns  int
+	}{
+		{name: "committed", wantRuns: 1},
+		{name: "retried", failures: []error{busy}, wantRuns: 1},
+		{name: "rolled back", failures: []error{database.NonRetryableError(errors.New("nope"))}, wantErr: true},
+		{name: "commit failed", commitErr: errors.New("nope"), wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tx := &databasefakes.FakeTx{}
+			tx.CommitReturns(tt.commitErr)
+			db := &databasefakes.FakeConnection{}
+			db.BeginTxReturns(tx, nil)
+
+			runs, attempt := 0, 0
+			err := database.TransactWithRetries(context.Background(), deps.Telemetry(), deps.Logger(), db, &sql.TxOptions{}, func(_ context.Context, tx database.Tx) error {
+				database.AfterCommit(tx, func() { runs++ })
+				attempt++
+				if attempt <= len(tt.failures) {
+					return tt.failures[attempt-1]
+				}
+				assert.Equal(t, 0, runs, "hook ran before commit")
+				return nil
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantRuns, runs)
+		})
+	}
+
+	t.Run("outside a transaction", func(t *testing.T) {
+		t.Parallel()
+
+		runs := 0
+		database.AfterCommit(&databasefakes.FakeTx{}, func() { runs++ })
+		assert.Equal(t, 1, runs)
+	})
+}
@@ -16,11 +16,19 @@ type (
 //counterfeiter:generate . Connection
 type Connection interface {
 	BeginTx(context.Context, *sql.TxOptions) (Tx, error)
+	Conn(context.Context) (*sql.Conn, error)
 	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
 	PrepareContext(context.Context, string) (*sql.Stmt, error)
 	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
 	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
 	Migrate(context.Context, embed.FS) error
+	MigrateTo(ctx context.Context, migrations embed.FS, version uint) error
+	ForceVersion(ctx context.Context, migrations embed.FS, version uint) error
+	Backup(ctx context.Context, path string) error
+	Restore(ctx context.Context, path string) error
+	SchemaVersion(ctx context.Context) (version uint, dirty bool, err error)
+	CheckIntegrity(ctx context.Context) error
+	CleanOrphans(ctx context.Context) (map[string]int64, error)
 	Close(ctx context.Context) error
 }
 
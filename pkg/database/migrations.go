@@ -0,0 +1,170 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"io/fs"
+	"slices"
+
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+)
+
+var (
+	ErrSchemaTooNew   = errors.New("database schema is newer than this version of the app")
+	ErrUnknownVersion = errors.New("no migration has that version")
+)
+
+// MigrationStatus is where a database's schema stands against the embedded
+// migrations
+type MigrationStatus struct {
+	// Current is the last migration applied, 0 if none were
+	Current uint
+	// Dirty means Current failed part way and needs fixing by hand
+	Dirty bool
+	// Latest is the newest migration this build knows
+	Latest uint
+	// Pending are the migrations after Current, oldest first
+	Pending []uint
+}
+
+// TooNew reports whether the database was migrated by a newer build
+func (s MigrationStatus) TooNew() bool {
+	return s.Current > s.Latest
+}
+
+// MigrationVersions lists every migration version in migrations, oldest
+// first
+func MigrationVersions(migrations embed.FS) ([]uint, error) {
+	sd, err := iofs.New(migrations, ".")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create source driver")
+	}
+	defer sd.Close()
+
+	v, err := sd.First()
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read migrations")
+	}
+
+	versions := []uint{v}
+	for {
+		next, err := sd.Next(v)
+		if errors.Is(err, fs.ErrNotExist) {
+			return versions, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read migrations")
+		}
+		versions = append(versions, next)
+		v = next
+	}
+}
+
+// LatestVersion is the newest migration version in migrations
+func LatestVersion(migrations embed.FS) (uint, error) {
+	versions, err := MigrationVersions(migrations)
+	if err != nil || len(versions) == 0 {
+		return 0, err
+	}
+	return versions[len(versions)-1], nil
+}
+
+// Status compares the schema of conn with migrations
+func Status(ctx context.Context, conn Connection, migrations embed.FS) (MigrationStatus, error) {
+	var status MigrationStatus
+
+	var err error
+	status.Current, status.Dirty, err = conn.SchemaVersion(ctx)
+	if err != nil {
+		return status, err
+	}
+
+	versions, err := MigrationVersions(migrations)
+	if err != nil {
+		return status, err
+	}
+
+	for _, v := range versions {
+		if v > status.Current {
+			status.Pending = append(status.Pending, v)
+		}
+	}
+	if len(versions) > 0 {
+		status.Latest = versions[len(versions)-1]
+	}
+
+	return status, nil
+}
+
+// checkVersion accepts 0 or a version that is in migrations
+func checkVersion(migrations embed.FS, version uint) error {
+	if version == 0 {
+		return nil
+	}
+
+	versions, err := MigrationVersions(migrations)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(versions, version) {
+		return errors.Wrap(ErrUnknownVersion, "cannot migrate", "version", version)
+	}
+	return nil
+}
+
+// Migrate runs the migrations, first taking a snapshot if any are pending
+// on a database that already has data. A database migrated by a newer build
+// is refused rather than run against a schema this one does not know.
+func (s Snapshots) Migrate(ctx context.Context, conn Connection, migrations embed.FS) error {
+	status, err := Status(ctx, conn, migrations)
+	if err != nil {
+		return err
+	}
+
+	if status.TooNew() {
+		return errors.Wrap(ErrSchemaTooNew, "cannot use this database", "version", status.Current, "latest", status.Latest)
+	}
+
+	if status.Current > 0 && len(status.Pending) > 0 && s.Enabled() {
+		if _, err := s.Take(ctx, conn, ReasonMigrate); err != nil {
+			return errors.Wrap(err, "could not snapshot the database before migrating")
+		}
+	}
+
+	return conn.Migrate(ctx, migrations)
+}
+
+// MigrateTo runs migrations up or down to version, first taking a snapshot
+// of a database that already has data
+func (s Snapshots) MigrateTo(ctx context.Context, conn Connection, migrations embed.FS, version uint) error {
+	if err := checkVersion(migrations, version); err != nil {
+		return err
+	}
+
+	current, _, err := conn.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if current > 0 && current != version && s.Enabled() {
+		if _, err := s.Take(ctx, conn, ReasonMigrate); err != nil {
+			return errors.Wrap(err, "could not snapshot the database before migrating")
+		}
+	}
+
+	return conn.MigrateTo(ctx, migrations, version)
+}
+
+// ForceVersion marks the schema as clean at version, which must be 0 or one
+// of migrations
+func ForceVersion(ctx context.Context, conn Connection, migrations embed.FS, version uint) error {
+	if err := checkVersion(migrations, version); err != nil {
+		return err
+	}
+	return conn.ForceVersion(ctx, migrations, version)
+}
@@ -0,0 +1,117 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kava-forge/eve-alts/migrations"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/database/databasefakes"
+)
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	conn := openTestDB(t)
+	versions, err := database.MigrationVersions(migrations.Migrations)
+	require.NoError(t, err)
+	require.NotEmpty(t, versions)
+	latest := versions[len(versions)-1]
+
+	status, err := database.Status(ctx, conn, migrations.Migrations)
+	require.NoError(t, err)
+	assert.Equal(t, database.MigrationStatus{Latest: latest, Pending: versions}, status)
+
+	var snaps database.Snapshots
+	require.NoError(t, snaps.MigrateTo(ctx, conn, migrations.Migrations, versions[2]))
+	status, err = database.Status(ctx, conn, migrations.Migrations)
+	require.NoError(t, err)
+	assert.Equal(t, versions[2], status.Current)
+	assert.Equal(t, versions[3:], status.Pending)
+
+	require.NoError(t, snaps.MigrateTo(ctx, conn, migrations.Migrations, latest))
+	require.NoError(t, snaps.MigrateTo(ctx, conn, migrations.Migrations, versions[1]))
+	status, err = database.Status(ctx, conn, migrations.Migrations)
+	require.NoError(t, err)
+	assert.Equal(t, versions[1], status.Current, "migrated back down")
+
+	require.NoError(t, snaps.MigrateTo(ctx, conn, migrations.Migrations, 0))
+	version, _, err := conn.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, version, "every migration undone")
+
+	err = snaps.MigrateTo(ctx, conn, migrations.Migrations, latest+100)
+	assert.ErrorIs(t, err, database.ErrUnknownVersion)
+}
+
+func TestForceVersion(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	conn := openTestDB(t)
+	require.NoError(t, conn.Migrate(ctx, migrations.Migrations))
+	latest, err := database.LatestVersion(migrations.Migrations)
+	require.NoError(t, err)
+
+	_, err = conn.ExecContext(ctx, "UPDATE schema_migrations SET dirty = true")
+	require.NoError(t, err)
+	assert.Error(t, conn.Migrate(ctx, migrations.Migrations), "a dirty schema is not migrated")
+
+	require.NoError(t, database.ForceVersion(ctx, conn, migrations.Migrations, latest))
+	version, dirty, err := conn.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, latest, version)
+	assert.False(t, dirty)
+	assert.NoError(t, conn.Migrate(ctx, migrations.Migrations))
+
+	err = database.ForceVersion(ctx, conn, migrations.Migrations, latest+100)
+	assert.ErrorIs(t, err, database.ErrUnknownVersion)
+}
+
+func TestSnapshots_Migrate_TooNew(t *testing.T) {
+	t.Parallel()
+
+	latest, err := database.LatestVersion(migrations.Migrations)
+	require.NoError(t, err)
+
+	conn := &databasefakes.FakeConnection{}
+	conn.SchemaVersionReturns(latest+1, false, nil)
+
+	snaps := database.Snapshots{Dir: t.TempDir()}
+	err = snaps.Migrate(context.Background(), conn, migrations.Migrations)
+	assert.ErrorIs(t, err, database.ErrSchemaTooNew)
+	assert.Zero(t, conn.MigrateCallCount())
+	assert.Zero(t, conn.BackupCallCount())
+}
+
+func TestUniqueTagNamesMigration(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	conn := openTestDB(t)
+	var snaps database.Snapshots
+	require.NoError(t, snaps.MigrateTo(ctx, conn, migrations.Migrations, 12))
+
+	for _, name := range []string{"Logi", "Logi", "Tackle", "Logi"} {
+		_, err := conn.ExecContext(ctx, `INSERT INTO tags ("name", "color_r", "color_g", "color_b", "color_a") VALUES (?, 0, 0, 0, 65535)`, name)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, conn.Migrate(ctx, migrations.Migrations))
+
+	rows, err := conn.QueryContext(ctx, `SELECT "name" FROM tags ORDER BY "id"`)
+	require.NoError(t, err)
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var n string
+		require.NoError(t, rows.Scan(&n))
+		names = append(names, n)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []string{"Logi", "Logi (2)", "Tackle", "Logi (4)"}, names)
+}
@@ -0,0 +1,144 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+)
+
+var ErrBadPragma = errors.New("invalid database setting")
+
+// IntegrityCheck is how thoroughly Check looks at the database file
+type IntegrityCheck string
+
+const (
+	IntegrityQuick IntegrityCheck = "quick"
+	IntegrityFull  IntegrityCheck = "full"
+	IntegrityOff   IntegrityCheck = "off"
+)
+
+var (
+	journalModes    = []string{"delete", "truncate", "persist", "memory", "wal", "off"}
+	synchronousKind = []string{"off", "normal", "full", "extra"}
+	txLocks         = []string{"deferred", "immediate", "exclusive"}
+	integrityChecks = []IntegrityCheck{IntegrityQuick, IntegrityFull, IntegrityOff}
+)
+
+// Pragmas configure every connection Open makes. They are passed in the
+// connection string, so each pooled connection gets them, not only the first.
+type Pragmas struct {
+	// ForeignKeys enforces REFERENCES clauses, including ON DELETE CASCADE
+	ForeignKeys bool
+	// JournalMode is one of delete, truncate, persist, memory, wal or off
+	JournalMode string
+	// BusyTimeout is how long a statement waits on another connection's
+	// lock before failing with SQLITE_BUSY
+	BusyTimeout time.Duration
+	// Synchronous is one of off, normal, full or extra
+	Synchronous string
+	// TxLock is how transactions begin: deferred, immediate or exclusive.
+	// immediate takes the write lock up front, so a transaction never fails
+	// part way through waiting to upgrade a read lock.
+	TxLock string
+	// Integrity is the check Check runs
+	Integrity IntegrityCheck
+}
+
+// DefaultPragmas suit the app database: several goroutines writing at once
+// and a file that is only ever local
+func DefaultPragmas() Pragmas {
+	return Pragmas{
+		ForeignKeys: true,
+		JournalMode: "wal",
+		BusyTimeout: 5 * time.Second,
+		Synchronous: "normal",
+		TxLock:      "immediate",
+		Integrity:   IntegrityQuick,
+	}
+}
+
+// FillDefaults sets any empty field from DefaultPragmas and checks the rest
+func (p *Pragmas) FillDefaults() error {
+	def := DefaultPragmas()
+
+	p.JournalMode = strings.ToLower(p.JournalMode)
+	if p.JournalMode == "" {
+		p.JournalMode = def.JournalMode
+	}
+	if !slices.Contains(journalModes, p.JournalMode) {
+		return errors.Wrap(ErrBadPragma, "unknown journal mode", "journal_mode", p.JournalMode)
+	}
+
+	if p.BusyTimeout < 0 {
+		return errors.Wrap(ErrBadPragma, "busy timeout cannot be negative", "busy_timeout", p.BusyTimeout)
+	}
+
+	p.Synchronous = strings.ToLower(p.Synchronous)
+	if p.Synchronous == "" {
+		p.Synchronous = def.Synchronous
+	}
+	if !slices.Contains(synchronousKind, p.Synchronous) {
+		return errors.Wrap(ErrBadPragma, "unknown synchronous setting", "synchronous", p.Synchronous)
+	}
+
+	p.TxLock = strings.ToLower(p.TxLock)
+	if p.TxLock == "" {
+		p.TxLock = def.TxLock
+	}
+	if !slices.Contains(txLocks, p.TxLock) {
+		return errors.Wrap(ErrBadPragma, "unknown transaction lock", "tx_lock", p.TxLock)
+	}
+
+	p.Integrity = IntegrityCheck(strings.ToLower(string(p.Integrity)))
+	if p.Integrity == "" {
+		p.Integrity = def.Integrity
+	}
+	if !slices.Contains(integrityChecks, p.Integrity) {
+		return errors.Wrap(ErrBadPragma, "unknown integrity check", "integrity_check", p.Integrity)
+	}
+
+	return nil
+}
+
+// dsn appends the pragmas as go-sqlite3 connection parameters
+func (p Pragmas) dsn(path string) string {
+	params := url.Values{}
+	params.Set("_foreign_keys", strconv.FormatBool(p.ForeignKeys))
+	if p.JournalMode != "" {
+		params.Set("_journal_mode", strings.ToUpper(p.JournalMode))
+	}
+	params.Set("_busy_timeout", strconv.FormatInt(p.BusyTimeout.Milliseconds(), 10))
+	if p.Synchronous != "" {
+		params.Set("_synchronous", strings.ToUpper(p.Synchronous))
+	}
+	if p.TxLock != "" {
+		params.Set("_txlock", p.TxLock)
+	}
+	return path + "?" + params.Encode()
+}
+
+// Open opens the SQLite database at path with pragmas applied to every
+// connection, and checks that it can be reached
+func Open(ctx context.Context, path string, pragmas Pragmas) (*WrappedConnection, error) {
+	if err := pragmas.FillDefaults(); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", pragmas.dsn(path))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open database", "path", path)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, errors.Wrap(err, "could not connect to database", "path", path)
+	}
+
+	return &WrappedConnection{DB: db, integrity: pragmas.Integrity}, nil
+}
@@ -0,0 +1,133 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kava-forge/eve-alts/migrations"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/testhelpers"
+)
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	conn := openTestDB(t)
+
+	// hold two connections at once, so the second is a new one from the pool
+	first, err := conn.Conn(ctx)
+	require.NoError(t, err)
+	defer first.Close()
+	second, err := conn.Conn(ctx)
+	require.NoError(t, err)
+	defer second.Close()
+
+	for _, c := range []interface {
+		QueryRowContext(context.Context, string, ...interface{}) *database.Row
+	}{first, second} {
+		var fk, busy, sync int
+		var journal string
+		require.NoError(t, c.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&fk))
+		require.NoError(t, c.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&journal))
+		require.NoError(t, c.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&busy))
+		require.NoError(t, c.QueryRowContext(ctx, "PRAGMA synchronous").Scan(&sync))
+
+		assert.Equal(t, 1, fk)
+		assert.Equal(t, "wal", journal)
+		assert.Equal(t, 5000, busy)
+		assert.Equal(t, 1, sync, "normal")
+	}
+}
+
+func TestPragmas_FillDefaults(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		pragmas database.Pragmas
+		wantErr bool
+	}{
+		{"empty", database.Pragmas{}, false},
+		{"upper case", database.Pragmas{JournalMode: "DELETE", Synchronous: "FULL", TxLock: "Deferred", Integrity: "Full"}, false},
+		{"journal mode", database.Pragmas{JournalMode: "wall"}, true},
+		{"synchronous", database.Pragmas{Synchronous: "sometimes"}, true},
+		{"tx lock", database.Pragmas{TxLock: "shared"}, true},
+		{"integrity check", database.Pragmas{Integrity: "thorough"}, true},
+		{"busy timeout", database.Pragmas{BusyTimeout: -time.Second}, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.pragmas.FillDefaults()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, database.ErrBadPragma)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, tt.pragmas.JournalMode)
+			assert.NotEmpty(t, tt.pragmas.Synchronous)
+			assert.NotEmpty(t, tt.pragmas.TxLock)
+			assert.NotEmpty(t, tt.pragmas.Integrity)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	loose := database.DefaultPragmas()
+	loose.ForeignKeys = false
+	conn := openTestDBWith(t, loose)
+	require.NoError(t, conn.Migrate(ctx, migrations.Migrations))
+
+	exec := func(conn database.Connection, query string, args ...interface{}) {
+		t.Helper()
+		_, err := conn.ExecContext(ctx, query, args...)
+		require.NoError(t, err)
+	}
+
+	exec(conn, `INSERT INTO corporations ("id", "name", "ticker", "picture") VALUES (1, 'Corp', 'CRP', '')`)
+	for c := 1; c <= 2; c++ {
+		exec(conn, `INSERT INTO characters ("id", "name", "picture", "corporation_id") VALUES (?, 'Character', '', 1)`, c)
+		exec(conn, `INSERT INTO tokens ("character_id", "access_token", "refresh_token", "token_type", "expiration") VALUES (?, '', '', '', ?)`, c, time.Now())
+		for sk := range 3 {
+			exec(conn, `INSERT INTO character_skills ("character_id", "skill_id", "skill_level") VALUES (?, ?, 1)`, c, 3000+sk)
+		}
+	}
+
+	// foreign keys are off, so this leaves the token and skills behind
+	exec(conn, `DELETE FROM characters WHERE "id" = 1`)
+
+	require.NoError(t, database.Check(ctx, testhelpers.NewTestDependencies(t).Logger(), conn, migrations.Migrations))
+	removed, err := conn.CleanOrphans(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, removed, "the first Check already cleaned up")
+
+	count := func(conn database.Connection, table string) int {
+		t.Helper()
+		var n int
+		require.NoError(t, conn.QueryRowContext(ctx, "SELECT count(*) FROM "+table).Scan(&n))
+		return n
+	}
+	assert.Equal(t, 3, count(conn, "character_skills"))
+	assert.Equal(t, 1, count(conn, "tokens"))
+
+	strict := openTestDB(t)
+	require.NoError(t, strict.Migrate(ctx, migrations.Migrations))
+	exec(strict, `INSERT INTO corporations ("id", "name", "ticker", "picture") VALUES (1, 'Corp', 'CRP', '')`)
+	exec(strict, `INSERT INTO characters ("id", "name", "picture", "corporation_id") VALUES (1, 'Character', '', 1)`)
+	exec(strict, `INSERT INTO character_skills ("character_id", "skill_id", "skill_level") VALUES (1, 3000, 1)`)
+	exec(strict, `DELETE FROM characters WHERE "id" = 1`)
+	assert.Zero(t, count(strict, "character_skills"), "deletes cascade")
+
+	_, err = strict.ExecContext(ctx, `INSERT INTO character_skills ("character_id", "skill_id", "skill_level") VALUES (99, 3000, 1)`)
+	assert.Error(t, err, "rows need their parent")
+}
@@ -0,0 +1,156 @@
+package database
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	gosqlite3 "github.com/mattn/go-sqlite3"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+)
+
+var (
+	// ErrBusy is another connection holding a lock past the busy timeout
+	ErrBusy = errors.New("database is busy")
+	// ErrConstraint is any constraint violation. The more specific errors
+	// below are also ErrConstraint.
+	ErrConstraint          = errors.New("constraint violation")
+	ErrUniqueViolation     = errors.New("unique constraint violation")
+	ErrForeignKeyViolation = errors.New("foreign key constraint violation")
+)
+
+// classifiedError keeps the driver's error, and its message, while also
+// matching the sentinels it was classified as
+type classifiedError struct {
+	error
+	kinds []error
+}
+
+func (e classifiedError) Unwrap() error { return e.error }
+
+func (e classifiedError) Is(target error) bool {
+	for _, k := range e.kinds {
+		if k == target {
+			return true
+		}
+	}
+	return false
+}
+
+// Classify marks a SQLite error with ErrBusy, ErrConstraint and the like,
+// so callers can check for them with errors.Is. Other errors, and errors
+// already classified, are returned as they are.
+func Classify(err error) error {
+	var sqlErr gosqlite3.Error
+	if !errors.As(err, &sqlErr) || errors.As(err, new(classifiedError)) {
+		return err
+	}
+
+	switch sqlErr.Code {
+	case gosqlite3.ErrBusy, gosqlite3.ErrLocked:
+		return classifiedError{err, []error{ErrBusy}}
+	case gosqlite3.ErrConstraint:
+		switch sqlErr.ExtendedCode {
+		case gosqlite3.ErrConstraintUnique, gosqlite3.ErrConstraintPrimaryKey:
+			return classifiedError{err, []error{ErrUniqueViolation, ErrConstraint}}
+		case gosqlite3.ErrConstraintForeignKey:
+			return classifiedError{err, []error{ErrForeignKeyViolation, ErrConstraint}}
+		}
+		return classifiedError{err, []error{ErrConstraint}}
+	}
+	return err
+}
+
+// Retryable reports whether a failed transaction should be tried again.
+// Busy and locked databases are; constraint violations, other SQLite errors
+// and cancelled contexts are not. Any other error is retried too, unless it
+// is wrapped in NonRetryableError.
+func Retryable(err error) bool {
+	err = Classify(err)
+
+	var noRetry nonRetryableError
+	switch {
+	case errors.As(err, &noRetry):
+		return false
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	case errors.Is(err, ErrBusy):
+		return true
+	case errors.As(err, new(gosqlite3.Error)):
+		return false
+	}
+	return true
+}
+
+// RetryPolicy decides how often and how long TransactWithPolicy tries a
+// transaction again
+type RetryPolicy struct {
+	// MaxAttempts counts the first try
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry. Each later wait is
+	// Multiplier times longer, up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// Jitter is the fraction of each wait that is random, from 0 to 1, so
+	// goroutines that collided do not retry in step
+	Jitter float64
+	// MaxElapsed stops retrying once this long has passed since the first
+	// try began. 0 has no limit.
+	MaxElapsed time.Duration
+	// Retryable classifies errors; nil uses the package's Retryable
+	Retryable func(error) bool
+}
+
+// DefaultRetryPolicy is what TransactWithRetries uses
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     500 * time.Millisecond,
+		Multiplier:     2,
+		Jitter:         0.5,
+		MaxElapsed:     5 * time.Second,
+		Retryable:      Retryable,
+	}
+}
+
+// Backoff is the wait before retry number retry, counting from 1
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	if retry < 1 || p.InitialBackoff <= 0 {
+		return 0
+	}
+
+	mult := max(p.Multiplier, 1)
+	wait := float64(p.InitialBackoff) * math.Pow(mult, float64(retry-1))
+	if p.MaxBackoff > 0 {
+		wait = min(wait, float64(p.MaxBackoff))
+	}
+
+	jitter := min(max(p.Jitter, 0), 1)
+	wait -= wait * jitter * rand.Float64() //nolint:gosec // jitter needs no secure randomness
+	return time.Duration(wait)
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable == nil {
+		return Retryable(err)
+	}
+	return p.Retryable(err)
+}
+
+// next returns the wait before retry number retry, and false if the policy
+// says to stop
+func (p RetryPolicy) next(retry int, started time.Time, err error) (time.Duration, bool) {
+	if retry >= max(p.MaxAttempts, 1) || !p.retryable(err) {
+		return 0, false
+	}
+
+	wait := p.Backoff(retry)
+	if p.MaxElapsed > 0 && time.Since(started)+wait > p.MaxElapsed {
+		return 0, false
+	}
+	return wait, true
+}
@@ -0,0 +1,175 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	gosqlite3 "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/migrations"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/database/databasefakes"
+	"github.com/kava-forge/eve-alts/pkg/testhelpers"
+)
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	conn := openTestDB(t)
+	require.NoError(t, conn.Migrate(ctx, migrations.Migrations))
+
+	insertTag := `INSERT INTO tags ("name", "color_r", "color_g", "color_b", "color_a") VALUES (?, 0, 0, 0, 65535)`
+	_, err := conn.ExecContext(ctx, insertTag, "Logi")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		query     string
+		args      []interface{}
+		wantIs    []error
+		wantNotIs []error
+		retryable bool
+	}{
+		{"unique", `INSERT INTO tags ("id", "name", "color_r", "color_g", "color_b", "color_a") VALUES (1, 'Other', 0, 0, 0, 65535)`, nil, []error{database.ErrUniqueViolation, database.ErrConstraint}, []error{database.ErrBusy}, false},
+		{"foreign key", `INSERT INTO tag_skills ("tag_id", "skill_id", "skill_level") VALUES (99, 1, 1)`, nil, []error{database.ErrForeignKeyViolation, database.ErrConstraint}, []error{database.ErrUniqueViolation}, false},
+		{"not null", insertTag, []interface{}{nil}, []error{database.ErrConstraint}, []error{database.ErrUniqueViolation, database.ErrForeignKeyViolation}, false},
+		{"syntax", `INSERT INTO`, nil, nil, []error{database.ErrConstraint, database.ErrBusy}, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := conn.ExecContext(ctx, tt.query, tt.args...)
+			require.Error(t, err)
+
+			classified := database.Classify(errors.Wrap(err, "could not insert"))
+			for _, want := range tt.wantIs {
+				assert.ErrorIs(t, classified, want)
+			}
+			for _, notWant := range tt.wantNotIs {
+				assert.NotErrorIs(t, classified, notWant)
+			}
+			assert.ErrorAs(t, classified, new(gosqlite3.Error), "the driver error is kept")
+			assert.Equal(t, tt.retryable, database.Retryable(classified))
+		})
+	}
+}
+
+func TestClassify_Busy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	impatient := database.DefaultPragmas()
+	impatient.BusyTimeout = 0
+	conn := openTestDBWith(t, impatient)
+
+	holder, err := conn.Conn(ctx)
+	require.NoError(t, err)
+	defer holder.Close()
+	_, err = holder.ExecContext(ctx, "BEGIN IMMEDIATE")
+	require.NoError(t, err)
+	defer func() { _, _ = holder.ExecContext(ctx, "ROLLBACK") }()
+
+	_, err = conn.BeginTx(ctx, nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, database.Classify(err), database.ErrBusy)
+	assert.True(t, database.Retryable(err))
+}
+
+func TestRetryable(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, database.Retryable(errors.New("network hiccup")))
+	assert.False(t, database.Retryable(database.NonRetryableError(errors.New("bad input"))))
+	assert.False(t, database.Retryable(errors.Wrap(context.Canceled, "gave up")))
+	assert.False(t, database.Retryable(gosqlite3.Error{Code: gosqlite3.ErrCorrupt}))
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	t.Parallel()
+
+	p := database.RetryPolicy{
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     50 * time.Millisecond,
+		Multiplier:     2,
+	}
+
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{0, 0},
+		{1, 10 * time.Millisecond},
+		{2, 20 * time.Millisecond},
+		{3, 40 * time.Millisecond},
+		{4, 50 * time.Millisecond},
+		{10, 50 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, p.Backoff(tt.retry), "retry %d", tt.retry)
+	}
+
+	p.Jitter = 0.5
+	for range 100 {
+		wait := p.Backoff(2)
+		assert.GreaterOrEqual(t, wait, 10*time.Millisecond)
+		assert.LessOrEqual(t, wait, 20*time.Millisecond)
+	}
+}
+
+func TestTransactWithPolicy(t *testing.T) {
+	t.Parallel()
+
+	deps := testhelpers.NewTestDependencies(t)
+	busy := gosqlite3.Error{Code: gosqlite3.ErrBusy}
+
+	tests := []struct {
+		name         string
+		policy       database.RetryPolicy
+		wantAttempts int
+	}{
+		{"attempts", database.RetryPolicy{MaxAttempts: 5}, 5},
+		{"elapsed", database.RetryPolicy{MaxAttempts: 100, InitialBackoff: 100 * time.Millisecond, MaxElapsed: 250 * time.Millisecond}, 3},
+		{"classifier", database.RetryPolicy{MaxAttempts: 5, Retryable: func(error) bool { return false }}, 1},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &databasefakes.FakeConnection{}
+			db.BeginTxReturns(&databasefakes.FakeTx{}, nil)
+
+			attempts := 0
+			err := database.TransactWithPolicy(context.Background(), deps.Telemetry(), deps.Logger(), db, &sql.TxOptions{}, tt.policy, func(context.Context, database.Tx) error {
+				attempts++
+				return busy
+			})
+			assert.ErrorIs(t, err, database.ErrBusy)
+			assert.Equal(t, tt.wantAttempts, attempts)
+		})
+	}
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		t.Parallel()
+
+		db := &databasefakes.FakeConnection{}
+		db.BeginTxReturns(&databasefakes.FakeTx{}, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		err := database.TransactWithPolicy(ctx, deps.Telemetry(), deps.Logger(), db, &sql.TxOptions{}, database.RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour}, func(context.Context, database.Tx) error {
+			cancel()
+			return busy
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, db.BeginTxCallCount())
+	})
+}
@@ -0,0 +1,213 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+)
+
+var ErrNoSnapshot = errors.New("no such snapshot")
+
+// DefaultSnapshotKeep is how many snapshots are kept when none is configured
+const DefaultSnapshotKeep = 10
+
+// SnapshotReason records why a snapshot was taken
+type SnapshotReason string
+
+const (
+	// ReasonManual is a snapshot someone asked for
+	ReasonManual SnapshotReason = "manual"
+	// ReasonMigrate is taken before migrations run on an existing database
+	ReasonMigrate SnapshotReason = "premigrate"
+	// ReasonRestore is taken before a restore replaces the database, so the
+	// restore can be undone
+	ReasonRestore SnapshotReason = "prerestore"
+)
+
+// Snapshot is one backup file. Its name holds when it was taken, the schema
+// version it had, and why it was taken.
+type Snapshot struct {
+	Name    string
+	Path    string
+	Taken   time.Time
+	Version uint
+	Reason  SnapshotReason
+	Size    int64
+}
+
+const snapshotTimeFormat = "20060102T150405.000Z"
+
+var snapshotName = regexp.MustCompile(`^database-(\d{8}T\d{6}\.\d{3}Z)-v(\d+)-([a-z]+)\.db$`)
+
+func parseSnapshot(dir string, info fs.FileInfo) (Snapshot, bool) {
+	m := snapshotName.FindStringSubmatch(info.Name())
+	if m == nil {
+		return Snapshot{}, false
+	}
+
+	taken, err := time.Parse(snapshotTimeFormat, m[1])
+	if err != nil {
+		return Snapshot{}, false
+	}
+	version, err := strconv.ParseUint(m[2], 10, 64)
+	if err != nil {
+		return Snapshot{}, false
+	}
+
+	return Snapshot{
+		Name:    info.Name(),
+		Path:    filepath.Join(dir, info.Name()),
+		Taken:   taken,
+		Version: uint(version),
+		Reason:  SnapshotReason(m[3]),
+		Size:    info.Size(),
+	}, true
+}
+
+// Snapshots keeps rotating backups of a database in Dir. With no Dir,
+// snapshots are turned off: Take and List do nothing.
+type Snapshots struct {
+	Dir string
+	// Keep is how many snapshots are kept; older ones are deleted after each
+	// new one is taken
+	Keep int
+}
+
+func (s Snapshots) Enabled() bool {
+	return s.Dir != ""
+}
+
+// Take backs up conn to a new snapshot, then prunes old ones
+func (s Snapshots) Take(ctx context.Context, conn Connection, reason SnapshotReason) (Snapshot, error) {
+	if !s.Enabled() {
+		return Snapshot{}, nil
+	}
+
+	snap, err := s.take(ctx, conn, reason)
+	if err != nil {
+		return snap, err
+	}
+	return snap, s.Prune()
+}
+
+// take is Take without pruning
+func (s Snapshots) take(ctx context.Context, conn Connection, reason SnapshotReason) (Snapshot, error) {
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return Snapshot{}, errors.Wrap(err, "could not create snapshot directory", "path", s.Dir)
+	}
+
+	version, _, err := conn.SchemaVersion(ctx)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	name := fmt.Sprintf("database-%s-v%d-%s.db", time.Now().UTC().Format(snapshotTimeFormat), version, reason)
+	path := filepath.Join(s.Dir, name)
+	if err := conn.Backup(ctx, path); err != nil {
+		return Snapshot{}, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return Snapshot{}, errors.Wrap(err, "could not read snapshot", "path", path)
+	}
+	snap, _ := parseSnapshot(s.Dir, info)
+
+	return snap, nil
+}
+
+// List returns the snapshots, newest first
+func (s Snapshots) List() ([]Snapshot, error) {
+	if !s.Enabled() {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(s.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list snapshots", "path", s.Dir)
+	}
+
+	snaps := make([]Snapshot, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		if snap, ok := parseSnapshot(s.Dir, info); ok {
+			snaps = append(snaps, snap)
+		}
+	}
+
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Taken.After(snaps[j].Taken) })
+	return snaps, nil
+}
+
+// Find looks up a snapshot by its file name
+func (s Snapshots) Find(name string) (Snapshot, error) {
+	snaps, err := s.List()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	for _, snap := range snaps {
+		if snap.Name == name {
+			return snap, nil
+		}
+	}
+	return Snapshot{}, errors.Wrap(ErrNoSnapshot, "could not find snapshot", "name", name)
+}
+
+// Prune deletes all but the newest Keep snapshots
+func (s Snapshots) Prune() error {
+	snaps, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	keep := s.Keep
+	if keep <= 0 {
+		keep = DefaultSnapshotKeep
+	}
+	if len(snaps) <= keep {
+		return nil
+	}
+
+	for _, snap := range snaps[keep:] {
+		if err := os.Remove(snap.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return errors.Wrap(err, "could not delete old snapshot", "path", snap.Path)
+		}
+	}
+	return nil
+}
+
+// Restore replaces the database with a snapshot, after taking a snapshot of
+// it as it is now. Old snapshots are only pruned once the restore is done, as
+// the one being restored may be the oldest.
+func (s Snapshots) Restore(ctx context.Context, conn Connection, name string) (undo Snapshot, err error) {
+	snap, err := s.Find(name)
+	if err != nil {
+		return undo, err
+	}
+
+	if undo, err = s.take(ctx, conn, ReasonRestore); err != nil {
+		return undo, errors.Wrap(err, "could not snapshot the database before restoring")
+	}
+
+	if err := conn.Restore(ctx, snap.Path); err != nil {
+		return undo, err
+	}
+	return undo, s.Prune()
+}
@@ -0,0 +1,179 @@
+package database_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kava-forge/eve-alts/migrations"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/database/databasefakes"
+)
+
+func openTestDB(t *testing.T) *database.WrappedConnection {
+	t.Helper()
+	return openTestDBWith(t, database.DefaultPragmas())
+}
+
+func openTestDBWith(t *testing.T, pragmas database.Pragmas) *database.WrappedConnection {
+	t.Helper()
+
+	conn, err := database.Open(context.Background(), filepath.Join(t.TempDir(), "database.db"), pragmas)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close(context.Background()) })
+
+	return conn
+}
+
+func countRows(t *testing.T, conn database.Connection) int {
+	t.Helper()
+
+	var n int
+	require.NoError(t, conn.QueryRowContext(context.Background(), "SELECT count(*) FROM test_rows").Scan(&n))
+	return n
+}
+
+func TestSnapshots(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	conn := openTestDB(t)
+	snaps := database.Snapshots{Dir: filepath.Join(t.TempDir(), "backups"), Keep: 3}
+
+	require.NoError(t, snaps.Migrate(ctx, conn, migrations.Migrations))
+	list, err := snaps.List()
+	require.NoError(t, err)
+	assert.Empty(t, list, "a new database is not snapshotted")
+
+	latest, err := database.LatestVersion(migrations.Migrations)
+	require.NoError(t, err)
+	version, dirty, err := conn.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, latest, version)
+	assert.False(t, dirty)
+
+	_, err = conn.ExecContext(ctx, "CREATE TABLE test_rows (id INTEGER PRIMARY KEY)")
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "INSERT INTO test_rows (id) VALUES (1), (2)")
+	require.NoError(t, err)
+
+	snap, err := snaps.Take(ctx, conn, database.ReasonManual)
+	require.NoError(t, err)
+	assert.Equal(t, latest, snap.Version)
+	assert.Equal(t, database.ReasonManual, snap.Reason)
+	assert.Positive(t, snap.Size)
+	assert.FileExists(t, snap.Path)
+
+	_, err = conn.ExecContext(ctx, "DELETE FROM test_rows")
+	require.NoError(t, err)
+	require.Equal(t, 0, countRows(t, conn))
+
+	undo, err := snaps.Restore(ctx, conn, snap.Name)
+	require.NoError(t, err)
+	assert.Equal(t, database.ReasonRestore, undo.Reason)
+	assert.Equal(t, 2, countRows(t, conn), "restored rows are seen by the open connection")
+
+	_, err = snaps.Restore(ctx, conn, "database-nope.db")
+	assert.ErrorIs(t, err, database.ErrNoSnapshot)
+
+	for range 3 {
+		_, err := snaps.Take(ctx, conn, database.ReasonManual)
+		require.NoError(t, err)
+	}
+	list, err = snaps.List()
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	assert.True(t, list[0].Taken.After(list[2].Taken), "newest first")
+	for _, s := range list {
+		assert.NotEqual(t, snap.Name, s.Name, "oldest snapshots are pruned")
+	}
+}
+
+func TestSnapshots_RestoreOldest(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	conn := openTestDB(t)
+	snaps := database.Snapshots{Dir: filepath.Join(t.TempDir(), "backups"), Keep: 3}
+
+	_, err := conn.ExecContext(ctx, "CREATE TABLE test_rows (id INTEGER PRIMARY KEY)")
+	require.NoError(t, err)
+	for i := range 3 {
+		_, err := conn.ExecContext(ctx, "INSERT INTO test_rows (id) VALUES (?)", i)
+		require.NoError(t, err)
+		_, err = snaps.Take(ctx, conn, database.ReasonManual)
+		require.NoError(t, err)
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	list, err := snaps.List()
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	oldest := list[2]
+
+	// the snapshot taken first would be pruned by the one taken to undo
+	undo, err := snaps.Restore(ctx, conn, oldest.Name)
+	require.NoError(t, err)
+	assert.Equal(t, 1, countRows(t, conn))
+
+	list, err = snaps.List()
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	assert.Equal(t, undo.Name, list[0].Name)
+}
+
+func TestSnapshots_Migrate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	latest, err := database.LatestVersion(migrations.Migrations)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		current uint
+		dir     bool
+		want    int
+	}{
+		{"pending", latest - 1, true, 1},
+		{"up to date", latest, true, 0},
+		{"new database", 0, true, 0},
+		{"disabled", latest - 1, false, 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			conn := &databasefakes.FakeConnection{}
+			conn.SchemaVersionReturns(tt.current, false, nil)
+			conn.BackupCalls(func(_ context.Context, path string) error {
+				return os.WriteFile(path, []byte("backup"), 0o600)
+			})
+
+			var snaps database.Snapshots
+			if tt.dir {
+				snaps.Dir = t.TempDir()
+			}
+
+			require.NoError(t, snaps.Migrate(ctx, conn, migrations.Migrations))
+			assert.Equal(t, 1, conn.MigrateCallCount())
+			assert.Equal(t, tt.want, conn.BackupCallCount())
+		})
+	}
+}
+
+func TestRestore_BadBackup(t *testing.T) {
+	t.Parallel()
+
+	conn := openTestDB(t)
+	path := filepath.Join(t.TempDir(), "garbage.db")
+	require.NoError(t, os.WriteFile(path, []byte("not a database at all, just some bytes"), 0o600))
+
+	assert.ErrorIs(t, conn.Restore(context.Background(), path), database.ErrBadBackup)
+}
@@ -3,11 +3,12 @@ package database
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/kava-forge/eve-alts/lib/errors"
 	"github.com/kava-forge/eve-alts/lib/logging"
-	trace "github.com/kava-forge/eve-alts/lib/telemetry"
+	"github.com/kava-forge/eve-alts/lib/logging/level"
 
 	"github.com/kava-forge/eve-alts/pkg/telemetry"
 )
@@ -28,58 +29,75 @@ func newTransaction(ctx context.Context, db Connection, opts *sql.TxOptions) (Tx
 	return tx, errors.Wrap(err, "could not start transaction")
 }
 
-func TransactWithRetries(ctx context.Context, tel *telemetry.Telemeter, logger logging.Logger, db Connection, opts *sql.TxOptions, txFunc func(context.Context, Tx) error) (err error) {
+// TransactWithRetries runs txFunc in a transaction under DefaultRetryPolicy
+func TransactWithRetries(ctx context.Context, tel *telemetry.Telemeter, logger logging.Logger, db Connection, opts *sql.TxOptions, txFunc func(context.Context, Tx) error) error {
+	return TransactWithPolicy(ctx, tel, logger, db, opts, DefaultRetryPolicy(), txFunc)
+}
+
+// TransactWithPolicy runs txFunc in a transaction and commits it, starting
+// over in a new transaction while policy allows. Errors are classified, so
+// errors.Is finds ErrUniqueViolation and the like through the result.
+func TransactWithPolicy(ctx context.Context, tel *telemetry.Telemeter, logger logging.Logger, db Connection, opts *sql.TxOptions, policy RetryPolicy, txFunc func(context.Context, Tx) error) (err error) {
 	ctx, span := tel.StartSpan(ctx, "database", "TransactWithRetries")
 	defer telemetry.EndSpan(span, &err)
 
-	var tx Tx
-	defer deferRollback(ctx, logger, tx)
-
-	success := false
 	var multi *multierror.Error
+	started := time.Now()
 
-	var attemptCtx context.Context
-	var attemptSpan trace.Span
-	for i := 0; i < 3; i++ {
-		if tx != nil {
-			deferRollback(attemptCtx, logger, tx)
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			telemetry.DefaultStats().TransactionRetry(ctx)
 		}
 
-		if attemptSpan != nil {
-			telemetry.EndSpan(attemptSpan, &err)
+		err = transactOnce(ctx, tel, logger, db, opts, txFunc)
+		if err == nil {
+			return nil
 		}
+		multi = multierror.Append(multi, err)
 
-		attemptCtx, attemptSpan = tel.StartSpan(ctx, "database", "TransactWithRetries.Attempt")
-
-		tx, err = newTransaction(ctx, db, opts)
-		if err != nil {
-			telemetry.EndSpan(attemptSpan, &err)
-			return errors.Wrap(err, "could not create new transaction")
+		wait, retry := policy.next(attempt, started, err)
+		if !retry {
+			return errors.Wrap(multi, "transaction failed")
 		}
 
-		err = txFunc(ctx, tx)
-		if err == nil {
-			success = true
-			break
+		level.Debug(logger).Message("retrying transaction", "attempt", attempt, "wait", wait, "error", err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Wrap(multierror.Append(multi, ctx.Err()), "transaction failed")
+		case <-timer.C:
 		}
+	}
+}
 
-		multi = multierror.Append(multi, err)
+// transactOnce is a single attempt: begin, txFunc and commit, rolling back
+// on any failure before returning. AfterCommit hooks run only on success.
+func transactOnce(ctx context.Context, tel *telemetry.Telemeter, logger logging.Logger, db Connection, opts *sql.TxOptions, txFunc func(context.Context, Tx) error) (err error) {
+	attemptCtx, attemptSpan := tel.StartSpan(ctx, "database", "TransactWithRetries.Attempt")
+	defer telemetry.EndSpan(attemptSpan, &err)
 
-		var noRetry nonRetryableError
-		if errors.As(err, &noRetry) {
-			break
-		}
+	rawTx, err := newTransaction(attemptCtx, db, opts)
+	if err != nil {
+		return Classify(err)
 	}
-
-	if attemptSpan != nil {
-		telemetry.EndSpan(attemptSpan, &err)
+	tx := &hookedTx{Tx: rawTx}
+	began := time.Now()
+	defer func() {
+		telemetry.DefaultStats().Transaction(attemptCtx, time.Since(began), err)
+	}()
+
+	if err := txFunc(attemptCtx, tx); err != nil {
+		deferRollback(attemptCtx, logger, tx)
+		return Classify(err)
 	}
 
-	if !success {
-		return errors.Wrap(multi, "transaction failed")
+	if err := tx.Commit(); err != nil {
+		deferRollback(attemptCtx, logger, tx)
+		return Classify(errors.Wrap(err, "could not commit transaction"))
 	}
-
-	return errors.Wrap(tx.Commit(), "could not commit transaction")
+	tx.committed()
+	return nil
 }
 
 type nonRetryableError struct {
@@ -89,3 +107,7 @@ type nonRetryableError struct {
 func NonRetryableError(err error) error {
 	return nonRetryableError{err}
 }
+
+func (e nonRetryableError) Unwrap() error {
+	return e.error
+}
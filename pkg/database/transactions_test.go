@@ -9,6 +9,7 @@ import (
 	"github.com/kava-forge/eve-alts/lib/errors"
 	"github.com/kava-forge/eve-alts/lib/logging"
 	"github.com/kava-forge/eve-alts/lib/logging/loggingfakes"
+	gosqlite3 "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/kava-forge/eve-alts/pkg/database"
@@ -45,6 +46,7 @@ func TestTransactWithRetries(t *testing.T) {
 		args          args
 		wantErr       bool
 		wantErrAs     interface{}
+		wantErrIs     error
 		wantAttemptCt int
 		wantCommit    bool
 	}{
@@ -79,6 +81,38 @@ func TestTransactWithRetries(t *testing.T) {
 			wantAttemptCt: 3,
 			wantCommit:    false,
 		},
+		{
+			name: "busy",
+			args: args{
+				tel:    fakeTel,
+				logger: &loggingfakes.FakeLogger{},
+				db:     &databasefakes.FakeConnection{},
+				opts:   &sql.TxOptions{},
+				txFunc: func(ctx context.Context, tx database.Tx) error {
+					return errors.Wrap(gosqlite3.Error{Code: gosqlite3.ErrBusy}, "could not write")
+				},
+			},
+			wantErr:       true,
+			wantErrIs:     database.ErrBusy,
+			wantAttemptCt: 3,
+			wantCommit:    false,
+		},
+		{
+			name: "unique violation",
+			args: args{
+				tel:    fakeTel,
+				logger: &loggingfakes.FakeLogger{},
+				db:     &databasefakes.FakeConnection{},
+				opts:   &sql.TxOptions{},
+				txFunc: func(ctx context.Context, tx database.Tx) error {
+					return errors.Wrap(gosqlite3.Error{Code: gosqlite3.ErrConstraint, ExtendedCode: gosqlite3.ErrConstraintUnique}, "could not InsertTag")
+				},
+			},
+			wantErr:       true,
+			wantErrIs:     database.ErrUniqueViolation,
+			wantAttemptCt: 1,
+			wantCommit:    false,
+		},
 		{
 			name: "non-retryable",
 			args: args{
@@ -116,6 +150,9 @@ func TestTransactWithRetries(t *testing.T) {
 					if tt.wantErrAs != nil {
 						assert.ErrorAs(t, err, &tt.wantErrAs)
 					}
+					if tt.wantErrIs != nil {
+						assert.ErrorIs(t, err, tt.wantErrIs)
+					}
 				}
 			} else {
 				assert.NoError(t, err)
@@ -128,6 +165,7 @@ func TestTransactWithRetries(t *testing.T) {
 				assert.Equal(t, 1, fakeTx.CommitCallCount())
 			} else {
 				assert.Equal(t, 0, fakeTx.CommitCallCount())
+				assert.Equal(t, tt.wantAttemptCt, fakeTx.RollbackCallCount(), "every failed attempt is rolled back")
 			}
 		})
 	}
@@ -6,6 +6,7 @@ import (
 	"embed"
 
 	"github.com/golang-migrate/migrate/v4"
+	migratedb "github.com/golang-migrate/migrate/v4/database"
 	"github.com/golang-migrate/migrate/v4/database/sqlite3"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/kava-forge/eve-alts/lib/errors"
@@ -13,6 +14,8 @@ import (
 
 type WrappedConnection struct {
 	*sql.DB
+
+	integrity IntegrityCheck
 }
 
 var _ Connection = (*WrappedConnection)(nil)
@@ -21,20 +24,28 @@ func (c *WrappedConnection) BeginTx(ctx context.Context, opts *sql.TxOptions) (T
 	return c.DB.BeginTx(ctx, opts)
 }
 
-func (c *WrappedConnection) Migrate(ctx context.Context, migrations embed.FS) error {
+func (c *WrappedConnection) migrator(migrations embed.FS) (*migrate.Migrate, error) {
 	sd, err := iofs.New(migrations, ".")
 	if err != nil {
-		return errors.Wrap(err, "could not create source driver")
+		return nil, errors.Wrap(err, "could not create source driver")
 	}
 
 	dd, err := sqlite3.WithInstance(c.DB, &sqlite3.Config{})
 	if err != nil {
-		return errors.Wrap(err, "could not create db driver")
+		return nil, errors.Wrap(err, "could not create db driver")
 	}
 
 	migrator, err := migrate.NewWithInstance("iofs", sd, "sqlite3", dd)
 	if err != nil {
-		return errors.Wrap(err, "could not create migrator")
+		return nil, errors.Wrap(err, "could not create migrator")
+	}
+	return migrator, nil
+}
+
+func (c *WrappedConnection) Migrate(ctx context.Context, migrations embed.FS) error {
+	migrator, err := c.migrator(migrations)
+	if err != nil {
+		return err
 	}
 
 	err = migrator.Up()
@@ -45,6 +56,42 @@ func (c *WrappedConnection) Migrate(ctx context.Context, migrations embed.FS) er
 	return nil
 }
 
+// MigrateTo runs migrations up or down until the schema is at version. 0
+// undoes every migration.
+func (c *WrappedConnection) MigrateTo(ctx context.Context, migrations embed.FS, version uint) error {
+	migrator, err := c.migrator(migrations)
+	if err != nil {
+		return err
+	}
+
+	if version == 0 {
+		err = migrator.Down()
+	} else {
+		err = migrator.Migrate(version)
+	}
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return errors.Wrap(err, "could not migrate database", "version", version)
+	}
+
+	return nil
+}
+
+// ForceVersion records version as applied and clean without running
+// anything, after a failed migration has been fixed by hand. 0 records that
+// no migrations are applied.
+func (c *WrappedConnection) ForceVersion(ctx context.Context, migrations embed.FS, version uint) error {
+	migrator, err := c.migrator(migrations)
+	if err != nil {
+		return err
+	}
+
+	v := int(version)
+	if version == 0 {
+		v = migratedb.NilVersion
+	}
+	return errors.Wrap(migrator.Force(v), "could not force schema version", "version", version)
+}
+
 func (c *WrappedConnection) Close(ctx context.Context) error {
 	return c.DB.Close()
 }
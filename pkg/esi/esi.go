@@ -21,6 +21,7 @@ import (
 	"golang.org/x/oauth2"
 
 	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/telemetry"
 )
 
 const (
@@ -37,6 +38,7 @@ var BaseURL, _ = url.Parse("https://esi.evetech.net")
 type dependencies interface {
 	Logger() logging.Logger
 	ESICallbackServer() *CallbackServer
+	Stats() *telemetry.Stats
 }
 
 func TokenFromRepository(in repository.Token) *oauth2.Token {
@@ -66,6 +68,7 @@ type Client interface {
 	GetAllianceData(ctx context.Context, tok *oauth2.Token, allianceID int64) (AllianceData, error)
 	GetAllianceIcons(ctx context.Context, tok *oauth2.Token, allianceID int64) (AllianceIcons, error)
 	GetSkills(ctx context.Context, tok *oauth2.Token, charID int64) (SkillList, error)
+	GetSkillQueue(ctx context.Context, tok *oauth2.Token, charID int64) (SkillQueue, error)
 }
 
 func NewClient(deps dependencies, redirect string) (Client, error) {
@@ -156,14 +159,41 @@ func (c *client) ValidateToken(ctx context.Context, tok *oauth2.Token) (data Cha
 	return data, nil
 }
 
-func (c *client) makeRequest(ctx context.Context, tok *oauth2.Token, req *stdhttp.Request, target interface{}) error {
+// refreshCounter sits under the oauth2 token cache, so every call to Token
+// is a real refresh
+type refreshCounter struct {
+	ctx   context.Context //nolint:containedctx // oauth2.TokenSource has no context
+	stats *telemetry.Stats
+	src   oauth2.TokenSource
+}
+
+func (r *refreshCounter) Token() (*oauth2.Token, error) {
+	tok, err := r.src.Token()
+	r.stats.TokenRefresh(r.ctx, err)
+	return tok, err
+}
+
+func (c *client) tokenSource(ctx context.Context, tok *oauth2.Token) oauth2.TokenSource {
+	return oauth2.ReuseTokenSource(tok, &refreshCounter{
+		ctx:   ctx,
+		stats: c.deps.Stats(),
+		src:   c.oauth2.TokenSource(ctx, &oauth2.Token{RefreshToken: tok.RefreshToken}),
+	})
+}
+
+// makeRequest does an authenticated ESI call. endpoint is the route with its
+// IDs left as placeholders, used to label metrics.
+func (c *client) makeRequest(ctx context.Context, tok *oauth2.Token, endpoint string, req *stdhttp.Request, target interface{}) error {
 	req.Header.Set("User-Agent", UserAgent)
 
-	resp, err := c.oauth2.Client(ctx, tok).Do(req)
+	start := time.Now()
+	resp, err := oauth2.NewClient(ctx, c.tokenSource(ctx, tok)).Do(req)
 	if err != nil {
+		c.deps.Stats().ESIRequest(ctx, endpoint, 0, time.Since(start))
 		return errors.Wrap(err, "could not do http request")
 	}
 	defer deferutil.CheckDeferLog(c.deps.Logger(), resp.Body.Close)
+	c.deps.Stats().ESIRequest(ctx, endpoint, resp.StatusCode, time.Since(start))
 
 	if resp.StatusCode != stdhttp.StatusOK {
 		return errors.WithDetails(errors.New("bad request response"), "uri", req.URL.String(), "code", resp.StatusCode)
@@ -193,7 +223,7 @@ func (c *client) GetCharacterPublicData(ctx context.Context, tok *oauth2.Token,
 	}
 
 	var respData CharacterPublicData
-	if err := c.makeRequest(ctx, tok, req, &respData); err != nil {
+	if err := c.makeRequest(ctx, tok, "/characters/{character_id}/", req, &respData); err != nil {
 		return CharacterPublicData{}, errors.Wrap(err, "could not unmarshal response")
 	}
 
@@ -212,7 +242,7 @@ func (c *client) GetCharacterPortrait(ctx context.Context, tok *oauth2.Token, ch
 	}
 
 	var respData CharacterPortait
-	if err := c.makeRequest(ctx, tok, req, &respData); err != nil {
+	if err := c.makeRequest(ctx, tok, "/characters/{character_id}/portrait/", req, &respData); err != nil {
 		return CharacterPortait{}, errors.Wrap(err, "could not unmarshal response")
 	}
 
@@ -231,7 +261,7 @@ func (c *client) GetCorporationData(ctx context.Context, tok *oauth2.Token, corp
 	}
 
 	var respData CorporationData
-	if err := c.makeRequest(ctx, tok, req, &respData); err != nil {
+	if err := c.makeRequest(ctx, tok, "/corporations/{corporation_id}/", req, &respData); err != nil {
 		return CorporationData{}, errors.Wrap(err, "could not unmarshal response")
 	}
 
@@ -250,7 +280,7 @@ func (c *client) GetCorporationIcons(ctx context.Context, tok *oauth2.Token, cor
 	}
 
 	var respData CorporationIcons
-	if err := c.makeRequest(ctx, tok, req, &respData); err != nil {
+	if err := c.makeRequest(ctx, tok, "/corporations/{corporation_id}/icons/", req, &respData); err != nil {
 		return CorporationIcons{}, errors.Wrap(err, "could not unmarshal response")
 	}
 
@@ -269,7 +299,7 @@ func (c *client) GetAllianceData(ctx context.Context, tok *oauth2.Token, allianc
 	}
 
 	var respData AllianceData
-	if err := c.makeRequest(ctx, tok, req, &respData); err != nil {
+	if err := c.makeRequest(ctx, tok, "/alliances/{alliance_id}/", req, &respData); err != nil {
 		return AllianceData{}, errors.Wrap(err, "could not unmarshal response")
 	}
 
@@ -288,7 +318,7 @@ func (c *client) GetAllianceIcons(ctx context.Context, tok *oauth2.Token, allian
 	}
 
 	var respData AllianceIcons
-	if err := c.makeRequest(ctx, tok, req, &respData); err != nil {
+	if err := c.makeRequest(ctx, tok, "/alliances/{alliance_id}/icons/", req, &respData); err != nil {
 		return AllianceIcons{}, errors.Wrap(err, "could not unmarshal response")
 	}
 
@@ -307,9 +337,28 @@ func (c *client) GetSkills(ctx context.Context, tok *oauth2.Token, charID int64)
 	}
 
 	var respData SkillList
-	if err := c.makeRequest(ctx, tok, req, &respData); err != nil {
+	if err := c.makeRequest(ctx, tok, "/characters/{character_id}/skills/", req, &respData); err != nil {
 		return SkillList{}, errors.Wrap(err, "could not unmarshal response")
 	}
 
 	return respData, nil
 }
+
+func (c *client) GetSkillQueue(ctx context.Context, tok *oauth2.Token, charID int64) (SkillQueue, error) {
+	u, err := BaseURL.Parse(fmt.Sprintf("/latest/characters/%d/skillqueue/", charID))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse skill queue url")
+	}
+
+	req, err := stdhttp.NewRequestWithContext(ctx, stdhttp.MethodGet, u.String(), stdhttp.NoBody)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not form http request")
+	}
+
+	var respData SkillQueue
+	if err := c.makeRequest(ctx, tok, "/characters/{character_id}/skillqueue/", req, &respData); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal response")
+	}
+
+	return respData, nil
+}
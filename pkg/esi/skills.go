@@ -1,10 +1,50 @@
 package esi
 
+import "time"
+
 type SkillList struct {
-	Skills []Skill `json:"skills"`
+	Skills  []Skill `json:"skills"`
+	TotalSP int64   `json:"total_sp"`
 }
 
 type Skill struct {
 	SkillID      int64 `json:"skill_id"`
 	TrainedLevel int64 `json:"trained_skill_level"`
+	ActiveLevel  int64 `json:"active_skill_level"`
+}
+
+// Omega reports whether every skill is active at its trained level. Skills
+// past the alpha clone limits are only partly active on an alpha, so any
+// difference means alpha. An alpha that has never trained past the limits
+// cannot be told apart and is reported as omega.
+func (l SkillList) Omega() bool {
+	for _, sk := range l.Skills {
+		if sk.ActiveLevel < sk.TrainedLevel {
+			return false
+		}
+	}
+	return true
+}
+
+// SkillQueue is a character's skill queue. ESI keeps finished entries until
+// the character next logs in.
+type SkillQueue []SkillQueueEntry
+
+type SkillQueueEntry struct {
+	SkillID       int64 `json:"skill_id"`
+	FinishedLevel int64 `json:"finished_level"`
+	QueuePosition int64 `json:"queue_position"`
+	// FinishDate is missing while the queue is paused
+	FinishDate *time.Time `json:"finish_date,omitempty"`
+}
+
+// Remaining counts the entries that have not finished by now
+func (q SkillQueue) Remaining(now time.Time) int64 {
+	var n int64
+	for _, e := range q {
+		if e.FinishDate == nil || e.FinishDate.After(now) {
+			n++
+		}
+	}
+	return n
 }
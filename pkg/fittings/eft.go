@@ -0,0 +1,128 @@
+package fittings
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+)
+
+var (
+	ErrMissingHeader = errors.New("missing fit header")
+	ErrMalformedItem = errors.New("malformed item")
+)
+
+// Fit is a parsed EFT block. The hull is not included in Items.
+type Fit struct {
+	Hull     string
+	HullLine int
+	Name     string
+	Items    []Item
+}
+
+// Item is a single module, charge, drone or cargo entry in a fit.
+type Item struct {
+	Line     int
+	Name     string
+	Quantity int64
+}
+
+// ParseEFT parses a fit in the EFT format used by the in-game fitting window:
+//
+//	[Hull, Fit Name]
+//	Module
+//	Module, Charge
+//	Module /OFFLINE
+//	[Empty Med slot]
+//
+//	Drone x5
+//
+// Blank lines separate the slot, drone and cargo sections, and all of them
+// are treated the same way.
+func ParseEFT(text string) (fit Fit, err error) {
+	scanner := bufio.NewScanner(strings.NewReader(text))
+
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if fit.Hull == "" {
+			if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+				return fit, errors.Wrap(ErrMissingHeader, "first line must be [Hull, Fit Name]", "line", lineNo)
+			}
+
+			hull, name, _ := strings.Cut(strings.Trim(line, "[]"), ",")
+			fit.Hull = strings.TrimSpace(hull)
+			fit.HullLine = lineNo
+			fit.Name = strings.TrimSpace(name)
+
+			if fit.Hull == "" {
+				return fit, errors.Wrap(ErrMissingHeader, "fit header has no hull", "line", lineNo)
+			}
+			continue
+		}
+
+		// [Empty High slot] and friends
+		if strings.HasPrefix(line, "[") {
+			continue
+		}
+
+		line = strings.TrimSpace(strings.TrimSuffix(line, "/OFFLINE"))
+
+		item, charge, hasCharge := strings.Cut(line, ",")
+
+		name, qty, err := parseQuantity(strings.TrimSpace(item))
+		if err != nil {
+			return fit, errors.Wrap(err, "could not parse item", "line", lineNo)
+		}
+		fit.Items = append(fit.Items, Item{Line: lineNo, Name: name, Quantity: qty})
+
+		if hasCharge {
+			charge = strings.TrimSpace(charge)
+			if charge == "" {
+				return fit, errors.Wrap(ErrMalformedItem, "empty charge", "line", lineNo)
+			}
+			fit.Items = append(fit.Items, Item{Line: lineNo, Name: charge, Quantity: 1})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fit, errors.Wrap(err, "could not read fit")
+	}
+
+	if fit.Hull == "" {
+		return fit, ErrMissingHeader
+	}
+
+	return fit, nil
+}
+
+// parseQuantity splits a trailing " xN" quantity off of an item name
+func parseQuantity(item string) (string, int64, error) {
+	if item == "" {
+		return "", 0, errors.Wrap(ErrMalformedItem, "empty item name")
+	}
+
+	idx := strings.LastIndex(item, " x")
+	if idx < 0 {
+		return item, 1, nil
+	}
+
+	qty, err := strconv.ParseInt(item[idx+2:], 10, 64)
+	if err != nil {
+		// not a quantity, just a name with " x" in it
+		return item, 1, nil //nolint:nilerr // intentional
+	}
+
+	if qty <= 0 {
+		return "", 0, errors.Wrap(ErrMalformedItem, "quantity must be positive", "quantity", qty)
+	}
+
+	return strings.TrimSpace(item[:idx]), qty, nil
+}
@@ -0,0 +1,169 @@
+package fittings_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/fittings"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/repository/repositoryfakes"
+)
+
+const guardianFit = `[Guardian, Guardian - Armor]
+Damage Control II
+Energized Adaptive Nano Membrane II
+
+10MN Afterburner II
+Cap Recharger II
+[Empty Med slot]
+
+Large Remote Armor Repairer II /OFFLINE
+Heavy Pulse Laser II, Multifrequency M
+
+Medium Capacitor Control Circuit I
+
+Hornet EC-300 x5
+
+Nanite Repair Paste x100
+`
+
+func TestParseEFT(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		text    string
+		want    fittings.Fit
+		wantErr error
+	}{
+		{
+			name: "full fit",
+			text: guardianFit,
+			want: fittings.Fit{
+				Hull:     "Guardian",
+				HullLine: 1,
+				Name:     "Guardian - Armor",
+				Items: []fittings.Item{
+					{Line: 2, Name: "Damage Control II", Quantity: 1},
+					{Line: 3, Name: "Energized Adaptive Nano Membrane II", Quantity: 1},
+					{Line: 5, Name: "10MN Afterburner II", Quantity: 1},
+					{Line: 6, Name: "Cap Recharger II", Quantity: 1},
+					{Line: 9, Name: "Large Remote Armor Repairer II", Quantity: 1},
+					{Line: 10, Name: "Heavy Pulse Laser II", Quantity: 1},
+					{Line: 10, Name: "Multifrequency M", Quantity: 1},
+					{Line: 12, Name: "Medium Capacitor Control Circuit I", Quantity: 1},
+					{Line: 14, Name: "Hornet EC-300", Quantity: 5},
+					{Line: 16, Name: "Nanite Repair Paste", Quantity: 100},
+				},
+			},
+		},
+		{
+			name: "leading blank lines",
+			text: "\n\n  [Rifter, ]\n",
+			want: fittings.Fit{
+				Hull:     "Rifter",
+				HullLine: 3,
+			},
+		},
+		{
+			name:    "no header",
+			text:    "Damage Control II\n",
+			wantErr: fittings.ErrMissingHeader,
+		},
+		{
+			name:    "empty",
+			text:    "",
+			wantErr: fittings.ErrMissingHeader,
+		},
+		{
+			name:    "empty charge",
+			text:    "[Rifter, x]\n200mm AutoCannon II,\n",
+			wantErr: fittings.ErrMalformedItem,
+		},
+		{
+			name:    "zero quantity",
+			text:    "[Rifter, x]\nWarrior II x0\n",
+			wantErr: fittings.ErrMalformedItem,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := fittings.ParseEFT(tt.text)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRequiredSkills(t *testing.T) {
+	t.Parallel()
+
+	typeIDs := map[string]int64{
+		"rifter":                  1,
+		"200mm autocannon ii":     2,
+		"minmatar frigate":        10,
+		"small projectile turret": 11,
+		"gunnery":                 12,
+		"spaceship command":       13,
+	}
+	reqs := map[int64][]repository.GetRequiredSkillsRow{
+		1:  {{SkillID: 10, SkillLevel: 1}},
+		2:  {{SkillID: 11, SkillLevel: 4}, {SkillID: 10, SkillLevel: 3}},
+		10: {{SkillID: 13, SkillLevel: 1}},
+		11: {{SkillID: 12, SkillLevel: 1}},
+	}
+
+	static := &repositoryfakes.FakeStaticData{}
+	static.GetTypeIDByNameCalls(func(ctx context.Context, name string, tx database.Tx) (int64, error) {
+		id, ok := typeIDs[strings.ToLower(name)]
+		if !ok {
+			return 0, sql.ErrNoRows
+		}
+		return id, nil
+	})
+	static.GetRequiredSkillsCalls(func(ctx context.Context, typeID int64, tx database.Tx) ([]repository.GetRequiredSkillsRow, error) {
+		return reqs[typeID], nil
+	})
+
+	fit, err := fittings.ParseEFT("[Rifter, Test]\n200mm AutoCannon II, Missing Ammo\nMissing Module\n")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	skills, err := fittings.RequiredSkills(context.Background(), static, fit)
+	assert.ErrorIs(t, err, fittings.ErrUnknownItem)
+	assert.Contains(t, err.Error(), "Missing Ammo")
+	assert.Contains(t, err.Error(), "Missing Module")
+
+	assert.Equal(t, []fittings.SkillRequirement{
+		{SkillID: 10, SkillLevel: 3},
+		{SkillID: 11, SkillLevel: 4},
+		{SkillID: 12, SkillLevel: 1},
+		{SkillID: 13, SkillLevel: 1},
+	}, skills)
+
+	// every type is only looked up once
+	assert.Equal(t, 6, static.GetRequiredSkillsCallCount())
+
+	// anything but a missing row is a failure, not an unknown item
+	broken := errors.New("database is locked")
+	static.GetTypeIDByNameReturns(0, broken)
+	_, err = fittings.RequiredSkills(context.Background(), static, fit)
+	assert.ErrorIs(t, err, broken)
+	assert.NotErrorIs(t, err, fittings.ErrUnknownItem)
+}
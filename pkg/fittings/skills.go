@@ -0,0 +1,97 @@
+package fittings
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+var ErrUnknownItem = errors.New("unknown item")
+
+type SkillRequirement struct {
+	SkillID    int64
+	SkillLevel int64
+}
+
+// RequiredSkills resolves every item in the fit (including the hull) to the
+// skills needed to use it, including all prerequisite skills. Each skill is
+// reported once, at the highest level needed. Items that cannot be found are
+// reported line by line, but the skills for the rest of the fit are still returned.
+func RequiredSkills(ctx context.Context, static repository.StaticData, fit Fit) ([]SkillRequirement, error) {
+	var errs error
+
+	items := make([]Item, 0, len(fit.Items)+1)
+	items = append(items, Item{Line: fit.HullLine, Name: fit.Hull, Quantity: 1})
+	items = append(items, fit.Items...)
+
+	typeIDs := make(map[int64]bool, len(items))
+	for _, it := range items {
+		typeID, err := static.GetTypeIDByName(ctx, it.Name, nil)
+		if errors.Is(err, database.ErrNoRows) {
+			errs = multierror.Append(errs, errors.Wrap(ErrUnknownItem, "could not find item", "line", it.Line, "item", it.Name))
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "could not look up item", "line", it.Line, "item", it.Name)
+		}
+		typeIDs[typeID] = true
+	}
+
+	r := resolver{
+		static:  static,
+		levels:  map[int64]int64{},
+		visited: map[int64]bool{},
+	}
+	for typeID := range typeIDs {
+		if err := r.resolve(ctx, typeID); err != nil {
+			return nil, err
+		}
+	}
+
+	skills := make([]SkillRequirement, 0, len(r.levels))
+	for id, lvl := range r.levels {
+		skills = append(skills, SkillRequirement{SkillID: id, SkillLevel: lvl})
+	}
+	sort.Slice(skills, func(i, j int) bool {
+		return skills[i].SkillID < skills[j].SkillID
+	})
+
+	return skills, errs
+}
+
+type resolver struct {
+	static  repository.StaticData
+	levels  map[int64]int64
+	visited map[int64]bool
+}
+
+// resolve walks the required skills of a type depth first. A skill's own
+// prerequisites don't depend on the level needed, so each type is only looked up once.
+func (r *resolver) resolve(ctx context.Context, typeID int64) error {
+	if r.visited[typeID] {
+		return nil
+	}
+	r.visited[typeID] = true
+
+	reqs, err := r.static.GetRequiredSkills(ctx, typeID, nil)
+	if err != nil {
+		return errors.Wrap(err, "could not get required skills", "type_id", typeID)
+	}
+
+	for _, req := range reqs {
+		if req.SkillLevel > r.levels[req.SkillID] {
+			r.levels[req.SkillID] = req.SkillLevel
+		}
+		if err := r.resolve(ctx, req.SkillID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -11,6 +11,9 @@ const (
 	CharacterID        = "evealts.character_id"
 	CharacterName      = "evealts.character_name"
 	CharacterPicture   = "evealts.character_picture"
+	CharacterTotalSP   = "evealts.character_total_sp"
+	CharacterOmega     = "evealts.character_omega"
+	SkillQueueLength   = "evealts.skill_queue_length"
 	CorporationID      = "evealts.corporation_id"
 	CorportaionName    = "evealts.corporation_name"
 	CorporationTicker  = "evealts.corporation_ticker"
@@ -21,6 +24,7 @@ const (
 	AlliancePicture    = "evealts.alliance_picture"
 	TagID              = "evealts.tag_id"
 	TagName            = "evealts.tag_name"
+	IncludedTagID      = "evealts.included_tag_id"
 	SkillID            = "evealts.skill_id"
 	SkillName          = "evealts.skill_name"
 	SkillLevel         = "evealts.skill_level"
@@ -28,7 +32,10 @@ const (
 	RoleName           = "evealts.role_name"
 	RoleLabel          = "evealts.role_label"
 	RoleOperator       = "evealts.role_operator"
+	ConditionKind      = "evealts.condition_kind"
 	Color              = "evealts.color"
+	NotificationID     = "evealts.notification_id"
+	WebhookURL         = "evealts.webhook_url"
 
 	HostPort  = "evealts.host_port"
 	ElapsedNS = "elapsed_ns"
@@ -0,0 +1,49 @@
+package matching
+
+import (
+	"testing"
+
+	"github.com/kava-forge/eve-alts/pkg/testhelpers/roster"
+)
+
+var (
+	R   *Results
+	IDs []int64
+)
+
+// roughly a large roster: 150 alts, 80 tags and 30 roles
+const (
+	benchChars = 150
+	benchTags  = 80
+	benchRoles = 30
+)
+
+func BenchmarkCompile(b *testing.B) {
+	chars, tags, roles := roster.Random(1, benchChars, benchTags, benchRoles)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		R = Compile(chars, tags, roles)
+	}
+}
+
+func BenchmarkEngineCached(b *testing.B) {
+	chars, tags, roles := roster.Random(1, benchChars, benchTags, benchRoles)
+	e := NewEngine()
+	e.Results(chars, tags, roles)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		R = e.Results(chars, tags, roles)
+	}
+}
+
+func BenchmarkFilter(b *testing.B) {
+	chars, tags, roles := roster.Random(1, benchChars, benchTags, benchRoles)
+	res := Compile(chars, tags, roles)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		IDs = res.Filter([]int64{2, 3}, TagRequired, []int64{2})
+	}
+}
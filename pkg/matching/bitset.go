@@ -0,0 +1,70 @@
+package matching
+
+import "math/bits"
+
+// Bitset holds one bit per character, in roster order
+type Bitset []uint64
+
+// NewBitset makes an empty bitset for n characters
+func NewBitset(n int) Bitset {
+	return make(Bitset, (n+63)/64)
+}
+
+// FullBitset makes a bitset for n characters with every bit set
+func FullBitset(n int) Bitset {
+	b := NewBitset(n)
+	for i := range b {
+		b[i] = ^uint64(0)
+	}
+	b.trim(n)
+	return b
+}
+
+func (b Bitset) Set(i int) {
+	b[i/64] |= 1 << (uint(i) % 64)
+}
+
+func (b Bitset) Has(i int) bool {
+	return b[i/64]&(1<<(uint(i)%64)) != 0
+}
+
+// And keeps only the bits also set in o
+func (b Bitset) And(o Bitset) {
+	for i := range b {
+		b[i] &= o[i]
+	}
+}
+
+// Or adds the bits set in o
+func (b Bitset) Or(o Bitset) {
+	for i := range b {
+		b[i] |= o[i]
+	}
+}
+
+// Not flips every bit of a bitset for n characters
+func (b Bitset) Not(n int) {
+	for i := range b {
+		b[i] = ^b[i]
+	}
+	b.trim(n)
+}
+
+func (b Bitset) Count() int {
+	c := 0
+	for _, w := range b {
+		c += bits.OnesCount64(w)
+	}
+	return c
+}
+
+func (b Bitset) Clone() Bitset {
+	return append(Bitset(nil), b...)
+}
+
+// trim clears the bits past the last character
+func (b Bitset) trim(n int) {
+	if rem := n % 64; rem != 0 && len(b) > 0 {
+		b[len(b)-1] &= (1 << uint(rem)) - 1
+	}
+}
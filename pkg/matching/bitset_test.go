@@ -0,0 +1,37 @@
+package matching_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kava-forge/eve-alts/pkg/matching"
+)
+
+func TestBitset(t *testing.T) {
+	t.Parallel()
+
+	const n = 70
+
+	b := matching.NewBitset(n)
+	b.Set(0)
+	b.Set(64)
+	b.Set(69)
+	assert.True(t, b.Has(64))
+	assert.False(t, b.Has(1))
+	assert.Equal(t, 3, b.Count())
+
+	full := matching.FullBitset(n)
+	assert.Equal(t, n, full.Count())
+
+	b.Not(n)
+	assert.Equal(t, n-3, b.Count(), "bits past the roster stay clear")
+
+	c := b.Clone()
+	c.And(matching.NewBitset(n))
+	assert.Equal(t, 0, c.Count())
+	assert.Equal(t, n-3, b.Count(), "clones are independent")
+
+	c.Or(full)
+	assert.Equal(t, n, c.Count())
+}
@@ -0,0 +1,481 @@
+// Package matching evaluates every character against every tag and role in
+// one pass. Tags are compiled to (skill, level) requirement vectors answered
+// from per-level bitsets over the roster, and role expressions combine the
+// tag bitsets with bitwise operations.
+package matching
+
+import (
+	"sync"
+
+	"github.com/kava-forge/eve-alts/pkg/conditions"
+	"github.com/kava-forge/eve-alts/pkg/operators"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+// TagLevel is how far a character meets a tag's skill levels
+type TagLevel int
+
+const (
+	// TagMissing means at least one required level is not trained
+	TagMissing TagLevel = iota
+	// TagRequired means every required level is trained, but not every recommended one
+	TagRequired
+	// TagRecommended means every required and recommended level is trained
+	TagRecommended
+)
+
+// maxNeed is the highest encoded level: levels are stored plus one, so that 0
+// means not injected and 1 means injected at level 0
+const maxNeed = 6
+
+// requirement is one entry of a compiled tag: the skill must be trained to at
+// least need-1
+type requirement struct {
+	skill int
+	need  int
+}
+
+// Results are the compiled matches of a roster against tags and roles
+type Results struct {
+	n     int
+	chars []int64
+	// charIdx, tagIdx and roleIdx map IDs to bitset positions and slots
+	charIdx map[int64]int
+	tagIdx  map[int64]int
+	roleIdx map[int64]int
+
+	required    []Bitset
+	recommended []Bitset
+	roles       []Bitset
+}
+
+// Compile evaluates every live character against every live tag and role.
+// Deleted items (ID 0) are left out. Role expressions follow the same rules as
+// the per-character evaluation: leaves for deleted tags and empty groups are
+// ignored, and a role with nothing left only depends on its conditions.
+func Compile(chars []*repository.CharacterDBData, tags []*repository.TagDBData, roles []*repository.RoleDBData) *Results {
+	r := &Results{
+		chars:   make([]int64, 0, len(chars)),
+		charIdx: make(map[int64]int, len(chars)),
+		tagIdx:  make(map[int64]int, len(tags)),
+		roleIdx: make(map[int64]int, len(roles)),
+	}
+
+	live := make([]*repository.CharacterDBData, 0, len(chars))
+	for _, c := range chars {
+		if c == nil || c.Character.ID == 0 {
+			continue
+		}
+		r.charIdx[c.Character.ID] = len(live)
+		r.chars = append(r.chars, c.Character.ID)
+		live = append(live, c)
+	}
+	r.n = len(live)
+
+	// compile tags to requirement vectors over a dense skill index
+	skillIdx := map[int64]int{}
+	compiled := make([][2][]requirement, 0, len(tags))
+	for _, t := range tags {
+		if t == nil || t.Tag.ID == 0 {
+			continue
+		}
+		r.tagIdx[t.Tag.ID] = len(compiled)
+		compiled = append(compiled, compileTag(t, skillIdx))
+	}
+
+	// atLeast[skill][need] has a bit for every character with the skill at
+	// need-1 or higher
+	atLeast := make([][maxNeed + 1]Bitset, len(skillIdx))
+	for i := range atLeast {
+		for need := 1; need <= maxNeed; need++ {
+			atLeast[i][need] = NewBitset(r.n)
+		}
+	}
+	for ci, c := range live {
+		for _, sk := range c.Skills {
+			si, ok := skillIdx[sk.SkillID]
+			if !ok {
+				continue
+			}
+			have := int(min(max(sk.SkillLevel, 0), 5)) + 1
+			for need := 1; need <= have; need++ {
+				atLeast[si][need].Set(ci)
+			}
+		}
+	}
+
+	r.required = make([]Bitset, len(compiled))
+	r.recommended = make([]Bitset, len(compiled))
+	for ti, ct := range compiled {
+		r.required[ti] = evalRequirements(r.n, ct[0], atLeast)
+		r.recommended[ti] = evalRequirements(r.n, ct[1], atLeast)
+		r.recommended[ti].And(r.required[ti])
+	}
+
+	r.roles = make([]Bitset, 0, len(roles))
+	for _, role := range roles {
+		if role == nil || role.Role.ID == 0 {
+			continue
+		}
+
+		match := FullBitset(r.n)
+		if role.Expr != nil {
+			if b, ok := r.evalExpr(role.Expr); ok {
+				match = b
+			}
+		}
+		for _, cond := range role.Conditions {
+			cb := NewBitset(r.n)
+			for ci, c := range live {
+				if MeetsCondition(c, cond) {
+					cb.Set(ci)
+				}
+			}
+			match.And(cb)
+		}
+
+		r.roleIdx[role.Role.ID] = len(r.roles)
+		r.roles = append(r.roles, match)
+	}
+
+	return r
+}
+
+// compileTag builds the required and recommended requirement vectors for a
+// tag's own and inherited skills, keeping the highest level per skill.
+// Recommended-only skills have no required level.
+func compileTag(t *repository.TagDBData, skillIdx map[int64]int) [2][]requirement {
+	required := map[int]int{}
+	recommended := map[int]int{}
+	for _, sk := range t.AllSkills() {
+		si, ok := skillIdx[sk.SkillID]
+		if !ok {
+			si = len(skillIdx)
+			skillIdx[sk.SkillID] = si
+		}
+
+		if sk.SkillLevel > 0 || sk.RecommendedLevel == 0 {
+			required[si] = max(required[si], int(min(sk.SkillLevel, 5))+1)
+		}
+		recommended[si] = max(recommended[si], int(min(max(sk.SkillLevel, sk.RecommendedLevel), 5))+1)
+	}
+
+	var ct [2][]requirement
+	for si, need := range required {
+		ct[0] = append(ct[0], requirement{skill: si, need: need})
+	}
+	for si, need := range recommended {
+		ct[1] = append(ct[1], requirement{skill: si, need: need})
+	}
+	return ct
+}
+
+func evalRequirements(n int, reqs []requirement, atLeast [][maxNeed + 1]Bitset) Bitset {
+	b := FullBitset(n)
+	for _, req := range reqs {
+		b.And(atLeast[req.skill][req.need])
+	}
+	return b
+}
+
+// evalExpr returns false for ok when the node should be ignored: a leaf whose
+// tag is gone, or a group without any remaining children
+func (r *Results) evalExpr(e *repository.RoleExpr) (Bitset, bool) {
+	if e.IsLeaf() {
+		ti, ok := r.tagIdx[e.TagID]
+		if !ok {
+			return nil, false
+		}
+		return r.required[ti].Clone(), true
+	}
+
+	children := make([]Bitset, 0, len(e.Children))
+	for _, ch := range e.Children {
+		if b, ok := r.evalExpr(ch); ok {
+			children = append(children, b)
+		}
+	}
+	if len(children) == 0 {
+		return nil, false
+	}
+
+	return combine(r.n, e, children), true
+}
+
+// combine applies a group's operator to the matches of its remaining
+// children. An unknown operator matches nobody.
+func combine(n int, e *repository.RoleExpr, children []Bitset) Bitset {
+	switch e.Operator {
+	case operators.OperatorAll:
+		b := FullBitset(n)
+		for _, ch := range children {
+			b.And(ch)
+		}
+		return b
+	case operators.OperatorAny, operators.OperatorNone:
+		b := NewBitset(n)
+		for _, ch := range children {
+			b.Or(ch)
+		}
+		if e.Operator == operators.OperatorNone {
+			b.Not(n)
+		}
+		return b
+	case operators.OperatorAtleast:
+		counts := make([]int64, n)
+		for _, ch := range children {
+			for ci := range counts {
+				if ch.Has(ci) {
+					counts[ci]++
+				}
+			}
+		}
+		b := NewBitset(n)
+		for ci, cnt := range counts {
+			if cnt >= e.MinCount {
+				b.Set(ci)
+			}
+		}
+		return b
+	default:
+		return NewBitset(n)
+	}
+}
+
+// ExprNode is how one node of a role expression evaluated for one character.
+// Children only has the nodes that were not ignored.
+type ExprNode struct {
+	Expr     *repository.RoleExpr
+	Matched  bool
+	Children []ExprNode
+}
+
+// EvalExpr evaluates a role expression node by node for one character, by the
+// same rules as Compile. leaf reports whether the character meets a tag's
+// required levels, and false for ok if the tag is gone. ok is false when the
+// node should be ignored.
+func EvalExpr(e *repository.RoleExpr, leaf func(tagID int64) (matched, ok bool)) (node ExprNode, ok bool) {
+	node.Expr = e
+	if e.IsLeaf() {
+		node.Matched, ok = leaf(e.TagID)
+		return node, ok
+	}
+
+	node.Children = make([]ExprNode, 0, len(e.Children))
+	children := make([]Bitset, 0, len(e.Children))
+	for _, ch := range e.Children {
+		chNode, ok := EvalExpr(ch, leaf)
+		if !ok {
+			continue
+		}
+		b := NewBitset(1)
+		if chNode.Matched {
+			b.Set(0)
+		}
+		children = append(children, b)
+		node.Children = append(node.Children, chNode)
+	}
+	if len(children) == 0 {
+		return node, false
+	}
+
+	node.Matched = combine(1, e, children).Has(0)
+	return node, true
+}
+
+// MeetsCondition checks one role condition against a character. Unknown
+// kinds, and an unknown clone state, are not met.
+func MeetsCondition(char *repository.CharacterDBData, cond repository.RoleCondition) bool {
+	switch conditions.Kind(cond.Kind) {
+	case conditions.KindCorporation:
+		return char.Corporation.ID == cond.Value
+	case conditions.KindAlliance:
+		return char.Alliance.ID.Valid && char.Alliance.ID.Int64 == cond.Value
+	case conditions.KindMinSp:
+		return char.Character.TotalSp >= cond.Value
+	case conditions.KindOmega:
+		return char.Character.Omega.Valid && char.Character.Omega.Bool
+	default:
+		return false
+	}
+}
+
+// Tag is how far the character meets the tag; ok is false if either is unknown
+func (r *Results) Tag(charID, tagID int64) (level TagLevel, ok bool) {
+	ci, ok := r.charIdx[charID]
+	if !ok {
+		return TagMissing, false
+	}
+	ti, ok := r.tagIdx[tagID]
+	if !ok {
+		return TagMissing, false
+	}
+
+	switch {
+	case r.recommended[ti].Has(ci):
+		return TagRecommended, true
+	case r.required[ti].Has(ci):
+		return TagRequired, true
+	default:
+		return TagMissing, true
+	}
+}
+
+// Role reports whether the character matches the role; ok is false if either
+// is unknown
+func (r *Results) Role(charID, roleID int64) (matched, ok bool) {
+	ci, ok := r.charIdx[charID]
+	if !ok {
+		return false, false
+	}
+	ri, ok := r.roleIdx[roleID]
+	if !ok {
+		return false, false
+	}
+	return r.roles[ri].Has(ci), true
+}
+
+// Filter lists the characters, in roster order, that meet every given tag at
+// least at level and match every given role. Unknown tags and roles match
+// nobody.
+func (r *Results) Filter(tagIDs []int64, level TagLevel, roleIDs []int64) []int64 {
+	b := FullBitset(r.n)
+	for _, id := range tagIDs {
+		ti, ok := r.tagIdx[id]
+		switch {
+		case !ok:
+			return []int64{}
+		case level >= TagRecommended:
+			b.And(r.recommended[ti])
+		case level >= TagRequired:
+			b.And(r.required[ti])
+		}
+	}
+	for _, id := range roleIDs {
+		ri, ok := r.roleIdx[id]
+		if !ok {
+			return []int64{}
+		}
+		b.And(r.roles[ri])
+	}
+
+	ids := make([]int64, 0, b.Count())
+	for ci, id := range r.chars {
+		if b.Has(ci) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Engine keeps the last compiled Results until the roster, tags or roles
+// change. Bound data is replaced on edit and has its ID zeroed on delete, so
+// a change is any difference in an item's pointer or ID. Callers must keep to
+// that: an item changed in place, behind the same pointer and ID, is not seen
+// and the cached results go stale.
+type Engine struct {
+	mu sync.Mutex
+
+	chars    []*repository.CharacterDBData
+	tags     []*repository.TagDBData
+	roles    []*repository.RoleDBData
+	ids      []int64
+	res      *Results
+	compiles int
+}
+
+func NewEngine() *Engine {
+	return &Engine{}
+}
+
+// Results returns the cached results, compiling them again first if anything
+// changed since the last call
+func (e *Engine) Results(chars []*repository.CharacterDBData, tags []*repository.TagDBData, roles []*repository.RoleDBData) *Results {
+	res, _ := e.Lookup(chars, tags, roles)
+	return res
+}
+
+// Lookup is Results, also reporting whether the cached results were used
+func (e *Engine) Lookup(chars []*repository.CharacterDBData, tags []*repository.TagDBData, roles []*repository.RoleDBData) (res *Results, hit bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.res != nil && e.unchanged(chars, tags, roles) {
+		return e.res, true
+	}
+
+	e.chars = append(e.chars[:0], chars...)
+	e.tags = append(e.tags[:0], tags...)
+	e.roles = append(e.roles[:0], roles...)
+	e.ids = e.ids[:0]
+	for _, c := range chars {
+		e.ids = append(e.ids, charID(c))
+	}
+	for _, t := range tags {
+		e.ids = append(e.ids, tagID(t))
+	}
+	for _, r := range roles {
+		e.ids = append(e.ids, roleID(r))
+	}
+
+	e.res = Compile(chars, tags, roles)
+	e.compiles++
+
+	return e.res, false
+}
+
+// Compiles is how many times the results were compiled, for diagnostics
+func (e *Engine) Compiles() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.compiles
+}
+
+func (e *Engine) unchanged(chars []*repository.CharacterDBData, tags []*repository.TagDBData, roles []*repository.RoleDBData) bool {
+	if len(chars) != len(e.chars) || len(tags) != len(e.tags) || len(roles) != len(e.roles) {
+		return false
+	}
+
+	i := 0
+	for j, c := range chars {
+		if c != e.chars[j] || charID(c) != e.ids[i] {
+			return false
+		}
+		i++
+	}
+	for j, t := range tags {
+		if t != e.tags[j] || tagID(t) != e.ids[i] {
+			return false
+		}
+		i++
+	}
+	for j, r := range roles {
+		if r != e.roles[j] || roleID(r) != e.ids[i] {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+func charID(c *repository.CharacterDBData) int64 {
+	if c == nil {
+		return 0
+	}
+	return c.Character.ID
+}
+
+func tagID(t *repository.TagDBData) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.Tag.ID
+}
+
+func roleID(r *repository.RoleDBData) int64 {
+	if r == nil {
+		return 0
+	}
+	return r.Role.ID
+}
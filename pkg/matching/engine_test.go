@@ -0,0 +1,212 @@
+package matching_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kava-forge/eve-alts/pkg/matching"
+	"github.com/kava-forge/eve-alts/pkg/operators"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+func testRoster() ([]*repository.CharacterDBData, []*repository.TagDBData) {
+	chars := []*repository.CharacterDBData{
+		{
+			Character:   repository.Character{ID: 1, Name: "Both", TotalSp: 10_000_000, Omega: sql.NullBool{Bool: true, Valid: true}},
+			Corporation: repository.Corporation{ID: 10},
+			Skills:      []repository.CharacterSkill{{SkillID: 100, SkillLevel: 5}, {SkillID: 101, SkillLevel: 4}},
+		},
+		{
+			Character:   repository.Character{ID: 2, Name: "Logi"},
+			Corporation: repository.Corporation{ID: 11},
+			Skills:      []repository.CharacterSkill{{SkillID: 100, SkillLevel: 4}},
+		},
+		{Character: repository.Character{ID: 3, Name: "None"}},
+		{Character: repository.Character{ID: 0, Name: "Deleted"}, Skills: []repository.CharacterSkill{{SkillID: 100, SkillLevel: 5}}},
+	}
+	tags := []*repository.TagDBData{
+		{Tag: repository.Tag{ID: 1, Name: "Logi"}, Skills: []repository.TagSkill{{TagID: 1, SkillID: 100, SkillLevel: 4, RecommendedLevel: 5}}},
+		{Tag: repository.Tag{ID: 2, Name: "Cap"}, Skills: []repository.TagSkill{{TagID: 2, SkillID: 101, SkillLevel: 0, RecommendedLevel: 4}}},
+		{Tag: repository.Tag{ID: 3, Name: "Gone"}},
+	}
+	tags[2].Tag.ID = 0
+
+	return chars, tags
+}
+
+func TestResults_Tag(t *testing.T) {
+	t.Parallel()
+
+	chars, tags := testRoster()
+	res := matching.Compile(chars, tags, nil)
+
+	tests := []struct {
+		name   string
+		charID int64
+		tagID  int64
+		want   matching.TagLevel
+		wantOK bool
+	}{
+		{"recommended", 1, 1, matching.TagRecommended, true},
+		{"required only", 2, 1, matching.TagRequired, true},
+		{"missing", 3, 1, matching.TagMissing, true},
+		{"recommended only skill is met", 2, 2, matching.TagRequired, true},
+		{"recommended only skill trained", 1, 2, matching.TagRecommended, true},
+		{"deleted character", 0, 1, matching.TagMissing, false},
+		{"unknown tag", 1, 3, matching.TagMissing, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := res.Tag(tt.charID, tt.tagID)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResults_Role(t *testing.T) {
+	t.Parallel()
+
+	chars, tags := testRoster()
+
+	tests := []struct {
+		name string
+		role *repository.RoleDBData
+		want []int64
+	}{
+		{
+			name: "all",
+			role: &repository.RoleDBData{Expr: repository.NewRoleGroup(operators.OperatorAll, repository.NewRoleLeaf(1), repository.NewRoleLeaf(2))},
+			want: []int64{1, 2},
+		},
+		{
+			name: "none",
+			role: &repository.RoleDBData{Expr: repository.NewRoleGroup(operators.OperatorNone, repository.NewRoleLeaf(1))},
+			want: []int64{3},
+		},
+		{
+			name: "atleast",
+			role: &repository.RoleDBData{Expr: repository.NewRoleAtleast(2,
+				repository.NewRoleLeaf(1),
+				repository.NewRoleGroup(operators.OperatorAny, repository.NewRoleLeaf(2)),
+				repository.NewRoleGroup(operators.OperatorNone, repository.NewRoleLeaf(2)),
+			)},
+			want: []int64{1, 2},
+		},
+		{
+			name: "deleted tags and empty groups are ignored",
+			role: &repository.RoleDBData{Expr: repository.NewRoleGroup(operators.OperatorAll,
+				repository.NewRoleLeaf(3),
+				repository.NewRoleGroup(operators.OperatorAny),
+			)},
+			want: []int64{1, 2, 3},
+		},
+		{
+			name: "conditions",
+			role: &repository.RoleDBData{
+				Expr: repository.NewRoleGroup(operators.OperatorAll, repository.NewRoleLeaf(1)),
+				Conditions: []repository.RoleCondition{
+					{Kind: "min_sp", Value: 5_000_000},
+					{Kind: "omega"},
+				},
+			},
+			want: []int64{1},
+		},
+		{
+			name: "only conditions",
+			role: &repository.RoleDBData{Conditions: []repository.RoleCondition{{Kind: "corporation", Value: 11}}},
+			want: []int64{2},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.role.Role.ID = 1
+			res := matching.Compile(chars, tags, []*repository.RoleDBData{tt.role})
+			assert.Equal(t, tt.want, res.Filter(nil, matching.TagMissing, []int64{1}))
+
+			ok, known := res.Role(3, 1)
+			assert.True(t, known)
+			assert.Equal(t, contains(tt.want, 3), ok)
+
+			// evaluating one character node by node gives the same answer
+			for _, c := range chars[:3] {
+				matched := true
+				if tt.role.Expr != nil {
+					node, ok := matching.EvalExpr(tt.role.Expr, func(tagID int64) (bool, bool) {
+						lvl, known := res.Tag(c.Character.ID, tagID)
+						return lvl >= matching.TagRequired, known
+					})
+					matched = !ok || node.Matched
+				}
+				for _, cond := range tt.role.Conditions {
+					matched = matched && matching.MeetsCondition(c, cond)
+				}
+				assert.Equal(t, contains(tt.want, c.Character.ID), matched, c.Character.Name)
+			}
+		})
+	}
+}
+
+func TestResults_Filter(t *testing.T) {
+	t.Parallel()
+
+	chars, tags := testRoster()
+	res := matching.Compile(chars, tags, nil)
+
+	assert.Equal(t, []int64{1, 2, 3}, res.Filter(nil, matching.TagRequired, nil))
+	assert.Equal(t, []int64{1, 2}, res.Filter([]int64{1}, matching.TagRequired, nil))
+	assert.Equal(t, []int64{1}, res.Filter([]int64{1, 2}, matching.TagRecommended, nil))
+	assert.Equal(t, []int64{}, res.Filter([]int64{3}, matching.TagRequired, nil))
+	assert.Equal(t, []int64{}, res.Filter(nil, matching.TagRequired, []int64{99}))
+}
+
+func TestEngine_Cache(t *testing.T) {
+	t.Parallel()
+
+	chars, tags := testRoster()
+	e := matching.NewEngine()
+
+	first := e.Results(chars, tags, nil)
+	assert.Same(t, first, e.Results(chars, tags, nil))
+	// a fresh slice with the same items is still the same data
+	assert.Same(t, first, e.Results(append([]*repository.CharacterDBData(nil), chars...), tags, nil))
+	assert.Equal(t, 1, e.Compiles())
+
+	// replacing a character, as a refresh does
+	updated := *chars[2]
+	updated.Skills = []repository.CharacterSkill{{SkillID: 100, SkillLevel: 5}}
+	chars[2] = &updated
+	second := e.Results(chars, tags, nil)
+	assert.NotSame(t, first, second)
+	lvl, _ := second.Tag(3, 1)
+	assert.Equal(t, matching.TagRecommended, lvl)
+
+	// deleting a tag in place
+	tags[0].Tag.ID = 0
+	third := e.Results(chars, tags, nil)
+	assert.NotSame(t, second, third)
+	_, ok := third.Tag(3, 1)
+	assert.False(t, ok)
+	assert.Equal(t, 3, e.Compiles())
+
+	res, hit := e.Lookup(chars, tags, nil)
+	assert.True(t, hit)
+	assert.Same(t, third, res)
+}
+
+func contains(ids []int64, id int64) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package matrix
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+)
+
+// WriteCSV writes one line per character, after a header naming each column
+func WriteCSV(w io.Writer, m *Matrix) error {
+	cw := csv.NewWriter(w)
+
+	header := make([]string, 0, len(m.Columns)+2)
+	header = append(header, "Character ID", "Character")
+	for _, c := range m.Columns {
+		header = append(header, c.Title())
+	}
+	if err := cw.Write(header); err != nil {
+		return errors.Wrap(err, "could not write header")
+	}
+
+	for _, r := range m.Rows {
+		line := make([]string, 0, len(r.Cells)+2)
+		line = append(line, strconv.FormatInt(r.CharacterID, 10), r.CharacterName)
+		for _, c := range r.Cells {
+			line = append(line, c.String())
+		}
+		if err := cw.Write(line); err != nil {
+			return errors.Wrap(err, "could not write row", "character", r.CharacterName)
+		}
+	}
+
+	cw.Flush()
+	return errors.Wrap(cw.Error(), "could not flush csv")
+}
@@ -0,0 +1,106 @@
+package matrix
+
+import (
+	"fmt"
+	"html/template"
+	"image/color"
+	"io"
+	"time"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+)
+
+var htmlTemplate = template.Must(template.New("matrix").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>EVE Alts matrix</title>
+<style>
+body { font-family: sans-serif; font-size: 13px; margin: 1em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; white-space: nowrap; }
+thead th { position: sticky; top: 0; }
+th.kind { background: #eee; }
+td.name { font-weight: bold; }
+td.recommended, td.qualified { background: #c6efce; color: #006100; }
+td.required { background: #ffeb9c; color: #9c5700; }
+td.missing, td.unqualified { background: #ffc7ce; color: #9c0006; }
+</style>
+</head>
+<body>
+<h1>EVE Alts matrix</h1>
+<p>Generated {{.Generated}}. {{len .Rows}} characters, {{.Tags}} tags, {{.Roles}} roles.</p>
+<table>
+<thead>
+<tr><th class="kind" rowspan="2">Character</th>{{if .Tags}}<th class="kind" colspan="{{.Tags}}">Tags</th>{{end}}{{if .Roles}}<th class="kind" colspan="{{.Roles}}">Roles</th>{{end}}</tr>
+<tr>{{range .Columns}}<th style="{{.Style}}">{{.Name}}</th>{{end}}</tr>
+</thead>
+<tbody>
+{{range .Rows}}<tr><td class="name">{{.CharacterName}}</td>{{range .Cells}}<td class="{{.State}}">{{.String}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>
+</body>
+</html>
+`))
+
+type htmlColumn struct {
+	Name  string
+	Style template.CSS
+}
+
+type htmlPage struct {
+	Generated string
+	Tags      int
+	Roles     int
+	Columns   []htmlColumn
+	Rows      []Row
+}
+
+// WriteHTML writes a self-contained page, with each column header in its tag
+// or role color
+func WriteHTML(w io.Writer, m *Matrix) error {
+	page := htmlPage{
+		Generated: time.Now().Format(time.RFC1123),
+		Columns:   make([]htmlColumn, 0, len(m.Columns)),
+		Rows:      m.Rows,
+	}
+	for _, c := range m.Columns {
+		switch c.Kind {
+		case ColumnTag:
+			page.Tags++
+		case ColumnRole:
+			page.Roles++
+		}
+		page.Columns = append(page.Columns, htmlColumn{
+			Name: c.Name,
+			// both colors come from hexColor, so they are safe to mark as CSS
+			Style: template.CSS(fmt.Sprintf("background: #%s; color: #%s;", hexColor(c.Color), hexColor(textColor(c.Color)))), //nolint:gosec // see above
+		})
+	}
+
+	return errors.Wrap(htmlTemplate.Execute(w, page), "could not render matrix html")
+}
+
+// opaque blends c onto white by its alpha
+func opaque(c color.Color) color.NRGBA {
+	nc, _ := color.NRGBAModel.Convert(c).(color.NRGBA)
+	blend := func(v uint8) uint8 {
+		return uint8((int(v)*int(nc.A) + 255*(255-int(nc.A))) / 255)
+	}
+	return color.NRGBA{R: blend(nc.R), G: blend(nc.G), B: blend(nc.B), A: 255}
+}
+
+// hexColor is the six digit RRGGBB form of c once blended onto white
+func hexColor(c color.Color) string {
+	o := opaque(c)
+	return fmt.Sprintf("%02X%02X%02X", o.R, o.G, o.B)
+}
+
+// textColor picks black or white, whichever reads better on bg
+func textColor(bg color.Color) color.Color {
+	o := opaque(bg)
+	if 299*int(o.R)+587*int(o.G)+114*int(o.B) > 128_000 {
+		return color.Black
+	}
+	return color.White
+}
@@ -0,0 +1,247 @@
+// Package matrix grades every character against every tag and role, and
+// writes the result as a CSV, HTML or XLSX report.
+package matrix
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/app/characters"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+var ErrUnknownFormat = errors.New("unknown matrix format")
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatHTML Format = "html"
+	FormatXLSX Format = "xlsx"
+)
+
+// Formats lists every format, in the order they are offered
+func Formats() []Format {
+	return []Format{FormatCSV, FormatHTML, FormatXLSX}
+}
+
+// ParseFormat checks a format name given on the command line
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats() {
+		if string(f) == strings.ToLower(s) {
+			return f, nil
+		}
+	}
+	return "", errors.Wrap(ErrUnknownFormat, "format must be csv, html or xlsx", "format", s)
+}
+
+// FormatFromPath picks the report format from a file extension
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".html", ".htm":
+		return FormatHTML, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	default:
+		return "", errors.Wrap(ErrUnknownFormat, "matrix files must end in .csv, .html or .xlsx", "path", path)
+	}
+}
+
+// Write encodes m in the given format
+func Write(w io.Writer, m *Matrix, f Format) error {
+	switch f {
+	case FormatCSV:
+		return WriteCSV(w, m)
+	case FormatHTML:
+		return WriteHTML(w, m)
+	case FormatXLSX:
+		return WriteXLSX(w, m)
+	default:
+		return errors.Wrap(ErrUnknownFormat, "cannot write matrix", "format", string(f))
+	}
+}
+
+type ColumnKind string
+
+const (
+	ColumnTag  ColumnKind = "tag"
+	ColumnRole ColumnKind = "role"
+)
+
+// Column is one tag or role; tags come first, then roles
+type Column struct {
+	Kind  ColumnKind
+	ID    int64
+	Name  string
+	Color color.Color
+}
+
+// Title names the column with its kind, as tags and roles may share names
+func (c Column) Title() string {
+	return fmt.Sprintf("%s (%s)", c.Name, c.Kind)
+}
+
+type State string
+
+const (
+	// StateRecommended means every required and recommended level of a tag is trained
+	StateRecommended State = "recommended"
+	// StateRequired means every required level of a tag is trained, but not every recommended one
+	StateRequired State = "required"
+	// StateMissing means at least one required level of a tag is not trained
+	StateMissing State = "missing"
+	// StateQualified means the character matches a role
+	StateQualified State = "qualified"
+	// StateUnqualified means the character does not match a role
+	StateUnqualified State = "unqualified"
+)
+
+// Cell is one character graded against one column
+type Cell struct {
+	State State
+	// Missing is how many skills are below their required level; for roles,
+	// the distinct skills across every tag that made the role fail
+	Missing int
+	// Conditions is how many of a role's conditions are not met
+	Conditions int
+}
+
+func (c Cell) String() string {
+	switch c.State {
+	case StateMissing:
+		return fmt.Sprintf("missing %d", c.Missing)
+	case StateUnqualified:
+		parts := make([]string, 0, 2)
+		if c.Missing > 0 {
+			parts = append(parts, fmt.Sprintf("missing %d", c.Missing))
+		}
+		if c.Conditions > 0 {
+			parts = append(parts, fmt.Sprintf("%d conditions unmet", c.Conditions))
+		}
+		if len(parts) == 0 {
+			return string(StateUnqualified)
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return string(c.State)
+	}
+}
+
+type Row struct {
+	CharacterID   int64
+	CharacterName string
+	// Cells line up with Matrix.Columns
+	Cells []Cell
+}
+
+type Matrix struct {
+	Columns []Column
+	Rows    []Row
+}
+
+// Build grades every character against every tag and role. Characters, tags
+// and roles are each sorted by name; deleted tags and roles are left out.
+func Build(chars []*repository.CharacterDBData, tags []*repository.TagDBData, roles []*repository.RoleDBData) *Matrix {
+	liveTags := make([]*repository.TagDBData, 0, len(tags))
+	for _, t := range tags {
+		if t != nil && t.Tag.ID != 0 {
+			liveTags = append(liveTags, t)
+		}
+	}
+	sort.SliceStable(liveTags, func(i, j int) bool { return liveTags[i].Tag.Name < liveTags[j].Tag.Name })
+
+	liveRoles := make([]*repository.RoleDBData, 0, len(roles))
+	for _, r := range roles {
+		if r != nil && r.Role.ID != 0 {
+			liveRoles = append(liveRoles, r)
+		}
+	}
+	sort.SliceStable(liveRoles, func(i, j int) bool { return liveRoles[i].Role.Name < liveRoles[j].Role.Name })
+
+	liveChars := make([]*repository.CharacterDBData, 0, len(chars))
+	for _, c := range chars {
+		if c != nil && c.Character.ID != 0 {
+			liveChars = append(liveChars, c)
+		}
+	}
+	sort.SliceStable(liveChars, func(i, j int) bool { return liveChars[i].Character.Name < liveChars[j].Character.Name })
+
+	m := &Matrix{
+		Columns: make([]Column, 0, len(liveTags)+len(liveRoles)),
+		Rows:    make([]Row, 0, len(liveChars)),
+	}
+	for _, t := range liveTags {
+		m.Columns = append(m.Columns, Column{Kind: ColumnTag, ID: t.Tag.ID, Name: t.Tag.Name, Color: t.Color()})
+	}
+	for _, r := range liveRoles {
+		m.Columns = append(m.Columns, Column{Kind: ColumnRole, ID: r.Role.ID, Name: r.Role.Name, Color: r.Color()})
+	}
+
+	for _, c := range liveChars {
+		row := Row{
+			CharacterID:   c.Character.ID,
+			CharacterName: c.Character.Name,
+			Cells:         make([]Cell, 0, len(m.Columns)),
+		}
+		for _, t := range liveTags {
+			row.Cells = append(row.Cells, tagCell(c, t))
+		}
+		for _, r := range liveRoles {
+			row.Cells = append(row.Cells, roleCell(c, r, liveTags))
+		}
+		m.Rows = append(m.Rows, row)
+	}
+
+	return m
+}
+
+func tagCell(char *repository.CharacterDBData, tag *repository.TagDBData) Cell {
+	match, missing, _ := characters.CharacterTagMatch(char, tag)
+	switch match {
+	case characters.TagMatchRecommended:
+		return Cell{State: StateRecommended}
+	case characters.TagMatchRequired:
+		return Cell{State: StateRequired}
+	default:
+		return Cell{State: StateMissing, Missing: len(missing)}
+	}
+}
+
+func roleCell(char *repository.CharacterDBData, role *repository.RoleDBData, tags []*repository.TagDBData) Cell {
+	ok, res := characters.CharacterMatchesRole(char, role, tags)
+	if ok {
+		return Cell{State: StateQualified}
+	}
+
+	failing := make(map[int64]struct{})
+	for _, t := range res.Failing() {
+		failing[t.ID] = struct{}{}
+	}
+
+	skills := make(map[int64]struct{})
+	var walk func(e characters.RoleExplanation)
+	walk = func(e characters.RoleExplanation) {
+		if e.IsLeaf() {
+			if _, ok := failing[e.Tag.ID]; ok {
+				for _, sk := range e.Missing {
+					skills[sk.SkillID] = struct{}{}
+				}
+			}
+			return
+		}
+		for _, ch := range e.Children {
+			walk(ch)
+		}
+	}
+	walk(res.Expr)
+
+	return Cell{State: StateUnqualified, Missing: len(skills), Conditions: len(res.FailingConditions())}
+}
@@ -0,0 +1,171 @@
+package matrix_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kava-forge/eve-alts/pkg/matrix"
+	"github.com/kava-forge/eve-alts/pkg/operators"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+func testMatrix() *matrix.Matrix {
+	chars := []*repository.CharacterDBData{
+		{Character: repository.Character{ID: 2, Name: "Zed", TotalSp: 1_000_000}, Skills: []repository.CharacterSkill{{SkillID: 100, SkillLevel: 1}}},
+		{Character: repository.Character{ID: 1, Name: "Amy", TotalSp: 50_000_000}, Skills: []repository.CharacterSkill{{SkillID: 100, SkillLevel: 5}, {SkillID: 101, SkillLevel: 3}}},
+		{Character: repository.Character{ID: 0, Name: "Deleted"}},
+	}
+	tags := []*repository.TagDBData{
+		{
+			Tag:    repository.Tag{ID: 10, Name: "Logi", ColorR: 0, ColorG: 255, ColorB: 0, ColorA: 255},
+			Skills: []repository.TagSkill{{SkillID: 100, SkillLevel: 4, RecommendedLevel: 5}, {SkillID: 101, SkillLevel: 3}},
+		},
+		{
+			Tag:    repository.Tag{ID: 11, Name: "Cap", ColorR: 0, ColorG: 0, ColorB: 128, ColorA: 255},
+			Skills: []repository.TagSkill{{SkillID: 100, SkillLevel: 4}, {SkillID: 102, SkillLevel: 5}},
+		},
+		{Tag: repository.Tag{ID: 0, Name: "Deleted"}},
+	}
+	roles := []*repository.RoleDBData{
+		{
+			Role:       repository.Role{ID: 20, Name: "Logi Pilot", ColorR: 255, ColorG: 255, ColorB: 255, ColorA: 255},
+			Expr:       repository.NewRoleGroup(operators.OperatorAll, repository.NewRoleLeaf(10)),
+			Conditions: []repository.RoleCondition{{Kind: "min_sp", Value: 20_000_000}},
+		},
+		{
+			Role: repository.Role{ID: 21, Name: "Capper", ColorR: 255, ColorA: 255},
+			Expr: repository.NewRoleGroup(operators.OperatorAll, repository.NewRoleLeaf(10), repository.NewRoleLeaf(11)),
+		},
+	}
+
+	return matrix.Build(chars, tags, roles)
+}
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	m := testMatrix()
+
+	titles := make([]string, 0, len(m.Columns))
+	for _, c := range m.Columns {
+		titles = append(titles, c.Title())
+	}
+	assert.Equal(t, []string{"Cap (tag)", "Logi (tag)", "Capper (role)", "Logi Pilot (role)"}, titles)
+
+	require.Len(t, m.Rows, 2)
+	assert.Equal(t, "Amy", m.Rows[0].CharacterName)
+	assert.Equal(t, []matrix.Cell{
+		{State: matrix.StateMissing, Missing: 1},
+		{State: matrix.StateRecommended},
+		{State: matrix.StateUnqualified, Missing: 1},
+		{State: matrix.StateQualified},
+	}, m.Rows[0].Cells)
+
+	assert.Equal(t, "Zed", m.Rows[1].CharacterName)
+	assert.Equal(t, []matrix.Cell{
+		{State: matrix.StateMissing, Missing: 2},
+		{State: matrix.StateMissing, Missing: 2},
+		{State: matrix.StateUnqualified, Missing: 3},
+		{State: matrix.StateUnqualified, Missing: 2, Conditions: 1},
+	}, m.Rows[1].Cells)
+	assert.Equal(t, "missing 2, 1 conditions unmet", m.Rows[1].Cells[3].String())
+}
+
+func TestFormatFromPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path    string
+		want    matrix.Format
+		wantErr bool
+	}{
+		{path: "out.csv", want: matrix.FormatCSV},
+		{path: "Report.HTML", want: matrix.FormatHTML},
+		{path: "book.xlsx", want: matrix.FormatXLSX},
+		{path: "book.xls", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := matrix.FormatFromPath(tt.path)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, matrix.ErrUnknownFormat)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, matrix.Write(&buf, testMatrix(), matrix.FormatCSV))
+
+	lines, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Character ID", "Character", "Cap (tag)", "Logi (tag)", "Capper (role)", "Logi Pilot (role)"},
+		{"1", "Amy", "missing 1", "recommended", "missing 1", "qualified"},
+		{"2", "Zed", "missing 2", "missing 2", "missing 3", "missing 2, 1 conditions unmet"},
+	}, lines)
+}
+
+func TestWriteHTML(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, matrix.Write(&buf, testMatrix(), matrix.FormatHTML))
+
+	out := buf.String()
+	assert.Contains(t, out, `<th style="background: #000080; color: #FFFFFF;">Cap</th>`)
+	assert.Contains(t, out, `<th style="background: #00FF00; color: #000000;">Logi</th>`)
+	assert.Contains(t, out, `<th class="kind" colspan="2">Roles</th>`)
+	assert.Contains(t, out, `<td class="recommended">recommended</td>`)
+	assert.Contains(t, out, `<td class="unqualified">missing 2, 1 conditions unmet</td>`)
+	assert.NotContains(t, out, "<link")
+	assert.NotContains(t, out, "<script")
+}
+
+func TestWriteXLSX(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, matrix.Write(&buf, testMatrix(), matrix.FormatXLSX))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	parts := make(map[string]string, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		body, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		parts[f.Name] = string(body)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		assert.Contains(t, parts, name)
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">Character</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="E1" s="8" t="inlineStr"><is><t xml:space="preserve">Logi Pilot (role)</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="C2" s="2" t="inlineStr"><is><t xml:space="preserve">recommended</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="E3" s="4" t="inlineStr"><is><t xml:space="preserve">missing 2, 1 conditions unmet</t></is></c>`)
+
+	assert.Contains(t, parts["xl/styles.xml"], `<fgColor rgb="FF00FF00"/>`)
+	assert.Contains(t, parts["xl/styles.xml"], `<cellXfs count="9">`)
+}
@@ -0,0 +1,180 @@
+package matrix
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+)
+
+// The workbook is written by hand: it is a zip of a handful of fixed parts,
+// one worksheet of inline strings and a style sheet with a fill per column.
+
+const xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`
+
+const xlsxRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Matrix" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+const xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+// cell style indexes into cellXfs; column headers follow xfColumn
+const (
+	xfDefault = iota
+	xfHeader
+	xfGood
+	xfRequired
+	xfBad
+	xfColumn
+)
+
+// WriteXLSX writes a single sheet workbook, with each column header filled in
+// its tag or role color and each cell filled by its state
+func WriteXLSX(w io.Writer, m *Matrix) error {
+	zw := zip.NewWriter(w)
+
+	parts := []struct {
+		name string
+		body string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles(m)},
+		{"xl/worksheets/sheet1.xml", xlsxSheet(m)},
+	}
+	for _, p := range parts {
+		f, err := zw.Create(p.name)
+		if err != nil {
+			return errors.Wrap(err, "could not add workbook part", "part", p.name)
+		}
+		if _, err := io.WriteString(f, p.body); err != nil {
+			return errors.Wrap(err, "could not write workbook part", "part", p.name)
+		}
+	}
+
+	return errors.Wrap(zw.Close(), "could not finish workbook")
+}
+
+func xlsxStyles(m *Matrix) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<fonts count="6">` +
+		`<font><sz val="11"/><name val="Calibri"/></font>` +
+		`<font><b/><sz val="11"/><name val="Calibri"/></font>` +
+		`<font><b/><sz val="11"/><color rgb="FFFFFFFF"/><name val="Calibri"/></font>` +
+		`<font><sz val="11"/><color rgb="FF006100"/><name val="Calibri"/></font>` +
+		`<font><sz val="11"/><color rgb="FF9C5700"/><name val="Calibri"/></font>` +
+		`<font><sz val="11"/><color rgb="FF9C0006"/><name val="Calibri"/></font>` +
+		`</fonts>`)
+
+	// the first two fills are reserved by the format
+	fills := []string{"EEEEEE", "C6EFCE", "FFEB9C", "FFC7CE"}
+	for _, c := range m.Columns {
+		fills = append(fills, hexColor(c.Color))
+	}
+	fmt.Fprintf(&b, `<fills count="%d"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill>`, len(fills)+2)
+	for _, f := range fills {
+		fmt.Fprintf(&b, `<fill><patternFill patternType="solid"><fgColor rgb="FF%s"/><bgColor indexed="64"/></patternFill></fill>`, f)
+	}
+	b.WriteString(`</fills>`)
+
+	b.WriteString(`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>`)
+	b.WriteString(`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`)
+
+	fmt.Fprintf(&b, `<cellXfs count="%d">`, xfColumn+len(m.Columns))
+	xf := func(font, fill int) {
+		fmt.Fprintf(&b, `<xf numFmtId="0" fontId="%d" fillId="%d" borderId="0" xfId="0" applyFont="1" applyFill="1"/>`, font, fill)
+	}
+	xf(0, 0) // xfDefault
+	xf(1, 2) // xfHeader
+	xf(3, 3) // xfGood
+	xf(4, 4) // xfRequired
+	xf(5, 5) // xfBad
+	for i, c := range m.Columns {
+		font := 1
+		if opaque(textColor(c.Color)).R == 255 {
+			font = 2
+		}
+		xf(font, 6+i)
+	}
+	b.WriteString(`</cellXfs>`)
+
+	b.WriteString(`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>`)
+	b.WriteString(`</styleSheet>`)
+	return b.String()
+}
+
+func xlsxSheet(m *Matrix) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	// keep the header row and the name column in view while scrolling
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane xSplit="1" ySplit="1" topLeftCell="B2" activePane="bottomRight" state="frozen"/></sheetView></sheetViews>`)
+	b.WriteString(`<cols><col min="1" max="1" width="28" customWidth="1"/>`)
+	if len(m.Columns) > 0 {
+		fmt.Fprintf(&b, `<col min="2" max="%d" width="18" customWidth="1"/>`, len(m.Columns)+1)
+	}
+	b.WriteString(`</cols><sheetData>`)
+
+	b.WriteString(`<row r="1">`)
+	xlsxCell(&b, 0, 1, "Character", xfHeader)
+	for i, c := range m.Columns {
+		xlsxCell(&b, i+1, 1, c.Title(), xfColumn+i)
+	}
+	b.WriteString(`</row>`)
+
+	for ri, r := range m.Rows {
+		row := ri + 2
+		fmt.Fprintf(&b, `<row r="%d">`, row)
+		xlsxCell(&b, 0, row, r.CharacterName, xfDefault)
+		for ci, c := range r.Cells {
+			style := xfBad
+			switch c.State {
+			case StateRecommended, StateQualified:
+				style = xfGood
+			case StateRequired:
+				style = xfRequired
+			}
+			xlsxCell(&b, ci+1, row, c.String(), style)
+		}
+		b.WriteString(`</row>`)
+	}
+
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+func xlsxCell(b *strings.Builder, col, row int, text string, style int) {
+	var esc bytes.Buffer
+	_ = xml.EscapeText(&esc, []byte(text))
+	fmt.Fprintf(b, `<c r="%s%d" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, columnName(col), row, style, esc.String())
+}
+
+// columnName is the spreadsheet letter name of a zero based column: A, B, ... Z, AA, AB, ...
+func columnName(col int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name
+}
@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kava-forge/eve-alts/pkg/matching"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+//go:generate go-enum --marshal --names --values
+
+// ENUM(role_gained, role_lost, omega_gained, omega_lost, queue_empty)
+type EventKind string
+
+// Event is one change to a character worth telling someone about
+type Event struct {
+	Kind          EventKind
+	CharacterID   int64
+	CharacterName string
+	// RoleName is set for role events
+	RoleName string
+}
+
+func (e Event) String() string {
+	switch e.Kind {
+	case EventKindRoleGained:
+		return fmt.Sprintf("%s now qualifies for role %s", e.CharacterName, e.RoleName)
+	case EventKindRoleLost:
+		return fmt.Sprintf("%s no longer qualifies for role %s", e.CharacterName, e.RoleName)
+	case EventKindOmegaGained:
+		return fmt.Sprintf("%s is now Omega", e.CharacterName)
+	case EventKindOmegaLost:
+		return fmt.Sprintf("%s lost Omega", e.CharacterName)
+	case EventKindQueueEmpty:
+		return fmt.Sprintf("%s's skill queue is empty", e.CharacterName)
+	default:
+		return fmt.Sprintf("%s: %s", e.CharacterName, e.Kind)
+	}
+}
+
+// Diff lists what changed between two snapshots of a character. A character
+// seen for the first time (before is nil) has nothing to report, and facts
+// that were unknown before a refresh do not count as changes.
+func Diff(before, after *repository.CharacterDBData, tags []*repository.TagDBData, roles []*repository.RoleDBData) []Event {
+	if before == nil || after == nil {
+		return nil
+	}
+
+	charID := after.Character.ID
+	event := func(kind EventKind, roleName string) Event {
+		return Event{Kind: kind, CharacterID: charID, CharacterName: after.Character.Name, RoleName: roleName}
+	}
+
+	var events []Event
+
+	sorted := make([]*repository.RoleDBData, 0, len(roles))
+	for _, r := range roles {
+		if r != nil && r.Role.ID != 0 {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Role.Name < sorted[j].Role.Name })
+
+	was := matching.Compile([]*repository.CharacterDBData{before}, tags, sorted)
+	is := matching.Compile([]*repository.CharacterDBData{after}, tags, sorted)
+	for _, r := range sorted {
+		had, _ := was.Role(before.Character.ID, r.Role.ID)
+		has, _ := is.Role(charID, r.Role.ID)
+		switch {
+		case has && !had:
+			events = append(events, event(EventKindRoleGained, r.Role.Name))
+		case had && !has:
+			events = append(events, event(EventKindRoleLost, r.Role.Name))
+		}
+	}
+
+	bo, ao := before.Character.Omega, after.Character.Omega
+	if bo.Valid && ao.Valid && bo.Bool != ao.Bool {
+		if ao.Bool {
+			events = append(events, event(EventKindOmegaGained, ""))
+		} else {
+			events = append(events, event(EventKindOmegaLost, ""))
+		}
+	}
+
+	bq, aq := before.Character.SkillQueueLength, after.Character.SkillQueueLength
+	if bq.Valid && bq.Int64 > 0 && aq.Valid && aq.Int64 == 0 {
+		events = append(events, event(EventKindQueueEmpty, ""))
+	}
+
+	return events
+}
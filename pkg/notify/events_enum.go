@@ -0,0 +1,96 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version:
+// Revision:
+// Build Date:
+// Built By:
+
+package notify
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// EventKindRoleGained is a EventKind of type role_gained.
+	EventKindRoleGained EventKind = "role_gained"
+	// EventKindRoleLost is a EventKind of type role_lost.
+	EventKindRoleLost EventKind = "role_lost"
+	// EventKindOmegaGained is a EventKind of type omega_gained.
+	EventKindOmegaGained EventKind = "omega_gained"
+	// EventKindOmegaLost is a EventKind of type omega_lost.
+	EventKindOmegaLost EventKind = "omega_lost"
+	// EventKindQueueEmpty is a EventKind of type queue_empty.
+	EventKindQueueEmpty EventKind = "queue_empty"
+)
+
+var ErrInvalidEventKind = fmt.Errorf("not a valid EventKind, try [%s]", strings.Join(_EventKindNames, ", "))
+
+var _EventKindNames = []string{
+	string(EventKindRoleGained),
+	string(EventKindRoleLost),
+	string(EventKindOmegaGained),
+	string(EventKindOmegaLost),
+	string(EventKindQueueEmpty),
+}
+
+// EventKindNames returns a list of possible string values of EventKind.
+func EventKindNames() []string {
+	tmp := make([]string, len(_EventKindNames))
+	copy(tmp, _EventKindNames)
+	return tmp
+}
+
+// EventKindValues returns a list of the values for EventKind
+func EventKindValues() []EventKind {
+	return []EventKind{
+		EventKindRoleGained,
+		EventKindRoleLost,
+		EventKindOmegaGained,
+		EventKindOmegaLost,
+		EventKindQueueEmpty,
+	}
+}
+
+// String implements the Stringer interface.
+func (x EventKind) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x EventKind) IsValid() bool {
+	_, err := ParseEventKind(string(x))
+	return err == nil
+}
+
+var _EventKindValue = map[string]EventKind{
+	"role_gained":  EventKindRoleGained,
+	"role_lost":    EventKindRoleLost,
+	"omega_gained": EventKindOmegaGained,
+	"omega_lost":   EventKindOmegaLost,
+	"queue_empty":  EventKindQueueEmpty,
+}
+
+// ParseEventKind attempts to convert a string to a EventKind.
+func ParseEventKind(name string) (EventKind, error) {
+	if x, ok := _EventKindValue[name]; ok {
+		return x, nil
+	}
+	return EventKind(""), fmt.Errorf("%s is %w", name, ErrInvalidEventKind)
+}
+
+// MarshalText implements the text marshaller method.
+func (x EventKind) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *EventKind) UnmarshalText(text []byte) error {
+	tmp, err := ParseEventKind(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
@@ -0,0 +1,129 @@
+package notify_test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kava-forge/eve-alts/pkg/notify"
+	"github.com/kava-forge/eve-alts/pkg/operators"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+var (
+	testTags = []*repository.TagDBData{{
+		Tag:    repository.Tag{ID: 5, Name: "Logi Cruiser"},
+		Skills: []repository.TagSkill{{TagID: 5, SkillID: 100, SkillLevel: 4}},
+	}}
+	testRoles = []*repository.RoleDBData{
+		{
+			Role: repository.Role{ID: 7, Name: "Logi"},
+			Expr: repository.NewRoleGroup(operators.OperatorAll, repository.NewRoleLeaf(5)),
+		},
+		{
+			Role:       repository.Role{ID: 8, Name: "Omega Logi"},
+			Expr:       repository.NewRoleGroup(operators.OperatorAll, repository.NewRoleLeaf(5)),
+			Conditions: []repository.RoleCondition{{Kind: "omega"}},
+		},
+	}
+)
+
+func testChar(level int64, omega sql.NullBool, queue sql.NullInt64) *repository.CharacterDBData {
+	return &repository.CharacterDBData{
+		Character: repository.Character{ID: 1, Name: "Alt X", Omega: omega, SkillQueueLength: queue},
+		Skills:    []repository.CharacterSkill{{CharacterID: 1, SkillID: 100, SkillLevel: level}},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	omega := sql.NullBool{Bool: true, Valid: true}
+	alpha := sql.NullBool{Valid: true}
+	training := sql.NullInt64{Int64: 3, Valid: true}
+	empty := sql.NullInt64{Valid: true}
+
+	tests := []struct {
+		name   string
+		before *repository.CharacterDBData
+		after  *repository.CharacterDBData
+		want   []string
+	}{
+		{
+			name:  "new character",
+			after: testChar(5, omega, empty),
+		},
+		{
+			name:   "nothing changed",
+			before: testChar(4, omega, training),
+			after:  testChar(5, omega, training),
+		},
+		{
+			name:   "trained into roles",
+			before: testChar(3, omega, training),
+			after:  testChar(4, omega, training),
+			want:   []string{"Alt X now qualifies for role Logi", "Alt X now qualifies for role Omega Logi"},
+		},
+		{
+			name:   "lost omega",
+			before: testChar(4, omega, training),
+			after:  testChar(4, alpha, training),
+			want:   []string{"Alt X no longer qualifies for role Omega Logi", "Alt X lost Omega"},
+		},
+		{
+			name:   "omega was unknown",
+			before: testChar(4, sql.NullBool{}, training),
+			after:  testChar(4, alpha, training),
+		},
+		{
+			name:   "queue ran out",
+			before: testChar(4, alpha, training),
+			after:  testChar(4, alpha, empty),
+			want:   []string{"Alt X's skill queue is empty"},
+		},
+		{
+			name:   "queue was unknown",
+			before: testChar(4, alpha, sql.NullInt64{}),
+			after:  testChar(4, alpha, empty),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, e := range notify.Diff(tt.before, tt.after, testTags, testRoles) {
+				got = append(got, e.String())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPayloads(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, notify.Payloads("bot", nil))
+
+	events := []notify.Event{
+		{Kind: notify.EventKindOmegaLost, CharacterName: "A"},
+		{Kind: notify.EventKindQueueEmpty, CharacterName: "B"},
+	}
+	assert.Equal(t, []notify.Payload{{Username: "bot", Content: "A lost Omega\nB's skill queue is empty"}}, notify.Payloads("bot", events))
+
+	long := make([]notify.Event, 0, 200)
+	for range 200 {
+		long = append(long, notify.Event{Kind: notify.EventKindRoleGained, CharacterName: strings.Repeat("x", 30), RoleName: "Logi"})
+	}
+	payloads := notify.Payloads("bot", long)
+	assert.Greater(t, len(payloads), 1)
+	lines := 0
+	for _, p := range payloads {
+		assert.LessOrEqual(t, len(p.Content), 2000)
+		lines += strings.Count(p.Content, "\n") + 1
+	}
+	assert.Equal(t, 200, lines)
+}
@@ -0,0 +1,292 @@
+// Package notify posts character changes found by a refresh (roles gained or
+// lost, Omega lapsing, an empty skill queue) to webhooks. Messages are queued
+// in the app database first, so one that cannot be delivered is retried with
+// backoff, across restarts, until it is delivered or given up on.
+package notify
+
+import (
+	"bytes"
+	"context"
+	stdhttp "net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kava-forge/eve-alts/lib/deferutil"
+	"github.com/kava-forge/eve-alts/lib/errors"
+	"github.com/kava-forge/eve-alts/lib/json"
+	"github.com/kava-forge/eve-alts/lib/logging"
+	"github.com/kava-forge/eve-alts/lib/logging/level"
+
+	"github.com/kava-forge/eve-alts/pkg/background"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/keys"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/telemetry"
+)
+
+const (
+	DefaultUsername    = "EVE Alts"
+	DefaultMaxAttempts = 8
+
+	// retryBase doubles with each failed attempt, up to retryMax
+	retryBase = 30 * time.Second
+	retryMax  = time.Hour
+
+	flushInterval = 30 * time.Second
+	flushBatch    = 50
+)
+
+type dependencies interface {
+	Logger() logging.Logger
+	Telemetry() *telemetry.Telemeter
+	AppRepo() repository.AppData
+}
+
+type Options struct {
+	Webhooks []string
+	// Events limits which kinds are sent; empty sends every kind
+	Events      []EventKind
+	Username    string
+	MaxAttempts int64
+	// Client defaults to one with a short timeout; the queue does the retrying
+	Client *stdhttp.Client
+}
+
+type Notifier struct {
+	deps   dependencies
+	logger logging.Logger
+	opts   Options
+	events map[EventKind]bool
+
+	// flushing keeps the background loop and a direct Flush from sending the
+	// same queued message twice
+	flushing sync.Mutex
+
+	// wake tells the background loop new messages were queued
+	wake     chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+var _ background.Serverlike = (*Notifier)(nil)
+
+func New(deps dependencies, opts Options) *Notifier {
+	if opts.Username == "" {
+		opts.Username = DefaultUsername
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.Client == nil {
+		opts.Client = &stdhttp.Client{Timeout: 10 * time.Second}
+	}
+
+	n := &Notifier{
+		deps:   deps,
+		logger: logging.With(deps.Logger(), keys.Component, "notify.Notifier"),
+		opts:   opts,
+		wake:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+	}
+	if len(opts.Events) > 0 {
+		n.events = make(map[EventKind]bool, len(opts.Events))
+		for _, k := range opts.Events {
+			n.events[k] = true
+		}
+	}
+	return n
+}
+
+// Enabled reports whether any webhooks are configured. A nil Notifier is
+// disabled.
+func (n *Notifier) Enabled() bool {
+	return n != nil && len(n.opts.Webhooks) > 0
+}
+
+// CharacterRefreshed diffs a character before and after a refresh against
+// the current tags and roles, then queues any events
+func (n *Notifier) CharacterRefreshed(ctx context.Context, before, after *repository.CharacterDBData) (err error) {
+	if !n.Enabled() || before == nil {
+		return nil
+	}
+
+	ctx, span := telemetry.StartSpan(ctx, n.deps.Telemetry(), "notify", "CharacterRefreshed")
+	defer telemetry.EndSpan(span, &err)
+
+	tags, err := n.deps.AppRepo().GetAllTags(ctx, nil)
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return errors.Wrap(err, "could not GetAllTags")
+	}
+	roles, err := n.deps.AppRepo().GetAllRoles(ctx, nil)
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return errors.Wrap(err, "could not GetAllRoles")
+	}
+
+	return n.Notify(ctx, Diff(before, after, tags, roles))
+}
+
+// Notify queues events for every webhook. It does not wait for them to be
+// sent: the background loop sends them as soon as it is woken.
+func (n *Notifier) Notify(ctx context.Context, events []Event) error {
+	if !n.Enabled() {
+		return nil
+	}
+
+	wanted := make([]Event, 0, len(events))
+	for _, e := range events {
+		if n.events == nil || n.events[e.Kind] {
+			wanted = append(wanted, e)
+		}
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	for _, p := range Payloads(n.opts.Username, wanted) {
+		b, err := json.Marshal(p)
+		if err != nil {
+			return errors.Wrap(err, "could not encode webhook payload")
+		}
+		for _, url := range n.opts.Webhooks {
+			if _, err := n.deps.AppRepo().InsertNotification(ctx, url, string(b), now, nil); err != nil {
+				return errors.Wrap(err, "could not InsertNotification")
+			}
+		}
+	}
+
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Flush sends every queued message that is due. Failures are rescheduled, not
+// returned; only trouble with the queue itself is.
+func (n *Notifier) Flush(ctx context.Context) error {
+	n.flushing.Lock()
+	defer n.flushing.Unlock()
+
+	due, err := n.deps.AppRepo().GetDueNotifications(ctx, time.Now(), flushBatch, nil)
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return errors.Wrap(err, "could not GetDueNotifications")
+	}
+
+	for _, msg := range due {
+		retryAfter, sendErr := n.send(ctx, msg)
+		if sendErr == nil {
+			level.Debug(n.logger).Message("sent notification", keys.NotificationID, msg.ID)
+			if err := n.deps.AppRepo().DeleteNotification(ctx, msg.ID, nil); err != nil {
+				return errors.Wrap(err, "could not DeleteNotification")
+			}
+			continue
+		}
+
+		attempts := msg.Attempts + 1
+		if errors.Is(sendErr, errPermanent) || attempts >= n.opts.MaxAttempts {
+			level.Error(n.logger).Err("giving up on notification", sendErr, keys.NotificationID, msg.ID, "attempts", attempts)
+			if err := n.deps.AppRepo().DeleteNotification(ctx, msg.ID, nil); err != nil {
+				return errors.Wrap(err, "could not DeleteNotification")
+			}
+			continue
+		}
+
+		wait := max(retryAfter, backoff(attempts))
+		level.Info(n.logger).Err("could not send notification, will retry", sendErr, keys.NotificationID, msg.ID, "attempts", attempts, "wait", wait)
+		if err := n.deps.AppRepo().RescheduleNotification(ctx, msg.ID, attempts, time.Now().Add(wait), sendErr.Error(), nil); err != nil {
+			return errors.Wrap(err, "could not RescheduleNotification")
+		}
+	}
+
+	return nil
+}
+
+// errPermanent marks a response that retrying will not fix
+var errPermanent = errors.New("webhook rejected the message")
+
+// permanentError keeps the cause's chain while matching errPermanent
+type permanentError struct {
+	error
+}
+
+func permanent(err error) error {
+	return &permanentError{error: err}
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.error
+}
+
+func (e *permanentError) Is(target error) bool {
+	return target == errPermanent
+}
+
+// backoff is how long to wait after the given number of failed attempts
+func backoff(attempts int64) time.Duration {
+	d := retryBase
+	for i := int64(1); i < attempts && d < retryMax; i++ {
+		d *= 2
+	}
+	return min(d, retryMax)
+}
+
+// send posts one message, returning how long the receiver asked us to wait
+// when it rate limits
+func (n *Notifier) send(ctx context.Context, msg repository.Notification) (time.Duration, error) {
+	req, err := stdhttp.NewRequestWithContext(ctx, stdhttp.MethodPost, msg.Url, bytes.NewBufferString(msg.Payload))
+	if err != nil {
+		return 0, permanent(errors.Wrap(err, "could not build webhook request", keys.NotificationID, msg.ID))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.opts.Client.Do(req)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not post webhook")
+	}
+	defer deferutil.CheckDeferLog(n.logger, resp.Body.Close)
+
+	switch {
+	case resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode == stdhttp.StatusTooManyRequests:
+		var wait time.Duration
+		if secs, err := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64); err == nil {
+			wait = time.Duration(secs * float64(time.Second))
+		}
+		return wait, errors.WithDetails(errors.New("webhook rate limited"), "code", resp.StatusCode)
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != stdhttp.StatusRequestTimeout:
+		return 0, permanent(errors.WithDetails(errors.New("webhook returned a client error"), "code", resp.StatusCode))
+	default:
+		return 0, errors.WithDetails(errors.New("webhook failed"), "code", resp.StatusCode)
+	}
+}
+
+// ListenAndServe sends queued messages when Notify queues more, and retries
+// them on a timer, until Shutdown, so the notifier runs alongside the servers
+// in background.RunBackground
+func (n *Notifier) ListenAndServe() error {
+	t := time.NewTicker(flushInterval)
+	defer t.Stop()
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), flushInterval)
+		if err := n.Flush(ctx); err != nil {
+			level.Error(n.logger).Err("could not flush notifications", err)
+		}
+		cancel()
+
+		select {
+		case <-n.stop:
+			return stdhttp.ErrServerClosed
+		case <-t.C:
+		case <-n.wake:
+		}
+	}
+}
+
+func (n *Notifier) Shutdown(context.Context) error {
+	n.stopOnce.Do(func() { close(n.stop) })
+	return nil
+}
@@ -0,0 +1,265 @@
+package notify_test
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kava-forge/eve-alts/lib/json"
+
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/notify"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/testhelpers"
+)
+
+// queue keeps the notifications table in memory behind the fake repository
+type queue struct {
+	mu     sync.Mutex
+	nextID int64
+	rows   map[int64]repository.Notification
+}
+
+func newQueue(deps *testhelpers.TestDependencies) *queue {
+	q := &queue{rows: map[int64]repository.Notification{}}
+
+	deps.TestAppRepo.InsertNotificationCalls(func(_ context.Context, url, payload string, next time.Time, _ database.Tx) (repository.Notification, error) {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		q.nextID++
+		n := repository.Notification{ID: q.nextID, Url: url, Payload: payload, NextAttempt: next}
+		q.rows[n.ID] = n
+		return n, nil
+	})
+	deps.TestAppRepo.GetDueNotificationsCalls(func(_ context.Context, now time.Time, limit int64, _ database.Tx) ([]repository.Notification, error) {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		due := make([]repository.Notification, 0, len(q.rows))
+		for _, n := range q.rows {
+			if !n.NextAttempt.After(now) {
+				due = append(due, n)
+			}
+		}
+		sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+		return due[:min(int64(len(due)), limit)], nil
+	})
+	deps.TestAppRepo.RescheduleNotificationCalls(func(_ context.Context, id, attempts int64, next time.Time, lastErr string, _ database.Tx) error {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		n := q.rows[id]
+		n.Attempts, n.NextAttempt, n.LastError = attempts, next, lastErr
+		q.rows[id] = n
+		return nil
+	})
+	deps.TestAppRepo.DeleteNotificationCalls(func(_ context.Context, id int64, _ database.Tx) error {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		delete(q.rows, id)
+		return nil
+	})
+
+	return q
+}
+
+func (q *queue) all() []repository.Notification {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	rows := make([]repository.Notification, 0, len(q.rows))
+	for _, n := range q.rows {
+		rows = append(rows, n)
+	}
+	return rows
+}
+
+// receiver records webhook posts and answers with the next status in line,
+// then 204 once they run out
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	received []notify.Payload
+}
+
+func (rc *receiver) ServeHTTP(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	b, _ := io.ReadAll(r.Body)
+	var p notify.Payload
+	_ = json.Unmarshal(b, &p)
+	rc.received = append(rc.received, p)
+
+	status := stdhttp.StatusNoContent
+	if len(rc.statuses) > 0 {
+		status, rc.statuses = rc.statuses[0], rc.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func TestNotifier(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		statuses     []int
+		events       []notify.EventKind
+		wantReceived int
+		wantQueued   int
+		wantAttempts int64
+	}{
+		{
+			name:         "delivered",
+			wantReceived: 1,
+		},
+		{
+			name:         "server error is retried later",
+			statuses:     []int{stdhttp.StatusBadGateway},
+			wantReceived: 1,
+			wantQueued:   1,
+			wantAttempts: 1,
+		},
+		{
+			name:         "rate limit is retried later",
+			statuses:     []int{stdhttp.StatusTooManyRequests},
+			wantReceived: 1,
+			wantQueued:   1,
+			wantAttempts: 1,
+		},
+		{
+			name:         "client error is dropped",
+			statuses:     []int{stdhttp.StatusNotFound},
+			wantReceived: 1,
+		},
+		{
+			name:   "filtered out",
+			events: []notify.EventKind{notify.EventKindQueueEmpty},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rc := &receiver{statuses: tt.statuses}
+			srv := httptest.NewServer(rc)
+			defer srv.Close()
+
+			deps := testhelpers.NewTestDependencies(t)
+			q := newQueue(deps)
+			deps.TestAppRepo.GetAllTagsReturns(testTags, nil)
+			deps.TestAppRepo.GetAllRolesReturns(testRoles, nil)
+
+			n := notify.New(deps, notify.Options{Webhooks: []string{srv.URL}, Events: tt.events, Username: "bot"})
+			require.True(t, n.Enabled())
+
+			err := n.CharacterRefreshed(context.Background(), testChar(3, sql.NullBool{}, sql.NullInt64{}), testChar(4, sql.NullBool{}, sql.NullInt64{}))
+			require.NoError(t, err)
+
+			// refreshing only queues, the flush sends
+			assert.Empty(t, rc.received)
+			assert.Len(t, q.all(), tt.wantReceived)
+			require.NoError(t, n.Flush(context.Background()))
+
+			require.Len(t, rc.received, tt.wantReceived)
+			if tt.wantReceived > 0 {
+				assert.Equal(t, notify.Payload{Username: "bot", Content: "Alt X now qualifies for role Logi"}, rc.received[0])
+			}
+
+			queued := q.all()
+			require.Len(t, queued, tt.wantQueued)
+			if tt.wantQueued > 0 {
+				assert.Equal(t, tt.wantAttempts, queued[0].Attempts)
+				assert.True(t, queued[0].NextAttempt.After(time.Now()))
+				assert.NotEmpty(t, queued[0].LastError)
+
+				// not due yet, so a flush leaves it alone
+				require.NoError(t, n.Flush(context.Background()))
+				assert.Len(t, rc.received, tt.wantReceived)
+			}
+		})
+	}
+}
+
+func TestNotifier_RetriesFromQueue(t *testing.T) {
+	t.Parallel()
+
+	rc := &receiver{statuses: []int{stdhttp.StatusInternalServerError}}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	deps := testhelpers.NewTestDependencies(t)
+	q := newQueue(deps)
+	n := notify.New(deps, notify.Options{Webhooks: []string{srv.URL}, MaxAttempts: 3})
+
+	// a message left over from an earlier run, already retried once
+	_, err := deps.TestAppRepo.InsertNotification(context.Background(), srv.URL, `{"content":"hello"}`, time.Now(), nil)
+	require.NoError(t, err)
+	require.NoError(t, deps.TestAppRepo.RescheduleNotification(context.Background(), 1, 1, time.Now(), "earlier failure", nil))
+
+	require.NoError(t, n.Flush(context.Background()))
+	queued := q.all()
+	require.Len(t, queued, 1)
+	assert.Equal(t, int64(2), queued[0].Attempts)
+
+	// the third failure uses up MaxAttempts and drops it
+	rc.statuses = []int{stdhttp.StatusInternalServerError}
+	require.NoError(t, deps.TestAppRepo.RescheduleNotification(context.Background(), 1, 2, time.Now(), "", nil))
+	require.NoError(t, n.Flush(context.Background()))
+	assert.Empty(t, q.all())
+	assert.Len(t, rc.received, 2)
+	assert.Equal(t, "hello", rc.received[0].Content)
+}
+
+func TestNotifier_Disabled(t *testing.T) {
+	t.Parallel()
+
+	var nilNotifier *notify.Notifier
+	assert.False(t, nilNotifier.Enabled())
+	assert.NoError(t, nilNotifier.CharacterRefreshed(context.Background(), testChar(3, sql.NullBool{}, sql.NullInt64{}), testChar(4, sql.NullBool{}, sql.NullInt64{})))
+
+	deps := testhelpers.NewTestDependencies(t)
+	n := notify.New(deps, notify.Options{})
+	assert.False(t, n.Enabled())
+	assert.NoError(t, n.CharacterRefreshed(context.Background(), testChar(3, sql.NullBool{}, sql.NullInt64{}), testChar(4, sql.NullBool{}, sql.NullInt64{})))
+	assert.Equal(t, 0, deps.TestAppRepo.GetAllRolesCallCount())
+}
+
+func TestNotifier_ListenAndServe(t *testing.T) {
+	t.Parallel()
+
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	deps := testhelpers.NewTestDependencies(t)
+	q := newQueue(deps)
+	n := notify.New(deps, notify.Options{Webhooks: []string{srv.URL}})
+
+	done := make(chan error)
+	go func() { done <- n.ListenAndServe() }()
+
+	// queued messages are sent without waiting for the next retry
+	require.NoError(t, n.Notify(context.Background(), []notify.Event{{Kind: notify.EventKindQueueEmpty, CharacterName: "Alt X"}}))
+	require.Eventually(t, func() bool {
+		rc.mu.Lock()
+		defer rc.mu.Unlock()
+		return len(rc.received) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool { return len(q.all()) == 0 }, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, n.Shutdown(context.Background()))
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, stdhttp.ErrServerClosed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("ListenAndServe did not stop")
+	}
+}
@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"strings"
+)
+
+// maxContent is Discord's limit on a message's content
+const maxContent = 2000
+
+// Payload is a Discord-compatible webhook message. Slack and most chat bridges
+// accept it too.
+type Payload struct {
+	Username string `json:"username,omitempty"`
+	Content  string `json:"content"`
+}
+
+// Payloads puts one event per line, splitting across as many messages as
+// Discord's length limit needs
+func Payloads(username string, events []Event) []Payload {
+	var payloads []Payload
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 {
+			payloads = append(payloads, Payload{Username: username, Content: b.String()})
+			b.Reset()
+		}
+	}
+
+	for _, e := range events {
+		line := e.String()
+		if len(line) > maxContent {
+			line = line[:maxContent]
+		}
+		if b.Len() > 0 && b.Len()+1+len(line) > maxContent {
+			flush()
+		}
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(line)
+	}
+	flush()
+
+	return payloads
+}
@@ -2,5 +2,5 @@ package operators
 
 //go:generate go-enum --sql --marshal --names --values
 
-// ENUM(all, any, none)
+// ENUM(all, any, none, atleast)
 type Operator string
@@ -20,6 +20,8 @@ const (
 	OperatorAny Operator = "any"
 	// OperatorNone is a Operator of type none.
 	OperatorNone Operator = "none"
+	// OperatorAtleast is a Operator of type atleast.
+	OperatorAtleast Operator = "atleast"
 )
 
 var ErrInvalidOperator = fmt.Errorf("not a valid Operator, try [%s]", strings.Join(_OperatorNames, ", "))
@@ -28,6 +30,7 @@ var _OperatorNames = []string{
 	string(OperatorAll),
 	string(OperatorAny),
 	string(OperatorNone),
+	string(OperatorAtleast),
 }
 
 // OperatorNames returns a list of possible string values of Operator.
@@ -43,6 +46,7 @@ func OperatorValues() []Operator {
 		OperatorAll,
 		OperatorAny,
 		OperatorNone,
+		OperatorAtleast,
 	}
 }
 
@@ -59,9 +63,10 @@ func (x Operator) IsValid() bool {
 }
 
 var _OperatorValue = map[string]Operator{
-	"all":  OperatorAll,
-	"any":  OperatorAny,
-	"none": OperatorNone,
+	"all":     OperatorAll,
+	"any":     OperatorAny,
+	"none":    OperatorNone,
+	"atleast": OperatorAtleast,
 }
 
 // ParseOperator attempts to convert a string to a Operator.
@@ -1,9 +1,11 @@
 package repository
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"image/color"
+	"slices"
 	"strconv"
 	"time"
 
@@ -11,9 +13,9 @@ import (
 	"github.com/kava-forge/eve-alts/lib/logging"
 	"github.com/kava-forge/eve-alts/lib/logging/level"
 
+	"github.com/kava-forge/eve-alts/pkg/conditions"
 	"github.com/kava-forge/eve-alts/pkg/database"
 	"github.com/kava-forge/eve-alts/pkg/keys"
-	"github.com/kava-forge/eve-alts/pkg/operators"
 	"github.com/kava-forge/eve-alts/pkg/repository/internal/appdb"
 	"github.com/kava-forge/eve-alts/pkg/telemetry"
 )
@@ -26,8 +28,12 @@ type (
 	CharacterSkill = appdb.CharacterSkill
 	Tag            = appdb.Tag
 	TagSkill       = appdb.TagSkill
+	TagInclude     = appdb.TagInclude
 	Role           = appdb.Role
-	RoleTag        = appdb.RoleTag
+	RoleNode       = appdb.RoleNode
+	RoleCondition  = appdb.RoleCondition
+	Notification   = appdb.Notification
+	VaultSetting   = appdb.VaultSetting
 )
 
 type CharacterDBData struct {
@@ -40,6 +46,12 @@ type CharacterDBData struct {
 type TagDBData struct {
 	Tag    Tag
 	Skills []TagSkill
+	// Includes are the tags directly included by this tag
+	Includes []Tag
+	// Inherited are the skills required through Includes, at any depth, that
+	// Skills does not already require at the same or a higher level.
+	// TagID is the tag each skill was inherited from.
+	Inherited []TagSkill
 }
 
 func (t TagDBData) Color() color.Color {
@@ -56,9 +68,13 @@ func (t TagDBData) StrID() string {
 }
 
 type RoleDBData struct {
-	Role     Role
-	Operator operators.Operator
-	Tags     []Tag
+	Role Role
+	// Expr is the role's requirement expression; the root is always a group
+	Expr *RoleExpr
+	// Tags are every tag referenced anywhere in Expr
+	Tags []Tag
+	// Conditions must all hold on top of Expr
+	Conditions []RoleCondition
 }
 
 func (t RoleDBData) Color() color.Color {
@@ -76,15 +92,18 @@ func (t RoleDBData) StrID() string {
 
 //counterfeiter:generate . AppData
 type AppData interface {
-	UpsertCharacter(ctx context.Context, charID int64, name, picture string, corporationID int64, tx database.Tx) (Character, error)
+	UpsertCharacter(ctx context.Context, charID int64, name, picture string, corporationID, totalSP int64, omega bool, tx database.Tx) (Character, error)
 	UpsertCorporation(ctx context.Context, corpID int64, name, ticker, picture string, allianceID int64, tx database.Tx) (Corporation, error)
 	UpsertAlliance(ctx context.Context, allianceID int64, name, ticker, picture string, tx database.Tx) (Alliance, error)
 	UpsertToken(ctx context.Context, charID int64, accessToken, refreshToken, tokenType string, expiration time.Time, tx database.Tx) (Token, error)
 	GetAllCharacters(ctx context.Context, tx database.Tx) ([]*CharacterDBData, error)
+	GetCharacter(ctx context.Context, charID int64, tx database.Tx) (*CharacterDBData, error)
+	UpdateCharacterSkillQueue(ctx context.Context, charID, length int64, tx database.Tx) error
 	GetTokenForCharacter(ctx context.Context, charID int64, tx database.Tx) (Token, error)
 	GetAllCharacterSkills(ctx context.Context, charID int64, tx database.Tx) ([]CharacterSkill, error)
 	UpsertCharacterSkill(ctx context.Context, charID, skillID, trainedLevel int64, tx database.Tx) (CharacterSkill, error)
 	DeleteCharacterSkills(ctx context.Context, charID int64, skillIDs []int64, tx database.Tx) error
+	ReplaceCharacterSkills(ctx context.Context, charID int64, levels map[int64]int64, tx database.Tx) ([]CharacterSkill, error)
 	DeleteCharacter(ctx context.Context, charID int64, tx database.Tx) error
 
 	InsertTag(ctx context.Context, name string, c color.Color, tx database.Tx) (Tag, error)
@@ -92,27 +111,45 @@ type AppData interface {
 	DeleteTag(ctx context.Context, tagID int64, tx database.Tx) error
 	GetAllTags(ctx context.Context, tx database.Tx) ([]*TagDBData, error)
 	GetAllTagSkills(ctx context.Context, tagID int64, tx database.Tx) ([]TagSkill, error)
-	UpsertTagSkill(ctx context.Context, tagID, skillID, skillLevel int64, tx database.Tx) (TagSkill, error)
+	UpsertTagSkill(ctx context.Context, tagID, skillID, skillLevel, recommendedLevel int64, tx database.Tx) (TagSkill, error)
 	DeleteTagSkills(ctx context.Context, tagID int64, skillIDs []int64, tx database.Tx) error
+	UpsertTagInclude(ctx context.Context, tagID, includedTagID int64, tx database.Tx) error
+	DeleteTagIncludes(ctx context.Context, tagID int64, includedTagIDs []int64, tx database.Tx) error
 
-	InsertRole(ctx context.Context, name, label string, operator operators.Operator, c color.Color, tx database.Tx) (Role, error)
-	UpdateRole(ctx context.Context, roleID int64, name, label string, operator operators.Operator, c color.Color, tx database.Tx) error
+	InsertRole(ctx context.Context, name, label string, c color.Color, tx database.Tx) (Role, error)
+	UpdateRole(ctx context.Context, roleID int64, name, label string, c color.Color, tx database.Tx) error
 	DeleteRole(ctx context.Context, roleID int64, tx database.Tx) error
 	GetAllRoles(ctx context.Context, tx database.Tx) ([]*RoleDBData, error)
 	GetAllRoleTags(ctx context.Context, roleID int64, tx database.Tx) ([]Tag, error)
-	UpsertRoleTag(ctx context.Context, roleID, tagID int64, tx database.Tx) (RoleTag, error)
-	DeleteRoleTags(ctx context.Context, roleID int64, tagIDs []int64, tx database.Tx) error
+	GetRoleExpr(ctx context.Context, roleID int64, tx database.Tx) (*RoleExpr, error)
+	SetRoleExpr(ctx context.Context, roleID int64, expr *RoleExpr, tx database.Tx) error
+	GetRoleConditions(ctx context.Context, roleID int64, tx database.Tx) ([]RoleCondition, error)
+	SetRoleConditions(ctx context.Context, roleID int64, conds []RoleCondition, tx database.Tx) error
+	InsertNotification(ctx context.Context, url, payload string, nextAttempt time.Time, tx database.Tx) (Notification, error)
+	GetDueNotifications(ctx context.Context, now time.Time, limit int64, tx database.Tx) ([]Notification, error)
+	RescheduleNotification(ctx context.Context, id, attempts int64, nextAttempt time.Time, lastError string, tx database.Tx) error
+	DeleteNotification(ctx context.Context, id int64, tx database.Tx) error
+
+	UnlockVault(ctx context.Context, src KeySource) error
+	RotateVault(ctx context.Context, to KeySource) error
+	VaultStatus(ctx context.Context) (VaultStatus, error)
+	ResetVault(ctx context.Context) error
+
+	Events() *EventBus
+	WatchExternalChanges(ctx context.Context, interval time.Duration) error
 }
 
 type appDependencies interface {
 	DB() database.Connection
 	Logger() logging.Logger
 	Telemetry() *telemetry.Telemeter
+	Vault() *TokenVault
 }
 
 type AppSqliteRepository struct {
 	deps    appDependencies
 	queries *appdb.Queries
+	events  *EventBus
 }
 
 var _ AppData = (*AppSqliteRepository)(nil)
@@ -121,6 +158,7 @@ func NewAppData(deps appDependencies) *AppSqliteRepository {
 	return &AppSqliteRepository{
 		deps:    deps,
 		queries: appdb.New(),
+		events:  NewEventBus(deps.Logger()),
 	}
 }
 
@@ -131,21 +169,53 @@ func (r *AppSqliteRepository) db(tx database.Tx) appdb.DBTX {
 	return r.deps.DB()
 }
 
-func (r *AppSqliteRepository) UpsertCharacter(ctx context.Context, charID int64, name, picture string, corporationID int64, tx database.Tx) (char Character, err error) {
+// batchSize caps how many ids are bound in one IN list, well under SQLite's
+// limit on variables in a statement
+const batchSize = 500
+
+// inBatches calls fn with ids split into slices of at most batchSize, so
+// children of many parents load in one statement per batch
+func inBatches(ids []int64, fn func(ids []int64) error) error {
+	for len(ids) > 0 {
+		n := min(batchSize, len(ids))
+		if err := fn(ids[:n]); err != nil {
+			return err
+		}
+		ids = ids[n:]
+	}
+	return nil
+}
+
+func (r *AppSqliteRepository) UpsertCharacter(ctx context.Context, charID int64, name, picture string, corporationID, totalSP int64, omega bool, tx database.Tx) (char Character, err error) {
 	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "UpsertCharacter")
 	defer telemetry.EndSpan(span, &err)
 
 	logger := r.deps.Logger()
-	level.Debug(logger).Message("calling UpsertCharacter", keys.CharacterID, charID, keys.CharacterName, name, keys.CharacterPicture, picture, keys.CorporationID, corporationID)
+	level.Debug(logger).Message("calling UpsertCharacter", keys.CharacterID, charID, keys.CharacterName, name, keys.CharacterPicture, picture, keys.CorporationID, corporationID, keys.CharacterTotalSP, totalSP, keys.CharacterOmega, omega)
 
 	inner := func(ctx context.Context, tx database.Tx) error {
+		exists, err := r.queries.CharacterExists(ctx, tx, charID)
+		if err != nil {
+			return errors.Wrap(err, "could not CharacterExists")
+		}
+
 		char, err = r.queries.UpsertCharacter(ctx, tx, appdb.UpsertCharacterParams{
 			ID:            charID,
 			Name:          name,
 			Picture:       picture,
 			CorporationID: corporationID,
+			TotalSp:       totalSP,
+			Omega:         sql.NullBool{Bool: omega, Valid: true},
 		})
-		return errors.Wrap(err, "could not UpsertCharacter")
+		if err != nil {
+			return errors.Wrap(err, "could not UpsertCharacter")
+		}
+		change := ChangeCreated
+		if exists != 0 {
+			change = ChangeUpdated
+		}
+		r.publish(tx, ChangeEvent{Entity: EntityCharacter, Change: change, ID: charID})
+		return nil
 	}
 
 	if tx == nil {
@@ -221,14 +291,27 @@ func (r *AppSqliteRepository) UpsertToken(ctx context.Context, charID int64, acc
 	level.Debug(logger).Message("calling UpsertToken", keys.CharacterID, charID)
 
 	inner := func(ctx context.Context, tx database.Tx) error {
+		if err := r.checkVaultKey(ctx, tx); err != nil {
+			return err
+		}
+
+		sealedAccess, err := r.deps.Vault().Seal(charID, "access_token", accessToken)
+		if err != nil {
+			return errors.Wrap(err, "could not seal access token")
+		}
+		sealedRefresh, err := r.deps.Vault().Seal(charID, "refresh_token", refreshToken)
+		if err != nil {
+			return errors.Wrap(err, "could not seal refresh token")
+		}
+
 		tok, err = r.queries.UpsertToken(ctx, tx, appdb.UpsertTokenParams{
 			CharacterID:  charID,
-			AccessToken:  accessToken,
-			RefreshToken: refreshToken,
+			AccessToken:  sealedAccess,
+			RefreshToken: sealedRefresh,
 			TokenType:    tokenType,
 			Expiration:   expiration,
 		})
-		return errors.Wrap(err, "could not UpsertCharacter")
+		return errors.Wrap(err, "could not UpsertToken")
 	}
 
 	if tx == nil {
@@ -236,6 +319,9 @@ func (r *AppSqliteRepository) UpsertToken(ctx context.Context, charID int64, acc
 	} else {
 		err = inner(ctx, tx)
 	}
+	if err == nil {
+		tok.AccessToken, tok.RefreshToken = accessToken, refreshToken
+	}
 	return tok, err
 }
 
@@ -251,24 +337,88 @@ func (r *AppSqliteRepository) GetAllCharacters(ctx context.Context, tx database.
 		return nil, errors.Wrap(err, "could not GetAllCharacters")
 	}
 
-	charDBData := make([]*CharacterDBData, 0, len(chars))
+	charIDs := make([]int64, 0, len(chars))
 	for _, c := range chars {
-		skills, err := r.GetAllCharacterSkills(ctx, c.Character.ID, tx)
-		if err != nil && !errors.Is(err, database.ErrNoRows) {
-			return nil, errors.Wrap(err, "could not GetAllCharacterSkills")
+		charIDs = append(charIDs, c.Character.ID)
+	}
+
+	skills := make(map[int64][]CharacterSkill, len(chars))
+	err = inBatches(charIDs, func(ids []int64) error {
+		batch, err := r.queries.GetCharacterSkillsForCharacters(ctx, r.db(tx), ids)
+		for _, sk := range batch {
+			skills[sk.CharacterID] = append(skills[sk.CharacterID], sk)
 		}
+		return err
+	})
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return nil, errors.Wrap(err, "could not GetCharacterSkillsForCharacters")
+	}
 
+	charDBData := make([]*CharacterDBData, 0, len(chars))
+	for _, c := range chars {
 		charDBData = append(charDBData, &CharacterDBData{
 			Character:   c.Character,
 			Corporation: c.Corporation,
 			Alliance:    c.Alliance,
-			Skills:      skills,
+			Skills:      skills[c.Character.ID],
 		})
 	}
 
 	return charDBData, nil
 }
 
+func (r *AppSqliteRepository) GetCharacter(ctx context.Context, charID int64, tx database.Tx) (_ *CharacterDBData, err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "GetCharacter")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling GetCharacter", keys.CharacterID, charID)
+
+	c, err := r.queries.GetCharacter(ctx, r.db(tx), charID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not GetCharacter")
+	}
+
+	skills, err := r.GetAllCharacterSkills(ctx, charID, tx)
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return nil, errors.Wrap(err, "could not GetAllCharacterSkills")
+	}
+
+	return &CharacterDBData{
+		Character:   c.Character,
+		Corporation: c.Corporation,
+		Alliance:    c.Alliance,
+		Skills:      skills,
+	}, nil
+}
+
+func (r *AppSqliteRepository) UpdateCharacterSkillQueue(ctx context.Context, charID, length int64, tx database.Tx) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "UpdateCharacterSkillQueue")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling UpdateCharacterSkillQueue", keys.CharacterID, charID, keys.SkillQueueLength, length)
+
+	inner := func(ctx context.Context, tx database.Tx) error {
+		err = r.queries.UpdateCharacterSkillQueue(ctx, tx, appdb.UpdateCharacterSkillQueueParams{
+			ID:               charID,
+			SkillQueueLength: sql.NullInt64{Int64: length, Valid: true},
+		})
+		if err != nil {
+			return errors.Wrap(err, "could not UpdateCharacterSkillQueue")
+		}
+		r.publish(tx, ChangeEvent{Entity: EntityCharacter, Change: ChangeUpdated, ID: charID})
+		return nil
+	}
+
+	if tx == nil {
+		err = errors.Wrap(database.TransactWithRetries(ctx, r.deps.Telemetry(), r.deps.Logger(), r.deps.DB(), &sql.TxOptions{}, inner), "could not TransactWithRetries")
+	} else {
+		err = inner(ctx, tx)
+	}
+	return err
+}
+
 func (r *AppSqliteRepository) GetTokenForCharacter(ctx context.Context, charID int64, tx database.Tx) (_ Token, err error) {
 	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "GetTokenForCharacter")
 	defer telemetry.EndSpan(span, &err)
@@ -281,6 +431,13 @@ func (r *AppSqliteRepository) GetTokenForCharacter(ctx context.Context, charID i
 		return tok, err
 	}
 
+	if tok.AccessToken, err = r.deps.Vault().Open(charID, "access_token", tok.AccessToken); err != nil {
+		return tok, errors.Wrap(err, "could not open access token")
+	}
+	if tok.RefreshToken, err = r.deps.Vault().Open(charID, "refresh_token", tok.RefreshToken); err != nil {
+		return tok, errors.Wrap(err, "could not open refresh token")
+	}
+
 	return tok, nil
 }
 
@@ -312,7 +469,11 @@ func (r *AppSqliteRepository) UpsertCharacterSkill(ctx context.Context, charID,
 			SkillID:     skillID,
 			SkillLevel:  skillLevel,
 		})
-		return errors.Wrap(err, "could not UpsertCharacterSkill")
+		if err != nil {
+			return errors.Wrap(err, "could not UpsertCharacterSkill")
+		}
+		r.publish(tx, ChangeEvent{Entity: EntityCharacter, Change: ChangeUpdated, ID: charID})
+		return nil
 	}
 
 	if tx == nil {
@@ -335,7 +496,11 @@ func (r *AppSqliteRepository) DeleteCharacterSkills(ctx context.Context, charID
 			CharacterID: charID,
 			SkillIds:    skillIDs,
 		})
-		return errors.Wrap(err, "could not UpsertCharacterSkill")
+		if err != nil {
+			return errors.Wrap(err, "could not UpsertCharacterSkill")
+		}
+		r.publish(tx, ChangeEvent{Entity: EntityCharacter, Change: ChangeUpdated, ID: charID})
+		return nil
 	}
 
 	if tx == nil {
@@ -346,6 +511,67 @@ func (r *AppSqliteRepository) DeleteCharacterSkills(ctx context.Context, charID
 	return err
 }
 
+// ReplaceCharacterSkills makes levels, keyed by skill id, the character's
+// whole skill list: they are upserted a chunk of rows per statement and any
+// other skills are deleted. The result is ordered by skill id.
+func (r *AppSqliteRepository) ReplaceCharacterSkills(ctx context.Context, charID int64, levels map[int64]int64, tx database.Tx) (skills []CharacterSkill, err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "ReplaceCharacterSkills")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling ReplaceCharacterSkills", keys.CharacterID, charID)
+
+	skillIDs := make([]int64, 0, len(levels))
+	for id := range levels {
+		skillIDs = append(skillIDs, id)
+	}
+	slices.Sort(skillIDs)
+
+	params := make([]appdb.UpsertCharacterSkillParams, 0, len(skillIDs))
+	for _, id := range skillIDs {
+		params = append(params, appdb.UpsertCharacterSkillParams{
+			CharacterID: charID,
+			SkillID:     id,
+			SkillLevel:  levels[id],
+		})
+	}
+
+	inner := func(ctx context.Context, tx database.Tx) error {
+		skills = nil
+		for rest := params; len(rest) > 0; {
+			n := min(appdb.BulkRows, len(rest))
+			written, err := r.queries.UpsertCharacterSkills(ctx, tx, rest[:n])
+			if err != nil {
+				return errors.Wrap(err, "could not UpsertCharacterSkills")
+			}
+			skills = append(skills, written...)
+			rest = rest[n:]
+		}
+
+		err := r.queries.DeleteCharacterSkillsExcept(ctx, tx, appdb.DeleteCharacterSkillsExceptParams{
+			CharacterID: charID,
+			Keep:        skillIDs,
+		})
+		if err != nil {
+			return errors.Wrap(err, "could not DeleteCharacterSkillsExcept")
+		}
+		r.publish(tx, ChangeEvent{Entity: EntityCharacter, Change: ChangeUpdated, ID: charID})
+		return nil
+	}
+
+	if tx == nil {
+		err = errors.Wrap(database.TransactWithRetries(ctx, r.deps.Telemetry(), r.deps.Logger(), r.deps.DB(), &sql.TxOptions{}, inner), "could not TransactWithRetries")
+	} else {
+		err = inner(ctx, tx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(skills, func(a, b CharacterSkill) int { return cmp.Compare(a.SkillID, b.SkillID) })
+	return skills, nil
+}
+
 func (r *AppSqliteRepository) DeleteCharacter(ctx context.Context, charID int64, tx database.Tx) (err error) {
 	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "DeleteCharacter")
 	defer telemetry.EndSpan(span, &err)
@@ -355,7 +581,11 @@ func (r *AppSqliteRepository) DeleteCharacter(ctx context.Context, charID int64,
 
 	inner := func(ctx context.Context, tx database.Tx) error {
 		err = r.queries.DeleteCharacter(ctx, tx, charID)
-		return errors.Wrap(err, "could not DeleteCharacter")
+		if err != nil {
+			return errors.Wrap(err, "could not DeleteCharacter")
+		}
+		r.publish(tx, ChangeEvent{Entity: EntityCharacter, Change: ChangeDeleted, ID: charID})
+		return nil
 	}
 
 	if tx == nil {
@@ -383,7 +613,11 @@ func (r *AppSqliteRepository) InsertTag(ctx context.Context, name string, c colo
 			ColorB: int64(cb),
 			ColorA: int64(ca),
 		})
-		return errors.Wrap(err, "could not InsertTag")
+		if err != nil {
+			return errors.Wrap(err, "could not InsertTag")
+		}
+		r.publish(tx, ChangeEvent{Entity: EntityTag, Change: ChangeCreated, ID: tag.ID})
+		return nil
 	}
 
 	if tx == nil {
@@ -412,7 +646,11 @@ func (r *AppSqliteRepository) UpdateTag(ctx context.Context, tagID int64, name s
 			ColorB: int64(cb),
 			ColorA: int64(ca),
 		})
-		return errors.Wrap(err, "could not UpdateTag")
+		if err != nil {
+			return errors.Wrap(err, "could not UpdateTag")
+		}
+		r.publish(tx, ChangeEvent{Entity: EntityTag, Change: ChangeUpdated, ID: tagID})
+		return nil
 	}
 
 	if tx == nil {
@@ -432,7 +670,11 @@ func (r *AppSqliteRepository) DeleteTag(ctx context.Context, tagID int64, tx dat
 
 	inner := func(ctx context.Context, tx database.Tx) error {
 		err = r.queries.DeleteTag(ctx, tx, tagID)
-		return errors.Wrap(err, "could not DeleteTag")
+		if err != nil {
+			return errors.Wrap(err, "could not DeleteTag")
+		}
+		r.publish(tx, ChangeEvent{Entity: EntityTag, Change: ChangeDeleted, ID: tagID})
+		return nil
 	}
 
 	if tx == nil {
@@ -455,19 +697,48 @@ func (r *AppSqliteRepository) GetAllTags(ctx context.Context, tx database.Tx) (_
 		return nil, errors.Wrap(err, "could not GetAllTags")
 	}
 
-	tagDBData := make([]*TagDBData, 0, len(tags))
+	includes, err := r.queries.GetAllTagIncludes(ctx, r.db(tx))
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return nil, errors.Wrap(err, "could not GetAllTagIncludes")
+	}
+
+	tagsByID := make(map[int64]Tag, len(tags))
 	for _, t := range tags {
-		skills, err := r.GetAllTagSkills(ctx, t.ID, tx)
-		if err != nil && !errors.Is(err, database.ErrNoRows) {
-			return nil, errors.Wrap(err, "could not GetAllTagSkills")
+		tagsByID[t.ID] = t
+	}
+	includedTags := make(map[int64][]Tag, len(includes))
+	for _, ti := range includes {
+		includedTags[ti.TagID] = append(includedTags[ti.TagID], tagsByID[ti.IncludedTagID])
+	}
+
+	tagIDs := make([]int64, 0, len(tags))
+	for _, t := range tags {
+		tagIDs = append(tagIDs, t.ID)
+	}
+
+	skills := make(map[int64][]TagSkill, len(tags))
+	err = inBatches(tagIDs, func(ids []int64) error {
+		batch, err := r.queries.GetTagSkillsForTags(ctx, r.db(tx), ids)
+		for _, sk := range batch {
+			skills[sk.TagID] = append(skills[sk.TagID], sk)
 		}
+		return err
+	})
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return nil, errors.Wrap(err, "could not GetTagSkillsForTags")
+	}
 
+	tagDBData := make([]*TagDBData, 0, len(tags))
+	for _, t := range tags {
 		tagDBData = append(tagDBData, &TagDBData{
-			Tag:    t,
-			Skills: skills,
+			Tag:      t,
+			Skills:   skills[t.ID],
+			Includes: includedTags[t.ID],
 		})
 	}
 
+	ResolveTagIncludes(tagDBData)
+
 	return tagDBData, nil
 }
 
@@ -486,7 +757,7 @@ func (r *AppSqliteRepository) GetAllTagSkills(ctx context.Context, tagID int64,
 	return skills, nil
 }
 
-func (r *AppSqliteRepository) UpsertTagSkill(ctx context.Context, tagID, skillID, skillLevel int64, tx database.Tx) (skill TagSkill, err error) {
+func (r *AppSqliteRepository) UpsertTagSkill(ctx context.Context, tagID, skillID, skillLevel, recommendedLevel int64, tx database.Tx) (skill TagSkill, err error) {
 	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "UpsertTagSkill")
 	defer telemetry.EndSpan(span, &err)
 
@@ -495,11 +766,16 @@ func (r *AppSqliteRepository) UpsertTagSkill(ctx context.Context, tagID, skillID
 
 	inner := func(ctx context.Context, tx database.Tx) error {
 		skill, err = r.queries.UpsertTagSkill(ctx, tx, appdb.UpsertTagSkillParams{
-			TagID:      tagID,
-			SkillID:    skillID,
-			SkillLevel: skillLevel,
+			TagID:            tagID,
+			SkillID:          skillID,
+			SkillLevel:       skillLevel,
+			RecommendedLevel: recommendedLevel,
 		})
-		return errors.Wrap(err, "could not UpsertCharacterSkill")
+		if err != nil {
+			return errors.Wrap(err, "could not UpsertCharacterSkill")
+		}
+		r.publish(tx, ChangeEvent{Entity: EntityTag, Change: ChangeUpdated, ID: tagID})
+		return nil
 	}
 
 	if tx == nil {
@@ -522,7 +798,74 @@ func (r *AppSqliteRepository) DeleteTagSkills(ctx context.Context, tagID int64,
 			TagID:    tagID,
 			SkillIds: skillIDs,
 		})
-		return errors.Wrap(err, "could not DeleteTagSkills")
+		if err != nil {
+			return errors.Wrap(err, "could not DeleteTagSkills")
+		}
+		r.publish(tx, ChangeEvent{Entity: EntityTag, Change: ChangeUpdated, ID: tagID})
+		return nil
+	}
+
+	if tx == nil {
+		err = errors.Wrap(database.TransactWithRetries(ctx, r.deps.Telemetry(), r.deps.Logger(), r.deps.DB(), &sql.TxOptions{}, inner), "could not TransactWithRetries")
+	} else {
+		err = inner(ctx, tx)
+	}
+	return err
+}
+
+func (r *AppSqliteRepository) UpsertTagInclude(ctx context.Context, tagID, includedTagID int64, tx database.Tx) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "UpsertTagInclude")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling UpsertTagInclude", keys.TagID, tagID, keys.IncludedTagID, includedTagID)
+
+	inner := func(ctx context.Context, tx database.Tx) error {
+		includes, err := r.queries.GetAllTagIncludes(ctx, tx)
+		if err != nil && !errors.Is(err, database.ErrNoRows) {
+			return errors.Wrap(err, "could not GetAllTagIncludes")
+		}
+
+		if tagIncludeCreatesCycle(includes, tagID, includedTagID) {
+			return database.NonRetryableError(errors.Wrap(ErrTagCycle, "could not UpsertTagInclude", keys.TagID, tagID, keys.IncludedTagID, includedTagID))
+		}
+
+		err = r.queries.UpsertTagInclude(ctx, tx, appdb.UpsertTagIncludeParams{
+			TagID:         tagID,
+			IncludedTagID: includedTagID,
+		})
+		if err != nil {
+			return errors.Wrap(err, "could not UpsertTagInclude")
+		}
+		r.publish(tx, ChangeEvent{Entity: EntityTag, Change: ChangeUpdated, ID: tagID})
+		return nil
+	}
+
+	if tx == nil {
+		err = errors.Wrap(database.TransactWithRetries(ctx, r.deps.Telemetry(), r.deps.Logger(), r.deps.DB(), &sql.TxOptions{}, inner), "could not TransactWithRetries")
+	} else {
+		err = inner(ctx, tx)
+	}
+	return err
+}
+
+func (r *AppSqliteRepository) DeleteTagIncludes(ctx context.Context, tagID int64, includedTagIDs []int64, tx database.Tx) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "DeleteTagIncludes")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling DeleteTagIncludes", keys.TagID, tagID, keys.IncludedTagID, includedTagIDs)
+
+	inner := func(ctx context.Context, tx database.Tx) error {
+		err = r.queries.DeleteTagIncludes(ctx, tx, appdb.DeleteTagIncludesParams{
+			TagID:          tagID,
+			IncludedTagIds: includedTagIDs,
+		})
+		if err != nil {
+			return errors.Wrap(err, "could not DeleteTagIncludes")
+		}
+		r.publish(tx, ChangeEvent{Entity: EntityTag, Change: ChangeUpdated, ID: tagID})
+		return nil
 	}
 
 	if tx == nil {
@@ -533,26 +876,29 @@ func (r *AppSqliteRepository) DeleteTagSkills(ctx context.Context, tagID int64,
 	return err
 }
 
-func (r *AppSqliteRepository) InsertRole(ctx context.Context, name, label string, operator operators.Operator, c color.Color, tx database.Tx) (role Role, err error) {
+func (r *AppSqliteRepository) InsertRole(ctx context.Context, name, label string, c color.Color, tx database.Tx) (role Role, err error) {
 	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "InsertRole")
 	defer telemetry.EndSpan(span, &err)
 
 	logger := r.deps.Logger()
-	level.Debug(logger).Message("calling InsertRole", keys.RoleName, name, keys.RoleLabel, label, keys.RoleOperator, operator, keys.Color, c)
+	level.Debug(logger).Message("calling InsertRole", keys.RoleName, name, keys.RoleLabel, label, keys.Color, c)
 
 	cr, cg, cb, ca := c.RGBA()
 
 	inner := func(ctx context.Context, tx database.Tx) error {
 		role, err = r.queries.InsertRole(ctx, tx, appdb.InsertRoleParams{
-			Name:     name,
-			Label:    label,
-			Operator: operator.String(),
-			ColorR:   int64(cr),
-			ColorG:   int64(cg),
-			ColorB:   int64(cb),
-			ColorA:   int64(ca),
+			Name:   name,
+			Label:  label,
+			ColorR: int64(cr),
+			ColorG: int64(cg),
+			ColorB: int64(cb),
+			ColorA: int64(ca),
 		})
-		return errors.Wrap(err, "could not InsertRole")
+		if err != nil {
+			return errors.Wrap(err, "could not InsertRole")
+		}
+		r.publish(tx, ChangeEvent{Entity: EntityRole, Change: ChangeCreated, ID: role.ID})
+		return nil
 	}
 
 	if tx == nil {
@@ -563,27 +909,30 @@ func (r *AppSqliteRepository) InsertRole(ctx context.Context, name, label string
 	return role, err
 }
 
-func (r *AppSqliteRepository) UpdateRole(ctx context.Context, roleID int64, name, label string, operator operators.Operator, c color.Color, tx database.Tx) (err error) {
+func (r *AppSqliteRepository) UpdateRole(ctx context.Context, roleID int64, name, label string, c color.Color, tx database.Tx) (err error) {
 	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "UpdateRole")
 	defer telemetry.EndSpan(span, &err)
 
 	logger := r.deps.Logger()
-	level.Debug(logger).Message("calling UpdateRole", keys.RoleID, roleID, keys.RoleName, name, keys.RoleLabel, label, keys.RoleOperator, operator, keys.Color, c)
+	level.Debug(logger).Message("calling UpdateRole", keys.RoleID, roleID, keys.RoleName, name, keys.RoleLabel, label, keys.Color, c)
 
 	cr, cg, cb, ca := c.RGBA()
 
 	inner := func(ctx context.Context, tx database.Tx) error {
 		err = r.queries.UpdateRole(ctx, tx, appdb.UpdateRoleParams{
-			ID:       roleID,
-			Name:     name,
-			Label:    label,
-			Operator: operator.String(),
-			ColorR:   int64(cr),
-			ColorG:   int64(cg),
-			ColorB:   int64(cb),
-			ColorA:   int64(ca),
+			ID:     roleID,
+			Name:   name,
+			Label:  label,
+			ColorR: int64(cr),
+			ColorG: int64(cg),
+			ColorB: int64(cb),
+			ColorA: int64(ca),
 		})
-		return errors.Wrap(err, "could not UpdateRole")
+		if err != nil {
+			return errors.Wrap(err, "could not UpdateRole")
+		}
+		r.publish(tx, ChangeEvent{Entity: EntityRole, Change: ChangeUpdated, ID: roleID})
+		return nil
 	}
 
 	if tx == nil {
@@ -603,7 +952,11 @@ func (r *AppSqliteRepository) DeleteRole(ctx context.Context, roleID int64, tx d
 
 	inner := func(ctx context.Context, tx database.Tx) error {
 		err = r.queries.DeleteRole(ctx, tx, roleID)
-		return errors.Wrap(err, "could not DeleteRole")
+		if err != nil {
+			return errors.Wrap(err, "could not DeleteRole")
+		}
+		r.publish(tx, ChangeEvent{Entity: EntityRole, Change: ChangeDeleted, ID: roleID})
+		return nil
 	}
 
 	if tx == nil {
@@ -626,17 +979,54 @@ func (r *AppSqliteRepository) GetAllRoles(ctx context.Context, tx database.Tx) (
 		return nil, errors.Wrap(err, "could not GetAllRoles")
 	}
 
-	roleDBData := make([]*RoleDBData, 0, len(roles))
+	roleIDs := make([]int64, 0, len(roles))
 	for _, ro := range roles {
-		tags, err := r.GetAllRoleTags(ctx, ro.ID, tx)
-		if err != nil && !errors.Is(err, database.ErrNoRows) {
-			return nil, errors.Wrap(err, "could not GetAllRoleTags")
+		roleIDs = append(roleIDs, ro.ID)
+	}
+
+	tags := make(map[int64][]Tag, len(roles))
+	err = inBatches(roleIDs, func(ids []int64) error {
+		batch, err := r.queries.GetRoleTagsForRoles(ctx, r.db(tx), ids)
+		for _, rt := range batch {
+			tags[rt.RoleID] = append(tags[rt.RoleID], rt.Tag)
+		}
+		return err
+	})
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return nil, errors.Wrap(err, "could not GetRoleTagsForRoles")
+	}
+
+	nodes := make(map[int64][]RoleNode, len(roles))
+	err = inBatches(roleIDs, func(ids []int64) error {
+		batch, err := r.queries.GetRoleNodesForRoles(ctx, r.db(tx), ids)
+		for _, n := range batch {
+			nodes[n.RoleID] = append(nodes[n.RoleID], n)
 		}
+		return err
+	})
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return nil, errors.Wrap(err, "could not GetRoleNodesForRoles")
+	}
+
+	conds := make(map[int64][]RoleCondition, len(roles))
+	err = inBatches(roleIDs, func(ids []int64) error {
+		batch, err := r.queries.GetRoleConditionsForRoles(ctx, r.db(tx), ids)
+		for _, c := range batch {
+			conds[c.RoleID] = append(conds[c.RoleID], c)
+		}
+		return err
+	})
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return nil, errors.Wrap(err, "could not GetRoleConditionsForRoles")
+	}
 
+	roleDBData := make([]*RoleDBData, 0, len(roles))
+	for _, ro := range roles {
 		roleDBData = append(roleDBData, &RoleDBData{
-			Role:     ro,
-			Operator: operators.Operator(ro.Operator),
-			Tags:     tags,
+			Role:       ro,
+			Expr:       buildRoleExpr(nodes[ro.ID]),
+			Tags:       tags[ro.ID],
+			Conditions: conds[ro.ID],
 		})
 	}
 
@@ -658,19 +1048,152 @@ func (r *AppSqliteRepository) GetAllRoleTags(ctx context.Context, roleID int64,
 	return tags, nil
 }
 
-func (r *AppSqliteRepository) UpsertRoleTag(ctx context.Context, roleID, tagID int64, tx database.Tx) (rt RoleTag, err error) {
-	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "UpsertRoleTag")
+func (r *AppSqliteRepository) GetRoleExpr(ctx context.Context, roleID int64, tx database.Tx) (_ *RoleExpr, err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "GetRoleExpr")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling GetRoleExpr", keys.RoleID, roleID)
+
+	nodes, err := r.queries.GetRoleNodes(ctx, r.db(tx), roleID)
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return nil, errors.Wrap(err, "could not GetRoleNodes")
+	}
+
+	return buildRoleExpr(nodes), nil
+}
+
+// SetRoleExpr replaces the role's whole expression tree
+func (r *AppSqliteRepository) SetRoleExpr(ctx context.Context, roleID int64, expr *RoleExpr, tx database.Tx) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "SetRoleExpr")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling SetRoleExpr", keys.RoleID, roleID)
+
+	if err := expr.Validate(); err != nil {
+		return database.NonRetryableError(err)
+	}
+
+	inner := func(ctx context.Context, tx database.Tx) error {
+		if err := r.queries.DeleteRoleNodes(ctx, tx, roleID); err != nil {
+			return errors.Wrap(err, "could not DeleteRoleNodes")
+		}
+
+		var insert func(e *RoleExpr, parentID sql.NullInt64, pos int) error
+		insert = func(e *RoleExpr, parentID sql.NullInt64, pos int) error {
+			params := appdb.InsertRoleNodeParams{
+				RoleID:   roleID,
+				ParentID: parentID,
+				Position: int64(pos),
+			}
+			if e.IsLeaf() {
+				params.TagID = sql.NullInt64{Int64: e.TagID, Valid: true}
+			} else {
+				params.Operator = sql.NullString{String: e.Operator.String(), Valid: true}
+				params.MinCount = e.MinCount
+			}
+
+			node, err := r.queries.InsertRoleNode(ctx, tx, params)
+			if err != nil {
+				return errors.Wrap(err, "could not InsertRoleNode", keys.TagID, e.TagID)
+			}
+
+			for i, ch := range e.Children {
+				if err := insert(ch, sql.NullInt64{Int64: node.ID, Valid: true}, i); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		if err := insert(expr, sql.NullInt64{}, 0); err != nil {
+			return err
+		}
+		r.publish(tx, ChangeEvent{Entity: EntityRole, Change: ChangeUpdated, ID: roleID})
+		return nil
+	}
+
+	if tx == nil {
+		err = errors.Wrap(database.TransactWithRetries(ctx, r.deps.Telemetry(), r.deps.Logger(), r.deps.DB(), &sql.TxOptions{}, inner), "could not TransactWithRetries")
+	} else {
+		err = inner(ctx, tx)
+	}
+	return err
+}
+
+func (r *AppSqliteRepository) GetRoleConditions(ctx context.Context, roleID int64, tx database.Tx) (_ []RoleCondition, err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "GetRoleConditions")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling GetRoleConditions", keys.RoleID, roleID)
+
+	conds, err := r.queries.GetRoleConditions(ctx, r.db(tx), roleID)
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return nil, errors.Wrap(err, "could not GetRoleConditions")
+	}
+
+	return conds, nil
+}
+
+// SetRoleConditions replaces all of the role's conditions. RoleID and ID on
+// the given conditions are ignored.
+func (r *AppSqliteRepository) SetRoleConditions(ctx context.Context, roleID int64, conds []RoleCondition, tx database.Tx) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "SetRoleConditions")
 	defer telemetry.EndSpan(span, &err)
 
 	logger := r.deps.Logger()
-	level.Debug(logger).Message("calling UpsertRoleTag", keys.RoleID, roleID, keys.TagID, tagID)
+	level.Debug(logger).Message("calling SetRoleConditions", keys.RoleID, roleID)
+
+	for _, c := range conds {
+		if _, err := conditions.ParseKind(c.Kind); err != nil {
+			return database.NonRetryableError(errors.Wrap(err, "invalid role condition", keys.ConditionKind, c.Kind))
+		}
+	}
 
 	inner := func(ctx context.Context, tx database.Tx) error {
-		rt, err = r.queries.UpsertRoleTag(ctx, tx, appdb.UpsertRoleTagParams{
-			TagID:  tagID,
-			RoleID: roleID,
+		if err := r.queries.DeleteRoleConditions(ctx, tx, roleID); err != nil {
+			return errors.Wrap(err, "could not DeleteRoleConditions")
+		}
+
+		for _, c := range conds {
+			if _, err := r.queries.InsertRoleCondition(ctx, tx, appdb.InsertRoleConditionParams{
+				RoleID: roleID,
+				Kind:   c.Kind,
+				Value:  c.Value,
+				Label:  c.Label,
+			}); err != nil {
+				return errors.Wrap(err, "could not InsertRoleCondition", keys.ConditionKind, c.Kind)
+			}
+		}
+
+		r.publish(tx, ChangeEvent{Entity: EntityRole, Change: ChangeUpdated, ID: roleID})
+		return nil
+	}
+
+	if tx == nil {
+		err = errors.Wrap(database.TransactWithRetries(ctx, r.deps.Telemetry(), r.deps.Logger(), r.deps.DB(), &sql.TxOptions{}, inner), "could not TransactWithRetries")
+	} else {
+		err = inner(ctx, tx)
+	}
+	return err
+}
+
+func (r *AppSqliteRepository) InsertNotification(ctx context.Context, url, payload string, nextAttempt time.Time, tx database.Tx) (n Notification, err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "InsertNotification")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling InsertNotification")
+
+	inner := func(ctx context.Context, tx database.Tx) error {
+		n, err = r.queries.InsertNotification(ctx, tx, appdb.InsertNotificationParams{
+			Url:         url,
+			Payload:     payload,
+			NextAttempt: nextAttempt,
 		})
-		return errors.Wrap(err, "could not UpsertRoleTag")
+		return errors.Wrap(err, "could not InsertNotification")
 	}
 
 	if tx == nil {
@@ -678,22 +1201,62 @@ func (r *AppSqliteRepository) UpsertRoleTag(ctx context.Context, roleID, tagID i
 	} else {
 		err = inner(ctx, tx)
 	}
-	return rt, err
+	return n, err
+}
+
+func (r *AppSqliteRepository) GetDueNotifications(ctx context.Context, now time.Time, limit int64, tx database.Tx) (_ []Notification, err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "GetDueNotifications")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling GetDueNotifications")
+
+	ns, err := r.queries.GetDueNotifications(ctx, r.db(tx), appdb.GetDueNotificationsParams{
+		NextAttempt: now,
+		Limit:       limit,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not GetDueNotifications")
+	}
+
+	return ns, nil
 }
 
-func (r *AppSqliteRepository) DeleteRoleTags(ctx context.Context, roleID int64, tagIDs []int64, tx database.Tx) (err error) {
-	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "DeleteRoleTags")
+func (r *AppSqliteRepository) RescheduleNotification(ctx context.Context, id, attempts int64, nextAttempt time.Time, lastError string, tx database.Tx) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "RescheduleNotification")
 	defer telemetry.EndSpan(span, &err)
 
 	logger := r.deps.Logger()
-	level.Debug(logger).Message("calling DeleteRoleTags", keys.RoleID, roleID, keys.TagID, tagIDs)
+	level.Debug(logger).Message("calling RescheduleNotification", keys.NotificationID, id)
 
 	inner := func(ctx context.Context, tx database.Tx) error {
-		err = r.queries.DeleteRoleTags(ctx, tx, appdb.DeleteRoleTagsParams{
-			RoleID: roleID,
-			TagIds: tagIDs,
+		err = r.queries.RescheduleNotification(ctx, tx, appdb.RescheduleNotificationParams{
+			ID:          id,
+			Attempts:    attempts,
+			NextAttempt: nextAttempt,
+			LastError:   lastError,
 		})
-		return errors.Wrap(err, "could not DeleteRoleTags")
+		return errors.Wrap(err, "could not RescheduleNotification")
+	}
+
+	if tx == nil {
+		err = errors.Wrap(database.TransactWithRetries(ctx, r.deps.Telemetry(), r.deps.Logger(), r.deps.DB(), &sql.TxOptions{}, inner), "could not TransactWithRetries")
+	} else {
+		err = inner(ctx, tx)
+	}
+	return err
+}
+
+func (r *AppSqliteRepository) DeleteNotification(ctx context.Context, id int64, tx database.Tx) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "DeleteNotification")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling DeleteNotification", keys.NotificationID, id)
+
+	inner := func(ctx context.Context, tx database.Tx) error {
+		err = r.queries.DeleteNotification(ctx, tx, id)
+		return errors.Wrap(err, "could not DeleteNotification")
 	}
 
 	if tx == nil {
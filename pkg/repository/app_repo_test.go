@@ -0,0 +1,363 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	libtelemetry "github.com/kava-forge/eve-alts/lib/telemetry"
+
+	"github.com/kava-forge/eve-alts/migrations"
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/telemetry"
+	"github.com/kava-forge/eve-alts/pkg/testhelpers"
+)
+
+// repoDependencies swaps in a real database, and a telemeter that drops
+// spans so they are not written out while benchmarking
+type repoDependencies struct {
+	*testhelpers.TestDependencies
+	db        database.Connection
+	telemeter *libtelemetry.Telemeter
+}
+
+func (d repoDependencies) DB() database.Connection            { return d.db }
+func (d repoDependencies) Telemetry() *libtelemetry.Telemeter { return d.telemeter }
+
+type seed struct {
+	characters      int
+	characterSkills int
+	tags            int
+	tagSkills       int
+	roles           int
+	roleTags        int
+}
+
+// roster is about the size of a large alt roster
+var roster = seed{characters: 300, characterSkills: 250, tags: 60, tagSkills: 15, roles: 25, roleTags: 4}
+
+// newSeededRepo migrates a new database and fills it with s. Every third
+// character, tag and role is left without children.
+func newSeededRepo(tb testing.TB, s seed) *repository.AppSqliteRepository {
+	tb.Helper()
+	return repository.NewAppData(newSeededDeps(tb, s))
+}
+
+func newSeededDeps(tb testing.TB, s seed) repoDependencies {
+	tb.Helper()
+
+	ctx := context.Background()
+
+	db, err := database.Open(ctx, filepath.Join(tb.TempDir(), "database.db"), database.DefaultPragmas())
+	require.NoError(tb, err)
+	tb.Cleanup(func() { _ = db.Close(ctx) })
+
+	require.NoError(tb, db.Migrate(ctx, migrations.Migrations))
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(tb, err)
+
+	exec := func(query string, args ...interface{}) {
+		_, err := tx.ExecContext(ctx, query, args...)
+		require.NoError(tb, err)
+	}
+
+	exec(`INSERT INTO corporations ("id", "name", "ticker", "picture") VALUES (1, 'Corp', 'CRP', '')`)
+	for c := 1; c <= s.characters; c++ {
+		exec(`INSERT INTO characters ("id", "name", "picture", "corporation_id") VALUES (?, ?, '', 1)`, c, fmt.Sprintf("Character %03d", c))
+		if c%3 == 0 {
+			continue
+		}
+		for sk := range s.characterSkills {
+			exec(`INSERT INTO character_skills ("character_id", "skill_id", "skill_level") VALUES (?, ?, ?)`, c, 3000+sk, sk%5+1)
+		}
+	}
+
+	for t := 1; t <= s.tags; t++ {
+		exec(`INSERT INTO tags ("id", "name", "color_r", "color_g", "color_b", "color_a") VALUES (?, ?, 0, 0, 0, 65535)`, t, fmt.Sprintf("Tag %03d", t))
+		if t%3 == 0 {
+			continue
+		}
+		for sk := range s.tagSkills {
+			exec(`INSERT INTO tag_skills ("tag_id", "skill_id", "skill_level") VALUES (?, ?, ?)`, t, 3000+t+sk, sk%5+1)
+		}
+	}
+
+	nodeID := 0
+	for r := 1; r <= s.roles; r++ {
+		exec(`INSERT INTO roles ("id", "name", "label", "color_r", "color_g", "color_b", "color_a") VALUES (?, ?, '', 0, 0, 0, 65535)`, r, fmt.Sprintf("Role %03d", r))
+		if r%3 == 0 {
+			continue
+		}
+
+		nodeID++
+		root := nodeID
+		exec(`INSERT INTO role_nodes ("id", "role_id", "operator") VALUES (?, ?, 'all')`, root, r)
+		for pos := range s.roleTags {
+			nodeID++
+			exec(`INSERT INTO role_nodes ("id", "role_id", "parent_id", "position", "tag_id") VALUES (?, ?, ?, ?, ?)`, nodeID, r, root, pos, (r+pos)%s.tags+1)
+		}
+		exec(`INSERT INTO role_conditions ("role_id", "kind", "value") VALUES (?, 'min_sp', ?)`, r, r*1_000_000)
+	}
+
+	require.NoError(tb, tx.Commit())
+
+	deps := repoDependencies{TestDependencies: testhelpers.NewTestDependencies(tb), db: db}
+	deps.telemeter, _, err = telemetry.NewTelemeter(ctx, deps, testhelpers.AppName, testhelpers.BuildVersion, testhelpers.Host, telemetry.Options{
+		PrometheusNamespace: "test",
+		TraceExporter:       telemetry.ExporterNone,
+	})
+	require.NoError(tb, err)
+
+	return deps
+}
+
+func TestAppSqliteRepository_GetAll(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		seed seed
+	}{
+		{"small", seed{characters: 4, characterSkills: 3, tags: 5, tagSkills: 2, roles: 4, roleTags: 2}},
+		{"more than one batch", seed{characters: 1100, characterSkills: 2, tags: 600, tagSkills: 1, roles: 550, roleTags: 1}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			repo := newSeededRepo(t, tt.seed)
+
+			chars, err := repo.GetAllCharacters(ctx, nil)
+			require.NoError(t, err)
+			require.Len(t, chars, tt.seed.characters)
+			for _, c := range chars {
+				want, err := repo.GetAllCharacterSkills(ctx, c.Character.ID, nil)
+				require.NoError(t, err)
+				assert.Equal(t, want, c.Skills, "character %d", c.Character.ID)
+				if c.Character.ID%3 == 0 {
+					assert.Empty(t, c.Skills)
+				} else {
+					assert.Len(t, c.Skills, tt.seed.characterSkills)
+				}
+			}
+
+			tags, err := repo.GetAllTags(ctx, nil)
+			require.NoError(t, err)
+			require.Len(t, tags, tt.seed.tags)
+			for _, tag := range tags {
+				want, err := repo.GetAllTagSkills(ctx, tag.Tag.ID, nil)
+				require.NoError(t, err)
+				assert.Equal(t, want, tag.Skills, "tag %d", tag.Tag.ID)
+			}
+
+			roles, err := repo.GetAllRoles(ctx, nil)
+			require.NoError(t, err)
+			require.Len(t, roles, tt.seed.roles)
+			for _, role := range roles {
+				wantTags, err := repo.GetAllRoleTags(ctx, role.Role.ID, nil)
+				require.NoError(t, err)
+				assert.Equal(t, wantTags, role.Tags, "role %d", role.Role.ID)
+
+				wantExpr, err := repo.GetRoleExpr(ctx, role.Role.ID, nil)
+				require.NoError(t, err)
+				assert.Equal(t, wantExpr, role.Expr, "role %d", role.Role.ID)
+
+				wantConds, err := repo.GetRoleConditions(ctx, role.Role.ID, nil)
+				require.NoError(t, err)
+				assert.Equal(t, wantConds, role.Conditions, "role %d", role.Role.ID)
+			}
+		})
+	}
+}
+
+func TestAppSqliteRepository_ReplaceCharacterSkills(t *testing.T) {
+	t.Parallel()
+
+	// character 1 starts with skills 3000 to 3249, each at level id%5+1
+	tests := []struct {
+		name   string
+		levels map[int64]int64
+	}{
+		{"changed and added", map[int64]int64{3000: 5, 3001: 2, 9999: 1}},
+		{"none left", map[int64]int64{}},
+		{"more than one chunk", func() map[int64]int64 {
+			levels := make(map[int64]int64, 700)
+			for id := range int64(700) {
+				levels[3000+id] = 5
+			}
+			return levels
+		}()},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			repo := newSeededRepo(t, seed{characters: 2, characterSkills: 250})
+
+			got, err := repo.ReplaceCharacterSkills(ctx, 1, tt.levels, nil)
+			require.NoError(t, err)
+
+			stored, err := repo.GetAllCharacterSkills(ctx, 1, nil)
+			require.NoError(t, err)
+			assert.Equal(t, stored, got)
+
+			require.Len(t, got, len(tt.levels))
+			for _, sk := range got {
+				assert.Equal(t, int64(1), sk.CharacterID)
+				assert.Equal(t, tt.levels[sk.SkillID], sk.SkillLevel, "skill %d", sk.SkillID)
+			}
+
+			other, err := repo.GetAllCharacterSkills(ctx, 2, nil)
+			require.NoError(t, err)
+			assert.Len(t, other, 250, "another character's skills changed")
+		})
+	}
+}
+
+func TestAppSqliteRepository_TagNamesUnique(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newSeededRepo(t, seed{tags: 2})
+
+	_, err := repo.InsertTag(ctx, "Tag 001", color.Black, nil)
+	assert.ErrorIs(t, err, database.ErrUniqueViolation)
+
+	err = repo.UpdateTag(ctx, 2, "Tag 001", color.Black, nil)
+	assert.ErrorIs(t, err, database.ErrUniqueViolation)
+
+	require.NoError(t, repo.UpdateTag(ctx, 1, "Tag 001", color.White, nil), "a tag keeps its own name")
+
+	tags, err := repo.GetAllTags(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+	assert.Equal(t, "Tag 002", tags[1].Tag.Name)
+}
+
+func BenchmarkGetAllCharacters(b *testing.B) {
+	ctx := context.Background()
+	repo := newSeededRepo(b, roster)
+
+	b.ResetTimer()
+	for range b.N {
+		if _, err := repo.GetAllCharacters(ctx, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkGetAllCharacters_PerCharacter loads skills one character at a
+// time, as GetAllCharacters used to, for comparison
+func BenchmarkGetAllCharacters_PerCharacter(b *testing.B) {
+	ctx := context.Background()
+	repo := newSeededRepo(b, roster)
+
+	chars, err := repo.GetAllCharacters(ctx, nil)
+	require.NoError(b, err)
+
+	b.ResetTimer()
+	for range b.N {
+		for _, c := range chars {
+			if _, err := repo.GetAllCharacterSkills(ctx, c.Character.ID, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkGetAllTags(b *testing.B) {
+	ctx := context.Background()
+	repo := newSeededRepo(b, roster)
+
+	b.ResetTimer()
+	for range b.N {
+		if _, err := repo.GetAllTags(ctx, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetAllRoles(b *testing.B) {
+	ctx := context.Background()
+	repo := newSeededRepo(b, roster)
+
+	b.ResetTimer()
+	for range b.N {
+		if _, err := repo.GetAllRoles(ctx, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// refreshedSkills is a character's skill list as a refresh sees it: most
+// skills kept at a new level, some dropped and some added
+func refreshedSkills(n int) map[int64]int64 {
+	levels := make(map[int64]int64, roster.characterSkills)
+	for sk := range int64(roster.characterSkills) {
+		if sk%10 == 0 {
+			continue
+		}
+		levels[3000+sk+int64(n%2)*100] = int64(n+1)%5 + 1
+	}
+	return levels
+}
+
+// BenchmarkReplaceCharacterSkills times the transaction writing a refreshed
+// character's skills, which is how long the refresh holds the write lock
+func BenchmarkReplaceCharacterSkills(b *testing.B) {
+	ctx := context.Background()
+	repo := newSeededRepo(b, roster)
+
+	b.ResetTimer()
+	for i := range b.N {
+		if _, err := repo.ReplaceCharacterSkills(ctx, 1, refreshedSkills(i), nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkReplaceCharacterSkills_PerSkill writes one skill per statement,
+// as refreshes used to, for comparison
+func BenchmarkReplaceCharacterSkills_PerSkill(b *testing.B) {
+	ctx := context.Background()
+	deps := newSeededDeps(b, roster)
+	repo := repository.NewAppData(deps)
+
+	b.ResetTimer()
+	for i := range b.N {
+		levels := refreshedSkills(i)
+
+		seen, err := repo.GetAllCharacterSkills(ctx, 1, nil)
+		require.NoError(b, err)
+		toDelete := make([]int64, 0, len(seen))
+		for _, sk := range seen {
+			if _, ok := levels[sk.SkillID]; !ok {
+				toDelete = append(toDelete, sk.SkillID)
+			}
+		}
+
+		err = database.TransactWithRetries(ctx, deps.Telemetry(), deps.Logger(), deps.DB(), &sql.TxOptions{}, func(ctx context.Context, tx database.Tx) error {
+			for id, lvl := range levels {
+				if _, err := repo.UpsertCharacterSkill(ctx, 1, id, lvl, tx); err != nil {
+					return err
+				}
+			}
+			return repo.DeleteCharacterSkills(ctx, 1, toDelete, tx)
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+	"github.com/kava-forge/eve-alts/lib/logging"
+	"github.com/kava-forge/eve-alts/lib/logging/level"
+
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/panics"
+)
+
+// Entity is the kind of row a ChangeEvent is about
+type Entity string
+
+const (
+	EntityCharacter Entity = "character"
+	EntityTag       Entity = "tag"
+	EntityRole      Entity = "role"
+)
+
+// Change is what happened to the row
+type Change string
+
+const (
+	ChangeCreated Change = "created"
+	ChangeUpdated Change = "updated"
+	ChangeDeleted Change = "deleted"
+)
+
+// watchMaxDelay is the most intervals WatchExternalChanges holds a reload back
+// while the database keeps changing
+const watchMaxDelay = 5
+
+// ChangeEvent says a row was written and committed. Changes to a row's
+// children, such as a tag's skills or a role's expression, are updates of the
+// row itself. ID 0 means any number of rows of Entity may have changed, and
+// all of them should be loaded again.
+type ChangeEvent struct {
+	Entity Entity
+	Change Change
+	ID     int64
+}
+
+// EventBus hands ChangeEvents to every subscriber. Each subscriber gets its
+// events in the order they were published, on a goroutine of its own, so a
+// slow subscriber holds up neither the writer nor the other subscribers.
+type EventBus struct {
+	logger logging.Logger
+
+	mu     sync.Mutex
+	nextID int
+	subs   map[int]*subscriber
+}
+
+func NewEventBus(logger logging.Logger) *EventBus {
+	return &EventBus{
+		logger: logger,
+		subs:   make(map[int]*subscriber),
+	}
+}
+
+// Subscribe calls fn with every event published from now on, until the
+// returned func is called. A nil bus has nothing to publish.
+func (b *EventBus) Subscribe(fn func(ChangeEvent)) (unsubscribe func()) {
+	if b == nil {
+		return func() {}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = &subscriber{logger: b.logger, fn: fn}
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subs, id)
+	}
+}
+
+// Publish queues evs for every subscriber and returns without waiting for
+// them
+func (b *EventBus) Publish(evs ...ChangeEvent) {
+	if b == nil || len(evs) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, s := range b.subs {
+		s.enqueue(evs)
+	}
+}
+
+type subscriber struct {
+	logger logging.Logger
+	fn     func(ChangeEvent)
+
+	mu       sync.Mutex
+	queue    []ChangeEvent
+	draining bool
+}
+
+// enqueue adds evs to the queue, skipping any an event still waiting in it
+// already covers, and starts draining it if nothing is
+func (s *subscriber) enqueue(evs []ChangeEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ev := range evs {
+		if !s.queued(ev) {
+			s.queue = append(s.queue, ev)
+		}
+	}
+
+	if !s.draining && len(s.queue) > 0 {
+		s.draining = true
+		go s.drain()
+	}
+}
+
+// queued reports whether an event waiting in the queue already covers ev. A
+// refresh writes a character several times in one transaction, and each
+// write would otherwise load it again.
+func (s *subscriber) queued(ev ChangeEvent) bool {
+	for _, q := range s.queue {
+		if q.Entity != ev.Entity || q.ID != ev.ID {
+			continue
+		}
+		if q.Change == ev.Change || (q.Change == ChangeCreated && ev.Change == ChangeUpdated) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *subscriber) drain() {
+	defer panics.Handler(s.logger)
+
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.draining = false
+			s.mu.Unlock()
+			return
+		}
+		ev := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+
+		s.fn(ev)
+	}
+}
+
+// publish sends evs once tx commits, or straight away if tx is not one
+// database.TransactWithRetries started
+func (r *AppSqliteRepository) publish(tx database.Tx, evs ...ChangeEvent) {
+	database.AfterCommit(tx, func() {
+		r.events.Publish(evs...)
+	})
+}
+
+func (r *AppSqliteRepository) Events() *EventBus {
+	return r.events
+}
+
+// WatchExternalChanges publishes reload events, with ID 0, for every entity
+// when the database changes under it, as it does when the CLI commits. It
+// also locks the token vault once its key no longer matches the stored one.
+// It checks every interval until ctx is done.
+//
+// SQLite's data_version cannot tell another process's commit from one made
+// on another connection of this process, so every change is reloaded. The
+// reload waits for an interval with no further change, so a burst of writes
+// costs one, but no longer than watchMaxDelay intervals, so a change is not
+// held back by writes that never stop.
+func (r *AppSqliteRepository) WatchExternalChanges(ctx context.Context, interval time.Duration) (err error) {
+	logger := r.deps.Logger()
+
+	// data_version is per connection, so it has to be the same one each time
+	conn, err := r.deps.DB().Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get a connection")
+	}
+	defer conn.Close()
+
+	version, err := dataVersion(ctx, conn)
+	if err != nil {
+		return err
+	}
+	// waiting counts the intervals a change has gone without its reload
+	var waiting int
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		v, err := dataVersion(ctx, conn)
+		if err != nil {
+			level.Error(logger).Err("could not check for external changes", err)
+			continue
+		}
+		changed := v != version
+		version = v
+
+		// vault rotate or vault reset leaves a key behind that must not seal
+		// anything more, whoever else wrote in the meantime
+		if changed && !r.deps.Vault().Locked() {
+			if err := r.checkVaultKey(ctx, nil); err != nil {
+				level.Error(logger).Err("could not check the token vault key", err)
+			}
+		}
+
+		if changed {
+			waiting++
+		}
+		if waiting == 0 || (changed && waiting < watchMaxDelay) {
+			continue
+		}
+
+		level.Debug(logger).Message("database changed", "data_version", v)
+		r.events.Publish(
+			ChangeEvent{Entity: EntityCharacter, Change: ChangeUpdated},
+			ChangeEvent{Entity: EntityTag, Change: ChangeUpdated},
+			ChangeEvent{Entity: EntityRole, Change: ChangeUpdated},
+		)
+		waiting = 0
+	}
+}
+
+func dataVersion(ctx context.Context, conn *sql.Conn) (v int64, err error) {
+	err = conn.QueryRowContext(ctx, "PRAGMA data_version").Scan(&v)
+	return v, errors.Wrap(err, "could not read data_version")
+}
@@ -0,0 +1,200 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/operators"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/testhelpers"
+)
+
+// subscribe collects bus events on an unbuffered channel, so a test that
+// has not taken the last event yet leaves the rest queued on the bus
+func subscribe(t *testing.T, bus *repository.EventBus) <-chan repository.ChangeEvent {
+	t.Helper()
+
+	got := make(chan repository.ChangeEvent)
+	done := make(chan struct{})
+	unsub := bus.Subscribe(func(ev repository.ChangeEvent) {
+		select {
+		case got <- ev:
+		case <-done:
+		}
+	})
+	t.Cleanup(func() {
+		unsub()
+		close(done)
+	})
+	return got
+}
+
+func nextEvent(t *testing.T, got <-chan repository.ChangeEvent) repository.ChangeEvent {
+	t.Helper()
+
+	select {
+	case ev := <-got:
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event published")
+	}
+	return repository.ChangeEvent{}
+}
+
+func TestEventBus(t *testing.T) {
+	t.Parallel()
+
+	bus := repository.NewEventBus(testhelpers.NewTestDependencies(t).Logger())
+	got := subscribe(t, bus)
+
+	first := repository.ChangeEvent{Entity: repository.EntityTag, Change: repository.ChangeDeleted, ID: 9}
+	updated := repository.ChangeEvent{Entity: repository.EntityCharacter, Change: repository.ChangeUpdated, ID: 1}
+	created := repository.ChangeEvent{Entity: repository.EntityRole, Change: repository.ChangeCreated, ID: 2}
+
+	// the subscriber is still handing over the first event while the rest
+	// queue up, so the repeats are dropped
+	bus.Publish(first)
+	bus.Publish(updated, updated)
+	bus.Publish(created, repository.ChangeEvent{Entity: repository.EntityRole, Change: repository.ChangeUpdated, ID: 2})
+	bus.Publish(updated)
+
+	assert.Equal(t, first, nextEvent(t, got))
+	assert.Equal(t, updated, nextEvent(t, got))
+	assert.Equal(t, created, nextEvent(t, got))
+
+	bus.Publish(updated)
+	assert.Equal(t, updated, nextEvent(t, got), "delivered events are not coalesced")
+
+	var nilBus *repository.EventBus
+	assert.NotPanics(t, func() {
+		nilBus.Subscribe(func(repository.ChangeEvent) {})()
+		nilBus.Publish(updated)
+	})
+}
+
+func TestAppSqliteRepository_Events(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	deps := newSeededDeps(t, seed{characters: 1, tags: 1})
+	repo := repository.NewAppData(deps)
+	got := subscribe(t, repo.Events())
+
+	event := func(e repository.Entity, c repository.Change, id int64) repository.ChangeEvent {
+		return repository.ChangeEvent{Entity: e, Change: c, ID: id}
+	}
+
+	_, err := repo.UpsertCharacter(ctx, 1, "Character 001", "", 1, 0, true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, event(repository.EntityCharacter, repository.ChangeUpdated, 1), nextEvent(t, got))
+
+	_, err = repo.UpsertCharacter(ctx, 2, "Character 002", "", 1, 0, true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, event(repository.EntityCharacter, repository.ChangeCreated, 2), nextEvent(t, got))
+
+	tag, err := repo.InsertTag(ctx, "New", color.Black, nil)
+	require.NoError(t, err)
+	assert.Equal(t, event(repository.EntityTag, repository.ChangeCreated, tag.ID), nextEvent(t, got))
+
+	// nothing is published for a transaction that rolls back
+	err = database.TransactWithRetries(ctx, deps.Telemetry(), deps.Logger(), deps.DB(), &sql.TxOptions{}, func(ctx context.Context, tx database.Tx) error {
+		if err := repo.UpdateTag(ctx, tag.ID, "Renamed", color.Black, tx); err != nil {
+			return err
+		}
+		return database.NonRetryableError(errors.New("abandoned"))
+	})
+	require.Error(t, err)
+
+	// nor before one commits, and writes to one row in it are coalesced
+	err = database.TransactWithRetries(ctx, deps.Telemetry(), deps.Logger(), deps.DB(), &sql.TxOptions{}, func(ctx context.Context, tx database.Tx) error {
+		role, err := repo.InsertRole(ctx, "Role", "R", color.Black, tx)
+		if err != nil {
+			return err
+		}
+		if err := repo.SetRoleExpr(ctx, role.ID, repository.NewRoleGroup(operators.OperatorAll, repository.NewRoleLeaf(tag.ID)), tx); err != nil {
+			return err
+		}
+		select {
+		case ev := <-got:
+			t.Errorf("event published before commit: %+v", ev)
+		case <-time.After(50 * time.Millisecond):
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	ev := nextEvent(t, got)
+	assert.Equal(t, repository.EntityRole, ev.Entity)
+	assert.Equal(t, repository.ChangeCreated, ev.Change)
+
+	require.NoError(t, repo.DeleteTag(ctx, tag.ID, nil))
+	assert.Equal(t, event(repository.EntityTag, repository.ChangeDeleted, tag.ID), nextEvent(t, got))
+
+	require.NoError(t, repo.DeleteCharacter(ctx, 2, nil))
+	assert.Equal(t, event(repository.EntityCharacter, repository.ChangeDeleted, 2), nextEvent(t, got))
+}
+
+func TestAppSqliteRepository_WatchExternalChanges(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	deps := newSeededDeps(t, seed{tags: 1})
+	repo := repository.NewAppData(deps)
+	got := subscribe(t, repo.Events())
+
+	watching := make(chan error, 1)
+	go func() { watching <- repo.WatchExternalChanges(ctx, 10*time.Millisecond) }()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-watching)
+	})
+
+	// the watcher has its own connection, so a write on any other is
+	// as good as one from another process
+	require.Eventually(t, func() bool {
+		_, err := deps.DB().ExecContext(ctx, `UPDATE tags SET "name" = 'Changed' WHERE "id" = 1`)
+		require.NoError(t, err)
+
+		select {
+		case ev := <-got:
+			assert.Equal(t, int64(0), ev.ID)
+			return true
+		case <-time.After(50 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, time.Millisecond)
+
+	// take the rest of the reload
+	for drained := false; !drained; {
+		select {
+		case <-got:
+		case <-time.After(100 * time.Millisecond):
+			drained = true
+		}
+	}
+
+	// a write every interval, such as a long refresh makes, still gets its
+	// reload in the end
+	stop := time.After(time.Second)
+	for reloaded := false; !reloaded; {
+		_, err := deps.DB().ExecContext(ctx, `UPDATE tags SET "name" = 'Again' WHERE "id" = 1`)
+		require.NoError(t, err)
+
+		select {
+		case ev := <-got:
+			assert.Equal(t, int64(0), ev.ID)
+			reloaded = true
+		case <-time.After(5 * time.Millisecond):
+		case <-stop:
+			t.Fatal("no reload while the database kept changing")
+		}
+	}
+}
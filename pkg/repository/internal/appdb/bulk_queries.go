@@ -0,0 +1,91 @@
+package appdb
+
+// Multi-row statements that sqlc cannot generate, since it has no way to
+// repeat a VALUES tuple per row. They follow the generated code's shape so
+// they sit beside it on Queries.
+
+import (
+	"context"
+	"strings"
+)
+
+// BulkRows caps the rows in one multi-row statement, which keeps the bound
+// variables under SQLite's historical limit of 999
+const BulkRows = 300
+
+const upsertCharacterSkillsPrefix = `INSERT INTO character_skills ("character_id", "skill_id", "skill_level")
+VALUES `
+
+const upsertCharacterSkillsSuffix = `
+ON CONFLICT ("character_id", "skill_id") DO UPDATE
+SET
+    "skill_level" = excluded.skill_level
+RETURNING character_id, skill_id, skill_level
+`
+
+// UpsertCharacterSkills writes up to BulkRows skills in one statement
+func (q *Queries) UpsertCharacterSkills(ctx context.Context, db DBTX, args []UpsertCharacterSkillParams) ([]CharacterSkill, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	var query strings.Builder
+	query.WriteString(upsertCharacterSkillsPrefix)
+	vals := make([]interface{}, 0, 3*len(args))
+	for i, arg := range args {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString("(?, ?, ?)")
+		vals = append(vals, arg.CharacterID, arg.SkillID, arg.SkillLevel)
+	}
+	query.WriteString(upsertCharacterSkillsSuffix)
+
+	rows, err := db.QueryContext(ctx, query.String(), vals...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]CharacterSkill, 0, len(args))
+	for rows.Next() {
+		var i CharacterSkill
+		if err := rows.Scan(&i.CharacterID, &i.SkillID, &i.SkillLevel); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteCharacterSkillsExcept = `DELETE FROM character_skills
+WHERE
+    "character_id" = ?`
+
+type DeleteCharacterSkillsExceptParams struct {
+	CharacterID int64
+	Keep        []int64
+}
+
+// DeleteCharacterSkillsExcept removes every skill of the character that is
+// not in Keep. Keep is bound in a single NOT IN list, so it cannot be split;
+// a character never has more skills than the variable limit allows.
+func (q *Queries) DeleteCharacterSkillsExcept(ctx context.Context, db DBTX, arg DeleteCharacterSkillsExceptParams) error {
+	query := deleteCharacterSkillsExcept
+	vals := make([]interface{}, 0, 1+len(arg.Keep))
+	vals = append(vals, arg.CharacterID)
+	if len(arg.Keep) > 0 {
+		query += `
+    AND "skill_id" NOT IN (?` + strings.Repeat(", ?", len(arg.Keep)-1) + `)`
+		for _, id := range arg.Keep {
+			vals = append(vals, id)
+		}
+	}
+	_, err := db.ExecContext(ctx, query, vals...)
+	return err
+}
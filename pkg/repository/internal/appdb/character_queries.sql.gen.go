@@ -12,6 +12,17 @@ import (
 	"time"
 )
 
+const characterExists = `-- name: CharacterExists :one
+SELECT EXISTS (SELECT 1 FROM characters WHERE "id" = ?)
+`
+
+func (q *Queries) CharacterExists(ctx context.Context, db DBTX, id int64) (int64, error) {
+	row := db.QueryRowContext(ctx, characterExists, id)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
 const deleteCharacter = `-- name: DeleteCharacter :exec
 DELETE FROM characters
 WHERE "id" = ?
@@ -82,7 +93,7 @@ func (q *Queries) GetAllCharacterSkills(ctx context.Context, db DBTX, characterI
 
 const getAllCharacters = `-- name: GetAllCharacters :many
 SELECT 
-    characters.id, characters.name, characters.picture, characters.corporation_id,
+    characters.id, characters.name, characters.picture, characters.corporation_id, characters.total_sp, characters.omega, characters.skill_queue_length,
     corporations.id, corporations.alliance_id, corporations.name, corporations.ticker, corporations.picture,
     alliances.id, alliances.name, alliances.ticker, alliances.picture
 FROM characters
@@ -110,6 +121,9 @@ func (q *Queries) GetAllCharacters(ctx context.Context, db DBTX) ([]GetAllCharac
 			&i.Character.Name,
 			&i.Character.Picture,
 			&i.Character.CorporationID,
+			&i.Character.TotalSp,
+			&i.Character.Omega,
+			&i.Character.SkillQueueLength,
 			&i.Corporation.ID,
 			&i.Corporation.AllianceID,
 			&i.Corporation.Name,
@@ -133,6 +147,87 @@ func (q *Queries) GetAllCharacters(ctx context.Context, db DBTX) ([]GetAllCharac
 	return items, nil
 }
 
+const getCharacter = `-- name: GetCharacter :one
+SELECT 
+    characters.id, characters.name, characters.picture, characters.corporation_id, characters.total_sp, characters.omega, characters.skill_queue_length,
+    corporations.id, corporations.alliance_id, corporations.name, corporations.ticker, corporations.picture,
+    alliances.id, alliances.name, alliances.ticker, alliances.picture
+FROM characters
+INNER JOIN corporations ON characters."corporation_id" = corporations."id"
+LEFT JOIN alliances ON corporations."alliance_id" = alliances."id"
+WHERE characters."id" = ?
+`
+
+type GetCharacterRow struct {
+	Character   Character
+	Corporation Corporation
+	Alliance    Alliance
+}
+
+func (q *Queries) GetCharacter(ctx context.Context, db DBTX, id int64) (GetCharacterRow, error) {
+	row := db.QueryRowContext(ctx, getCharacter, id)
+	var i GetCharacterRow
+	err := row.Scan(
+		&i.Character.ID,
+		&i.Character.Name,
+		&i.Character.Picture,
+		&i.Character.CorporationID,
+		&i.Character.TotalSp,
+		&i.Character.Omega,
+		&i.Character.SkillQueueLength,
+		&i.Corporation.ID,
+		&i.Corporation.AllianceID,
+		&i.Corporation.Name,
+		&i.Corporation.Ticker,
+		&i.Corporation.Picture,
+		&i.Alliance.ID,
+		&i.Alliance.Name,
+		&i.Alliance.Ticker,
+		&i.Alliance.Picture,
+	)
+	return i, err
+}
+
+const getCharacterSkillsForCharacters = `-- name: GetCharacterSkillsForCharacters :many
+SELECT character_id, skill_id, skill_level
+FROM character_skills
+WHERE "character_id" IN (/*SLICE:character_ids*/?)
+ORDER BY "character_id", "skill_id"
+`
+
+func (q *Queries) GetCharacterSkillsForCharacters(ctx context.Context, db DBTX, characterIds []int64) ([]CharacterSkill, error) {
+	query := getCharacterSkillsForCharacters
+	var queryParams []interface{}
+	if len(characterIds) > 0 {
+		for _, v := range characterIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:character_ids*/?", strings.Repeat(",?", len(characterIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:character_ids*/?", "NULL", 1)
+	}
+	rows, err := db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CharacterSkill
+	for rows.Next() {
+		var i CharacterSkill
+		if err := rows.Scan(&i.CharacterID, &i.SkillID, &i.SkillLevel); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTokenForCharacter = `-- name: GetTokenForCharacter :one
 SELECT id, character_id, access_token, refresh_token, token_type, expiration 
 FROM tokens
@@ -154,6 +249,22 @@ func (q *Queries) GetTokenForCharacter(ctx context.Context, db DBTX, characterID
 	return i, err
 }
 
+const updateCharacterSkillQueue = `-- name: UpdateCharacterSkillQueue :exec
+UPDATE characters
+SET "skill_queue_length" = ?
+WHERE "id" = ?
+`
+
+type UpdateCharacterSkillQueueParams struct {
+	SkillQueueLength sql.NullInt64
+	ID               int64
+}
+
+func (q *Queries) UpdateCharacterSkillQueue(ctx context.Context, db DBTX, arg UpdateCharacterSkillQueueParams) error {
+	_, err := db.ExecContext(ctx, updateCharacterSkillQueue, arg.SkillQueueLength, arg.ID)
+	return err
+}
+
 const upsertAlliance = `-- name: UpsertAlliance :one
 INSERT INTO alliances ("id", "name", "ticker", "picture")
 VALUES (?, ?, ?, ?)
@@ -190,14 +301,16 @@ func (q *Queries) UpsertAlliance(ctx context.Context, db DBTX, arg UpsertAllianc
 }
 
 const upsertCharacter = `-- name: UpsertCharacter :one
-INSERT INTO characters ("id", "name", "picture", "corporation_id")
-VALUES (?, ?, ?, ?)
+INSERT INTO characters ("id", "name", "picture", "corporation_id", "total_sp", "omega")
+VALUES (?, ?, ?, ?, ?, ?)
 ON CONFLICT ("id") DO UPDATE
 SET
     "name" = excluded.name,
     "picture" = excluded.picture,
-    "corporation_id" = excluded.corporation_id
-RETURNING id, name, picture, corporation_id
+    "corporation_id" = excluded.corporation_id,
+    "total_sp" = excluded.total_sp,
+    "omega" = excluded.omega
+RETURNING id, name, picture, corporation_id, total_sp, omega, skill_queue_length
 `
 
 type UpsertCharacterParams struct {
@@ -205,6 +318,8 @@ type UpsertCharacterParams struct {
 	Name          string
 	Picture       string
 	CorporationID int64
+	TotalSp       int64
+	Omega         sql.NullBool
 }
 
 func (q *Queries) UpsertCharacter(ctx context.Context, db DBTX, arg UpsertCharacterParams) (Character, error) {
@@ -213,6 +328,8 @@ func (q *Queries) UpsertCharacter(ctx context.Context, db DBTX, arg UpsertCharac
 		arg.Name,
 		arg.Picture,
 		arg.CorporationID,
+		arg.TotalSp,
+		arg.Omega,
 	)
 	var i Character
 	err := row.Scan(
@@ -220,6 +337,9 @@ func (q *Queries) UpsertCharacter(ctx context.Context, db DBTX, arg UpsertCharac
 		&i.Name,
 		&i.Picture,
 		&i.CorporationID,
+		&i.TotalSp,
+		&i.Omega,
+		&i.SkillQueueLength,
 	)
 	return i, err
 }
@@ -17,10 +17,13 @@ type Alliance struct {
 }
 
 type Character struct {
-	ID            int64
-	Name          string
-	Picture       string
-	CorporationID int64
+	ID               int64
+	Name             string
+	Picture          string
+	CorporationID    int64
+	TotalSp          int64
+	Omega            sql.NullBool
+	SkillQueueLength sql.NullInt64
 }
 
 type CharacterSkill struct {
@@ -37,20 +40,41 @@ type Corporation struct {
 	Picture    string
 }
 
+type Notification struct {
+	ID          int64
+	Url         string
+	Payload     string
+	Attempts    int64
+	NextAttempt time.Time
+	LastError   string
+}
+
 type Role struct {
-	ID       int64
-	Name     string
-	Label    string
-	Operator string
-	ColorR   int64
-	ColorG   int64
-	ColorB   int64
-	ColorA   int64
+	ID     int64
+	Name   string
+	Label  string
+	ColorR int64
+	ColorG int64
+	ColorB int64
+	ColorA int64
 }
 
-type RoleTag struct {
+type RoleCondition struct {
+	ID     int64
 	RoleID int64
-	TagID  int64
+	Kind   string
+	Value  int64
+	Label  string
+}
+
+type RoleNode struct {
+	ID       int64
+	RoleID   int64
+	ParentID sql.NullInt64
+	Position int64
+	Operator sql.NullString
+	TagID    sql.NullInt64
+	MinCount int64
 }
 
 type Tag struct {
@@ -62,10 +86,16 @@ type Tag struct {
 	ColorA int64
 }
 
+type TagInclude struct {
+	TagID         int64
+	IncludedTagID int64
+}
+
 type TagSkill struct {
-	TagID      int64
-	SkillID    int64
-	SkillLevel int64
+	TagID            int64
+	SkillID          int64
+	SkillLevel       int64
+	RecommendedLevel int64
 }
 
 type Token struct {
@@ -76,3 +106,13 @@ type Token struct {
 	TokenType    string
 	Expiration   time.Time
 }
+
+type VaultSetting struct {
+	ID         int64
+	Mode       string
+	KdfSalt    []byte
+	KdfTime    int64
+	KdfMemory  int64
+	KdfThreads int64
+	CheckValue string
+}
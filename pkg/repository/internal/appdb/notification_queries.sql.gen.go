@@ -0,0 +1,116 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: notification_queries.sql
+
+package appdb
+
+import (
+	"context"
+	"time"
+)
+
+const deleteNotification = `-- name: DeleteNotification :exec
+DELETE FROM notifications
+WHERE "id" = ?
+`
+
+func (q *Queries) DeleteNotification(ctx context.Context, db DBTX, id int64) error {
+	_, err := db.ExecContext(ctx, deleteNotification, id)
+	return err
+}
+
+const getDueNotifications = `-- name: GetDueNotifications :many
+SELECT id, url, payload, attempts, next_attempt, last_error
+FROM notifications
+WHERE "next_attempt" <= ?
+ORDER BY "next_attempt", "id"
+LIMIT ?
+`
+
+type GetDueNotificationsParams struct {
+	NextAttempt time.Time
+	Limit       int64
+}
+
+func (q *Queries) GetDueNotifications(ctx context.Context, db DBTX, arg GetDueNotificationsParams) ([]Notification, error) {
+	rows, err := db.QueryContext(ctx, getDueNotifications, arg.NextAttempt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notification
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Payload,
+			&i.Attempts,
+			&i.NextAttempt,
+			&i.LastError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertNotification = `-- name: InsertNotification :one
+INSERT INTO notifications ("url", "payload", "next_attempt")
+VALUES (?, ?, ?)
+RETURNING id, url, payload, attempts, next_attempt, last_error
+`
+
+type InsertNotificationParams struct {
+	Url         string
+	Payload     string
+	NextAttempt time.Time
+}
+
+func (q *Queries) InsertNotification(ctx context.Context, db DBTX, arg InsertNotificationParams) (Notification, error) {
+	row := db.QueryRowContext(ctx, insertNotification, arg.Url, arg.Payload, arg.NextAttempt)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Payload,
+		&i.Attempts,
+		&i.NextAttempt,
+		&i.LastError,
+	)
+	return i, err
+}
+
+const rescheduleNotification = `-- name: RescheduleNotification :exec
+UPDATE notifications
+SET
+    "attempts" = ?,
+    "next_attempt" = ?,
+    "last_error" = ?
+WHERE "id" = ?
+`
+
+type RescheduleNotificationParams struct {
+	Attempts    int64
+	NextAttempt time.Time
+	LastError   string
+	ID          int64
+}
+
+func (q *Queries) RescheduleNotification(ctx context.Context, db DBTX, arg RescheduleNotificationParams) error {
+	_, err := db.ExecContext(ctx, rescheduleNotification,
+		arg.Attempts,
+		arg.NextAttempt,
+		arg.LastError,
+		arg.ID,
+	)
+	return err
+}
@@ -9,30 +9,55 @@ import (
 )
 
 type Querier interface {
+	CharacterExists(ctx context.Context, db DBTX, id int64) (int64, error)
+	DeleteAllTokens(ctx context.Context, db DBTX) error
 	DeleteCharacter(ctx context.Context, db DBTX, id int64) error
 	DeleteCharacterSkills(ctx context.Context, db DBTX, arg DeleteCharacterSkillsParams) error
+	DeleteNotification(ctx context.Context, db DBTX, id int64) error
 	DeleteRole(ctx context.Context, db DBTX, id int64) error
-	DeleteRoleTags(ctx context.Context, db DBTX, arg DeleteRoleTagsParams) error
+	DeleteRoleConditions(ctx context.Context, db DBTX, roleID int64) error
+	DeleteRoleNodes(ctx context.Context, db DBTX, roleID int64) error
 	DeleteTag(ctx context.Context, db DBTX, id int64) error
+	DeleteTagIncludes(ctx context.Context, db DBTX, arg DeleteTagIncludesParams) error
 	DeleteTagSkills(ctx context.Context, db DBTX, arg DeleteTagSkillsParams) error
+	DeleteVaultSetting(ctx context.Context, db DBTX) error
 	GetAllCharacterSkills(ctx context.Context, db DBTX, characterID int64) ([]CharacterSkill, error)
 	GetAllCharacters(ctx context.Context, db DBTX) ([]GetAllCharactersRow, error)
 	GetAllRoleTags(ctx context.Context, db DBTX, roleID int64) ([]Tag, error)
 	GetAllRoles(ctx context.Context, db DBTX) ([]Role, error)
+	GetAllTagIncludes(ctx context.Context, db DBTX) ([]TagInclude, error)
 	GetAllTagSkills(ctx context.Context, db DBTX, tagID int64) ([]TagSkill, error)
 	GetAllTags(ctx context.Context, db DBTX) ([]Tag, error)
+	GetAllTokens(ctx context.Context, db DBTX) ([]Token, error)
+	GetCharacter(ctx context.Context, db DBTX, id int64) (GetCharacterRow, error)
+	GetCharacterSkillsForCharacters(ctx context.Context, db DBTX, characterIds []int64) ([]CharacterSkill, error)
+	GetDueNotifications(ctx context.Context, db DBTX, arg GetDueNotificationsParams) ([]Notification, error)
+	GetRoleConditions(ctx context.Context, db DBTX, roleID int64) ([]RoleCondition, error)
+	GetRoleConditionsForRoles(ctx context.Context, db DBTX, roleIds []int64) ([]RoleCondition, error)
+	GetRoleNodes(ctx context.Context, db DBTX, roleID int64) ([]RoleNode, error)
+	GetRoleNodesForRoles(ctx context.Context, db DBTX, roleIds []int64) ([]RoleNode, error)
+	GetRoleTagsForRoles(ctx context.Context, db DBTX, roleIds []int64) ([]GetRoleTagsForRolesRow, error)
+	GetTagSkillsForTags(ctx context.Context, db DBTX, tagIds []int64) ([]TagSkill, error)
 	GetTokenForCharacter(ctx context.Context, db DBTX, characterID int64) (Token, error)
+	GetVaultSetting(ctx context.Context, db DBTX) (VaultSetting, error)
+	InsertNotification(ctx context.Context, db DBTX, arg InsertNotificationParams) (Notification, error)
 	InsertRole(ctx context.Context, db DBTX, arg InsertRoleParams) (Role, error)
+	InsertRoleCondition(ctx context.Context, db DBTX, arg InsertRoleConditionParams) (RoleCondition, error)
+	InsertRoleNode(ctx context.Context, db DBTX, arg InsertRoleNodeParams) (RoleNode, error)
 	InsertTag(ctx context.Context, db DBTX, arg InsertTagParams) (Tag, error)
+	RescheduleNotification(ctx context.Context, db DBTX, arg RescheduleNotificationParams) error
+	UpdateCharacterSkillQueue(ctx context.Context, db DBTX, arg UpdateCharacterSkillQueueParams) error
 	UpdateRole(ctx context.Context, db DBTX, arg UpdateRoleParams) error
 	UpdateTag(ctx context.Context, db DBTX, arg UpdateTagParams) error
+	UpdateTokenSecrets(ctx context.Context, db DBTX, arg UpdateTokenSecretsParams) error
 	UpsertAlliance(ctx context.Context, db DBTX, arg UpsertAllianceParams) (Alliance, error)
 	UpsertCharacter(ctx context.Context, db DBTX, arg UpsertCharacterParams) (Character, error)
 	UpsertCharacterSkill(ctx context.Context, db DBTX, arg UpsertCharacterSkillParams) (CharacterSkill, error)
 	UpsertCorporation(ctx context.Context, db DBTX, arg UpsertCorporationParams) (Corporation, error)
-	UpsertRoleTag(ctx context.Context, db DBTX, arg UpsertRoleTagParams) (RoleTag, error)
+	UpsertTagInclude(ctx context.Context, db DBTX, arg UpsertTagIncludeParams) error
 	UpsertTagSkill(ctx context.Context, db DBTX, arg UpsertTagSkillParams) (TagSkill, error)
 	UpsertToken(ctx context.Context, db DBTX, arg UpsertTokenParams) (Token, error)
+	UpsertVaultSetting(ctx context.Context, db DBTX, arg UpsertVaultSettingParams) error
 }
 
 var _ Querier = (*Queries)(nil)
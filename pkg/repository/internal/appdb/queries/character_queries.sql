@@ -20,13 +20,15 @@ SET
 RETURNING *;
 
 -- name: UpsertCharacter :one
-INSERT INTO characters ("id", "name", "picture", "corporation_id")
-VALUES (?, ?, ?, ?)
+INSERT INTO characters ("id", "name", "picture", "corporation_id", "total_sp", "omega")
+VALUES (?, ?, ?, ?, ?, ?)
 ON CONFLICT ("id") DO UPDATE
 SET
     "name" = excluded.name,
     "picture" = excluded.picture,
-    "corporation_id" = excluded.corporation_id
+    "corporation_id" = excluded.corporation_id,
+    "total_sp" = excluded.total_sp,
+    "omega" = excluded.omega
 RETURNING *;
 
 -- name: DeleteCharacter :exec
@@ -66,6 +68,12 @@ FROM character_skills
 WHERE "character_id" = ?
 ORDER BY "skill_id";
 
+-- name: GetCharacterSkillsForCharacters :many
+SELECT *
+FROM character_skills
+WHERE "character_id" IN (sqlc.slice(character_ids))
+ORDER BY "character_id", "skill_id";
+
 -- name: UpsertCharacterSkill :one
 INSERT INTO character_skills ("character_id", "skill_id", "skill_level")
 VALUES (?, ?, ?)
@@ -78,4 +86,22 @@ RETURNING *;
 DELETE FROM character_skills
 WHERE 
     "character_id" = ?
-    AND "skill_id" IN (sqlc.slice(skill_ids));
\ No newline at end of file
+    AND "skill_id" IN (sqlc.slice(skill_ids));
+
+-- name: GetCharacter :one
+SELECT 
+    sqlc.embed(characters),
+    sqlc.embed(corporations),
+    sqlc.embed(alliances)
+FROM characters
+INNER JOIN corporations ON characters."corporation_id" = corporations."id"
+LEFT JOIN alliances ON corporations."alliance_id" = alliances."id"
+WHERE characters."id" = ?;
+
+-- name: UpdateCharacterSkillQueue :exec
+UPDATE characters
+SET "skill_queue_length" = ?
+WHERE "id" = ?;
+
+-- name: CharacterExists :one
+SELECT EXISTS (SELECT 1 FROM characters WHERE "id" = ?);
@@ -0,0 +1,23 @@
+-- name: InsertNotification :one
+INSERT INTO notifications ("url", "payload", "next_attempt")
+VALUES (?, ?, ?)
+RETURNING *;
+
+-- name: GetDueNotifications :many
+SELECT *
+FROM notifications
+WHERE "next_attempt" <= ?
+ORDER BY "next_attempt", "id"
+LIMIT ?;
+
+-- name: RescheduleNotification :exec
+UPDATE notifications
+SET
+    "attempts" = ?,
+    "next_attempt" = ?,
+    "last_error" = ?
+WHERE "id" = ?;
+
+-- name: DeleteNotification :exec
+DELETE FROM notifications
+WHERE "id" = ?;
@@ -1,7 +1,7 @@
 -- name: InsertRole :one
 
-INSERT INTO roles ("name", "label", "operator", "color_r", "color_g", "color_b", "color_a")
-VALUES (?, ?, ?, ?, ?, ?, ?)
+INSERT INTO roles ("name", "label", "color_r", "color_g", "color_b", "color_a")
+VALUES (?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: UpdateRole :exec
@@ -9,7 +9,6 @@ UPDATE roles
 SET
     "name" = ?,
     "label" = ?,
-    "operator" = ?,
     "color_r" = ?,
     "color_g" = ?,
     "color_b" = ?,
@@ -26,21 +25,60 @@ SELECT
 FROM roles
 ORDER BY "name";
 
--- name: UpsertRoleTag :one
-INSERT INTO role_tags ("role_id", "tag_id")
-VALUES (?, ?)
-ON CONFLICT ("role_id", "tag_id") DO NOTHING
+-- name: InsertRoleNode :one
+INSERT INTO role_nodes ("role_id", "parent_id", "position", "operator", "min_count", "tag_id")
+VALUES (?, ?, ?, ?, ?, ?)
 RETURNING *;
 
--- name: DeleteRoleTags :exec
-DELETE FROM role_tags
-WHERE 
-    "role_id" = ?
-    AND "tag_id" IN (sqlc.slice(tag_ids));
+-- name: DeleteRoleNodes :exec
+DELETE FROM role_nodes
+WHERE "role_id" = ?;
+
+-- name: GetRoleNodes :many
+SELECT *
+FROM role_nodes
+WHERE "role_id" = ?
+ORDER BY "parent_id", "position", "id";
+
+-- name: GetRoleNodesForRoles :many
+SELECT *
+FROM role_nodes
+WHERE "role_id" IN (sqlc.slice(role_ids))
+ORDER BY "role_id", "parent_id", "position", "id";
 
 -- name: GetAllRoleTags :many
-SELECT tags.*
+SELECT DISTINCT tags.*
 FROM tags
-JOIN role_tags ON tags."id" = role_tags."tag_id"
-WHERE role_tags."role_id" = ?
-ORDER BY tags."name";
\ No newline at end of file
+JOIN role_nodes ON tags."id" = role_nodes."tag_id"
+WHERE role_nodes."role_id" = ?
+ORDER BY tags."name";
+
+-- name: GetRoleTagsForRoles :many
+SELECT DISTINCT
+    role_nodes."role_id",
+    sqlc.embed(tags)
+FROM tags
+JOIN role_nodes ON tags."id" = role_nodes."tag_id"
+WHERE role_nodes."role_id" IN (sqlc.slice(role_ids))
+ORDER BY role_nodes."role_id", tags."name";
+
+-- name: InsertRoleCondition :one
+INSERT INTO role_conditions ("role_id", "kind", "value", "label")
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: DeleteRoleConditions :exec
+DELETE FROM role_conditions
+WHERE "role_id" = ?;
+
+-- name: GetRoleConditions :many
+SELECT *
+FROM role_conditions
+WHERE "role_id" = ?
+ORDER BY "id";
+
+-- name: GetRoleConditionsForRoles :many
+SELECT *
+FROM role_conditions
+WHERE "role_id" IN (sqlc.slice(role_ids))
+ORDER BY "role_id", "id";
@@ -25,11 +25,12 @@ FROM tags
 ORDER BY "name";
 
 -- name: UpsertTagSkill :one
-INSERT INTO tag_skills ("tag_id", "skill_id", "skill_level")
-VALUES (?, ?, ?)
+INSERT INTO tag_skills ("tag_id", "skill_id", "skill_level", "recommended_level")
+VALUES (?, ?, ?, ?)
 ON CONFLICT ("tag_id", "skill_id") DO UPDATE
 SET
-    "skill_level" = excluded.skill_level
+    "skill_level" = excluded.skill_level,
+    "recommended_level" = excluded.recommended_level
 RETURNING *;
 
 -- name: DeleteTagSkills :exec
@@ -42,4 +43,26 @@ WHERE
 SELECT *
 FROM tag_skills
 WHERE "tag_id" = ?
-ORDER BY "skill_id";
\ No newline at end of file
+ORDER BY "skill_id";
+
+-- name: GetTagSkillsForTags :many
+SELECT *
+FROM tag_skills
+WHERE "tag_id" IN (sqlc.slice(tag_ids))
+ORDER BY "tag_id", "skill_id";
+
+-- name: UpsertTagInclude :exec
+INSERT INTO tag_includes ("tag_id", "included_tag_id")
+VALUES (?, ?)
+ON CONFLICT ("tag_id", "included_tag_id") DO NOTHING;
+
+-- name: DeleteTagIncludes :exec
+DELETE FROM tag_includes
+WHERE 
+    "tag_id" = ?
+    AND "included_tag_id" IN (sqlc.slice(included_tag_ids));
+
+-- name: GetAllTagIncludes :many
+SELECT *
+FROM tag_includes
+ORDER BY "tag_id", "included_tag_id";
\ No newline at end of file
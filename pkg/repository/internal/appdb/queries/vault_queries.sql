@@ -0,0 +1,34 @@
+-- name: GetVaultSetting :one
+SELECT *
+FROM vault_settings
+WHERE "id" = 1;
+
+-- name: UpsertVaultSetting :exec
+INSERT INTO vault_settings ("id", "mode", "kdf_salt", "kdf_time", "kdf_memory", "kdf_threads", "check_value")
+VALUES (1, ?, ?, ?, ?, ?, ?)
+ON CONFLICT ("id") DO UPDATE
+SET
+    "mode" = excluded.mode,
+    "kdf_salt" = excluded.kdf_salt,
+    "kdf_time" = excluded.kdf_time,
+    "kdf_memory" = excluded.kdf_memory,
+    "kdf_threads" = excluded.kdf_threads,
+    "check_value" = excluded.check_value;
+
+-- name: DeleteVaultSetting :exec
+DELETE FROM vault_settings;
+
+-- name: GetAllTokens :many
+SELECT *
+FROM tokens
+ORDER BY "character_id";
+
+-- name: UpdateTokenSecrets :exec
+UPDATE tokens
+SET
+    "access_token" = ?,
+    "refresh_token" = ?
+WHERE "character_id" = ?;
+
+-- name: DeleteAllTokens :exec
+DELETE FROM tokens;
@@ -7,6 +7,7 @@ package appdb
 
 import (
 	"context"
+	"database/sql"
 	"strings"
 )
 
@@ -20,39 +21,31 @@ func (q *Queries) DeleteRole(ctx context.Context, db DBTX, id int64) error {
 	return err
 }
 
-const deleteRoleTags = `-- name: DeleteRoleTags :exec
-DELETE FROM role_tags
-WHERE 
-    "role_id" = ?
-    AND "tag_id" IN (/*SLICE:tag_ids*/?)
+const deleteRoleConditions = `-- name: DeleteRoleConditions :exec
+DELETE FROM role_conditions
+WHERE "role_id" = ?
 `
 
-type DeleteRoleTagsParams struct {
-	RoleID int64
-	TagIds []int64
+func (q *Queries) DeleteRoleConditions(ctx context.Context, db DBTX, roleID int64) error {
+	_, err := db.ExecContext(ctx, deleteRoleConditions, roleID)
+	return err
 }
 
-func (q *Queries) DeleteRoleTags(ctx context.Context, db DBTX, arg DeleteRoleTagsParams) error {
-	query := deleteRoleTags
-	var queryParams []interface{}
-	queryParams = append(queryParams, arg.RoleID)
-	if len(arg.TagIds) > 0 {
-		for _, v := range arg.TagIds {
-			queryParams = append(queryParams, v)
-		}
-		query = strings.Replace(query, "/*SLICE:tag_ids*/?", strings.Repeat(",?", len(arg.TagIds))[1:], 1)
-	} else {
-		query = strings.Replace(query, "/*SLICE:tag_ids*/?", "NULL", 1)
-	}
-	_, err := db.ExecContext(ctx, query, queryParams...)
+const deleteRoleNodes = `-- name: DeleteRoleNodes :exec
+DELETE FROM role_nodes
+WHERE "role_id" = ?
+`
+
+func (q *Queries) DeleteRoleNodes(ctx context.Context, db DBTX, roleID int64) error {
+	_, err := db.ExecContext(ctx, deleteRoleNodes, roleID)
 	return err
 }
 
 const getAllRoleTags = `-- name: GetAllRoleTags :many
-SELECT tags.id, tags.name, tags.color_r, tags.color_g, tags.color_b, tags.color_a
+SELECT DISTINCT tags.id, tags.name, tags.color_r, tags.color_g, tags.color_b, tags.color_a
 FROM tags
-JOIN role_tags ON tags."id" = role_tags."tag_id"
-WHERE role_tags."role_id" = ?
+JOIN role_nodes ON tags."id" = role_nodes."tag_id"
+WHERE role_nodes."role_id" = ?
 ORDER BY tags."name"
 `
 
@@ -88,7 +81,7 @@ func (q *Queries) GetAllRoleTags(ctx context.Context, db DBTX, roleID int64) ([]
 
 const getAllRoles = `-- name: GetAllRoles :many
 SELECT 
-    id, name, label, operator, color_r, color_g, color_b, color_a
+    id, name, label, color_r, color_g, color_b, color_a
 FROM roles
 ORDER BY "name"
 `
@@ -106,7 +99,6 @@ func (q *Queries) GetAllRoles(ctx context.Context, db DBTX) ([]Role, error) {
 			&i.ID,
 			&i.Name,
 			&i.Label,
-			&i.Operator,
 			&i.ColorR,
 			&i.ColorG,
 			&i.ColorB,
@@ -125,28 +117,250 @@ func (q *Queries) GetAllRoles(ctx context.Context, db DBTX) ([]Role, error) {
 	return items, nil
 }
 
+const getRoleConditions = `-- name: GetRoleConditions :many
+SELECT id, role_id, kind, value, label
+FROM role_conditions
+WHERE "role_id" = ?
+ORDER BY "id"
+`
+
+func (q *Queries) GetRoleConditions(ctx context.Context, db DBTX, roleID int64) ([]RoleCondition, error) {
+	rows, err := db.QueryContext(ctx, getRoleConditions, roleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RoleCondition
+	for rows.Next() {
+		var i RoleCondition
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoleID,
+			&i.Kind,
+			&i.Value,
+			&i.Label,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoleConditionsForRoles = `-- name: GetRoleConditionsForRoles :many
+SELECT id, role_id, kind, value, label
+FROM role_conditions
+WHERE "role_id" IN (/*SLICE:role_ids*/?)
+ORDER BY "role_id", "id"
+`
+
+func (q *Queries) GetRoleConditionsForRoles(ctx context.Context, db DBTX, roleIds []int64) ([]RoleCondition, error) {
+	query := getRoleConditionsForRoles
+	var queryParams []interface{}
+	if len(roleIds) > 0 {
+		for _, v := range roleIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:role_ids*/?", strings.Repeat(",?", len(roleIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:role_ids*/?", "NULL", 1)
+	}
+	rows, err := db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RoleCondition
+	for rows.Next() {
+		var i RoleCondition
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoleID,
+			&i.Kind,
+			&i.Value,
+			&i.Label,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoleNodes = `-- name: GetRoleNodes :many
+SELECT id, role_id, parent_id, position, operator, tag_id, min_count
+FROM role_nodes
+WHERE "role_id" = ?
+ORDER BY "parent_id", "position", "id"
+`
+
+func (q *Queries) GetRoleNodes(ctx context.Context, db DBTX, roleID int64) ([]RoleNode, error) {
+	rows, err := db.QueryContext(ctx, getRoleNodes, roleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RoleNode
+	for rows.Next() {
+		var i RoleNode
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoleID,
+			&i.ParentID,
+			&i.Position,
+			&i.Operator,
+			&i.TagID,
+			&i.MinCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoleNodesForRoles = `-- name: GetRoleNodesForRoles :many
+SELECT id, role_id, parent_id, position, operator, tag_id, min_count
+FROM role_nodes
+WHERE "role_id" IN (/*SLICE:role_ids*/?)
+ORDER BY "role_id", "parent_id", "position", "id"
+`
+
+func (q *Queries) GetRoleNodesForRoles(ctx context.Context, db DBTX, roleIds []int64) ([]RoleNode, error) {
+	query := getRoleNodesForRoles
+	var queryParams []interface{}
+	if len(roleIds) > 0 {
+		for _, v := range roleIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:role_ids*/?", strings.Repeat(",?", len(roleIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:role_ids*/?", "NULL", 1)
+	}
+	rows, err := db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RoleNode
+	for rows.Next() {
+		var i RoleNode
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoleID,
+			&i.ParentID,
+			&i.Position,
+			&i.Operator,
+			&i.TagID,
+			&i.MinCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoleTagsForRoles = `-- name: GetRoleTagsForRoles :many
+SELECT DISTINCT
+    role_nodes."role_id",
+    tags.id, tags.name, tags.color_r, tags.color_g, tags.color_b, tags.color_a
+FROM tags
+JOIN role_nodes ON tags."id" = role_nodes."tag_id"
+WHERE role_nodes."role_id" IN (/*SLICE:role_ids*/?)
+ORDER BY role_nodes."role_id", tags."name"
+`
+
+type GetRoleTagsForRolesRow struct {
+	RoleID int64
+	Tag    Tag
+}
+
+func (q *Queries) GetRoleTagsForRoles(ctx context.Context, db DBTX, roleIds []int64) ([]GetRoleTagsForRolesRow, error) {
+	query := getRoleTagsForRoles
+	var queryParams []interface{}
+	if len(roleIds) > 0 {
+		for _, v := range roleIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:role_ids*/?", strings.Repeat(",?", len(roleIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:role_ids*/?", "NULL", 1)
+	}
+	rows, err := db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRoleTagsForRolesRow
+	for rows.Next() {
+		var i GetRoleTagsForRolesRow
+		if err := rows.Scan(
+			&i.RoleID,
+			&i.Tag.ID,
+			&i.Tag.Name,
+			&i.Tag.ColorR,
+			&i.Tag.ColorG,
+			&i.Tag.ColorB,
+			&i.Tag.ColorA,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertRole = `-- name: InsertRole :one
 
-INSERT INTO roles ("name", "label", "operator", "color_r", "color_g", "color_b", "color_a")
-VALUES (?, ?, ?, ?, ?, ?, ?)
-RETURNING id, name, label, operator, color_r, color_g, color_b, color_a
+INSERT INTO roles ("name", "label", "color_r", "color_g", "color_b", "color_a")
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING id, name, label, color_r, color_g, color_b, color_a
 `
 
 type InsertRoleParams struct {
-	Name     string
-	Label    string
-	Operator string
-	ColorR   int64
-	ColorG   int64
-	ColorB   int64
-	ColorA   int64
+	Name   string
+	Label  string
+	ColorR int64
+	ColorG int64
+	ColorB int64
+	ColorA int64
 }
 
 func (q *Queries) InsertRole(ctx context.Context, db DBTX, arg InsertRoleParams) (Role, error) {
 	row := db.QueryRowContext(ctx, insertRole,
 		arg.Name,
 		arg.Label,
-		arg.Operator,
 		arg.ColorR,
 		arg.ColorG,
 		arg.ColorB,
@@ -157,7 +371,6 @@ func (q *Queries) InsertRole(ctx context.Context, db DBTX, arg InsertRoleParams)
 		&i.ID,
 		&i.Name,
 		&i.Label,
-		&i.Operator,
 		&i.ColorR,
 		&i.ColorG,
 		&i.ColorB,
@@ -166,12 +379,79 @@ func (q *Queries) InsertRole(ctx context.Context, db DBTX, arg InsertRoleParams)
 	return i, err
 }
 
+const insertRoleCondition = `-- name: InsertRoleCondition :one
+INSERT INTO role_conditions ("role_id", "kind", "value", "label")
+VALUES (?, ?, ?, ?)
+RETURNING id, role_id, kind, value, label
+`
+
+type InsertRoleConditionParams struct {
+	RoleID int64
+	Kind   string
+	Value  int64
+	Label  string
+}
+
+func (q *Queries) InsertRoleCondition(ctx context.Context, db DBTX, arg InsertRoleConditionParams) (RoleCondition, error) {
+	row := db.QueryRowContext(ctx, insertRoleCondition,
+		arg.RoleID,
+		arg.Kind,
+		arg.Value,
+		arg.Label,
+	)
+	var i RoleCondition
+	err := row.Scan(
+		&i.ID,
+		&i.RoleID,
+		&i.Kind,
+		&i.Value,
+		&i.Label,
+	)
+	return i, err
+}
+
+const insertRoleNode = `-- name: InsertRoleNode :one
+INSERT INTO role_nodes ("role_id", "parent_id", "position", "operator", "min_count", "tag_id")
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING id, role_id, parent_id, position, operator, tag_id, min_count
+`
+
+type InsertRoleNodeParams struct {
+	RoleID   int64
+	ParentID sql.NullInt64
+	Position int64
+	Operator sql.NullString
+	MinCount int64
+	TagID    sql.NullInt64
+}
+
+func (q *Queries) InsertRoleNode(ctx context.Context, db DBTX, arg InsertRoleNodeParams) (RoleNode, error) {
+	row := db.QueryRowContext(ctx, insertRoleNode,
+		arg.RoleID,
+		arg.ParentID,
+		arg.Position,
+		arg.Operator,
+		arg.MinCount,
+		arg.TagID,
+	)
+	var i RoleNode
+	err := row.Scan(
+		&i.ID,
+		&i.RoleID,
+		&i.ParentID,
+		&i.Position,
+		&i.Operator,
+		&i.TagID,
+		&i.MinCount,
+	)
+	return i, err
+}
+
 const updateRole = `-- name: UpdateRole :exec
 UPDATE roles
 SET
     "name" = ?,
     "label" = ?,
-    "operator" = ?,
     "color_r" = ?,
     "color_g" = ?,
     "color_b" = ?,
@@ -180,21 +460,19 @@ WHERE "id" = ?
 `
 
 type UpdateRoleParams struct {
-	Name     string
-	Label    string
-	Operator string
-	ColorR   int64
-	ColorG   int64
-	ColorB   int64
-	ColorA   int64
-	ID       int64
+	Name   string
+	Label  string
+	ColorR int64
+	ColorG int64
+	ColorB int64
+	ColorA int64
+	ID     int64
 }
 
 func (q *Queries) UpdateRole(ctx context.Context, db DBTX, arg UpdateRoleParams) error {
 	_, err := db.ExecContext(ctx, updateRole,
 		arg.Name,
 		arg.Label,
-		arg.Operator,
 		arg.ColorR,
 		arg.ColorG,
 		arg.ColorB,
@@ -203,22 +481,3 @@ func (q *Queries) UpdateRole(ctx context.Context, db DBTX, arg UpdateRoleParams)
 	)
 	return err
 }
-
-const upsertRoleTag = `-- name: UpsertRoleTag :one
-INSERT INTO role_tags ("role_id", "tag_id")
-VALUES (?, ?)
-ON CONFLICT ("role_id", "tag_id") DO NOTHING
-RETURNING role_id, tag_id
-`
-
-type UpsertRoleTagParams struct {
-	RoleID int64
-	TagID  int64
-}
-
-func (q *Queries) UpsertRoleTag(ctx context.Context, db DBTX, arg UpsertRoleTagParams) (RoleTag, error) {
-	row := db.QueryRowContext(ctx, upsertRoleTag, arg.RoleID, arg.TagID)
-	var i RoleTag
-	err := row.Scan(&i.RoleID, &i.TagID)
-	return i, err
-}
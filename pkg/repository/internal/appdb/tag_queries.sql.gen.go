@@ -20,6 +20,34 @@ func (q *Queries) DeleteTag(ctx context.Context, db DBTX, id int64) error {
 	return err
 }
 
+const deleteTagIncludes = `-- name: DeleteTagIncludes :exec
+DELETE FROM tag_includes
+WHERE 
+    "tag_id" = ?
+    AND "included_tag_id" IN (/*SLICE:included_tag_ids*/?)
+`
+
+type DeleteTagIncludesParams struct {
+	TagID          int64
+	IncludedTagIds []int64
+}
+
+func (q *Queries) DeleteTagIncludes(ctx context.Context, db DBTX, arg DeleteTagIncludesParams) error {
+	query := deleteTagIncludes
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.TagID)
+	if len(arg.IncludedTagIds) > 0 {
+		for _, v := range arg.IncludedTagIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:included_tag_ids*/?", strings.Repeat(",?", len(arg.IncludedTagIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:included_tag_ids*/?", "NULL", 1)
+	}
+	_, err := db.ExecContext(ctx, query, queryParams...)
+	return err
+}
+
 const deleteTagSkills = `-- name: DeleteTagSkills :exec
 DELETE FROM tag_skills
 WHERE 
@@ -48,8 +76,37 @@ func (q *Queries) DeleteTagSkills(ctx context.Context, db DBTX, arg DeleteTagSki
 	return err
 }
 
+const getAllTagIncludes = `-- name: GetAllTagIncludes :many
+SELECT tag_id, included_tag_id
+FROM tag_includes
+ORDER BY "tag_id", "included_tag_id"
+`
+
+func (q *Queries) GetAllTagIncludes(ctx context.Context, db DBTX) ([]TagInclude, error) {
+	rows, err := db.QueryContext(ctx, getAllTagIncludes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TagInclude
+	for rows.Next() {
+		var i TagInclude
+		if err := rows.Scan(&i.TagID, &i.IncludedTagID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAllTagSkills = `-- name: GetAllTagSkills :many
-SELECT tag_id, skill_id, skill_level
+SELECT tag_id, skill_id, skill_level, recommended_level
 FROM tag_skills
 WHERE "tag_id" = ?
 ORDER BY "skill_id"
@@ -64,7 +121,12 @@ func (q *Queries) GetAllTagSkills(ctx context.Context, db DBTX, tagID int64) ([]
 	var items []TagSkill
 	for rows.Next() {
 		var i TagSkill
-		if err := rows.Scan(&i.TagID, &i.SkillID, &i.SkillLevel); err != nil {
+		if err := rows.Scan(
+			&i.TagID,
+			&i.SkillID,
+			&i.SkillLevel,
+			&i.RecommendedLevel,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -115,6 +177,51 @@ func (q *Queries) GetAllTags(ctx context.Context, db DBTX) ([]Tag, error) {
 	return items, nil
 }
 
+const getTagSkillsForTags = `-- name: GetTagSkillsForTags :many
+SELECT tag_id, skill_id, skill_level, recommended_level
+FROM tag_skills
+WHERE "tag_id" IN (/*SLICE:tag_ids*/?)
+ORDER BY "tag_id", "skill_id"
+`
+
+func (q *Queries) GetTagSkillsForTags(ctx context.Context, db DBTX, tagIds []int64) ([]TagSkill, error) {
+	query := getTagSkillsForTags
+	var queryParams []interface{}
+	if len(tagIds) > 0 {
+		for _, v := range tagIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tag_ids*/?", strings.Repeat(",?", len(tagIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tag_ids*/?", "NULL", 1)
+	}
+	rows, err := db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TagSkill
+	for rows.Next() {
+		var i TagSkill
+		if err := rows.Scan(
+			&i.TagID,
+			&i.SkillID,
+			&i.SkillLevel,
+			&i.RecommendedLevel,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertTag = `-- name: InsertTag :one
 
 INSERT INTO tags ("name", "color_r", "color_g", "color_b", "color_a")
@@ -182,24 +289,52 @@ func (q *Queries) UpdateTag(ctx context.Context, db DBTX, arg UpdateTagParams) e
 	return err
 }
 
+const upsertTagInclude = `-- name: UpsertTagInclude :exec
+INSERT INTO tag_includes ("tag_id", "included_tag_id")
+VALUES (?, ?)
+ON CONFLICT ("tag_id", "included_tag_id") DO NOTHING
+`
+
+type UpsertTagIncludeParams struct {
+	TagID         int64
+	IncludedTagID int64
+}
+
+func (q *Queries) UpsertTagInclude(ctx context.Context, db DBTX, arg UpsertTagIncludeParams) error {
+	_, err := db.ExecContext(ctx, upsertTagInclude, arg.TagID, arg.IncludedTagID)
+	return err
+}
+
 const upsertTagSkill = `-- name: UpsertTagSkill :one
-INSERT INTO tag_skills ("tag_id", "skill_id", "skill_level")
-VALUES (?, ?, ?)
+INSERT INTO tag_skills ("tag_id", "skill_id", "skill_level", "recommended_level")
+VALUES (?, ?, ?, ?)
 ON CONFLICT ("tag_id", "skill_id") DO UPDATE
 SET
-    "skill_level" = excluded.skill_level
-RETURNING tag_id, skill_id, skill_level
+    "skill_level" = excluded.skill_level,
+    "recommended_level" = excluded.recommended_level
+RETURNING tag_id, skill_id, skill_level, recommended_level
 `
 
 type UpsertTagSkillParams struct {
-	TagID      int64
-	SkillID    int64
-	SkillLevel int64
+	TagID            int64
+	SkillID          int64
+	SkillLevel       int64
+	RecommendedLevel int64
 }
 
 func (q *Queries) UpsertTagSkill(ctx context.Context, db DBTX, arg UpsertTagSkillParams) (TagSkill, error) {
-	row := db.QueryRowContext(ctx, upsertTagSkill, arg.TagID, arg.SkillID, arg.SkillLevel)
+	row := db.QueryRowContext(ctx, upsertTagSkill,
+		arg.TagID,
+		arg.SkillID,
+		arg.SkillLevel,
+		arg.RecommendedLevel,
+	)
 	var i TagSkill
-	err := row.Scan(&i.TagID, &i.SkillID, &i.SkillLevel)
+	err := row.Scan(
+		&i.TagID,
+		&i.SkillID,
+		&i.SkillLevel,
+		&i.RecommendedLevel,
+	)
 	return i, err
 }
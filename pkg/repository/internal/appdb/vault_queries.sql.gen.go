@@ -0,0 +1,138 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: vault_queries.sql
+
+package appdb
+
+import (
+	"context"
+)
+
+const deleteAllTokens = `-- name: DeleteAllTokens :exec
+DELETE FROM tokens
+`
+
+func (q *Queries) DeleteAllTokens(ctx context.Context, db DBTX) error {
+	_, err := db.ExecContext(ctx, deleteAllTokens)
+	return err
+}
+
+const deleteVaultSetting = `-- name: DeleteVaultSetting :exec
+DELETE FROM vault_settings
+`
+
+func (q *Queries) DeleteVaultSetting(ctx context.Context, db DBTX) error {
+	_, err := db.ExecContext(ctx, deleteVaultSetting)
+	return err
+}
+
+const getAllTokens = `-- name: GetAllTokens :many
+SELECT id, character_id, access_token, refresh_token, token_type, expiration
+FROM tokens
+ORDER BY "character_id"
+`
+
+func (q *Queries) GetAllTokens(ctx context.Context, db DBTX) ([]Token, error) {
+	rows, err := db.QueryContext(ctx, getAllTokens)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Token
+	for rows.Next() {
+		var i Token
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.AccessToken,
+			&i.RefreshToken,
+			&i.TokenType,
+			&i.Expiration,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVaultSetting = `-- name: GetVaultSetting :one
+SELECT id, mode, kdf_salt, kdf_time, kdf_memory, kdf_threads, check_value
+FROM vault_settings
+WHERE "id" = 1
+`
+
+func (q *Queries) GetVaultSetting(ctx context.Context, db DBTX) (VaultSetting, error) {
+	row := db.QueryRowContext(ctx, getVaultSetting)
+	var i VaultSetting
+	err := row.Scan(
+		&i.ID,
+		&i.Mode,
+		&i.KdfSalt,
+		&i.KdfTime,
+		&i.KdfMemory,
+		&i.KdfThreads,
+		&i.CheckValue,
+	)
+	return i, err
+}
+
+const updateTokenSecrets = `-- name: UpdateTokenSecrets :exec
+UPDATE tokens
+SET
+    "access_token" = ?,
+    "refresh_token" = ?
+WHERE "character_id" = ?
+`
+
+type UpdateTokenSecretsParams struct {
+	AccessToken  string
+	RefreshToken string
+	CharacterID  int64
+}
+
+func (q *Queries) UpdateTokenSecrets(ctx context.Context, db DBTX, arg UpdateTokenSecretsParams) error {
+	_, err := db.ExecContext(ctx, updateTokenSecrets, arg.AccessToken, arg.RefreshToken, arg.CharacterID)
+	return err
+}
+
+const upsertVaultSetting = `-- name: UpsertVaultSetting :exec
+INSERT INTO vault_settings ("id", "mode", "kdf_salt", "kdf_time", "kdf_memory", "kdf_threads", "check_value")
+VALUES (1, ?, ?, ?, ?, ?, ?)
+ON CONFLICT ("id") DO UPDATE
+SET
+    "mode" = excluded.mode,
+    "kdf_salt" = excluded.kdf_salt,
+    "kdf_time" = excluded.kdf_time,
+    "kdf_memory" = excluded.kdf_memory,
+    "kdf_threads" = excluded.kdf_threads,
+    "check_value" = excluded.check_value
+`
+
+type UpsertVaultSettingParams struct {
+	Mode       string
+	KdfSalt    []byte
+	KdfTime    int64
+	KdfMemory  int64
+	KdfThreads int64
+	CheckValue string
+}
+
+func (q *Queries) UpsertVaultSetting(ctx context.Context, db DBTX, arg UpsertVaultSettingParams) error {
+	_, err := db.ExecContext(ctx, upsertVaultSetting,
+		arg.Mode,
+		arg.KdfSalt,
+		arg.KdfTime,
+		arg.KdfMemory,
+		arg.KdfThreads,
+		arg.CheckValue,
+	)
+	return err
+}
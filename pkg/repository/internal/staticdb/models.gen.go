@@ -8,6 +8,13 @@ import (
 	"database/sql"
 )
 
+type DgmTypeAttribute struct {
+	TypeID      int64
+	AttributeID int64
+	ValueInt    sql.NullInt64
+	ValueFloat  sql.NullFloat64
+}
+
 type SqliteMaster struct {
 	Type     sql.NullString
 	Name     string
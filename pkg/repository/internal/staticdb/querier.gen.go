@@ -10,10 +10,14 @@ import (
 
 type Querier interface {
 	BatchGetSkillNames(ctx context.Context, db DBTX, arg BatchGetSkillNamesParams) ([]BatchGetSkillNamesRow, error)
+	BatchGetSkillRanks(ctx context.Context, db DBTX, skillIds []int64) ([]BatchGetSkillRanksRow, error)
 	CleanTranslations(ctx context.Context, db DBTX) error
+	CleanTypeAttributes(ctx context.Context, db DBTX) error
+	GetRequiredSkills(ctx context.Context, db DBTX, typeID int64) ([]GetRequiredSkillsRow, error)
 	GetSkillIDFromName(ctx context.Context, db DBTX, arg GetSkillIDFromNameParams) (int64, error)
 	GetSkillName(ctx context.Context, db DBTX, arg GetSkillNameParams) (string, error)
 	GetTableNames(ctx context.Context, db DBTX) ([]string, error)
+	GetTypeIDFromName(ctx context.Context, db DBTX, arg GetTypeIDFromNameParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -68,6 +68,109 @@ func (q *Queries) BatchGetSkillNames(ctx context.Context, db DBTX, arg BatchGetS
 	return items, nil
 }
 
+const batchGetSkillRanks = `-- name: BatchGetSkillRanks :many
+;
+
+SELECT
+    "typeID" as skill_id,
+    CAST(COALESCE("valueInt", "valueFloat") AS INTEGER) as skill_rank
+FROM
+    dgmTypeAttributes
+WHERE
+    "attributeID" = 275
+    AND "typeID" IN (/*SLICE:skill_ids*/?)
+`
+
+type BatchGetSkillRanksRow struct {
+	SkillID   int64
+	SkillRank int64
+}
+
+func (q *Queries) BatchGetSkillRanks(ctx context.Context, db DBTX, skillIds []int64) ([]BatchGetSkillRanksRow, error) {
+	query := batchGetSkillRanks
+	var queryParams []interface{}
+	if len(skillIds) > 0 {
+		for _, v := range skillIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:skill_ids*/?", strings.Repeat(",?", len(skillIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:skill_ids*/?", "NULL", 1)
+	}
+	rows, err := db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BatchGetSkillRanksRow
+	for rows.Next() {
+		var i BatchGetSkillRanksRow
+		if err := rows.Scan(&i.SkillID, &i.SkillRank); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRequiredSkills = `-- name: GetRequiredSkills :many
+;
+
+SELECT
+    CAST(COALESCE(skill."valueInt", skill."valueFloat") AS INTEGER) as skill_id,
+    CAST(COALESCE(lvl."valueInt", lvl."valueFloat") AS INTEGER) as skill_level
+FROM
+    dgmTypeAttributes skill
+INNER JOIN dgmTypeAttributes lvl ON
+    lvl."typeID" = skill."typeID"
+    AND lvl."attributeID" = CASE skill."attributeID"
+        WHEN 182 THEN 277
+        WHEN 183 THEN 278
+        WHEN 184 THEN 279
+        WHEN 1285 THEN 1286
+        WHEN 1289 THEN 1287
+        WHEN 1290 THEN 1288
+    END
+WHERE
+    skill."typeID" = ?1
+    AND skill."attributeID" IN (182, 183, 184, 1285, 1289, 1290)
+ORDER BY skill."attributeID"
+`
+
+type GetRequiredSkillsRow struct {
+	SkillID    int64
+	SkillLevel int64
+}
+
+func (q *Queries) GetRequiredSkills(ctx context.Context, db DBTX, typeID int64) ([]GetRequiredSkillsRow, error) {
+	rows, err := db.QueryContext(ctx, getRequiredSkills, typeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRequiredSkillsRow
+	for rows.Next() {
+		var i GetRequiredSkillsRow
+		if err := rows.Scan(&i.SkillID, &i.SkillLevel); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSkillIDFromName = `-- name: GetSkillIDFromName :one
 ;
 
@@ -116,3 +219,29 @@ func (q *Queries) GetSkillName(ctx context.Context, db DBTX, arg GetSkillNamePar
 	err := row.Scan(&skill_name)
 	return skill_name, err
 }
+
+const getTypeIDFromName = `-- name: GetTypeIDFromName :one
+;
+
+SELECT
+    "keyID" as type_id
+FROM
+    trnTranslations
+WHERE
+    "tcID" = 8
+    AND "languageID" = ?1
+    AND LOWER("text") = ?2
+LIMIT 1
+`
+
+type GetTypeIDFromNameParams struct {
+	Language      string
+	TypeNameLower string
+}
+
+func (q *Queries) GetTypeIDFromName(ctx context.Context, db DBTX, arg GetTypeIDFromNameParams) (int64, error) {
+	row := db.QueryRowContext(ctx, getTypeIDFromName, arg.Language, arg.TypeNameLower)
+	var type_id int64
+	err := row.Scan(&type_id)
+	return type_id, err
+}
@@ -31,4 +31,49 @@ WHERE
     AND "languageID" = sqlc.arg(language)
     AND LOWER("text") = sqlc.arg(skill_name_lower)
 LIMIT 1
+;
+
+-- name: GetTypeIDFromName :one
+SELECT
+    "keyID" as type_id
+FROM
+    trnTranslations
+WHERE
+    "tcID" = 8
+    AND "languageID" = sqlc.arg(language)
+    AND LOWER("text") = sqlc.arg(type_name_lower)
+LIMIT 1
+;
+
+-- name: GetRequiredSkills :many
+SELECT
+    CAST(COALESCE(skill."valueInt", skill."valueFloat") AS INTEGER) as skill_id,
+    CAST(COALESCE(lvl."valueInt", lvl."valueFloat") AS INTEGER) as skill_level
+FROM
+    dgmTypeAttributes skill
+INNER JOIN dgmTypeAttributes lvl ON
+    lvl."typeID" = skill."typeID"
+    AND lvl."attributeID" = CASE skill."attributeID"
+        WHEN 182 THEN 277
+        WHEN 183 THEN 278
+        WHEN 184 THEN 279
+        WHEN 1285 THEN 1286
+        WHEN 1289 THEN 1287
+        WHEN 1290 THEN 1288
+    END
+WHERE
+    skill."typeID" = sqlc.arg(type_id)
+    AND skill."attributeID" IN (182, 183, 184, 1285, 1289, 1290)
+ORDER BY skill."attributeID"
+;
+
+-- name: BatchGetSkillRanks :many
+SELECT
+    "typeID" as skill_id,
+    CAST(COALESCE("valueInt", "valueFloat") AS INTEGER) as skill_rank
+FROM
+    dgmTypeAttributes
+WHERE
+    "attributeID" = 275
+    AND "typeID" IN (sqlc.slice(skill_ids))
 ;
\ No newline at end of file
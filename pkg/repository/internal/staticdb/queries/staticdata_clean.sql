@@ -8,4 +8,12 @@ ORDER BY "name";
 
 -- name: CleanTranslations :exec
 DELETE FROM trnTranslations
-WHERE "tcID" != 8;
\ No newline at end of file
+WHERE "tcID" != 8;
+
+-- name: CleanTypeAttributes :exec
+DELETE FROM dgmTypeAttributes
+WHERE "attributeID" NOT IN (
+    182, 183, 184, 1285, 1289, 1290, -- requiredSkill1..6
+    277, 278, 279, 1286, 1287, 1288, -- requiredSkill1Level..6Level
+    275 -- skillTimeConstant (rank)
+);
@@ -19,6 +19,20 @@ func (q *Queries) CleanTranslations(ctx context.Context, db DBTX) error {
 	return err
 }
 
+const cleanTypeAttributes = `-- name: CleanTypeAttributes :exec
+DELETE FROM dgmTypeAttributes
+WHERE "attributeID" NOT IN (
+    182, 183, 184, 1285, 1289, 1290, -- requiredSkill1..6
+    277, 278, 279, 1286, 1287, 1288, -- requiredSkill1Level..6Level
+    275 -- skillTimeConstant (rank)
+)
+`
+
+func (q *Queries) CleanTypeAttributes(ctx context.Context, db DBTX) error {
+	_, err := db.ExecContext(ctx, cleanTypeAttributes)
+	return err
+}
+
 const getTableNames = `-- name: GetTableNames :many
 SELECT 
     "name"
@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/kava-forge/eve-alts/pkg/database"
-	"github.com/kava-forge/eve-alts/pkg/operators"
 	"github.com/kava-forge/eve-alts/pkg/repository"
 	"github.com/kava-forge/eve-alts/pkg/repository/internal/appdb"
 )
@@ -41,31 +40,30 @@ type FakeAppData struct {
 	deleteCharacterSkillsReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteRoleStub        func(context.Context, int64, database.Tx) error
-	deleteRoleMutex       sync.RWMutex
-	deleteRoleArgsForCall []struct {
+	DeleteNotificationStub        func(context.Context, int64, database.Tx) error
+	deleteNotificationMutex       sync.RWMutex
+	deleteNotificationArgsForCall []struct {
 		arg1 context.Context
 		arg2 int64
 		arg3 database.Tx
 	}
-	deleteRoleReturns struct {
+	deleteNotificationReturns struct {
 		result1 error
 	}
-	deleteRoleReturnsOnCall map[int]struct {
+	deleteNotificationReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteRoleTagsStub        func(context.Context, int64, []int64, database.Tx) error
-	deleteRoleTagsMutex       sync.RWMutex
-	deleteRoleTagsArgsForCall []struct {
+	DeleteRoleStub        func(context.Context, int64, database.Tx) error
+	deleteRoleMutex       sync.RWMutex
+	deleteRoleArgsForCall []struct {
 		arg1 context.Context
 		arg2 int64
-		arg3 []int64
-		arg4 database.Tx
+		arg3 database.Tx
 	}
-	deleteRoleTagsReturns struct {
+	deleteRoleReturns struct {
 		result1 error
 	}
-	deleteRoleTagsReturnsOnCall map[int]struct {
+	deleteRoleReturnsOnCall map[int]struct {
 		result1 error
 	}
 	DeleteTagStub        func(context.Context, int64, database.Tx) error
@@ -81,6 +79,20 @@ type FakeAppData struct {
 	deleteTagReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DeleteTagIncludesStub        func(context.Context, int64, []int64, database.Tx) error
+	deleteTagIncludesMutex       sync.RWMutex
+	deleteTagIncludesArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 []int64
+		arg4 database.Tx
+	}
+	deleteTagIncludesReturns struct {
+		result1 error
+	}
+	deleteTagIncludesReturnsOnCall map[int]struct {
+		result1 error
+	}
 	DeleteTagSkillsStub        func(context.Context, int64, []int64, database.Tx) error
 	deleteTagSkillsMutex       sync.RWMutex
 	deleteTagSkillsArgsForCall []struct {
@@ -95,6 +107,15 @@ type FakeAppData struct {
 	deleteTagSkillsReturnsOnCall map[int]struct {
 		result1 error
 	}
+	EventsStub        func() *repository.EventBus
+	eventsMutex       sync.RWMutex
+	eventsArgsForCall []struct{}
+	eventsReturns     struct {
+		result1 *repository.EventBus
+	}
+	eventsReturnsOnCall map[int]struct {
+		result1 *repository.EventBus
+	}
 	GetAllCharacterSkillsStub        func(context.Context, int64, database.Tx) ([]appdb.CharacterSkill, error)
 	getAllCharacterSkillsMutex       sync.RWMutex
 	getAllCharacterSkillsArgsForCall []struct {
@@ -182,6 +203,67 @@ type FakeAppData struct {
 		result1 []*repository.TagDBData
 		result2 error
 	}
+	GetCharacterStub        func(context.Context, int64, database.Tx) (*repository.CharacterDBData, error)
+	getCharacterMutex       sync.RWMutex
+	getCharacterArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 database.Tx
+	}
+	getCharacterReturns struct {
+		result1 *repository.CharacterDBData
+		result2 error
+	}
+	getCharacterReturnsOnCall map[int]struct {
+		result1 *repository.CharacterDBData
+		result2 error
+	}
+	GetDueNotificationsStub        func(context.Context, time.Time, int64, database.Tx) ([]appdb.Notification, error)
+	getDueNotificationsMutex       sync.RWMutex
+	getDueNotificationsArgsForCall []struct {
+		arg1 context.Context
+		arg2 time.Time
+		arg3 int64
+		arg4 database.Tx
+	}
+	getDueNotificationsReturns struct {
+		result1 []appdb.Notification
+		result2 error
+	}
+	getDueNotificationsReturnsOnCall map[int]struct {
+		result1 []appdb.Notification
+		result2 error
+	}
+	GetRoleConditionsStub        func(context.Context, int64, database.Tx) ([]appdb.RoleCondition, error)
+	getRoleConditionsMutex       sync.RWMutex
+	getRoleConditionsArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 database.Tx
+	}
+	getRoleConditionsReturns struct {
+		result1 []appdb.RoleCondition
+		result2 error
+	}
+	getRoleConditionsReturnsOnCall map[int]struct {
+		result1 []appdb.RoleCondition
+		result2 error
+	}
+	GetRoleExprStub        func(context.Context, int64, database.Tx) (*repository.RoleExpr, error)
+	getRoleExprMutex       sync.RWMutex
+	getRoleExprArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 database.Tx
+	}
+	getRoleExprReturns struct {
+		result1 *repository.RoleExpr
+		result2 error
+	}
+	getRoleExprReturnsOnCall map[int]struct {
+		result1 *repository.RoleExpr
+		result2 error
+	}
 	GetTokenForCharacterStub        func(context.Context, int64, database.Tx) (appdb.Token, error)
 	getTokenForCharacterMutex       sync.RWMutex
 	getTokenForCharacterArgsForCall []struct {
@@ -197,15 +279,31 @@ type FakeAppData struct {
 		result1 appdb.Token
 		result2 error
 	}
-	InsertRoleStub        func(context.Context, string, string, operators.Operator, color.Color, database.Tx) (appdb.Role, error)
+	InsertNotificationStub        func(context.Context, string, string, time.Time, database.Tx) (appdb.Notification, error)
+	insertNotificationMutex       sync.RWMutex
+	insertNotificationArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 time.Time
+		arg5 database.Tx
+	}
+	insertNotificationReturns struct {
+		result1 appdb.Notification
+		result2 error
+	}
+	insertNotificationReturnsOnCall map[int]struct {
+		result1 appdb.Notification
+		result2 error
+	}
+	InsertRoleStub        func(context.Context, string, string, color.Color, database.Tx) (appdb.Role, error)
 	insertRoleMutex       sync.RWMutex
 	insertRoleArgsForCall []struct {
 		arg1 context.Context
 		arg2 string
 		arg3 string
-		arg4 operators.Operator
-		arg5 color.Color
-		arg6 database.Tx
+		arg4 color.Color
+		arg5 database.Tx
 	}
 	insertRoleReturns struct {
 		result1 appdb.Role
@@ -231,16 +329,124 @@ type FakeAppData struct {
 		result1 appdb.Tag
 		result2 error
 	}
-	UpdateRoleStub        func(context.Context, int64, string, string, operators.Operator, color.Color, database.Tx) error
+	ReplaceCharacterSkillsStub        func(context.Context, int64, map[int64]int64, database.Tx) ([]appdb.CharacterSkill, error)
+	replaceCharacterSkillsMutex       sync.RWMutex
+	replaceCharacterSkillsArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 map[int64]int64
+		arg4 database.Tx
+	}
+	replaceCharacterSkillsReturns struct {
+		result1 []appdb.CharacterSkill
+		result2 error
+	}
+	replaceCharacterSkillsReturnsOnCall map[int]struct {
+		result1 []appdb.CharacterSkill
+		result2 error
+	}
+	RescheduleNotificationStub        func(context.Context, int64, int64, time.Time, string, database.Tx) error
+	rescheduleNotificationMutex       sync.RWMutex
+	rescheduleNotificationArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 int64
+		arg4 time.Time
+		arg5 string
+		arg6 database.Tx
+	}
+	rescheduleNotificationReturns struct {
+		result1 error
+	}
+	rescheduleNotificationReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ResetVaultStub        func(context.Context) error
+	resetVaultMutex       sync.RWMutex
+	resetVaultArgsForCall []struct {
+		arg1 context.Context
+	}
+	resetVaultReturns struct {
+		result1 error
+	}
+	resetVaultReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RotateVaultStub        func(context.Context, repository.KeySource) error
+	rotateVaultMutex       sync.RWMutex
+	rotateVaultArgsForCall []struct {
+		arg1 context.Context
+		arg2 repository.KeySource
+	}
+	rotateVaultReturns struct {
+		result1 error
+	}
+	rotateVaultReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetRoleConditionsStub        func(context.Context, int64, []appdb.RoleCondition, database.Tx) error
+	setRoleConditionsMutex       sync.RWMutex
+	setRoleConditionsArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 []appdb.RoleCondition
+		arg4 database.Tx
+	}
+	setRoleConditionsReturns struct {
+		result1 error
+	}
+	setRoleConditionsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetRoleExprStub        func(context.Context, int64, *repository.RoleExpr, database.Tx) error
+	setRoleExprMutex       sync.RWMutex
+	setRoleExprArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 *repository.RoleExpr
+		arg4 database.Tx
+	}
+	setRoleExprReturns struct {
+		result1 error
+	}
+	setRoleExprReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UnlockVaultStub        func(context.Context, repository.KeySource) error
+	unlockVaultMutex       sync.RWMutex
+	unlockVaultArgsForCall []struct {
+		arg1 context.Context
+		arg2 repository.KeySource
+	}
+	unlockVaultReturns struct {
+		result1 error
+	}
+	unlockVaultReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateCharacterSkillQueueStub        func(context.Context, int64, int64, database.Tx) error
+	updateCharacterSkillQueueMutex       sync.RWMutex
+	updateCharacterSkillQueueArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 int64
+		arg4 database.Tx
+	}
+	updateCharacterSkillQueueReturns struct {
+		result1 error
+	}
+	updateCharacterSkillQueueReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateRoleStub        func(context.Context, int64, string, string, color.Color, database.Tx) error
 	updateRoleMutex       sync.RWMutex
 	updateRoleArgsForCall []struct {
 		arg1 context.Context
 		arg2 int64
 		arg3 string
 		arg4 string
-		arg5 operators.Operator
-		arg6 color.Color
-		arg7 database.Tx
+		arg5 color.Color
+		arg6 database.Tx
 	}
 	updateRoleReturns struct {
 		result1 error
@@ -281,7 +487,7 @@ type FakeAppData struct {
 		result1 appdb.Alliance
 		result2 error
 	}
-	UpsertCharacterStub        func(context.Context, int64, string, string, int64, database.Tx) (appdb.Character, error)
+	UpsertCharacterStub        func(context.Context, int64, string, string, int64, int64, bool, database.Tx) (appdb.Character, error)
 	upsertCharacterMutex       sync.RWMutex
 	upsertCharacterArgsForCall []struct {
 		arg1 context.Context
@@ -289,7 +495,9 @@ type FakeAppData struct {
 		arg3 string
 		arg4 string
 		arg5 int64
-		arg6 database.Tx
+		arg6 int64
+		arg7 bool
+		arg8 database.Tx
 	}
 	upsertCharacterReturns struct {
 		result1 appdb.Character
@@ -335,30 +543,29 @@ type FakeAppData struct {
 		result1 appdb.Corporation
 		result2 error
 	}
-	UpsertRoleTagStub        func(context.Context, int64, int64, database.Tx) (appdb.RoleTag, error)
-	upsertRoleTagMutex       sync.RWMutex
-	upsertRoleTagArgsForCall []struct {
+	UpsertTagIncludeStub        func(context.Context, int64, int64, database.Tx) error
+	upsertTagIncludeMutex       sync.RWMutex
+	upsertTagIncludeArgsForCall []struct {
 		arg1 context.Context
 		arg2 int64
 		arg3 int64
 		arg4 database.Tx
 	}
-	upsertRoleTagReturns struct {
-		result1 appdb.RoleTag
-		result2 error
+	upsertTagIncludeReturns struct {
+		result1 error
 	}
-	upsertRoleTagReturnsOnCall map[int]struct {
-		result1 appdb.RoleTag
-		result2 error
+	upsertTagIncludeReturnsOnCall map[int]struct {
+		result1 error
 	}
-	UpsertTagSkillStub        func(context.Context, int64, int64, int64, database.Tx) (appdb.TagSkill, error)
+	UpsertTagSkillStub        func(context.Context, int64, int64, int64, int64, database.Tx) (appdb.TagSkill, error)
 	upsertTagSkillMutex       sync.RWMutex
 	upsertTagSkillArgsForCall []struct {
 		arg1 context.Context
 		arg2 int64
 		arg3 int64
 		arg4 int64
-		arg5 database.Tx
+		arg5 int64
+		arg6 database.Tx
 	}
 	upsertTagSkillReturns struct {
 		result1 appdb.TagSkill
@@ -387,6 +594,31 @@ type FakeAppData struct {
 		result1 appdb.Token
 		result2 error
 	}
+	VaultStatusStub        func(context.Context) (repository.VaultStatus, error)
+	vaultStatusMutex       sync.RWMutex
+	vaultStatusArgsForCall []struct {
+		arg1 context.Context
+	}
+	vaultStatusReturns struct {
+		result1 repository.VaultStatus
+		result2 error
+	}
+	vaultStatusReturnsOnCall map[int]struct {
+		result1 repository.VaultStatus
+		result2 error
+	}
+	WatchExternalChangesStub        func(context.Context, time.Duration) error
+	watchExternalChangesMutex       sync.RWMutex
+	watchExternalChangesArgsForCall []struct {
+		arg1 context.Context
+		arg2 time.Duration
+	}
+	watchExternalChangesReturns struct {
+		result1 error
+	}
+	watchExternalChangesReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -523,6 +755,69 @@ func (fake *FakeAppData) DeleteCharacterSkillsReturnsOnCall(i int, result1 error
 	}{result1}
 }
 
+func (fake *FakeAppData) DeleteNotification(arg1 context.Context, arg2 int64, arg3 database.Tx) error {
+	fake.deleteNotificationMutex.Lock()
+	ret, specificReturn := fake.deleteNotificationReturnsOnCall[len(fake.deleteNotificationArgsForCall)]
+	fake.deleteNotificationArgsForCall = append(fake.deleteNotificationArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 database.Tx
+	}{arg1, arg2, arg3})
+	stub := fake.DeleteNotificationStub
+	fakeReturns := fake.deleteNotificationReturns
+	fake.recordInvocation("DeleteNotification", []interface{}{arg1, arg2, arg3})
+	fake.deleteNotificationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAppData) DeleteNotificationCallCount() int {
+	fake.deleteNotificationMutex.RLock()
+	defer fake.deleteNotificationMutex.RUnlock()
+	return len(fake.deleteNotificationArgsForCall)
+}
+
+func (fake *FakeAppData) DeleteNotificationCalls(stub func(context.Context, int64, database.Tx) error) {
+	fake.deleteNotificationMutex.Lock()
+	defer fake.deleteNotificationMutex.Unlock()
+	fake.DeleteNotificationStub = stub
+}
+
+func (fake *FakeAppData) DeleteNotificationArgsForCall(i int) (context.Context, int64, database.Tx) {
+	fake.deleteNotificationMutex.RLock()
+	defer fake.deleteNotificationMutex.RUnlock()
+	argsForCall := fake.deleteNotificationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAppData) DeleteNotificationReturns(result1 error) {
+	fake.deleteNotificationMutex.Lock()
+	defer fake.deleteNotificationMutex.Unlock()
+	fake.DeleteNotificationStub = nil
+	fake.deleteNotificationReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAppData) DeleteNotificationReturnsOnCall(i int, result1 error) {
+	fake.deleteNotificationMutex.Lock()
+	defer fake.deleteNotificationMutex.Unlock()
+	fake.DeleteNotificationStub = nil
+	if fake.deleteNotificationReturnsOnCall == nil {
+		fake.deleteNotificationReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteNotificationReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeAppData) DeleteRole(arg1 context.Context, arg2 int64, arg3 database.Tx) error {
 	fake.deleteRoleMutex.Lock()
 	ret, specificReturn := fake.deleteRoleReturnsOnCall[len(fake.deleteRoleArgsForCall)]
@@ -586,75 +881,6 @@ func (fake *FakeAppData) DeleteRoleReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeAppData) DeleteRoleTags(arg1 context.Context, arg2 int64, arg3 []int64, arg4 database.Tx) error {
-	var arg3Copy []int64
-	if arg3 != nil {
-		arg3Copy = make([]int64, len(arg3))
-		copy(arg3Copy, arg3)
-	}
-	fake.deleteRoleTagsMutex.Lock()
-	ret, specificReturn := fake.deleteRoleTagsReturnsOnCall[len(fake.deleteRoleTagsArgsForCall)]
-	fake.deleteRoleTagsArgsForCall = append(fake.deleteRoleTagsArgsForCall, struct {
-		arg1 context.Context
-		arg2 int64
-		arg3 []int64
-		arg4 database.Tx
-	}{arg1, arg2, arg3Copy, arg4})
-	stub := fake.DeleteRoleTagsStub
-	fakeReturns := fake.deleteRoleTagsReturns
-	fake.recordInvocation("DeleteRoleTags", []interface{}{arg1, arg2, arg3Copy, arg4})
-	fake.deleteRoleTagsMutex.Unlock()
-	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4)
-	}
-	if specificReturn {
-		return ret.result1
-	}
-	return fakeReturns.result1
-}
-
-func (fake *FakeAppData) DeleteRoleTagsCallCount() int {
-	fake.deleteRoleTagsMutex.RLock()
-	defer fake.deleteRoleTagsMutex.RUnlock()
-	return len(fake.deleteRoleTagsArgsForCall)
-}
-
-func (fake *FakeAppData) DeleteRoleTagsCalls(stub func(context.Context, int64, []int64, database.Tx) error) {
-	fake.deleteRoleTagsMutex.Lock()
-	defer fake.deleteRoleTagsMutex.Unlock()
-	fake.DeleteRoleTagsStub = stub
-}
-
-func (fake *FakeAppData) DeleteRoleTagsArgsForCall(i int) (context.Context, int64, []int64, database.Tx) {
-	fake.deleteRoleTagsMutex.RLock()
-	defer fake.deleteRoleTagsMutex.RUnlock()
-	argsForCall := fake.deleteRoleTagsArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
-}
-
-func (fake *FakeAppData) DeleteRoleTagsReturns(result1 error) {
-	fake.deleteRoleTagsMutex.Lock()
-	defer fake.deleteRoleTagsMutex.Unlock()
-	fake.DeleteRoleTagsStub = nil
-	fake.deleteRoleTagsReturns = struct {
-		result1 error
-	}{result1}
-}
-
-func (fake *FakeAppData) DeleteRoleTagsReturnsOnCall(i int, result1 error) {
-	fake.deleteRoleTagsMutex.Lock()
-	defer fake.deleteRoleTagsMutex.Unlock()
-	fake.DeleteRoleTagsStub = nil
-	if fake.deleteRoleTagsReturnsOnCall == nil {
-		fake.deleteRoleTagsReturnsOnCall = make(map[int]struct {
-			result1 error
-		})
-	}
-	fake.deleteRoleTagsReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
-}
-
 func (fake *FakeAppData) DeleteTag(arg1 context.Context, arg2 int64, arg3 database.Tx) error {
 	fake.deleteTagMutex.Lock()
 	ret, specificReturn := fake.deleteTagReturnsOnCall[len(fake.deleteTagArgsForCall)]
@@ -718,24 +944,24 @@ func (fake *FakeAppData) DeleteTagReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeAppData) DeleteTagSkills(arg1 context.Context, arg2 int64, arg3 []int64, arg4 database.Tx) error {
+func (fake *FakeAppData) DeleteTagIncludes(arg1 context.Context, arg2 int64, arg3 []int64, arg4 database.Tx) error {
 	var arg3Copy []int64
 	if arg3 != nil {
 		arg3Copy = make([]int64, len(arg3))
 		copy(arg3Copy, arg3)
 	}
-	fake.deleteTagSkillsMutex.Lock()
-	ret, specificReturn := fake.deleteTagSkillsReturnsOnCall[len(fake.deleteTagSkillsArgsForCall)]
-	fake.deleteTagSkillsArgsForCall = append(fake.deleteTagSkillsArgsForCall, struct {
+	fake.deleteTagIncludesMutex.Lock()
+	ret, specificReturn := fake.deleteTagIncludesReturnsOnCall[len(fake.deleteTagIncludesArgsForCall)]
+	fake.deleteTagIncludesArgsForCall = append(fake.deleteTagIncludesArgsForCall, struct {
 		arg1 context.Context
 		arg2 int64
 		arg3 []int64
 		arg4 database.Tx
 	}{arg1, arg2, arg3Copy, arg4})
-	stub := fake.DeleteTagSkillsStub
-	fakeReturns := fake.deleteTagSkillsReturns
-	fake.recordInvocation("DeleteTagSkills", []interface{}{arg1, arg2, arg3Copy, arg4})
-	fake.deleteTagSkillsMutex.Unlock()
+	stub := fake.DeleteTagIncludesStub
+	fakeReturns := fake.deleteTagIncludesReturns
+	fake.recordInvocation("DeleteTagIncludes", []interface{}{arg1, arg2, arg3Copy, arg4})
+	fake.deleteTagIncludesMutex.Unlock()
 	if stub != nil {
 		return stub(arg1, arg2, arg3, arg4)
 	}
@@ -745,48 +971,169 @@ func (fake *FakeAppData) DeleteTagSkills(arg1 context.Context, arg2 int64, arg3
 	return fakeReturns.result1
 }
 
-func (fake *FakeAppData) DeleteTagSkillsCallCount() int {
-	fake.deleteTagSkillsMutex.RLock()
-	defer fake.deleteTagSkillsMutex.RUnlock()
-	return len(fake.deleteTagSkillsArgsForCall)
+func (fake *FakeAppData) DeleteTagIncludesCallCount() int {
+	fake.deleteTagIncludesMutex.RLock()
+	defer fake.deleteTagIncludesMutex.RUnlock()
+	return len(fake.deleteTagIncludesArgsForCall)
 }
 
-func (fake *FakeAppData) DeleteTagSkillsCalls(stub func(context.Context, int64, []int64, database.Tx) error) {
-	fake.deleteTagSkillsMutex.Lock()
-	defer fake.deleteTagSkillsMutex.Unlock()
-	fake.DeleteTagSkillsStub = stub
+func (fake *FakeAppData) DeleteTagIncludesCalls(stub func(context.Context, int64, []int64, database.Tx) error) {
+	fake.deleteTagIncludesMutex.Lock()
+	defer fake.deleteTagIncludesMutex.Unlock()
+	fake.DeleteTagIncludesStub = stub
 }
 
-func (fake *FakeAppData) DeleteTagSkillsArgsForCall(i int) (context.Context, int64, []int64, database.Tx) {
-	fake.deleteTagSkillsMutex.RLock()
-	defer fake.deleteTagSkillsMutex.RUnlock()
-	argsForCall := fake.deleteTagSkillsArgsForCall[i]
+func (fake *FakeAppData) DeleteTagIncludesArgsForCall(i int) (context.Context, int64, []int64, database.Tx) {
+	fake.deleteTagIncludesMutex.RLock()
+	defer fake.deleteTagIncludesMutex.RUnlock()
+	argsForCall := fake.deleteTagIncludesArgsForCall[i]
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
-func (fake *FakeAppData) DeleteTagSkillsReturns(result1 error) {
-	fake.deleteTagSkillsMutex.Lock()
-	defer fake.deleteTagSkillsMutex.Unlock()
-	fake.DeleteTagSkillsStub = nil
-	fake.deleteTagSkillsReturns = struct {
+func (fake *FakeAppData) DeleteTagIncludesReturns(result1 error) {
+	fake.deleteTagIncludesMutex.Lock()
+	defer fake.deleteTagIncludesMutex.Unlock()
+	fake.DeleteTagIncludesStub = nil
+	fake.deleteTagIncludesReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeAppData) DeleteTagSkillsReturnsOnCall(i int, result1 error) {
-	fake.deleteTagSkillsMutex.Lock()
-	defer fake.deleteTagSkillsMutex.Unlock()
-	fake.DeleteTagSkillsStub = nil
-	if fake.deleteTagSkillsReturnsOnCall == nil {
-		fake.deleteTagSkillsReturnsOnCall = make(map[int]struct {
+func (fake *FakeAppData) DeleteTagIncludesReturnsOnCall(i int, result1 error) {
+	fake.deleteTagIncludesMutex.Lock()
+	defer fake.deleteTagIncludesMutex.Unlock()
+	fake.DeleteTagIncludesStub = nil
+	if fake.deleteTagIncludesReturnsOnCall == nil {
+		fake.deleteTagIncludesReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.deleteTagSkillsReturnsOnCall[i] = struct {
+	fake.deleteTagIncludesReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
 
+func (fake *FakeAppData) DeleteTagSkills(arg1 context.Context, arg2 int64, arg3 []int64, arg4 database.Tx) error {
+	var arg3Copy []int64
+	if arg3 != nil {
+		arg3Copy = make([]int64, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.deleteTagSkillsMutex.Lock()
+	ret, specificReturn := fake.deleteTagSkillsReturnsOnCall[len(fake.deleteTagSkillsArgsForCall)]
+	fake.deleteTagSkillsArgsForCall = append(fake.deleteTagSkillsArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 []int64
+		arg4 database.Tx
+	}{arg1, arg2, arg3Copy, arg4})
+	stub := fake.DeleteTagSkillsStub
+	fakeReturns := fake.deleteTagSkillsReturns
+	fake.recordInvocation("DeleteTagSkills", []interface{}{arg1, arg2, arg3Copy, arg4})
+	fake.deleteTagSkillsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAppData) DeleteTagSkillsCallCount() int {
+	fake.deleteTagSkillsMutex.RLock()
+	defer fake.deleteTagSkillsMutex.RUnlock()
+	return len(fake.deleteTagSkillsArgsForCall)
+}
+
+func (fake *FakeAppData) DeleteTagSkillsCalls(stub func(context.Context, int64, []int64, database.Tx) error) {
+	fake.deleteTagSkillsMutex.Lock()
+	defer fake.deleteTagSkillsMutex.Unlock()
+	fake.DeleteTagSkillsStub = stub
+}
+
+func (fake *FakeAppData) DeleteTagSkillsArgsForCall(i int) (context.Context, int64, []int64, database.Tx) {
+	fake.deleteTagSkillsMutex.RLock()
+	defer fake.deleteTagSkillsMutex.RUnlock()
+	argsForCall := fake.deleteTagSkillsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeAppData) DeleteTagSkillsReturns(result1 error) {
+	fake.deleteTagSkillsMutex.Lock()
+	defer fake.deleteTagSkillsMutex.Unlock()
+	fake.DeleteTagSkillsStub = nil
+	fake.deleteTagSkillsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAppData) DeleteTagSkillsReturnsOnCall(i int, result1 error) {
+	fake.deleteTagSkillsMutex.Lock()
+	defer fake.deleteTagSkillsMutex.Unlock()
+	fake.DeleteTagSkillsStub = nil
+	if fake.deleteTagSkillsReturnsOnCall == nil {
+		fake.deleteTagSkillsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteTagSkillsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAppData) Events() *repository.EventBus {
+	fake.eventsMutex.Lock()
+	ret, specificReturn := fake.eventsReturnsOnCall[len(fake.eventsArgsForCall)]
+	fake.eventsArgsForCall = append(fake.eventsArgsForCall, struct{}{})
+	stub := fake.EventsStub
+	fakeReturns := fake.eventsReturns
+	fake.recordInvocation("Events", []interface{}{})
+	fake.eventsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAppData) EventsCallCount() int {
+	fake.eventsMutex.RLock()
+	defer fake.eventsMutex.RUnlock()
+	return len(fake.eventsArgsForCall)
+}
+
+func (fake *FakeAppData) EventsCalls(stub func() *repository.EventBus) {
+	fake.eventsMutex.Lock()
+	defer fake.eventsMutex.Unlock()
+	fake.EventsStub = stub
+}
+
+func (fake *FakeAppData) EventsReturns(result1 *repository.EventBus) {
+	fake.eventsMutex.Lock()
+	defer fake.eventsMutex.Unlock()
+	fake.EventsStub = nil
+	fake.eventsReturns = struct {
+		result1 *repository.EventBus
+	}{result1}
+}
+
+func (fake *FakeAppData) EventsReturnsOnCall(i int, result1 *repository.EventBus) {
+	fake.eventsMutex.Lock()
+	defer fake.eventsMutex.Unlock()
+	fake.EventsStub = nil
+	if fake.eventsReturnsOnCall == nil {
+		fake.eventsReturnsOnCall = make(map[int]struct {
+			result1 *repository.EventBus
+		})
+	}
+	fake.eventsReturnsOnCall[i] = struct {
+		result1 *repository.EventBus
+	}{result1}
+}
+
 func (fake *FakeAppData) GetAllCharacterSkills(arg1 context.Context, arg2 int64, arg3 database.Tx) ([]appdb.CharacterSkill, error) {
 	fake.getAllCharacterSkillsMutex.Lock()
 	ret, specificReturn := fake.getAllCharacterSkillsReturnsOnCall[len(fake.getAllCharacterSkillsArgsForCall)]
@@ -1180,209 +1527,1056 @@ func (fake *FakeAppData) GetAllTagsReturnsOnCall(i int, result1 []*repository.Ta
 	}{result1, result2}
 }
 
+func (fake *FakeAppData) GetCharacter(arg1 context.Context, arg2 int64, arg3 database.Tx) (*repository.CharacterDBData, error) {
+	fake.getCharacterMutex.Lock()
+	ret, specificReturn := fake.getCharacterReturnsOnCall[len(fake.getCharacterArgsForCall)]
+	fake.getCharacterArgsForCall = append(fake.getCharacterArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 database.Tx
+	}{arg1, arg2, arg3})
+	stub := fake.GetCharacterStub
+	fakeReturns := fake.getCharacterReturns
+	fake.recordInvocation("GetCharacter", []interface{}{arg1, arg2, arg3})
+	fake.getCharacterMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAppData) GetCharacterCallCount() int {
+	fake.getCharacterMutex.RLock()
+	defer fake.getCharacterMutex.RUnlock()
+	return len(fake.getCharacterArgsForCall)
+}
+
+func (fake *FakeAppData) GetCharacterCalls(stub func(context.Context, int64, database.Tx) (*repository.CharacterDBData, error)) {
+	fake.getCharacterMutex.Lock()
+	defer fake.getCharacterMutex.Unlock()
+	fake.GetCharacterStub = stub
+}
+
+func (fake *FakeAppData) GetCharacterArgsForCall(i int) (context.Context, int64, database.Tx) {
+	fake.getCharacterMutex.RLock()
+	defer fake.getCharacterMutex.RUnlock()
+	argsForCall := fake.getCharacterArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAppData) GetCharacterReturns(result1 *repository.CharacterDBData, result2 error) {
+	fake.getCharacterMutex.Lock()
+	defer fake.getCharacterMutex.Unlock()
+	fake.GetCharacterStub = nil
+	fake.getCharacterReturns = struct {
+		result1 *repository.CharacterDBData
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) GetCharacterReturnsOnCall(i int, result1 *repository.CharacterDBData, result2 error) {
+	fake.getCharacterMutex.Lock()
+	defer fake.getCharacterMutex.Unlock()
+	fake.GetCharacterStub = nil
+	if fake.getCharacterReturnsOnCall == nil {
+		fake.getCharacterReturnsOnCall = make(map[int]struct {
+			result1 *repository.CharacterDBData
+			result2 error
+		})
+	}
+	fake.getCharacterReturnsOnCall[i] = struct {
+		result1 *repository.CharacterDBData
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) GetDueNotifications(arg1 context.Context, arg2 time.Time, arg3 int64, arg4 database.Tx) ([]appdb.Notification, error) {
+	fake.getDueNotificationsMutex.Lock()
+	ret, specificReturn := fake.getDueNotificationsReturnsOnCall[len(fake.getDueNotificationsArgsForCall)]
+	fake.getDueNotificationsArgsForCall = append(fake.getDueNotificationsArgsForCall, struct {
+		arg1 context.Context
+		arg2 time.Time
+		arg3 int64
+		arg4 database.Tx
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.GetDueNotificationsStub
+	fakeReturns := fake.getDueNotificationsReturns
+	fake.recordInvocation("GetDueNotifications", []interface{}{arg1, arg2, arg3, arg4})
+	fake.getDueNotificationsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAppData) GetDueNotificationsCallCount() int {
+	fake.getDueNotificationsMutex.RLock()
+	defer fake.getDueNotificationsMutex.RUnlock()
+	return len(fake.getDueNotificationsArgsForCall)
+}
+
+func (fake *FakeAppData) GetDueNotificationsCalls(stub func(context.Context, time.Time, int64, database.Tx) ([]appdb.Notification, error)) {
+	fake.getDueNotificationsMutex.Lock()
+	defer fake.getDueNotificationsMutex.Unlock()
+	fake.GetDueNotificationsStub = stub
+}
+
+func (fake *FakeAppData) GetDueNotificationsArgsForCall(i int) (context.Context, time.Time, int64, database.Tx) {
+	fake.getDueNotificationsMutex.RLock()
+	defer fake.getDueNotificationsMutex.RUnlock()
+	argsForCall := fake.getDueNotificationsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeAppData) GetDueNotificationsReturns(result1 []appdb.Notification, result2 error) {
+	fake.getDueNotificationsMutex.Lock()
+	defer fake.getDueNotificationsMutex.Unlock()
+	fake.GetDueNotificationsStub = nil
+	fake.getDueNotificationsReturns = struct {
+		result1 []appdb.Notification
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) GetDueNotificationsReturnsOnCall(i int, result1 []appdb.Notification, result2 error) {
+	fake.getDueNotificationsMutex.Lock()
+	defer fake.getDueNotificationsMutex.Unlock()
+	fake.GetDueNotificationsStub = nil
+	if fake.getDueNotificationsReturnsOnCall == nil {
+		fake.getDueNotificationsReturnsOnCall = make(map[int]struct {
+			result1 []appdb.Notification
+			result2 error
+		})
+	}
+	fake.getDueNotificationsReturnsOnCall[i] = struct {
+		result1 []appdb.Notification
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) GetRoleConditions(arg1 context.Context, arg2 int64, arg3 database.Tx) ([]appdb.RoleCondition, error) {
+	fake.getRoleConditionsMutex.Lock()
+	ret, specificReturn := fake.getRoleConditionsReturnsOnCall[len(fake.getRoleConditionsArgsForCall)]
+	fake.getRoleConditionsArgsForCall = append(fake.getRoleConditionsArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 database.Tx
+	}{arg1, arg2, arg3})
+	stub := fake.GetRoleConditionsStub
+	fakeReturns := fake.getRoleConditionsReturns
+	fake.recordInvocation("GetRoleConditions", []interface{}{arg1, arg2, arg3})
+	fake.getRoleConditionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAppData) GetRoleConditionsCallCount() int {
+	fake.getRoleConditionsMutex.RLock()
+	defer fake.getRoleConditionsMutex.RUnlock()
+	return len(fake.getRoleConditionsArgsForCall)
+}
+
+func (fake *FakeAppData) GetRoleConditionsCalls(stub func(context.Context, int64, database.Tx) ([]appdb.RoleCondition, error)) {
+	fake.getRoleConditionsMutex.Lock()
+	defer fake.getRoleConditionsMutex.Unlock()
+	fake.GetRoleConditionsStub = stub
+}
+
+func (fake *FakeAppData) GetRoleConditionsArgsForCall(i int) (context.Context, int64, database.Tx) {
+	fake.getRoleConditionsMutex.RLock()
+	defer fake.getRoleConditionsMutex.RUnlock()
+	argsForCall := fake.getRoleConditionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAppData) GetRoleConditionsReturns(result1 []appdb.RoleCondition, result2 error) {
+	fake.getRoleConditionsMutex.Lock()
+	defer fake.getRoleConditionsMutex.Unlock()
+	fake.GetRoleConditionsStub = nil
+	fake.getRoleConditionsReturns = struct {
+		result1 []appdb.RoleCondition
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) GetRoleConditionsReturnsOnCall(i int, result1 []appdb.RoleCondition, result2 error) {
+	fake.getRoleConditionsMutex.Lock()
+	defer fake.getRoleConditionsMutex.Unlock()
+	fake.GetRoleConditionsStub = nil
+	if fake.getRoleConditionsReturnsOnCall == nil {
+		fake.getRoleConditionsReturnsOnCall = make(map[int]struct {
+			result1 []appdb.RoleCondition
+			result2 error
+		})
+	}
+	fake.getRoleConditionsReturnsOnCall[i] = struct {
+		result1 []appdb.RoleCondition
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) GetRoleExpr(arg1 context.Context, arg2 int64, arg3 database.Tx) (*repository.RoleExpr, error) {
+	fake.getRoleExprMutex.Lock()
+	ret, specificReturn := fake.getRoleExprReturnsOnCall[len(fake.getRoleExprArgsForCall)]
+	fake.getRoleExprArgsForCall = append(fake.getRoleExprArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 database.Tx
+	}{arg1, arg2, arg3})
+	stub := fake.GetRoleExprStub
+	fakeReturns := fake.getRoleExprReturns
+	fake.recordInvocation("GetRoleExpr", []interface{}{arg1, arg2, arg3})
+	fake.getRoleExprMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAppData) GetRoleExprCallCount() int {
+	fake.getRoleExprMutex.RLock()
+	defer fake.getRoleExprMutex.RUnlock()
+	return len(fake.getRoleExprArgsForCall)
+}
+
+func (fake *FakeAppData) GetRoleExprCalls(stub func(context.Context, int64, database.Tx) (*repository.RoleExpr, error)) {
+	fake.getRoleExprMutex.Lock()
+	defer fake.getRoleExprMutex.Unlock()
+	fake.GetRoleExprStub = stub
+}
+
+func (fake *FakeAppData) GetRoleExprArgsForCall(i int) (context.Context, int64, database.Tx) {
+	fake.getRoleExprMutex.RLock()
+	defer fake.getRoleExprMutex.RUnlock()
+	argsForCall := fake.getRoleExprArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAppData) GetRoleExprReturns(result1 *repository.RoleExpr, result2 error) {
+	fake.getRoleExprMutex.Lock()
+	defer fake.getRoleExprMutex.Unlock()
+	fake.GetRoleExprStub = nil
+	fake.getRoleExprReturns = struct {
+		result1 *repository.RoleExpr
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) GetRoleExprReturnsOnCall(i int, result1 *repository.RoleExpr, result2 error) {
+	fake.getRoleExprMutex.Lock()
+	defer fake.getRoleExprMutex.Unlock()
+	fake.GetRoleExprStub = nil
+	if fake.getRoleExprReturnsOnCall == nil {
+		fake.getRoleExprReturnsOnCall = make(map[int]struct {
+			result1 *repository.RoleExpr
+			result2 error
+		})
+	}
+	fake.getRoleExprReturnsOnCall[i] = struct {
+		result1 *repository.RoleExpr
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeAppData) GetTokenForCharacter(arg1 context.Context, arg2 int64, arg3 database.Tx) (appdb.Token, error) {
 	fake.getTokenForCharacterMutex.Lock()
 	ret, specificReturn := fake.getTokenForCharacterReturnsOnCall[len(fake.getTokenForCharacterArgsForCall)]
 	fake.getTokenForCharacterArgsForCall = append(fake.getTokenForCharacterArgsForCall, struct {
 		arg1 context.Context
 		arg2 int64
-		arg3 database.Tx
-	}{arg1, arg2, arg3})
-	stub := fake.GetTokenForCharacterStub
-	fakeReturns := fake.getTokenForCharacterReturns
-	fake.recordInvocation("GetTokenForCharacter", []interface{}{arg1, arg2, arg3})
-	fake.getTokenForCharacterMutex.Unlock()
+		arg3 database.Tx
+	}{arg1, arg2, arg3})
+	stub := fake.GetTokenForCharacterStub
+	fakeReturns := fake.getTokenForCharacterReturns
+	fake.recordInvocation("GetTokenForCharacter", []interface{}{arg1, arg2, arg3})
+	fake.getTokenForCharacterMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAppData) GetTokenForCharacterCallCount() int {
+	fake.getTokenForCharacterMutex.RLock()
+	defer fake.getTokenForCharacterMutex.RUnlock()
+	return len(fake.getTokenForCharacterArgsForCall)
+}
+
+func (fake *FakeAppData) GetTokenForCharacterCalls(stub func(context.Context, int64, database.Tx) (appdb.Token, error)) {
+	fake.getTokenForCharacterMutex.Lock()
+	defer fake.getTokenForCharacterMutex.Unlock()
+	fake.GetTokenForCharacterStub = stub
+}
+
+func (fake *FakeAppData) GetTokenForCharacterArgsForCall(i int) (context.Context, int64, database.Tx) {
+	fake.getTokenForCharacterMutex.RLock()
+	defer fake.getTokenForCharacterMutex.RUnlock()
+	argsForCall := fake.getTokenForCharacterArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAppData) GetTokenForCharacterReturns(result1 appdb.Token, result2 error) {
+	fake.getTokenForCharacterMutex.Lock()
+	defer fake.getTokenForCharacterMutex.Unlock()
+	fake.GetTokenForCharacterStub = nil
+	fake.getTokenForCharacterReturns = struct {
+		result1 appdb.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) GetTokenForCharacterReturnsOnCall(i int, result1 appdb.Token, result2 error) {
+	fake.getTokenForCharacterMutex.Lock()
+	defer fake.getTokenForCharacterMutex.Unlock()
+	fake.GetTokenForCharacterStub = nil
+	if fake.getTokenForCharacterReturnsOnCall == nil {
+		fake.getTokenForCharacterReturnsOnCall = make(map[int]struct {
+			result1 appdb.Token
+			result2 error
+		})
+	}
+	fake.getTokenForCharacterReturnsOnCall[i] = struct {
+		result1 appdb.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) InsertNotification(arg1 context.Context, arg2 string, arg3 string, arg4 time.Time, arg5 database.Tx) (appdb.Notification, error) {
+	fake.insertNotificationMutex.Lock()
+	ret, specificReturn := fake.insertNotificationReturnsOnCall[len(fake.insertNotificationArgsForCall)]
+	fake.insertNotificationArgsForCall = append(fake.insertNotificationArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 time.Time
+		arg5 database.Tx
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.InsertNotificationStub
+	fakeReturns := fake.insertNotificationReturns
+	fake.recordInvocation("InsertNotification", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.insertNotificationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAppData) InsertNotificationCallCount() int {
+	fake.insertNotificationMutex.RLock()
+	defer fake.insertNotificationMutex.RUnlock()
+	return len(fake.insertNotificationArgsForCall)
+}
+
+func (fake *FakeAppData) InsertNotificationCalls(stub func(context.Context, string, string, time.Time, database.Tx) (appdb.Notification, error)) {
+	fake.insertNotificationMutex.Lock()
+	defer fake.insertNotificationMutex.Unlock()
+	fake.InsertNotificationStub = stub
+}
+
+func (fake *FakeAppData) InsertNotificationArgsForCall(i int) (context.Context, string, string, time.Time, database.Tx) {
+	fake.insertNotificationMutex.RLock()
+	defer fake.insertNotificationMutex.RUnlock()
+	argsForCall := fake.insertNotificationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeAppData) InsertNotificationReturns(result1 appdb.Notification, result2 error) {
+	fake.insertNotificationMutex.Lock()
+	defer fake.insertNotificationMutex.Unlock()
+	fake.InsertNotificationStub = nil
+	fake.insertNotificationReturns = struct {
+		result1 appdb.Notification
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) InsertNotificationReturnsOnCall(i int, result1 appdb.Notification, result2 error) {
+	fake.insertNotificationMutex.Lock()
+	defer fake.insertNotificationMutex.Unlock()
+	fake.InsertNotificationStub = nil
+	if fake.insertNotificationReturnsOnCall == nil {
+		fake.insertNotificationReturnsOnCall = make(map[int]struct {
+			result1 appdb.Notification
+			result2 error
+		})
+	}
+	fake.insertNotificationReturnsOnCall[i] = struct {
+		result1 appdb.Notification
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) InsertRole(arg1 context.Context, arg2 string, arg3 string, arg4 color.Color, arg5 database.Tx) (appdb.Role, error) {
+	fake.insertRoleMutex.Lock()
+	ret, specificReturn := fake.insertRoleReturnsOnCall[len(fake.insertRoleArgsForCall)]
+	fake.insertRoleArgsForCall = append(fake.insertRoleArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 color.Color
+		arg5 database.Tx
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.InsertRoleStub
+	fakeReturns := fake.insertRoleReturns
+	fake.recordInvocation("InsertRole", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.insertRoleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAppData) InsertRoleCallCount() int {
+	fake.insertRoleMutex.RLock()
+	defer fake.insertRoleMutex.RUnlock()
+	return len(fake.insertRoleArgsForCall)
+}
+
+func (fake *FakeAppData) InsertRoleCalls(stub func(context.Context, string, string, color.Color, database.Tx) (appdb.Role, error)) {
+	fake.insertRoleMutex.Lock()
+	defer fake.insertRoleMutex.Unlock()
+	fake.InsertRoleStub = stub
+}
+
+func (fake *FakeAppData) InsertRoleArgsForCall(i int) (context.Context, string, string, color.Color, database.Tx) {
+	fake.insertRoleMutex.RLock()
+	defer fake.insertRoleMutex.RUnlock()
+	argsForCall := fake.insertRoleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeAppData) InsertRoleReturns(result1 appdb.Role, result2 error) {
+	fake.insertRoleMutex.Lock()
+	defer fake.insertRoleMutex.Unlock()
+	fake.InsertRoleStub = nil
+	fake.insertRoleReturns = struct {
+		result1 appdb.Role
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) InsertRoleReturnsOnCall(i int, result1 appdb.Role, result2 error) {
+	fake.insertRoleMutex.Lock()
+	defer fake.insertRoleMutex.Unlock()
+	fake.InsertRoleStub = nil
+	if fake.insertRoleReturnsOnCall == nil {
+		fake.insertRoleReturnsOnCall = make(map[int]struct {
+			result1 appdb.Role
+			result2 error
+		})
+	}
+	fake.insertRoleReturnsOnCall[i] = struct {
+		result1 appdb.Role
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) InsertTag(arg1 context.Context, arg2 string, arg3 color.Color, arg4 database.Tx) (appdb.Tag, error) {
+	fake.insertTagMutex.Lock()
+	ret, specificReturn := fake.insertTagReturnsOnCall[len(fake.insertTagArgsForCall)]
+	fake.insertTagArgsForCall = append(fake.insertTagArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 color.Color
+		arg4 database.Tx
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.InsertTagStub
+	fakeReturns := fake.insertTagReturns
+	fake.recordInvocation("InsertTag", []interface{}{arg1, arg2, arg3, arg4})
+	fake.insertTagMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAppData) InsertTagCallCount() int {
+	fake.insertTagMutex.RLock()
+	defer fake.insertTagMutex.RUnlock()
+	return len(fake.insertTagArgsForCall)
+}
+
+func (fake *FakeAppData) InsertTagCalls(stub func(context.Context, string, color.Color, database.Tx) (appdb.Tag, error)) {
+	fake.insertTagMutex.Lock()
+	defer fake.insertTagMutex.Unlock()
+	fake.InsertTagStub = stub
+}
+
+func (fake *FakeAppData) InsertTagArgsForCall(i int) (context.Context, string, color.Color, database.Tx) {
+	fake.insertTagMutex.RLock()
+	defer fake.insertTagMutex.RUnlock()
+	argsForCall := fake.insertTagArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeAppData) InsertTagReturns(result1 appdb.Tag, result2 error) {
+	fake.insertTagMutex.Lock()
+	defer fake.insertTagMutex.Unlock()
+	fake.InsertTagStub = nil
+	fake.insertTagReturns = struct {
+		result1 appdb.Tag
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) InsertTagReturnsOnCall(i int, result1 appdb.Tag, result2 error) {
+	fake.insertTagMutex.Lock()
+	defer fake.insertTagMutex.Unlock()
+	fake.InsertTagStub = nil
+	if fake.insertTagReturnsOnCall == nil {
+		fake.insertTagReturnsOnCall = make(map[int]struct {
+			result1 appdb.Tag
+			result2 error
+		})
+	}
+	fake.insertTagReturnsOnCall[i] = struct {
+		result1 appdb.Tag
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) ReplaceCharacterSkills(arg1 context.Context, arg2 int64, arg3 map[int64]int64, arg4 database.Tx) ([]appdb.CharacterSkill, error) {
+	fake.replaceCharacterSkillsMutex.Lock()
+	ret, specificReturn := fake.replaceCharacterSkillsReturnsOnCall[len(fake.replaceCharacterSkillsArgsForCall)]
+	fake.replaceCharacterSkillsArgsForCall = append(fake.replaceCharacterSkillsArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 map[int64]int64
+		arg4 database.Tx
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.ReplaceCharacterSkillsStub
+	fakeReturns := fake.replaceCharacterSkillsReturns
+	fake.recordInvocation("ReplaceCharacterSkills", []interface{}{arg1, arg2, arg3, arg4})
+	fake.replaceCharacterSkillsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAppData) ReplaceCharacterSkillsCallCount() int {
+	fake.replaceCharacterSkillsMutex.RLock()
+	defer fake.replaceCharacterSkillsMutex.RUnlock()
+	return len(fake.replaceCharacterSkillsArgsForCall)
+}
+
+func (fake *FakeAppData) ReplaceCharacterSkillsCalls(stub func(context.Context, int64, map[int64]int64, database.Tx) ([]appdb.CharacterSkill, error)) {
+	fake.replaceCharacterSkillsMutex.Lock()
+	defer fake.replaceCharacterSkillsMutex.Unlock()
+	fake.ReplaceCharacterSkillsStub = stub
+}
+
+func (fake *FakeAppData) ReplaceCharacterSkillsArgsForCall(i int) (context.Context, int64, map[int64]int64, database.Tx) {
+	fake.replaceCharacterSkillsMutex.RLock()
+	defer fake.replaceCharacterSkillsMutex.RUnlock()
+	argsForCall := fake.replaceCharacterSkillsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeAppData) ReplaceCharacterSkillsReturns(result1 []appdb.CharacterSkill, result2 error) {
+	fake.replaceCharacterSkillsMutex.Lock()
+	defer fake.replaceCharacterSkillsMutex.Unlock()
+	fake.ReplaceCharacterSkillsStub = nil
+	fake.replaceCharacterSkillsReturns = struct {
+		result1 []appdb.CharacterSkill
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) ReplaceCharacterSkillsReturnsOnCall(i int, result1 []appdb.CharacterSkill, result2 error) {
+	fake.replaceCharacterSkillsMutex.Lock()
+	defer fake.replaceCharacterSkillsMutex.Unlock()
+	fake.ReplaceCharacterSkillsStub = nil
+	if fake.replaceCharacterSkillsReturnsOnCall == nil {
+		fake.replaceCharacterSkillsReturnsOnCall = make(map[int]struct {
+			result1 []appdb.CharacterSkill
+			result2 error
+		})
+	}
+	fake.replaceCharacterSkillsReturnsOnCall[i] = struct {
+		result1 []appdb.CharacterSkill
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) RescheduleNotification(arg1 context.Context, arg2 int64, arg3 int64, arg4 time.Time, arg5 string, arg6 database.Tx) error {
+	fake.rescheduleNotificationMutex.Lock()
+	ret, specificReturn := fake.rescheduleNotificationReturnsOnCall[len(fake.rescheduleNotificationArgsForCall)]
+	fake.rescheduleNotificationArgsForCall = append(fake.rescheduleNotificationArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 int64
+		arg4 time.Time
+		arg5 string
+		arg6 database.Tx
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
+	stub := fake.RescheduleNotificationStub
+	fakeReturns := fake.rescheduleNotificationReturns
+	fake.recordInvocation("RescheduleNotification", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.rescheduleNotificationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAppData) RescheduleNotificationCallCount() int {
+	fake.rescheduleNotificationMutex.RLock()
+	defer fake.rescheduleNotificationMutex.RUnlock()
+	return len(fake.rescheduleNotificationArgsForCall)
+}
+
+func (fake *FakeAppData) RescheduleNotificationCalls(stub func(context.Context, int64, int64, time.Time, string, database.Tx) error) {
+	fake.rescheduleNotificationMutex.Lock()
+	defer fake.rescheduleNotificationMutex.Unlock()
+	fake.RescheduleNotificationStub = stub
+}
+
+func (fake *FakeAppData) RescheduleNotificationArgsForCall(i int) (context.Context, int64, int64, time.Time, string, database.Tx) {
+	fake.rescheduleNotificationMutex.RLock()
+	defer fake.rescheduleNotificationMutex.RUnlock()
+	argsForCall := fake.rescheduleNotificationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+}
+
+func (fake *FakeAppData) RescheduleNotificationReturns(result1 error) {
+	fake.rescheduleNotificationMutex.Lock()
+	defer fake.rescheduleNotificationMutex.Unlock()
+	fake.RescheduleNotificationStub = nil
+	fake.rescheduleNotificationReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAppData) RescheduleNotificationReturnsOnCall(i int, result1 error) {
+	fake.rescheduleNotificationMutex.Lock()
+	defer fake.rescheduleNotificationMutex.Unlock()
+	fake.RescheduleNotificationStub = nil
+	if fake.rescheduleNotificationReturnsOnCall == nil {
+		fake.rescheduleNotificationReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.rescheduleNotificationReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAppData) ResetVault(arg1 context.Context) error {
+	fake.resetVaultMutex.Lock()
+	ret, specificReturn := fake.resetVaultReturnsOnCall[len(fake.resetVaultArgsForCall)]
+	fake.resetVaultArgsForCall = append(fake.resetVaultArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.ResetVaultStub
+	fakeReturns := fake.resetVaultReturns
+	fake.recordInvocation("ResetVault", []interface{}{arg1})
+	fake.resetVaultMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAppData) ResetVaultCallCount() int {
+	fake.resetVaultMutex.RLock()
+	defer fake.resetVaultMutex.RUnlock()
+	return len(fake.resetVaultArgsForCall)
+}
+
+func (fake *FakeAppData) ResetVaultCalls(stub func(context.Context) error) {
+	fake.resetVaultMutex.Lock()
+	defer fake.resetVaultMutex.Unlock()
+	fake.ResetVaultStub = stub
+}
+
+func (fake *FakeAppData) ResetVaultArgsForCall(i int) context.Context {
+	fake.resetVaultMutex.RLock()
+	defer fake.resetVaultMutex.RUnlock()
+	argsForCall := fake.resetVaultArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAppData) ResetVaultReturns(result1 error) {
+	fake.resetVaultMutex.Lock()
+	defer fake.resetVaultMutex.Unlock()
+	fake.ResetVaultStub = nil
+	fake.resetVaultReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAppData) ResetVaultReturnsOnCall(i int, result1 error) {
+	fake.resetVaultMutex.Lock()
+	defer fake.resetVaultMutex.Unlock()
+	fake.ResetVaultStub = nil
+	if fake.resetVaultReturnsOnCall == nil {
+		fake.resetVaultReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.resetVaultReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAppData) RotateVault(arg1 context.Context, arg2 repository.KeySource) error {
+	fake.rotateVaultMutex.Lock()
+	ret, specificReturn := fake.rotateVaultReturnsOnCall[len(fake.rotateVaultArgsForCall)]
+	fake.rotateVaultArgsForCall = append(fake.rotateVaultArgsForCall, struct {
+		arg1 context.Context
+		arg2 repository.KeySource
+	}{arg1, arg2})
+	stub := fake.RotateVaultStub
+	fakeReturns := fake.rotateVaultReturns
+	fake.recordInvocation("RotateVault", []interface{}{arg1, arg2})
+	fake.rotateVaultMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAppData) RotateVaultCallCount() int {
+	fake.rotateVaultMutex.RLock()
+	defer fake.rotateVaultMutex.RUnlock()
+	return len(fake.rotateVaultArgsForCall)
+}
+
+func (fake *FakeAppData) RotateVaultCalls(stub func(context.Context, repository.KeySource) error) {
+	fake.rotateVaultMutex.Lock()
+	defer fake.rotateVaultMutex.Unlock()
+	fake.RotateVaultStub = stub
+}
+
+func (fake *FakeAppData) RotateVaultArgsForCall(i int) (context.Context, repository.KeySource) {
+	fake.rotateVaultMutex.RLock()
+	defer fake.rotateVaultMutex.RUnlock()
+	argsForCall := fake.rotateVaultArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAppData) RotateVaultReturns(result1 error) {
+	fake.rotateVaultMutex.Lock()
+	defer fake.rotateVaultMutex.Unlock()
+	fake.RotateVaultStub = nil
+	fake.rotateVaultReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAppData) RotateVaultReturnsOnCall(i int, result1 error) {
+	fake.rotateVaultMutex.Lock()
+	defer fake.rotateVaultMutex.Unlock()
+	fake.RotateVaultStub = nil
+	if fake.rotateVaultReturnsOnCall == nil {
+		fake.rotateVaultReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.rotateVaultReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAppData) SetRoleConditions(arg1 context.Context, arg2 int64, arg3 []appdb.RoleCondition, arg4 database.Tx) error {
+	var arg3Copy []appdb.RoleCondition
+	if arg3 != nil {
+		arg3Copy = make([]appdb.RoleCondition, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.setRoleConditionsMutex.Lock()
+	ret, specificReturn := fake.setRoleConditionsReturnsOnCall[len(fake.setRoleConditionsArgsForCall)]
+	fake.setRoleConditionsArgsForCall = append(fake.setRoleConditionsArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 []appdb.RoleCondition
+		arg4 database.Tx
+	}{arg1, arg2, arg3Copy, arg4})
+	stub := fake.SetRoleConditionsStub
+	fakeReturns := fake.setRoleConditionsReturns
+	fake.recordInvocation("SetRoleConditions", []interface{}{arg1, arg2, arg3Copy, arg4})
+	fake.setRoleConditionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAppData) SetRoleConditionsCallCount() int {
+	fake.setRoleConditionsMutex.RLock()
+	defer fake.setRoleConditionsMutex.RUnlock()
+	return len(fake.setRoleConditionsArgsForCall)
+}
+
+func (fake *FakeAppData) SetRoleConditionsCalls(stub func(context.Context, int64, []appdb.RoleCondition, database.Tx) error) {
+	fake.setRoleConditionsMutex.Lock()
+	defer fake.setRoleConditionsMutex.Unlock()
+	fake.SetRoleConditionsStub = stub
+}
+
+func (fake *FakeAppData) SetRoleConditionsArgsForCall(i int) (context.Context, int64, []appdb.RoleCondition, database.Tx) {
+	fake.setRoleConditionsMutex.RLock()
+	defer fake.setRoleConditionsMutex.RUnlock()
+	argsForCall := fake.setRoleConditionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeAppData) SetRoleConditionsReturns(result1 error) {
+	fake.setRoleConditionsMutex.Lock()
+	defer fake.setRoleConditionsMutex.Unlock()
+	fake.SetRoleConditionsStub = nil
+	fake.setRoleConditionsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAppData) SetRoleConditionsReturnsOnCall(i int, result1 error) {
+	fake.setRoleConditionsMutex.Lock()
+	defer fake.setRoleConditionsMutex.Unlock()
+	fake.SetRoleConditionsStub = nil
+	if fake.setRoleConditionsReturnsOnCall == nil {
+		fake.setRoleConditionsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setRoleConditionsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAppData) SetRoleExpr(arg1 context.Context, arg2 int64, arg3 *repository.RoleExpr, arg4 database.Tx) error {
+	fake.setRoleExprMutex.Lock()
+	ret, specificReturn := fake.setRoleExprReturnsOnCall[len(fake.setRoleExprArgsForCall)]
+	fake.setRoleExprArgsForCall = append(fake.setRoleExprArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 *repository.RoleExpr
+		arg4 database.Tx
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.SetRoleExprStub
+	fakeReturns := fake.setRoleExprReturns
+	fake.recordInvocation("SetRoleExpr", []interface{}{arg1, arg2, arg3, arg4})
+	fake.setRoleExprMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	return fakeReturns.result1, fakeReturns.result2
+	return fakeReturns.result1
 }
 
-func (fake *FakeAppData) GetTokenForCharacterCallCount() int {
-	fake.getTokenForCharacterMutex.RLock()
-	defer fake.getTokenForCharacterMutex.RUnlock()
-	return len(fake.getTokenForCharacterArgsForCall)
+func (fake *FakeAppData) SetRoleExprCallCount() int {
+	fake.setRoleExprMutex.RLock()
+	defer fake.setRoleExprMutex.RUnlock()
+	return len(fake.setRoleExprArgsForCall)
 }
 
-func (fake *FakeAppData) GetTokenForCharacterCalls(stub func(context.Context, int64, database.Tx) (appdb.Token, error)) {
-	fake.getTokenForCharacterMutex.Lock()
-	defer fake.getTokenForCharacterMutex.Unlock()
-	fake.GetTokenForCharacterStub = stub
+func (fake *FakeAppData) SetRoleExprCalls(stub func(context.Context, int64, *repository.RoleExpr, database.Tx) error) {
+	fake.setRoleExprMutex.Lock()
+	defer fake.setRoleExprMutex.Unlock()
+	fake.SetRoleExprStub = stub
 }
 
-func (fake *FakeAppData) GetTokenForCharacterArgsForCall(i int) (context.Context, int64, database.Tx) {
-	fake.getTokenForCharacterMutex.RLock()
-	defer fake.getTokenForCharacterMutex.RUnlock()
-	argsForCall := fake.getTokenForCharacterArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+func (fake *FakeAppData) SetRoleExprArgsForCall(i int) (context.Context, int64, *repository.RoleExpr, database.Tx) {
+	fake.setRoleExprMutex.RLock()
+	defer fake.setRoleExprMutex.RUnlock()
+	argsForCall := fake.setRoleExprArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
-func (fake *FakeAppData) GetTokenForCharacterReturns(result1 appdb.Token, result2 error) {
-	fake.getTokenForCharacterMutex.Lock()
-	defer fake.getTokenForCharacterMutex.Unlock()
-	fake.GetTokenForCharacterStub = nil
-	fake.getTokenForCharacterReturns = struct {
-		result1 appdb.Token
-		result2 error
-	}{result1, result2}
+func (fake *FakeAppData) SetRoleExprReturns(result1 error) {
+	fake.setRoleExprMutex.Lock()
+	defer fake.setRoleExprMutex.Unlock()
+	fake.SetRoleExprStub = nil
+	fake.setRoleExprReturns = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeAppData) GetTokenForCharacterReturnsOnCall(i int, result1 appdb.Token, result2 error) {
-	fake.getTokenForCharacterMutex.Lock()
-	defer fake.getTokenForCharacterMutex.Unlock()
-	fake.GetTokenForCharacterStub = nil
-	if fake.getTokenForCharacterReturnsOnCall == nil {
-		fake.getTokenForCharacterReturnsOnCall = make(map[int]struct {
-			result1 appdb.Token
-			result2 error
+func (fake *FakeAppData) SetRoleExprReturnsOnCall(i int, result1 error) {
+	fake.setRoleExprMutex.Lock()
+	defer fake.setRoleExprMutex.Unlock()
+	fake.SetRoleExprStub = nil
+	if fake.setRoleExprReturnsOnCall == nil {
+		fake.setRoleExprReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.getTokenForCharacterReturnsOnCall[i] = struct {
-		result1 appdb.Token
-		result2 error
-	}{result1, result2}
+	fake.setRoleExprReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeAppData) InsertRole(arg1 context.Context, arg2 string, arg3 string, arg4 operators.Operator, arg5 color.Color, arg6 database.Tx) (appdb.Role, error) {
-	fake.insertRoleMutex.Lock()
-	ret, specificReturn := fake.insertRoleReturnsOnCall[len(fake.insertRoleArgsForCall)]
-	fake.insertRoleArgsForCall = append(fake.insertRoleArgsForCall, struct {
+func (fake *FakeAppData) UnlockVault(arg1 context.Context, arg2 repository.KeySource) error {
+	fake.unlockVaultMutex.Lock()
+	ret, specificReturn := fake.unlockVaultReturnsOnCall[len(fake.unlockVaultArgsForCall)]
+	fake.unlockVaultArgsForCall = append(fake.unlockVaultArgsForCall, struct {
 		arg1 context.Context
-		arg2 string
-		arg3 string
-		arg4 operators.Operator
-		arg5 color.Color
-		arg6 database.Tx
-	}{arg1, arg2, arg3, arg4, arg5, arg6})
-	stub := fake.InsertRoleStub
-	fakeReturns := fake.insertRoleReturns
-	fake.recordInvocation("InsertRole", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
-	fake.insertRoleMutex.Unlock()
+		arg2 repository.KeySource
+	}{arg1, arg2})
+	stub := fake.UnlockVaultStub
+	fakeReturns := fake.unlockVaultReturns
+	fake.recordInvocation("UnlockVault", []interface{}{arg1, arg2})
+	fake.unlockVaultMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	return fakeReturns.result1, fakeReturns.result2
+	return fakeReturns.result1
 }
 
-func (fake *FakeAppData) InsertRoleCallCount() int {
-	fake.insertRoleMutex.RLock()
-	defer fake.insertRoleMutex.RUnlock()
-	return len(fake.insertRoleArgsForCall)
+func (fake *FakeAppData) UnlockVaultCallCount() int {
+	fake.unlockVaultMutex.RLock()
+	defer fake.unlockVaultMutex.RUnlock()
+	return len(fake.unlockVaultArgsForCall)
 }
 
-func (fake *FakeAppData) InsertRoleCalls(stub func(context.Context, string, string, operators.Operator, color.Color, database.Tx) (appdb.Role, error)) {
-	fake.insertRoleMutex.Lock()
-	defer fake.insertRoleMutex.Unlock()
-	fake.InsertRoleStub = stub
+func (fake *FakeAppData) UnlockVaultCalls(stub func(context.Context, repository.KeySource) error) {
+	fake.unlockVaultMutex.Lock()
+	defer fake.unlockVaultMutex.Unlock()
+	fake.UnlockVaultStub = stub
 }
 
-func (fake *FakeAppData) InsertRoleArgsForCall(i int) (context.Context, string, string, operators.Operator, color.Color, database.Tx) {
-	fake.insertRoleMutex.RLock()
-	defer fake.insertRoleMutex.RUnlock()
-	argsForCall := fake.insertRoleArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+func (fake *FakeAppData) UnlockVaultArgsForCall(i int) (context.Context, repository.KeySource) {
+	fake.unlockVaultMutex.RLock()
+	defer fake.unlockVaultMutex.RUnlock()
+	argsForCall := fake.unlockVaultArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeAppData) InsertRoleReturns(result1 appdb.Role, result2 error) {
-	fake.insertRoleMutex.Lock()
-	defer fake.insertRoleMutex.Unlock()
-	fake.InsertRoleStub = nil
-	fake.insertRoleReturns = struct {
-		result1 appdb.Role
-		result2 error
-	}{result1, result2}
+func (fake *FakeAppData) UnlockVaultReturns(result1 error) {
+	fake.unlockVaultMutex.Lock()
+	defer fake.unlockVaultMutex.Unlock()
+	fake.UnlockVaultStub = nil
+	fake.unlockVaultReturns = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeAppData) InsertRoleReturnsOnCall(i int, result1 appdb.Role, result2 error) {
-	fake.insertRoleMutex.Lock()
-	defer fake.insertRoleMutex.Unlock()
-	fake.InsertRoleStub = nil
-	if fake.insertRoleReturnsOnCall == nil {
-		fake.insertRoleReturnsOnCall = make(map[int]struct {
-			result1 appdb.Role
-			result2 error
+func (fake *FakeAppData) UnlockVaultReturnsOnCall(i int, result1 error) {
+	fake.unlockVaultMutex.Lock()
+	defer fake.unlockVaultMutex.Unlock()
+	fake.UnlockVaultStub = nil
+	if fake.unlockVaultReturnsOnCall == nil {
+		fake.unlockVaultReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.insertRoleReturnsOnCall[i] = struct {
-		result1 appdb.Role
-		result2 error
-	}{result1, result2}
+	fake.unlockVaultReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeAppData) InsertTag(arg1 context.Context, arg2 string, arg3 color.Color, arg4 database.Tx) (appdb.Tag, error) {
-	fake.insertTagMutex.Lock()
-	ret, specificReturn := fake.insertTagReturnsOnCall[len(fake.insertTagArgsForCall)]
-	fake.insertTagArgsForCall = append(fake.insertTagArgsForCall, struct {
+func (fake *FakeAppData) UpdateCharacterSkillQueue(arg1 context.Context, arg2 int64, arg3 int64, arg4 database.Tx) error {
+	fake.updateCharacterSkillQueueMutex.Lock()
+	ret, specificReturn := fake.updateCharacterSkillQueueReturnsOnCall[len(fake.updateCharacterSkillQueueArgsForCall)]
+	fake.updateCharacterSkillQueueArgsForCall = append(fake.updateCharacterSkillQueueArgsForCall, struct {
 		arg1 context.Context
-		arg2 string
-		arg3 color.Color
+		arg2 int64
+		arg3 int64
 		arg4 database.Tx
 	}{arg1, arg2, arg3, arg4})
-	stub := fake.InsertTagStub
-	fakeReturns := fake.insertTagReturns
-	fake.recordInvocation("InsertTag", []interface{}{arg1, arg2, arg3, arg4})
-	fake.insertTagMutex.Unlock()
+	stub := fake.UpdateCharacterSkillQueueStub
+	fakeReturns := fake.updateCharacterSkillQueueReturns
+	fake.recordInvocation("UpdateCharacterSkillQueue", []interface{}{arg1, arg2, arg3, arg4})
+	fake.updateCharacterSkillQueueMutex.Unlock()
 	if stub != nil {
 		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	return fakeReturns.result1, fakeReturns.result2
+	return fakeReturns.result1
 }
 
-func (fake *FakeAppData) InsertTagCallCount() int {
-	fake.insertTagMutex.RLock()
-	defer fake.insertTagMutex.RUnlock()
-	return len(fake.insertTagArgsForCall)
+func (fake *FakeAppData) UpdateCharacterSkillQueueCallCount() int {
+	fake.updateCharacterSkillQueueMutex.RLock()
+	defer fake.updateCharacterSkillQueueMutex.RUnlock()
+	return len(fake.updateCharacterSkillQueueArgsForCall)
 }
 
-func (fake *FakeAppData) InsertTagCalls(stub func(context.Context, string, color.Color, database.Tx) (appdb.Tag, error)) {
-	fake.insertTagMutex.Lock()
-	defer fake.insertTagMutex.Unlock()
-	fake.InsertTagStub = stub
+func (fake *FakeAppData) UpdateCharacterSkillQueueCalls(stub func(context.Context, int64, int64, database.Tx) error) {
+	fake.updateCharacterSkillQueueMutex.Lock()
+	defer fake.updateCharacterSkillQueueMutex.Unlock()
+	fake.UpdateCharacterSkillQueueStub = stub
 }
 
-func (fake *FakeAppData) InsertTagArgsForCall(i int) (context.Context, string, color.Color, database.Tx) {
-	fake.insertTagMutex.RLock()
-	defer fake.insertTagMutex.RUnlock()
-	argsForCall := fake.insertTagArgsForCall[i]
+func (fake *FakeAppData) UpdateCharacterSkillQueueArgsForCall(i int) (context.Context, int64, int64, database.Tx) {
+	fake.updateCharacterSkillQueueMutex.RLock()
+	defer fake.updateCharacterSkillQueueMutex.RUnlock()
+	argsForCall := fake.updateCharacterSkillQueueArgsForCall[i]
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
-func (fake *FakeAppData) InsertTagReturns(result1 appdb.Tag, result2 error) {
-	fake.insertTagMutex.Lock()
-	defer fake.insertTagMutex.Unlock()
-	fake.InsertTagStub = nil
-	fake.insertTagReturns = struct {
-		result1 appdb.Tag
-		result2 error
-	}{result1, result2}
+func (fake *FakeAppData) UpdateCharacterSkillQueueReturns(result1 error) {
+	fake.updateCharacterSkillQueueMutex.Lock()
+	defer fake.updateCharacterSkillQueueMutex.Unlock()
+	fake.UpdateCharacterSkillQueueStub = nil
+	fake.updateCharacterSkillQueueReturns = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeAppData) InsertTagReturnsOnCall(i int, result1 appdb.Tag, result2 error) {
-	fake.insertTagMutex.Lock()
-	defer fake.insertTagMutex.Unlock()
-	fake.InsertTagStub = nil
-	if fake.insertTagReturnsOnCall == nil {
-		fake.insertTagReturnsOnCall = make(map[int]struct {
-			result1 appdb.Tag
-			result2 error
+func (fake *FakeAppData) UpdateCharacterSkillQueueReturnsOnCall(i int, result1 error) {
+	fake.updateCharacterSkillQueueMutex.Lock()
+	defer fake.updateCharacterSkillQueueMutex.Unlock()
+	fake.UpdateCharacterSkillQueueStub = nil
+	if fake.updateCharacterSkillQueueReturnsOnCall == nil {
+		fake.updateCharacterSkillQueueReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.insertTagReturnsOnCall[i] = struct {
-		result1 appdb.Tag
-		result2 error
-	}{result1, result2}
+	fake.updateCharacterSkillQueueReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeAppData) UpdateRole(arg1 context.Context, arg2 int64, arg3 string, arg4 string, arg5 operators.Operator, arg6 color.Color, arg7 database.Tx) error {
+func (fake *FakeAppData) UpdateRole(arg1 context.Context, arg2 int64, arg3 string, arg4 string, arg5 color.Color, arg6 database.Tx) error {
 	fake.updateRoleMutex.Lock()
 	ret, specificReturn := fake.updateRoleReturnsOnCall[len(fake.updateRoleArgsForCall)]
 	fake.updateRoleArgsForCall = append(fake.updateRoleArgsForCall, struct {
@@ -1390,16 +2584,15 @@ func (fake *FakeAppData) UpdateRole(arg1 context.Context, arg2 int64, arg3 strin
 		arg2 int64
 		arg3 string
 		arg4 string
-		arg5 operators.Operator
-		arg6 color.Color
-		arg7 database.Tx
-	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
+		arg5 color.Color
+		arg6 database.Tx
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
 	stub := fake.UpdateRoleStub
 	fakeReturns := fake.updateRoleReturns
-	fake.recordInvocation("UpdateRole", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
+	fake.recordInvocation("UpdateRole", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
 	fake.updateRoleMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
 	}
 	if specificReturn {
 		return ret.result1
@@ -1413,17 +2606,17 @@ func (fake *FakeAppData) UpdateRoleCallCount() int {
 	return len(fake.updateRoleArgsForCall)
 }
 
-func (fake *FakeAppData) UpdateRoleCalls(stub func(context.Context, int64, string, string, operators.Operator, color.Color, database.Tx) error) {
+func (fake *FakeAppData) UpdateRoleCalls(stub func(context.Context, int64, string, string, color.Color, database.Tx) error) {
 	fake.updateRoleMutex.Lock()
 	defer fake.updateRoleMutex.Unlock()
 	fake.UpdateRoleStub = stub
 }
 
-func (fake *FakeAppData) UpdateRoleArgsForCall(i int) (context.Context, int64, string, string, operators.Operator, color.Color, database.Tx) {
+func (fake *FakeAppData) UpdateRoleArgsForCall(i int) (context.Context, int64, string, string, color.Color, database.Tx) {
 	fake.updateRoleMutex.RLock()
 	defer fake.updateRoleMutex.RUnlock()
 	argsForCall := fake.updateRoleArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
 }
 
 func (fake *FakeAppData) UpdateRoleReturns(result1 error) {
@@ -1583,7 +2776,7 @@ func (fake *FakeAppData) UpsertAllianceReturnsOnCall(i int, result1 appdb.Allian
 	}{result1, result2}
 }
 
-func (fake *FakeAppData) UpsertCharacter(arg1 context.Context, arg2 int64, arg3 string, arg4 string, arg5 int64, arg6 database.Tx) (appdb.Character, error) {
+func (fake *FakeAppData) UpsertCharacter(arg1 context.Context, arg2 int64, arg3 string, arg4 string, arg5 int64, arg6 int64, arg7 bool, arg8 database.Tx) (appdb.Character, error) {
 	fake.upsertCharacterMutex.Lock()
 	ret, specificReturn := fake.upsertCharacterReturnsOnCall[len(fake.upsertCharacterArgsForCall)]
 	fake.upsertCharacterArgsForCall = append(fake.upsertCharacterArgsForCall, struct {
@@ -1592,14 +2785,16 @@ func (fake *FakeAppData) UpsertCharacter(arg1 context.Context, arg2 int64, arg3
 		arg3 string
 		arg4 string
 		arg5 int64
-		arg6 database.Tx
-	}{arg1, arg2, arg3, arg4, arg5, arg6})
+		arg6 int64
+		arg7 bool
+		arg8 database.Tx
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
 	stub := fake.UpsertCharacterStub
 	fakeReturns := fake.upsertCharacterReturns
-	fake.recordInvocation("UpsertCharacter", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.recordInvocation("UpsertCharacter", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
 	fake.upsertCharacterMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -1613,17 +2808,17 @@ func (fake *FakeAppData) UpsertCharacterCallCount() int {
 	return len(fake.upsertCharacterArgsForCall)
 }
 
-func (fake *FakeAppData) UpsertCharacterCalls(stub func(context.Context, int64, string, string, int64, database.Tx) (appdb.Character, error)) {
+func (fake *FakeAppData) UpsertCharacterCalls(stub func(context.Context, int64, string, string, int64, int64, bool, database.Tx) (appdb.Character, error)) {
 	fake.upsertCharacterMutex.Lock()
 	defer fake.upsertCharacterMutex.Unlock()
 	fake.UpsertCharacterStub = stub
 }
 
-func (fake *FakeAppData) UpsertCharacterArgsForCall(i int) (context.Context, int64, string, string, int64, database.Tx) {
+func (fake *FakeAppData) UpsertCharacterArgsForCall(i int) (context.Context, int64, string, string, int64, int64, bool, database.Tx) {
 	fake.upsertCharacterMutex.RLock()
 	defer fake.upsertCharacterMutex.RUnlock()
 	argsForCall := fake.upsertCharacterArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
 }
 
 func (fake *FakeAppData) UpsertCharacterReturns(result1 appdb.Character, result2 error) {
@@ -1790,74 +2985,71 @@ func (fake *FakeAppData) UpsertCorporationReturnsOnCall(i int, result1 appdb.Cor
 	}{result1, result2}
 }
 
-func (fake *FakeAppData) UpsertRoleTag(arg1 context.Context, arg2 int64, arg3 int64, arg4 database.Tx) (appdb.RoleTag, error) {
-	fake.upsertRoleTagMutex.Lock()
-	ret, specificReturn := fake.upsertRoleTagReturnsOnCall[len(fake.upsertRoleTagArgsForCall)]
-	fake.upsertRoleTagArgsForCall = append(fake.upsertRoleTagArgsForCall, struct {
+func (fake *FakeAppData) UpsertTagInclude(arg1 context.Context, arg2 int64, arg3 int64, arg4 database.Tx) error {
+	fake.upsertTagIncludeMutex.Lock()
+	ret, specificReturn := fake.upsertTagIncludeReturnsOnCall[len(fake.upsertTagIncludeArgsForCall)]
+	fake.upsertTagIncludeArgsForCall = append(fake.upsertTagIncludeArgsForCall, struct {
 		arg1 context.Context
 		arg2 int64
 		arg3 int64
 		arg4 database.Tx
 	}{arg1, arg2, arg3, arg4})
-	stub := fake.UpsertRoleTagStub
-	fakeReturns := fake.upsertRoleTagReturns
-	fake.recordInvocation("UpsertRoleTag", []interface{}{arg1, arg2, arg3, arg4})
-	fake.upsertRoleTagMutex.Unlock()
+	stub := fake.UpsertTagIncludeStub
+	fakeReturns := fake.upsertTagIncludeReturns
+	fake.recordInvocation("UpsertTagInclude", []interface{}{arg1, arg2, arg3, arg4})
+	fake.upsertTagIncludeMutex.Unlock()
 	if stub != nil {
 		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	return fakeReturns.result1, fakeReturns.result2
+	return fakeReturns.result1
 }
 
-func (fake *FakeAppData) UpsertRoleTagCallCount() int {
-	fake.upsertRoleTagMutex.RLock()
-	defer fake.upsertRoleTagMutex.RUnlock()
-	return len(fake.upsertRoleTagArgsForCall)
+func (fake *FakeAppData) UpsertTagIncludeCallCount() int {
+	fake.upsertTagIncludeMutex.RLock()
+	defer fake.upsertTagIncludeMutex.RUnlock()
+	return len(fake.upsertTagIncludeArgsForCall)
 }
 
-func (fake *FakeAppData) UpsertRoleTagCalls(stub func(context.Context, int64, int64, database.Tx) (appdb.RoleTag, error)) {
-	fake.upsertRoleTagMutex.Lock()
-	defer fake.upsertRoleTagMutex.Unlock()
-	fake.UpsertRoleTagStub = stub
+func (fake *FakeAppData) UpsertTagIncludeCalls(stub func(context.Context, int64, int64, database.Tx) error) {
+	fake.upsertTagIncludeMutex.Lock()
+	defer fake.upsertTagIncludeMutex.Unlock()
+	fake.UpsertTagIncludeStub = stub
 }
 
-func (fake *FakeAppData) UpsertRoleTagArgsForCall(i int) (context.Context, int64, int64, database.Tx) {
-	fake.upsertRoleTagMutex.RLock()
-	defer fake.upsertRoleTagMutex.RUnlock()
-	argsForCall := fake.upsertRoleTagArgsForCall[i]
+func (fake *FakeAppData) UpsertTagIncludeArgsForCall(i int) (context.Context, int64, int64, database.Tx) {
+	fake.upsertTagIncludeMutex.RLock()
+	defer fake.upsertTagIncludeMutex.RUnlock()
+	argsForCall := fake.upsertTagIncludeArgsForCall[i]
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
-func (fake *FakeAppData) UpsertRoleTagReturns(result1 appdb.RoleTag, result2 error) {
-	fake.upsertRoleTagMutex.Lock()
-	defer fake.upsertRoleTagMutex.Unlock()
-	fake.UpsertRoleTagStub = nil
-	fake.upsertRoleTagReturns = struct {
-		result1 appdb.RoleTag
-		result2 error
-	}{result1, result2}
+func (fake *FakeAppData) UpsertTagIncludeReturns(result1 error) {
+	fake.upsertTagIncludeMutex.Lock()
+	defer fake.upsertTagIncludeMutex.Unlock()
+	fake.UpsertTagIncludeStub = nil
+	fake.upsertTagIncludeReturns = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeAppData) UpsertRoleTagReturnsOnCall(i int, result1 appdb.RoleTag, result2 error) {
-	fake.upsertRoleTagMutex.Lock()
-	defer fake.upsertRoleTagMutex.Unlock()
-	fake.UpsertRoleTagStub = nil
-	if fake.upsertRoleTagReturnsOnCall == nil {
-		fake.upsertRoleTagReturnsOnCall = make(map[int]struct {
-			result1 appdb.RoleTag
-			result2 error
+func (fake *FakeAppData) UpsertTagIncludeReturnsOnCall(i int, result1 error) {
+	fake.upsertTagIncludeMutex.Lock()
+	defer fake.upsertTagIncludeMutex.Unlock()
+	fake.UpsertTagIncludeStub = nil
+	if fake.upsertTagIncludeReturnsOnCall == nil {
+		fake.upsertTagIncludeReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.upsertRoleTagReturnsOnCall[i] = struct {
-		result1 appdb.RoleTag
-		result2 error
-	}{result1, result2}
+	fake.upsertTagIncludeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeAppData) UpsertTagSkill(arg1 context.Context, arg2 int64, arg3 int64, arg4 int64, arg5 database.Tx) (appdb.TagSkill, error) {
+func (fake *FakeAppData) UpsertTagSkill(arg1 context.Context, arg2 int64, arg3 int64, arg4 int64, arg5 int64, arg6 database.Tx) (appdb.TagSkill, error) {
 	fake.upsertTagSkillMutex.Lock()
 	ret, specificReturn := fake.upsertTagSkillReturnsOnCall[len(fake.upsertTagSkillArgsForCall)]
 	fake.upsertTagSkillArgsForCall = append(fake.upsertTagSkillArgsForCall, struct {
@@ -1865,14 +3057,15 @@ func (fake *FakeAppData) UpsertTagSkill(arg1 context.Context, arg2 int64, arg3 i
 		arg2 int64
 		arg3 int64
 		arg4 int64
-		arg5 database.Tx
-	}{arg1, arg2, arg3, arg4, arg5})
+		arg5 int64
+		arg6 database.Tx
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
 	stub := fake.UpsertTagSkillStub
 	fakeReturns := fake.upsertTagSkillReturns
-	fake.recordInvocation("UpsertTagSkill", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("UpsertTagSkill", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
 	fake.upsertTagSkillMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -1886,17 +3079,17 @@ func (fake *FakeAppData) UpsertTagSkillCallCount() int {
 	return len(fake.upsertTagSkillArgsForCall)
 }
 
-func (fake *FakeAppData) UpsertTagSkillCalls(stub func(context.Context, int64, int64, int64, database.Tx) (appdb.TagSkill, error)) {
+func (fake *FakeAppData) UpsertTagSkillCalls(stub func(context.Context, int64, int64, int64, int64, database.Tx) (appdb.TagSkill, error)) {
 	fake.upsertTagSkillMutex.Lock()
 	defer fake.upsertTagSkillMutex.Unlock()
 	fake.UpsertTagSkillStub = stub
 }
 
-func (fake *FakeAppData) UpsertTagSkillArgsForCall(i int) (context.Context, int64, int64, int64, database.Tx) {
+func (fake *FakeAppData) UpsertTagSkillArgsForCall(i int) (context.Context, int64, int64, int64, int64, database.Tx) {
 	fake.upsertTagSkillMutex.RLock()
 	defer fake.upsertTagSkillMutex.RUnlock()
 	argsForCall := fake.upsertTagSkillArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
 }
 
 func (fake *FakeAppData) UpsertTagSkillReturns(result1 appdb.TagSkill, result2 error) {
@@ -1995,6 +3188,132 @@ func (fake *FakeAppData) UpsertTokenReturnsOnCall(i int, result1 appdb.Token, re
 	}{result1, result2}
 }
 
+func (fake *FakeAppData) VaultStatus(arg1 context.Context) (repository.VaultStatus, error) {
+	fake.vaultStatusMutex.Lock()
+	ret, specificReturn := fake.vaultStatusReturnsOnCall[len(fake.vaultStatusArgsForCall)]
+	fake.vaultStatusArgsForCall = append(fake.vaultStatusArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.VaultStatusStub
+	fakeReturns := fake.vaultStatusReturns
+	fake.recordInvocation("VaultStatus", []interface{}{arg1})
+	fake.vaultStatusMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAppData) VaultStatusCallCount() int {
+	fake.vaultStatusMutex.RLock()
+	defer fake.vaultStatusMutex.RUnlock()
+	return len(fake.vaultStatusArgsForCall)
+}
+
+func (fake *FakeAppData) VaultStatusCalls(stub func(context.Context) (repository.VaultStatus, error)) {
+	fake.vaultStatusMutex.Lock()
+	defer fake.vaultStatusMutex.Unlock()
+	fake.VaultStatusStub = stub
+}
+
+func (fake *FakeAppData) VaultStatusArgsForCall(i int) context.Context {
+	fake.vaultStatusMutex.RLock()
+	defer fake.vaultStatusMutex.RUnlock()
+	argsForCall := fake.vaultStatusArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAppData) VaultStatusReturns(result1 repository.VaultStatus, result2 error) {
+	fake.vaultStatusMutex.Lock()
+	defer fake.vaultStatusMutex.Unlock()
+	fake.VaultStatusStub = nil
+	fake.vaultStatusReturns = struct {
+		result1 repository.VaultStatus
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) VaultStatusReturnsOnCall(i int, result1 repository.VaultStatus, result2 error) {
+	fake.vaultStatusMutex.Lock()
+	defer fake.vaultStatusMutex.Unlock()
+	fake.VaultStatusStub = nil
+	if fake.vaultStatusReturnsOnCall == nil {
+		fake.vaultStatusReturnsOnCall = make(map[int]struct {
+			result1 repository.VaultStatus
+			result2 error
+		})
+	}
+	fake.vaultStatusReturnsOnCall[i] = struct {
+		result1 repository.VaultStatus
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppData) WatchExternalChanges(arg1 context.Context, arg2 time.Duration) error {
+	fake.watchExternalChangesMutex.Lock()
+	ret, specificReturn := fake.watchExternalChangesReturnsOnCall[len(fake.watchExternalChangesArgsForCall)]
+	fake.watchExternalChangesArgsForCall = append(fake.watchExternalChangesArgsForCall, struct {
+		arg1 context.Context
+		arg2 time.Duration
+	}{arg1, arg2})
+	stub := fake.WatchExternalChangesStub
+	fakeReturns := fake.watchExternalChangesReturns
+	fake.recordInvocation("WatchExternalChanges", []interface{}{arg1, arg2})
+	fake.watchExternalChangesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAppData) WatchExternalChangesCallCount() int {
+	fake.watchExternalChangesMutex.RLock()
+	defer fake.watchExternalChangesMutex.RUnlock()
+	return len(fake.watchExternalChangesArgsForCall)
+}
+
+func (fake *FakeAppData) WatchExternalChangesCalls(stub func(context.Context, time.Duration) error) {
+	fake.watchExternalChangesMutex.Lock()
+	defer fake.watchExternalChangesMutex.Unlock()
+	fake.WatchExternalChangesStub = stub
+}
+
+func (fake *FakeAppData) WatchExternalChangesArgsForCall(i int) (context.Context, time.Duration) {
+	fake.watchExternalChangesMutex.RLock()
+	defer fake.watchExternalChangesMutex.RUnlock()
+	argsForCall := fake.watchExternalChangesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAppData) WatchExternalChangesReturns(result1 error) {
+	fake.watchExternalChangesMutex.Lock()
+	defer fake.watchExternalChangesMutex.Unlock()
+	fake.WatchExternalChangesStub = nil
+	fake.watchExternalChangesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAppData) WatchExternalChangesReturnsOnCall(i int, result1 error) {
+	fake.watchExternalChangesMutex.Lock()
+	defer fake.watchExternalChangesMutex.Unlock()
+	fake.WatchExternalChangesStub = nil
+	if fake.watchExternalChangesReturnsOnCall == nil {
+		fake.watchExternalChangesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.watchExternalChangesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeAppData) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -2002,14 +3321,18 @@ func (fake *FakeAppData) Invocations() map[string][][]interface{} {
 	defer fake.deleteCharacterMutex.RUnlock()
 	fake.deleteCharacterSkillsMutex.RLock()
 	defer fake.deleteCharacterSkillsMutex.RUnlock()
+	fake.deleteNotificationMutex.RLock()
+	defer fake.deleteNotificationMutex.RUnlock()
 	fake.deleteRoleMutex.RLock()
 	defer fake.deleteRoleMutex.RUnlock()
-	fake.deleteRoleTagsMutex.RLock()
-	defer fake.deleteRoleTagsMutex.RUnlock()
 	fake.deleteTagMutex.RLock()
 	defer fake.deleteTagMutex.RUnlock()
+	fake.deleteTagIncludesMutex.RLock()
+	defer fake.deleteTagIncludesMutex.RUnlock()
 	fake.deleteTagSkillsMutex.RLock()
 	defer fake.deleteTagSkillsMutex.RUnlock()
+	fake.eventsMutex.RLock()
+	defer fake.eventsMutex.RUnlock()
 	fake.getAllCharacterSkillsMutex.RLock()
 	defer fake.getAllCharacterSkillsMutex.RUnlock()
 	fake.getAllCharactersMutex.RLock()
@@ -2022,12 +3345,38 @@ func (fake *FakeAppData) Invocations() map[string][][]interface{} {
 	defer fake.getAllTagSkillsMutex.RUnlock()
 	fake.getAllTagsMutex.RLock()
 	defer fake.getAllTagsMutex.RUnlock()
+	fake.getCharacterMutex.RLock()
+	defer fake.getCharacterMutex.RUnlock()
+	fake.getDueNotificationsMutex.RLock()
+	defer fake.getDueNotificationsMutex.RUnlock()
+	fake.getRoleConditionsMutex.RLock()
+	defer fake.getRoleConditionsMutex.RUnlock()
+	fake.getRoleExprMutex.RLock()
+	defer fake.getRoleExprMutex.RUnlock()
 	fake.getTokenForCharacterMutex.RLock()
 	defer fake.getTokenForCharacterMutex.RUnlock()
+	fake.insertNotificationMutex.RLock()
+	defer fake.insertNotificationMutex.RUnlock()
 	fake.insertRoleMutex.RLock()
 	defer fake.insertRoleMutex.RUnlock()
 	fake.insertTagMutex.RLock()
 	defer fake.insertTagMutex.RUnlock()
+	fake.replaceCharacterSkillsMutex.RLock()
+	defer fake.replaceCharacterSkillsMutex.RUnlock()
+	fake.rescheduleNotificationMutex.RLock()
+	defer fake.rescheduleNotificationMutex.RUnlock()
+	fake.resetVaultMutex.RLock()
+	defer fake.resetVaultMutex.RUnlock()
+	fake.rotateVaultMutex.RLock()
+	defer fake.rotateVaultMutex.RUnlock()
+	fake.setRoleConditionsMutex.RLock()
+	defer fake.setRoleConditionsMutex.RUnlock()
+	fake.setRoleExprMutex.RLock()
+	defer fake.setRoleExprMutex.RUnlock()
+	fake.unlockVaultMutex.RLock()
+	defer fake.unlockVaultMutex.RUnlock()
+	fake.updateCharacterSkillQueueMutex.RLock()
+	defer fake.updateCharacterSkillQueueMutex.RUnlock()
 	fake.updateRoleMutex.RLock()
 	defer fake.updateRoleMutex.RUnlock()
 	fake.updateTagMutex.RLock()
@@ -2040,12 +3389,16 @@ func (fake *FakeAppData) Invocations() map[string][][]interface{} {
 	defer fake.upsertCharacterSkillMutex.RUnlock()
 	fake.upsertCorporationMutex.RLock()
 	defer fake.upsertCorporationMutex.RUnlock()
-	fake.upsertRoleTagMutex.RLock()
-	defer fake.upsertRoleTagMutex.RUnlock()
+	fake.upsertTagIncludeMutex.RLock()
+	defer fake.upsertTagIncludeMutex.RUnlock()
 	fake.upsertTagSkillMutex.RLock()
 	defer fake.upsertTagSkillMutex.RUnlock()
 	fake.upsertTokenMutex.RLock()
 	defer fake.upsertTokenMutex.RUnlock()
+	fake.vaultStatusMutex.RLock()
+	defer fake.vaultStatusMutex.RUnlock()
+	fake.watchExternalChangesMutex.RLock()
+	defer fake.watchExternalChangesMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -26,6 +26,21 @@ type FakeStaticData struct {
 		result1 []staticdb.BatchGetSkillNamesRow
 		result2 error
 	}
+	BatchGetSkillRanksStub        func(context.Context, []int64, database.Tx) ([]staticdb.BatchGetSkillRanksRow, error)
+	batchGetSkillRanksMutex       sync.RWMutex
+	batchGetSkillRanksArgsForCall []struct {
+		arg1 context.Context
+		arg2 []int64
+		arg3 database.Tx
+	}
+	batchGetSkillRanksReturns struct {
+		result1 []staticdb.BatchGetSkillRanksRow
+		result2 error
+	}
+	batchGetSkillRanksReturnsOnCall map[int]struct {
+		result1 []staticdb.BatchGetSkillRanksRow
+		result2 error
+	}
 	CleanTranslationsStub        func(context.Context, database.Tx) error
 	cleanTranslationsMutex       sync.RWMutex
 	cleanTranslationsArgsForCall []struct {
@@ -38,6 +53,33 @@ type FakeStaticData struct {
 	cleanTranslationsReturnsOnCall map[int]struct {
 		result1 error
 	}
+	CleanTypeAttributesStub        func(context.Context, database.Tx) error
+	cleanTypeAttributesMutex       sync.RWMutex
+	cleanTypeAttributesArgsForCall []struct {
+		arg1 context.Context
+		arg2 database.Tx
+	}
+	cleanTypeAttributesReturns struct {
+		result1 error
+	}
+	cleanTypeAttributesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetRequiredSkillsStub        func(context.Context, int64, database.Tx) ([]staticdb.GetRequiredSkillsRow, error)
+	getRequiredSkillsMutex       sync.RWMutex
+	getRequiredSkillsArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 database.Tx
+	}
+	getRequiredSkillsReturns struct {
+		result1 []staticdb.GetRequiredSkillsRow
+		result2 error
+	}
+	getRequiredSkillsReturnsOnCall map[int]struct {
+		result1 []staticdb.GetRequiredSkillsRow
+		result2 error
+	}
 	GetSkillIDByNameStub        func(context.Context, string, database.Tx) (int64, error)
 	getSkillIDByNameMutex       sync.RWMutex
 	getSkillIDByNameArgsForCall []struct {
@@ -82,6 +124,21 @@ type FakeStaticData struct {
 		result1 []string
 		result2 error
 	}
+	GetTypeIDByNameStub        func(context.Context, string, database.Tx) (int64, error)
+	getTypeIDByNameMutex       sync.RWMutex
+	getTypeIDByNameArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 database.Tx
+	}
+	getTypeIDByNameReturns struct {
+		result1 int64
+		result2 error
+	}
+	getTypeIDByNameReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -157,6 +214,77 @@ func (fake *FakeStaticData) BatchGetSkillNamesReturnsOnCall(i int, result1 []sta
 	}{result1, result2}
 }
 
+func (fake *FakeStaticData) BatchGetSkillRanks(arg1 context.Context, arg2 []int64, arg3 database.Tx) ([]staticdb.BatchGetSkillRanksRow, error) {
+	var arg2Copy []int64
+	if arg2 != nil {
+		arg2Copy = make([]int64, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.batchGetSkillRanksMutex.Lock()
+	ret, specificReturn := fake.batchGetSkillRanksReturnsOnCall[len(fake.batchGetSkillRanksArgsForCall)]
+	fake.batchGetSkillRanksArgsForCall = append(fake.batchGetSkillRanksArgsForCall, struct {
+		arg1 context.Context
+		arg2 []int64
+		arg3 database.Tx
+	}{arg1, arg2Copy, arg3})
+	stub := fake.BatchGetSkillRanksStub
+	fakeReturns := fake.batchGetSkillRanksReturns
+	fake.recordInvocation("BatchGetSkillRanks", []interface{}{arg1, arg2Copy, arg3})
+	fake.batchGetSkillRanksMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStaticData) BatchGetSkillRanksCallCount() int {
+	fake.batchGetSkillRanksMutex.RLock()
+	defer fake.batchGetSkillRanksMutex.RUnlock()
+	return len(fake.batchGetSkillRanksArgsForCall)
+}
+
+func (fake *FakeStaticData) BatchGetSkillRanksCalls(stub func(context.Context, []int64, database.Tx) ([]staticdb.BatchGetSkillRanksRow, error)) {
+	fake.batchGetSkillRanksMutex.Lock()
+	defer fake.batchGetSkillRanksMutex.Unlock()
+	fake.BatchGetSkillRanksStub = stub
+}
+
+func (fake *FakeStaticData) BatchGetSkillRanksArgsForCall(i int) (context.Context, []int64, database.Tx) {
+	fake.batchGetSkillRanksMutex.RLock()
+	defer fake.batchGetSkillRanksMutex.RUnlock()
+	argsForCall := fake.batchGetSkillRanksArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStaticData) BatchGetSkillRanksReturns(result1 []staticdb.BatchGetSkillRanksRow, result2 error) {
+	fake.batchGetSkillRanksMutex.Lock()
+	defer fake.batchGetSkillRanksMutex.Unlock()
+	fake.BatchGetSkillRanksStub = nil
+	fake.batchGetSkillRanksReturns = struct {
+		result1 []staticdb.BatchGetSkillRanksRow
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStaticData) BatchGetSkillRanksReturnsOnCall(i int, result1 []staticdb.BatchGetSkillRanksRow, result2 error) {
+	fake.batchGetSkillRanksMutex.Lock()
+	defer fake.batchGetSkillRanksMutex.Unlock()
+	fake.BatchGetSkillRanksStub = nil
+	if fake.batchGetSkillRanksReturnsOnCall == nil {
+		fake.batchGetSkillRanksReturnsOnCall = make(map[int]struct {
+			result1 []staticdb.BatchGetSkillRanksRow
+			result2 error
+		})
+	}
+	fake.batchGetSkillRanksReturnsOnCall[i] = struct {
+		result1 []staticdb.BatchGetSkillRanksRow
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeStaticData) CleanTranslations(arg1 context.Context, arg2 database.Tx) error {
 	fake.cleanTranslationsMutex.Lock()
 	ret, specificReturn := fake.cleanTranslationsReturnsOnCall[len(fake.cleanTranslationsArgsForCall)]
@@ -219,6 +347,134 @@ func (fake *FakeStaticData) CleanTranslationsReturnsOnCall(i int, result1 error)
 	}{result1}
 }
 
+func (fake *FakeStaticData) CleanTypeAttributes(arg1 context.Context, arg2 database.Tx) error {
+	fake.cleanTypeAttributesMutex.Lock()
+	ret, specificReturn := fake.cleanTypeAttributesReturnsOnCall[len(fake.cleanTypeAttributesArgsForCall)]
+	fake.cleanTypeAttributesArgsForCall = append(fake.cleanTypeAttributesArgsForCall, struct {
+		arg1 context.Context
+		arg2 database.Tx
+	}{arg1, arg2})
+	stub := fake.CleanTypeAttributesStub
+	fakeReturns := fake.cleanTypeAttributesReturns
+	fake.recordInvocation("CleanTypeAttributes", []interface{}{arg1, arg2})
+	fake.cleanTypeAttributesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStaticData) CleanTypeAttributesCallCount() int {
+	fake.cleanTypeAttributesMutex.RLock()
+	defer fake.cleanTypeAttributesMutex.RUnlock()
+	return len(fake.cleanTypeAttributesArgsForCall)
+}
+
+func (fake *FakeStaticData) CleanTypeAttributesCalls(stub func(context.Context, database.Tx) error) {
+	fake.cleanTypeAttributesMutex.Lock()
+	defer fake.cleanTypeAttributesMutex.Unlock()
+	fake.CleanTypeAttributesStub = stub
+}
+
+func (fake *FakeStaticData) CleanTypeAttributesArgsForCall(i int) (context.Context, database.Tx) {
+	fake.cleanTypeAttributesMutex.RLock()
+	defer fake.cleanTypeAttributesMutex.RUnlock()
+	argsForCall := fake.cleanTypeAttributesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStaticData) CleanTypeAttributesReturns(result1 error) {
+	fake.cleanTypeAttributesMutex.Lock()
+	defer fake.cleanTypeAttributesMutex.Unlock()
+	fake.CleanTypeAttributesStub = nil
+	fake.cleanTypeAttributesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStaticData) CleanTypeAttributesReturnsOnCall(i int, result1 error) {
+	fake.cleanTypeAttributesMutex.Lock()
+	defer fake.cleanTypeAttributesMutex.Unlock()
+	fake.CleanTypeAttributesStub = nil
+	if fake.cleanTypeAttributesReturnsOnCall == nil {
+		fake.cleanTypeAttributesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.cleanTypeAttributesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStaticData) GetRequiredSkills(arg1 context.Context, arg2 int64, arg3 database.Tx) ([]staticdb.GetRequiredSkillsRow, error) {
+	fake.getRequiredSkillsMutex.Lock()
+	ret, specificReturn := fake.getRequiredSkillsReturnsOnCall[len(fake.getRequiredSkillsArgsForCall)]
+	fake.getRequiredSkillsArgsForCall = append(fake.getRequiredSkillsArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 database.Tx
+	}{arg1, arg2, arg3})
+	stub := fake.GetRequiredSkillsStub
+	fakeReturns := fake.getRequiredSkillsReturns
+	fake.recordInvocation("GetRequiredSkills", []interface{}{arg1, arg2, arg3})
+	fake.getRequiredSkillsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStaticData) GetRequiredSkillsCallCount() int {
+	fake.getRequiredSkillsMutex.RLock()
+	defer fake.getRequiredSkillsMutex.RUnlock()
+	return len(fake.getRequiredSkillsArgsForCall)
+}
+
+func (fake *FakeStaticData) GetRequiredSkillsCalls(stub func(context.Context, int64, database.Tx) ([]staticdb.GetRequiredSkillsRow, error)) {
+	fake.getRequiredSkillsMutex.Lock()
+	defer fake.getRequiredSkillsMutex.Unlock()
+	fake.GetRequiredSkillsStub = stub
+}
+
+func (fake *FakeStaticData) GetRequiredSkillsArgsForCall(i int) (context.Context, int64, database.Tx) {
+	fake.getRequiredSkillsMutex.RLock()
+	defer fake.getRequiredSkillsMutex.RUnlock()
+	argsForCall := fake.getRequiredSkillsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStaticData) GetRequiredSkillsReturns(result1 []staticdb.GetRequiredSkillsRow, result2 error) {
+	fake.getRequiredSkillsMutex.Lock()
+	defer fake.getRequiredSkillsMutex.Unlock()
+	fake.GetRequiredSkillsStub = nil
+	fake.getRequiredSkillsReturns = struct {
+		result1 []staticdb.GetRequiredSkillsRow
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStaticData) GetRequiredSkillsReturnsOnCall(i int, result1 []staticdb.GetRequiredSkillsRow, result2 error) {
+	fake.getRequiredSkillsMutex.Lock()
+	defer fake.getRequiredSkillsMutex.Unlock()
+	fake.GetRequiredSkillsStub = nil
+	if fake.getRequiredSkillsReturnsOnCall == nil {
+		fake.getRequiredSkillsReturnsOnCall = make(map[int]struct {
+			result1 []staticdb.GetRequiredSkillsRow
+			result2 error
+		})
+	}
+	fake.getRequiredSkillsReturnsOnCall[i] = struct {
+		result1 []staticdb.GetRequiredSkillsRow
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeStaticData) GetSkillIDByName(arg1 context.Context, arg2 string, arg3 database.Tx) (int64, error) {
 	fake.getSkillIDByNameMutex.Lock()
 	ret, specificReturn := fake.getSkillIDByNameReturnsOnCall[len(fake.getSkillIDByNameArgsForCall)]
@@ -416,19 +672,93 @@ func (fake *FakeStaticData) GetTableNamesReturnsOnCall(i int, result1 []string,
 	}{result1, result2}
 }
 
+func (fake *FakeStaticData) GetTypeIDByName(arg1 context.Context, arg2 string, arg3 database.Tx) (int64, error) {
+	fake.getTypeIDByNameMutex.Lock()
+	ret, specificReturn := fake.getTypeIDByNameReturnsOnCall[len(fake.getTypeIDByNameArgsForCall)]
+	fake.getTypeIDByNameArgsForCall = append(fake.getTypeIDByNameArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 database.Tx
+	}{arg1, arg2, arg3})
+	stub := fake.GetTypeIDByNameStub
+	fakeReturns := fake.getTypeIDByNameReturns
+	fake.recordInvocation("GetTypeIDByName", []interface{}{arg1, arg2, arg3})
+	fake.getTypeIDByNameMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStaticData) GetTypeIDByNameCallCount() int {
+	fake.getTypeIDByNameMutex.RLock()
+	defer fake.getTypeIDByNameMutex.RUnlock()
+	return len(fake.getTypeIDByNameArgsForCall)
+}
+
+func (fake *FakeStaticData) GetTypeIDByNameCalls(stub func(context.Context, string, database.Tx) (int64, error)) {
+	fake.getTypeIDByNameMutex.Lock()
+	defer fake.getTypeIDByNameMutex.Unlock()
+	fake.GetTypeIDByNameStub = stub
+}
+
+func (fake *FakeStaticData) GetTypeIDByNameArgsForCall(i int) (context.Context, string, database.Tx) {
+	fake.getTypeIDByNameMutex.RLock()
+	defer fake.getTypeIDByNameMutex.RUnlock()
+	argsForCall := fake.getTypeIDByNameArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStaticData) GetTypeIDByNameReturns(result1 int64, result2 error) {
+	fake.getTypeIDByNameMutex.Lock()
+	defer fake.getTypeIDByNameMutex.Unlock()
+	fake.GetTypeIDByNameStub = nil
+	fake.getTypeIDByNameReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStaticData) GetTypeIDByNameReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.getTypeIDByNameMutex.Lock()
+	defer fake.getTypeIDByNameMutex.Unlock()
+	fake.GetTypeIDByNameStub = nil
+	if fake.getTypeIDByNameReturnsOnCall == nil {
+		fake.getTypeIDByNameReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.getTypeIDByNameReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeStaticData) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.batchGetSkillNamesMutex.RLock()
 	defer fake.batchGetSkillNamesMutex.RUnlock()
+	fake.batchGetSkillRanksMutex.RLock()
+	defer fake.batchGetSkillRanksMutex.RUnlock()
 	fake.cleanTranslationsMutex.RLock()
 	defer fake.cleanTranslationsMutex.RUnlock()
+	fake.cleanTypeAttributesMutex.RLock()
+	defer fake.cleanTypeAttributesMutex.RUnlock()
+	fake.getRequiredSkillsMutex.RLock()
+	defer fake.getRequiredSkillsMutex.RUnlock()
 	fake.getSkillIDByNameMutex.RLock()
 	defer fake.getSkillIDByNameMutex.RUnlock()
 	fake.getSkillNameMutex.RLock()
 	defer fake.getSkillNameMutex.RUnlock()
 	fake.getTableNamesMutex.RLock()
 	defer fake.getTableNamesMutex.RUnlock()
+	fake.getTypeIDByNameMutex.RLock()
+	defer fake.getTypeIDByNameMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/operators"
+)
+
+var ErrInvalidRoleExpr = errors.New("invalid role expression")
+
+// RoleExpr is one node of a role's requirement expression. A group combines
+// its Children with Operator; a leaf (TagID set) requires a single tag.
+type RoleExpr struct {
+	Operator operators.Operator
+	// MinCount is how many children an "atleast" group needs to match
+	MinCount int64
+	TagID    int64
+	Children []*RoleExpr
+}
+
+// NewRoleGroup builds a group node over children
+func NewRoleGroup(op operators.Operator, children ...*RoleExpr) *RoleExpr {
+	return &RoleExpr{Operator: op, Children: children}
+}
+
+// NewRoleAtleast builds an "atleast" group needing n of its children
+func NewRoleAtleast(n int64, children ...*RoleExpr) *RoleExpr {
+	return &RoleExpr{Operator: operators.OperatorAtleast, MinCount: n, Children: children}
+}
+
+// NewRoleLeaf builds a leaf node requiring tagID
+func NewRoleLeaf(tagID int64) *RoleExpr {
+	return &RoleExpr{TagID: tagID}
+}
+
+func (e *RoleExpr) IsLeaf() bool {
+	return e.TagID != 0
+}
+
+// Clone deep copies the expression, so editors can work on it without
+// touching the bound role data
+func (e *RoleExpr) Clone() *RoleExpr {
+	if e == nil {
+		return nil
+	}
+
+	c := &RoleExpr{Operator: e.Operator, MinCount: e.MinCount, TagID: e.TagID}
+	if len(e.Children) > 0 {
+		c.Children = make([]*RoleExpr, 0, len(e.Children))
+		for _, ch := range e.Children {
+			c.Children = append(c.Children, ch.Clone())
+		}
+	}
+	return c
+}
+
+// TagIDs lists every tag referenced in the expression once, in the order
+// they first appear
+func (e *RoleExpr) TagIDs() []int64 {
+	seen := map[int64]bool{}
+	ids := make([]int64, 0)
+
+	var walk func(n *RoleExpr)
+	walk = func(n *RoleExpr) {
+		if n.IsLeaf() {
+			if !seen[n.TagID] {
+				seen[n.TagID] = true
+				ids = append(ids, n.TagID)
+			}
+			return
+		}
+		for _, ch := range n.Children {
+			walk(ch)
+		}
+	}
+	walk(e)
+
+	return ids
+}
+
+// Validate checks that the root is a group, every group has a known operator,
+// "atleast" groups ask for between 1 and all of their children, and no leaf
+// has children
+func (e *RoleExpr) Validate() error {
+	if e == nil || e.IsLeaf() {
+		return errors.Wrap(ErrInvalidRoleExpr, "the root of a role expression must be a group")
+	}
+
+	var check func(n *RoleExpr) error
+	check = func(n *RoleExpr) error {
+		if n.IsLeaf() {
+			if len(n.Children) > 0 {
+				return errors.Wrap(ErrInvalidRoleExpr, "a tag cannot have children", "tag_id", n.TagID)
+			}
+			return nil
+		}
+
+		if !n.Operator.IsValid() {
+			return errors.Wrap(ErrInvalidRoleExpr, "unknown operator", "operator", n.Operator)
+		}
+		if n.Operator == operators.OperatorAtleast && (n.MinCount < 1 || n.MinCount > int64(len(n.Children))) {
+			return errors.Wrap(ErrInvalidRoleExpr, "an atleast group must need between 1 and all of its children", "min_count", n.MinCount, "children", len(n.Children))
+		}
+		for _, ch := range n.Children {
+			if err := check(ch); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return check(e)
+}
+
+// buildRoleExpr assembles stored nodes into a tree. A role without nodes gets
+// an empty "all" group; nodes whose parent is missing are dropped.
+func buildRoleExpr(nodes []RoleNode) *RoleExpr {
+	byID := make(map[int64]*RoleExpr, len(nodes))
+	for _, n := range nodes {
+		e := &RoleExpr{TagID: n.TagID.Int64}
+		if n.Operator.Valid {
+			e.Operator = operators.Operator(n.Operator.String)
+			e.MinCount = n.MinCount
+		}
+		byID[n.ID] = e
+	}
+
+	var root *RoleExpr
+	// nodes are ordered by parent and position, so children append in order
+	for _, n := range nodes {
+		if !n.ParentID.Valid {
+			if root == nil {
+				root = byID[n.ID]
+			}
+			continue
+		}
+
+		if parent, ok := byID[n.ParentID.Int64]; ok {
+			parent.Children = append(parent.Children, byID[n.ID])
+		}
+	}
+
+	if root == nil {
+		root = NewRoleGroup(operators.OperatorAll)
+	}
+
+	return root
+}
@@ -0,0 +1,46 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kava-forge/eve-alts/pkg/operators"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+func TestRoleExpr(t *testing.T) {
+	t.Parallel()
+
+	expr := repository.NewRoleGroup(operators.OperatorAll,
+		repository.NewRoleLeaf(2),
+		repository.NewRoleGroup(operators.OperatorAny, repository.NewRoleLeaf(1), repository.NewRoleLeaf(2)),
+	)
+	assert.NoError(t, expr.Validate())
+	assert.Equal(t, []int64{2, 1}, expr.TagIDs())
+
+	clone := expr.Clone()
+	assert.Equal(t, expr, clone)
+	clone.Children[1].Operator = operators.OperatorNone
+	assert.Equal(t, operators.OperatorAny, expr.Children[1].Operator)
+
+	tests := []struct {
+		name string
+		expr *repository.RoleExpr
+	}{
+		{name: "nil", expr: nil},
+		{name: "leaf root", expr: repository.NewRoleLeaf(1)},
+		{name: "bad operator", expr: repository.NewRoleGroup(operators.OperatorAll, repository.NewRoleGroup("most"))},
+		{name: "atleast zero", expr: repository.NewRoleAtleast(0, repository.NewRoleLeaf(1))},
+		{name: "atleast too many", expr: repository.NewRoleAtleast(2, repository.NewRoleLeaf(1))},
+		{name: "leaf with children", expr: repository.NewRoleGroup(operators.OperatorAll, &repository.RoleExpr{TagID: 1, Children: []*repository.RoleExpr{repository.NewRoleLeaf(2)}})},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.ErrorIs(t, tt.expr.Validate(), repository.ErrInvalidRoleExpr)
+		})
+	}
+}
@@ -12,15 +12,23 @@ import (
 	"github.com/kava-forge/eve-alts/pkg/telemetry"
 )
 
-type BatchGetSkillNamesRow = staticdb.BatchGetSkillNamesRow
+type (
+	BatchGetSkillNamesRow = staticdb.BatchGetSkillNamesRow
+	GetRequiredSkillsRow  = staticdb.GetRequiredSkillsRow
+	BatchGetSkillRanksRow = staticdb.BatchGetSkillRanksRow
+)
 
 //counterfeiter:generate . StaticData
 type StaticData interface {
 	GetTableNames(ctx context.Context, tx database.Tx) ([]string, error)
 	CleanTranslations(ctx context.Context, tx database.Tx) error
+	CleanTypeAttributes(ctx context.Context, tx database.Tx) error
 	GetSkillName(ctx context.Context, skillID int64, tx database.Tx) (string, error)
 	GetSkillIDByName(ctx context.Context, skillName string, tx database.Tx) (int64, error)
 	BatchGetSkillNames(ctx context.Context, skillIDs []int64, tx database.Tx) ([]BatchGetSkillNamesRow, error)
+	GetTypeIDByName(ctx context.Context, typeName string, tx database.Tx) (int64, error)
+	GetRequiredSkills(ctx context.Context, typeID int64, tx database.Tx) ([]GetRequiredSkillsRow, error)
+	BatchGetSkillRanks(ctx context.Context, skillIDs []int64, tx database.Tx) ([]BatchGetSkillRanksRow, error)
 }
 
 type staticDependencies interface {
@@ -75,6 +83,16 @@ func (r *StaticSqliteRepository) CleanTranslations(ctx context.Context, tx datab
 	return r.queries.CleanTranslations(ctx, r.db(tx))
 }
 
+func (r *StaticSqliteRepository) CleanTypeAttributes(ctx context.Context, tx database.Tx) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.static", "CleanTypeAttributes")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling CleanTypeAttributes")
+
+	return r.queries.CleanTypeAttributes(ctx, r.db(tx))
+}
+
 func (r *StaticSqliteRepository) GetSkillName(ctx context.Context, skillID int64, tx database.Tx) (_ string, err error) {
 	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.static", "GetSkillName")
 	defer telemetry.EndSpan(span, &err)
@@ -128,3 +146,53 @@ func (r *StaticSqliteRepository) BatchGetSkillNames(ctx context.Context, skillID
 
 	return rows, nil
 }
+
+func (r *StaticSqliteRepository) GetTypeIDByName(ctx context.Context, typeName string, tx database.Tx) (_ int64, err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.static", "GetTypeIDByName")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling GetTypeIDByName", "type_name", typeName)
+
+	id, err := r.queries.GetTypeIDFromName(ctx, r.db(tx), staticdb.GetTypeIDFromNameParams{
+		TypeNameLower: strings.ToLower(typeName),
+		Language:      "en",
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetRequiredSkills returns the skills (and levels) directly required to use
+// the given type. Skills are types too, so this also returns a skill's prerequisites.
+func (r *StaticSqliteRepository) GetRequiredSkills(ctx context.Context, typeID int64, tx database.Tx) (_ []GetRequiredSkillsRow, err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.static", "GetRequiredSkills")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling GetRequiredSkills", "type_id", typeID)
+
+	rows, err := r.queries.GetRequiredSkills(ctx, r.db(tx), typeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *StaticSqliteRepository) BatchGetSkillRanks(ctx context.Context, skillIDs []int64, tx database.Tx) (_ []BatchGetSkillRanksRow, err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.static", "BatchGetSkillRanks")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling BatchGetSkillRanks")
+
+	rows, err := r.queries.BatchGetSkillRanks(ctx, r.db(tx), skillIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
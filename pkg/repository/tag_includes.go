@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"sort"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+)
+
+var ErrTagCycle = errors.New("tag includes would form a cycle")
+
+// ResolveTagIncludes fills in Inherited for every tag from the Skills of the
+// tags it includes, directly or transitively. Tags with an ID of 0 (deleted)
+// are ignored, both as includers and as includes.
+func ResolveTagIncludes(tags []*TagDBData) {
+	byID := make(map[int64]*TagDBData, len(tags))
+	for _, t := range tags {
+		if t != nil && t.Tag.ID != 0 {
+			byID[t.Tag.ID] = t
+		}
+	}
+
+	for _, t := range byID {
+		t.Inherited = inheritedSkills(t, byID)
+	}
+}
+
+func inheritedSkills(tag *TagDBData, byID map[int64]*TagDBData) []TagSkill {
+	best := make(map[int64]TagSkill)
+
+	// visited also guards against cycles that slipped into the data
+	visited := map[int64]bool{tag.Tag.ID: true}
+	var walk func(t *TagDBData)
+	walk = func(t *TagDBData) {
+		for _, inc := range t.Includes {
+			it, ok := byID[inc.ID]
+			if !ok || visited[inc.ID] {
+				continue
+			}
+			visited[inc.ID] = true
+
+			for _, sk := range it.Skills {
+				if cur, ok := best[sk.SkillID]; ok {
+					sk = mergeTagSkill(cur, sk)
+				}
+				best[sk.SkillID] = sk
+			}
+			walk(it)
+		}
+	}
+	walk(tag)
+
+	own := make(map[int64]TagSkill, len(tag.Skills))
+	for _, sk := range tag.Skills {
+		own[sk.SkillID] = sk
+	}
+
+	inherited := make([]TagSkill, 0, len(best))
+	for _, sk := range best {
+		if o, ok := own[sk.SkillID]; ok && o.SkillLevel >= sk.SkillLevel && o.RecommendedLevel >= sk.RecommendedLevel {
+			continue
+		}
+		inherited = append(inherited, sk)
+	}
+	sort.Slice(inherited, func(i, j int) bool { return inherited[i].SkillID < inherited[j].SkillID })
+
+	return inherited
+}
+
+// AllSkills is the flattened requirement set: the tag's own skills, raised to
+// any higher required or recommended level from an included tag
+func (t TagDBData) AllSkills() []TagSkill {
+	if len(t.Inherited) == 0 {
+		return t.Skills
+	}
+
+	all := make(map[int64]TagSkill, len(t.Skills)+len(t.Inherited))
+	for _, sk := range t.Skills {
+		all[sk.SkillID] = sk
+	}
+	for _, sk := range t.Inherited {
+		if cur, ok := all[sk.SkillID]; ok {
+			sk = mergeTagSkill(cur, sk)
+		}
+		all[sk.SkillID] = sk
+	}
+
+	skills := make([]TagSkill, 0, len(all))
+	for _, sk := range all {
+		skills = append(skills, sk)
+	}
+	sort.Slice(skills, func(i, j int) bool { return skills[i].SkillID < skills[j].SkillID })
+
+	return skills
+}
+
+// mergeTagSkill keeps the higher required and recommended levels of a and b.
+// TagID follows whichever sets the required level.
+func mergeTagSkill(a, b TagSkill) TagSkill {
+	m := a
+	if b.SkillLevel > a.SkillLevel {
+		m = b
+	}
+	m.RecommendedLevel = max(a.RecommendedLevel, b.RecommendedLevel)
+	return m
+}
+
+// tagIncludeCreatesCycle reports whether adding tagID -> includedTagID to the
+// existing includes would let a tag (eventually) include itself
+func tagIncludeCreatesCycle(includes []TagInclude, tagID, includedTagID int64) bool {
+	if tagID == includedTagID {
+		return true
+	}
+
+	edges := make(map[int64][]int64, len(includes))
+	for _, ti := range includes {
+		edges[ti.TagID] = append(edges[ti.TagID], ti.IncludedTagID)
+	}
+
+	visited := make(map[int64]bool, len(edges))
+	stack := []int64{includedTagID}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if cur == tagID {
+			return true
+		}
+		if visited[cur] {
+			continue
+		}
+		visited[cur] = true
+		stack = append(stack, edges[cur]...)
+	}
+
+	return false
+}
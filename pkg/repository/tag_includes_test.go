@@ -0,0 +1,68 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+func TestResolveTagIncludes(t *testing.T) {
+	t.Parallel()
+
+	basic := &repository.TagDBData{
+		Tag: repository.Tag{ID: 1, Name: "Basic Logi"},
+		Skills: []repository.TagSkill{
+			{TagID: 1, SkillID: 100, SkillLevel: 3},
+			{TagID: 1, SkillID: 101, SkillLevel: 4},
+		},
+	}
+	advanced := &repository.TagDBData{
+		Tag: repository.Tag{ID: 2, Name: "Advanced Logi"},
+		Skills: []repository.TagSkill{
+			{TagID: 2, SkillID: 100, SkillLevel: 5}, // already higher than Basic Logi
+			{TagID: 2, SkillID: 102, SkillLevel: 1},
+		},
+		Includes: []repository.Tag{basic.Tag},
+	}
+	guardian := &repository.TagDBData{
+		Tag:      repository.Tag{ID: 3, Name: "Guardian"},
+		Skills:   []repository.TagSkill{{TagID: 3, SkillID: 101, SkillLevel: 2}},
+		Includes: []repository.Tag{advanced.Tag},
+	}
+	deleted := &repository.TagDBData{
+		Tag:    repository.Tag{ID: 0, Name: "Deleted"},
+		Skills: []repository.TagSkill{{TagID: 4, SkillID: 999, SkillLevel: 5}},
+	}
+	// cycles are rejected on write, but resolution must still terminate
+	loopA := &repository.TagDBData{Tag: repository.Tag{ID: 5}, Skills: []repository.TagSkill{{TagID: 5, SkillID: 200, SkillLevel: 1}}}
+	loopB := &repository.TagDBData{Tag: repository.Tag{ID: 6}, Skills: []repository.TagSkill{{TagID: 6, SkillID: 201, SkillLevel: 1}}}
+	loopA.Includes = []repository.Tag{loopB.Tag}
+	loopB.Includes = []repository.Tag{loopA.Tag}
+	guardian.Includes = append(guardian.Includes, repository.Tag{ID: 4})
+
+	repository.ResolveTagIncludes([]*repository.TagDBData{basic, advanced, guardian, deleted, loopA, loopB})
+
+	assert.Empty(t, basic.Inherited)
+	assert.Equal(t, basic.Skills, basic.AllSkills())
+
+	assert.Equal(t, []repository.TagSkill{
+		{TagID: 1, SkillID: 101, SkillLevel: 4},
+	}, advanced.Inherited)
+	assert.Equal(t, []repository.TagSkill{
+		{TagID: 2, SkillID: 100, SkillLevel: 5},
+		{TagID: 1, SkillID: 101, SkillLevel: 4},
+		{TagID: 2, SkillID: 102, SkillLevel: 1},
+	}, advanced.AllSkills())
+
+	// transitive, and the inherited 101 beats the tag's own lower level
+	assert.Equal(t, []repository.TagSkill{
+		{TagID: 2, SkillID: 100, SkillLevel: 5},
+		{TagID: 1, SkillID: 101, SkillLevel: 4},
+		{TagID: 2, SkillID: 102, SkillLevel: 1},
+	}, guardian.Inherited)
+
+	assert.Equal(t, []repository.TagSkill{{TagID: 6, SkillID: 201, SkillLevel: 1}}, loopA.Inherited)
+	assert.Equal(t, []repository.TagSkill{{TagID: 5, SkillID: 200, SkillLevel: 1}}, loopB.Inherited)
+}
@@ -0,0 +1,624 @@
+package repository
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+	"github.com/kava-forge/eve-alts/lib/logging/level"
+
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/keys"
+	"github.com/kava-forge/eve-alts/pkg/repository/internal/appdb"
+	"github.com/kava-forge/eve-alts/pkg/telemetry"
+)
+
+var (
+	ErrVaultLocked      = errors.New("token vault is locked")
+	ErrWrongVaultKey    = errors.New("wrong token vault key")
+	ErrVaultModeChange  = errors.New("token vault mode changed")
+	ErrKeyFileMissing   = errors.New("token vault key file is missing")
+	ErrBadKeyFile       = errors.New("invalid token vault key file")
+	ErrEmptyPassphrase  = errors.New("empty token vault passphrase")
+	ErrUnknownVaultMode = errors.New("unknown token vault mode")
+	ErrVaultKeyChanged  = errors.New("token vault key changed")
+)
+
+// sealedPrefix marks a sealed token column. Columns without it are plaintext
+// rows from before the vault, and are sealed the next time it is unlocked.
+const sealedPrefix = "vault1:"
+
+// checkValue is sealed with the key and stored, so a wrong key is caught on
+// unlock rather than on the next token refresh
+const checkValue = "eve-alts token vault"
+
+// VaultKey is an XChaCha20-Poly1305 key
+type VaultKey [chacha20poly1305.KeySize]byte
+
+// GenerateVaultKey makes a random key, for a new key file
+func GenerateVaultKey() (VaultKey, error) {
+	var k VaultKey
+	_, err := rand.Read(k[:])
+	return k, errors.Wrap(err, "could not generate vault key")
+}
+
+// KDFParams are the Argon2id settings a passphrase key was derived with
+type KDFParams struct {
+	Salt    []byte
+	Time    uint32
+	Memory  uint32 // KiB
+	Threads uint8
+}
+
+// NewKDFParams picks a fresh salt with the recommended Argon2id settings
+func NewKDFParams() (KDFParams, error) {
+	p := KDFParams{Salt: make([]byte, 16), Time: 3, Memory: 64 * 1024, Threads: 4}
+	_, err := rand.Read(p.Salt)
+	return p, errors.Wrap(err, "could not generate salt")
+}
+
+// DeriveVaultKey derives a key from a passphrase
+func DeriveVaultKey(passphrase string, p KDFParams) (VaultKey, error) {
+	var k VaultKey
+	if passphrase == "" {
+		return k, ErrEmptyPassphrase
+	}
+	copy(k[:], argon2.IDKey([]byte(passphrase), p.Salt, p.Time, p.Memory, p.Threads, uint32(len(k))))
+	return k, nil
+}
+
+// ReadKeyFile reads a base64 key written by WriteKeyFile
+func ReadKeyFile(path string) (VaultKey, error) {
+	var k VaultKey
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return k, errors.Wrap(ErrKeyFileMissing, "could not read key file", "path", path)
+	}
+	if err != nil {
+		return k, errors.Wrap(err, "could not read key file", "path", path)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil || len(raw) != len(k) {
+		return k, errors.Wrap(ErrBadKeyFile, "key file must hold a base64 encoded 32 byte key", "path", path)
+	}
+	copy(k[:], raw)
+	return k, nil
+}
+
+// WriteKeyFile writes k to path, readable only by the current user. An
+// existing file is replaced atomically.
+func WriteKeyFile(path string, k VaultKey) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return errors.Wrap(err, "could not create key file directory", "path", path)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(base64.StdEncoding.EncodeToString(k[:])+"\n"), 0o600); err != nil {
+		return errors.Wrap(err, "could not write key file", "path", tmp)
+	}
+	return errors.Wrap(os.Rename(tmp, path), "could not replace key file", "path", path)
+}
+
+// TokenVault seals token columns before they are written and opens them
+// after they are read. It starts locked; until it is unlocked, tokens can be
+// neither read nor written.
+type TokenVault struct {
+	mu      sync.RWMutex
+	current cipher.AEAD
+	// previous is the key being rotated away from, tried when current fails
+	previous cipher.AEAD
+}
+
+func NewTokenVault() *TokenVault {
+	return &TokenVault{}
+}
+
+func newAEAD(k VaultKey) cipher.AEAD {
+	aead, err := chacha20poly1305.NewX(k[:])
+	if err != nil {
+		// only possible with a key of the wrong size, which VaultKey rules out
+		panic(err)
+	}
+	return aead
+}
+
+// Unlock sets the key, replacing any previous one
+func (v *TokenVault) Unlock(k VaultKey) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.current = newAEAD(k)
+	v.previous = nil
+}
+
+func (v *TokenVault) Lock() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.current = nil
+	v.previous = nil
+}
+
+// Locked is true for a nil vault as well
+func (v *TokenVault) Locked() bool {
+	if v == nil {
+		return true
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.current == nil
+}
+
+// beginRotation seals with k from now on, while still opening with the old
+// key. endRotation either forgets the old key or goes back to it.
+func (v *TokenVault) beginRotation(k VaultKey) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.previous = v.current
+	v.current = newAEAD(k)
+}
+
+func (v *TokenVault) endRotation(keep bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if !keep {
+		v.current = v.previous
+	}
+	v.previous = nil
+}
+
+// additionalData binds a sealed value to its row and column, so values cannot
+// be swapped between characters
+func additionalData(charID int64, column string) []byte {
+	return []byte(fmt.Sprintf("%d/%s", charID, column))
+}
+
+// Seal encrypts a column value for a character
+func (v *TokenVault) Seal(charID int64, column, plaintext string) (string, error) {
+	if v == nil {
+		return "", ErrVaultLocked
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if v.current == nil {
+		return "", ErrVaultLocked
+	}
+
+	nonce := make([]byte, v.current.NonceSize(), v.current.NonceSize()+len(plaintext)+v.current.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", errors.Wrap(err, "could not generate nonce")
+	}
+	sealed := v.current.Seal(nonce, nonce, []byte(plaintext), additionalData(charID, column))
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a column value sealed by Seal. Plaintext values are returned
+// as they are.
+func (v *TokenVault) Open(charID int64, column, stored string) (string, error) {
+	if !IsSealed(stored) {
+		return stored, nil
+	}
+	if v == nil {
+		return "", ErrVaultLocked
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, sealedPrefix))
+	if err != nil {
+		return "", errors.Wrap(ErrWrongVaultKey, "sealed value is not valid base64", "column", column)
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if v.current == nil {
+		return "", ErrVaultLocked
+	}
+
+	for _, aead := range []cipher.AEAD{v.current, v.previous} {
+		if aead == nil || len(raw) < aead.NonceSize() {
+			continue
+		}
+		nonce, ciphertext := raw[:aead.NonceSize()], raw[aead.NonceSize():]
+		if plain, err := aead.Open(nil, nonce, ciphertext, additionalData(charID, column)); err == nil {
+			return string(plain), nil
+		}
+	}
+	return "", errors.Wrap(ErrWrongVaultKey, "could not open sealed value", "column", column)
+}
+
+// IsSealed reports whether a stored column value was sealed by a vault
+func IsSealed(stored string) bool {
+	return strings.HasPrefix(stored, sealedPrefix)
+}
+
+// VaultMode is where the vault key comes from
+type VaultMode string
+
+const (
+	// VaultModeKeyFile keeps a random key in a file beside the database
+	VaultModeKeyFile VaultMode = "keyfile"
+	// VaultModePassphrase derives the key from a passphrase asked for on start
+	VaultModePassphrase VaultMode = "passphrase"
+)
+
+// ParseVaultMode checks a configured vault mode; empty means a key file
+func ParseVaultMode(s string) (VaultMode, error) {
+	switch VaultMode(strings.ToLower(s)) {
+	case "", VaultModeKeyFile:
+		return VaultModeKeyFile, nil
+	case VaultModePassphrase:
+		return VaultModePassphrase, nil
+	default:
+		return "", errors.Wrap(ErrUnknownVaultMode, "vault mode must be keyfile or passphrase", "mode", s)
+	}
+}
+
+// KeySource says how to get the vault key. Passphrase is only used in
+// passphrase mode, and KeyFile only in key file mode.
+type KeySource struct {
+	Mode       VaultMode
+	KeyFile    string
+	Passphrase string
+}
+
+// unlockKey loads or derives the key for a stored setting. With no setting,
+// the vault is new: a passphrase gets fresh KDF parameters, and a missing key
+// file is created.
+func (s KeySource) unlockKey(setting *VaultSetting) (VaultKey, KDFParams, error) {
+	switch s.Mode {
+	case VaultModePassphrase:
+		if setting == nil {
+			return s.newKey()
+		}
+		p := KDFParams{
+			Salt:    setting.KdfSalt,
+			Time:    uint32(setting.KdfTime),
+			Memory:  uint32(setting.KdfMemory),
+			Threads: uint8(setting.KdfThreads),
+		}
+		k, err := DeriveVaultKey(s.Passphrase, p)
+		return k, p, err
+	case VaultModeKeyFile:
+		k, err := ReadKeyFile(s.KeyFile)
+		if errors.Is(err, ErrKeyFileMissing) && setting == nil {
+			if k, _, err = s.newKey(); err != nil {
+				return k, KDFParams{}, err
+			}
+			err = WriteKeyFile(s.KeyFile, k)
+		}
+		return k, KDFParams{}, err
+	default:
+		return VaultKey{}, KDFParams{}, errors.Wrap(ErrUnknownVaultMode, "cannot load vault key", "mode", string(s.Mode))
+	}
+}
+
+// newKey makes a key that has never been used: a random one for a key file,
+// or one derived with a fresh salt for a passphrase. Key files are not written.
+func (s KeySource) newKey() (VaultKey, KDFParams, error) {
+	switch s.Mode {
+	case VaultModePassphrase:
+		p, err := NewKDFParams()
+		if err != nil {
+			return VaultKey{}, p, err
+		}
+		k, err := DeriveVaultKey(s.Passphrase, p)
+		return k, p, err
+	case VaultModeKeyFile:
+		k, err := GenerateVaultKey()
+		return k, KDFParams{}, err
+	default:
+		return VaultKey{}, KDFParams{}, errors.Wrap(ErrUnknownVaultMode, "cannot make vault key", "mode", string(s.Mode))
+	}
+}
+
+// newSetting describes a key, so it can be checked on the next unlock
+func newSetting(v *TokenVault, mode VaultMode, p KDFParams) (VaultSetting, error) {
+	check, err := v.Seal(0, "check", checkValue)
+	if err != nil {
+		return VaultSetting{}, err
+	}
+	salt := p.Salt
+	if salt == nil {
+		// key files have no salt, and the column is not nullable
+		salt = []byte{}
+	}
+	return VaultSetting{
+		ID:         1,
+		Mode:       string(mode),
+		KdfSalt:    salt,
+		KdfTime:    int64(p.Time),
+		KdfMemory:  int64(p.Memory),
+		KdfThreads: int64(p.Threads),
+		CheckValue: check,
+	}, nil
+}
+
+// VaultStatus summarizes the vault for people checking on it
+type VaultStatus struct {
+	// Mode is empty until the vault is first unlocked
+	Mode   VaultMode
+	Locked bool
+	// Sealed and Plain count the stored tokens in each state
+	Sealed int
+	Plain  int
+}
+
+func (r *AppSqliteRepository) getVaultSetting(ctx context.Context, tx database.Tx) (*VaultSetting, error) {
+	setting, err := r.queries.GetVaultSetting(ctx, r.db(tx))
+	if errors.Is(err, database.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not GetVaultSetting")
+	}
+	return &setting, nil
+}
+
+func (r *AppSqliteRepository) upsertVaultSetting(ctx context.Context, setting VaultSetting, tx database.Tx) error {
+	err := r.queries.UpsertVaultSetting(ctx, tx, appdb.UpsertVaultSettingParams{
+		Mode:       setting.Mode,
+		KdfSalt:    setting.KdfSalt,
+		KdfTime:    setting.KdfTime,
+		KdfMemory:  setting.KdfMemory,
+		KdfThreads: setting.KdfThreads,
+		CheckValue: setting.CheckValue,
+	})
+	return errors.Wrap(err, "could not UpsertVaultSetting")
+}
+
+// resealTokens opens every stored token and seals it again with the current
+// key. Plaintext tokens are sealed for the first time.
+func (r *AppSqliteRepository) resealTokens(ctx context.Context, onlyPlain bool, tx database.Tx) error {
+	vault := r.deps.Vault()
+
+	toks, err := r.queries.GetAllTokens(ctx, tx)
+	if err != nil {
+		return errors.Wrap(err, "could not GetAllTokens")
+	}
+
+	for _, tok := range toks {
+		if onlyPlain && IsSealed(tok.AccessToken) && IsSealed(tok.RefreshToken) {
+			continue
+		}
+
+		access, err := vault.Open(tok.CharacterID, "access_token", tok.AccessToken)
+		if err != nil {
+			return errors.Wrap(err, "could not open access token", keys.CharacterID, tok.CharacterID)
+		}
+		refresh, err := vault.Open(tok.CharacterID, "refresh_token", tok.RefreshToken)
+		if err != nil {
+			return errors.Wrap(err, "could not open refresh token", keys.CharacterID, tok.CharacterID)
+		}
+
+		if access, err = vault.Seal(tok.CharacterID, "access_token", access); err != nil {
+			return err
+		}
+		if refresh, err = vault.Seal(tok.CharacterID, "refresh_token", refresh); err != nil {
+			return err
+		}
+
+		if err := r.queries.UpdateTokenSecrets(ctx, tx, appdb.UpdateTokenSecretsParams{
+			AccessToken:  access,
+			RefreshToken: refresh,
+			CharacterID:  tok.CharacterID,
+		}); err != nil {
+			return errors.Wrap(err, "could not UpdateTokenSecrets", keys.CharacterID, tok.CharacterID)
+		}
+	}
+
+	return nil
+}
+
+// checkVaultKey makes sure the vault's key still matches the stored setting
+// before anything is sealed with it. Another process, such as vault rotate or
+// vault reset, may have changed the key since this one unlocked the vault, and
+// tokens sealed with the old key could never be opened again. On a mismatch
+// the vault is locked.
+func (r *AppSqliteRepository) checkVaultKey(ctx context.Context, tx database.Tx) error {
+	vault := r.deps.Vault()
+	if vault.Locked() {
+		return database.NonRetryableError(ErrVaultLocked)
+	}
+
+	setting, err := r.getVaultSetting(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if setting != nil {
+		if v, err := vault.Open(0, "check", setting.CheckValue); err == nil && v == checkValue {
+			return nil
+		}
+	}
+
+	vault.Lock()
+	return database.NonRetryableError(errors.Wrap(ErrVaultKeyChanged, "the token vault key was changed by another process; restart to unlock it again"))
+}
+
+// UnlockVault loads the vault key from src and checks it against the stored
+// setting. The first unlock sets the vault up. Any tokens still stored as
+// plaintext are sealed.
+func (r *AppSqliteRepository) UnlockVault(ctx context.Context, src KeySource) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "UnlockVault")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling UnlockVault", "mode", src.Mode)
+
+	setting, err := r.getVaultSetting(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if setting != nil && VaultMode(setting.Mode) != src.Mode {
+		return errors.Wrap(ErrVaultModeChange, "the token vault uses another mode; run vault rotate to change it", "stored", setting.Mode, "configured", string(src.Mode))
+	}
+
+	k, p, err := src.unlockKey(setting)
+	if err != nil {
+		return err
+	}
+
+	if setting != nil {
+		check := NewTokenVault()
+		check.Unlock(k)
+		if v, err := check.Open(0, "check", setting.CheckValue); err != nil || v != checkValue {
+			return errors.Wrap(ErrWrongVaultKey, "the vault key does not match", "mode", setting.Mode)
+		}
+	}
+
+	vault := r.deps.Vault()
+	vault.Unlock(k)
+
+	inner := func(ctx context.Context, tx database.Tx) error {
+		if setting == nil {
+			ns, err := newSetting(vault, src.Mode, p)
+			if err != nil {
+				return err
+			}
+			if err := r.upsertVaultSetting(ctx, ns, tx); err != nil {
+				return err
+			}
+		}
+		return r.resealTokens(ctx, true, tx)
+	}
+
+	if err = database.TransactWithRetries(ctx, r.deps.Telemetry(), r.deps.Logger(), r.deps.DB(), &sql.TxOptions{}, inner); err != nil {
+		vault.Lock()
+		return errors.Wrap(err, "could not TransactWithRetries")
+	}
+	return nil
+}
+
+// RotateVault seals every token with a new key from to, which may use
+// another mode. A new key file is only moved into place once the tokens
+// sealed with it are committed.
+func (r *AppSqliteRepository) RotateVault(ctx context.Context, to KeySource) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "RotateVault")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling RotateVault", "mode", to.Mode)
+
+	vault := r.deps.Vault()
+	if vault.Locked() {
+		return ErrVaultLocked
+	}
+
+	k, p, err := to.newKey()
+	if err != nil {
+		return err
+	}
+
+	var pending string
+	if to.Mode == VaultModeKeyFile {
+		pending = to.KeyFile + ".new"
+		if err := WriteKeyFile(pending, k); err != nil {
+			return err
+		}
+	}
+
+	vault.beginRotation(k)
+
+	inner := func(ctx context.Context, tx database.Tx) error {
+		ns, err := newSetting(vault, to.Mode, p)
+		if err != nil {
+			return err
+		}
+		if err := r.upsertVaultSetting(ctx, ns, tx); err != nil {
+			return err
+		}
+		return r.resealTokens(ctx, false, tx)
+	}
+
+	if err = database.TransactWithRetries(ctx, r.deps.Telemetry(), r.deps.Logger(), r.deps.DB(), &sql.TxOptions{}, inner); err != nil {
+		vault.endRotation(false)
+		if pending != "" {
+			_ = os.Remove(pending)
+		}
+		return errors.Wrap(err, "could not TransactWithRetries")
+	}
+	vault.endRotation(true)
+
+	if pending != "" {
+		if err := os.Rename(pending, to.KeyFile); err != nil {
+			return errors.Wrap(err, "tokens are sealed with the new key, but it could not be moved into place", "from", pending, "to", to.KeyFile)
+		}
+	}
+	return nil
+}
+
+// VaultStatus reports the vault mode and how many tokens are sealed
+func (r *AppSqliteRepository) VaultStatus(ctx context.Context) (status VaultStatus, err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "VaultStatus")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling VaultStatus")
+
+	status.Locked = r.deps.Vault().Locked()
+
+	setting, err := r.getVaultSetting(ctx, nil)
+	if err != nil {
+		return status, err
+	}
+	if setting != nil {
+		status.Mode = VaultMode(setting.Mode)
+	}
+
+	toks, err := r.queries.GetAllTokens(ctx, r.db(nil))
+	if err != nil {
+		return status, errors.Wrap(err, "could not GetAllTokens")
+	}
+	for _, tok := range toks {
+		if IsSealed(tok.AccessToken) && IsSealed(tok.RefreshToken) {
+			status.Sealed++
+		} else {
+			status.Plain++
+		}
+	}
+
+	return status, nil
+}
+
+// ResetVault deletes every stored token along with the vault setting, for
+// when the passphrase or key file is lost. Characters are kept, but must be
+// added again to refresh them.
+func (r *AppSqliteRepository) ResetVault(ctx context.Context) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, r.deps.Telemetry(), "repository.app", "ResetVault")
+	defer telemetry.EndSpan(span, &err)
+
+	logger := r.deps.Logger()
+	level.Debug(logger).Message("calling ResetVault")
+
+	inner := func(ctx context.Context, tx database.Tx) error {
+		if err := r.queries.DeleteAllTokens(ctx, tx); err != nil {
+			return errors.Wrap(err, "could not DeleteAllTokens")
+		}
+		return errors.Wrap(r.queries.DeleteVaultSetting(ctx, tx), "could not DeleteVaultSetting")
+	}
+
+	if err = database.TransactWithRetries(ctx, r.deps.Telemetry(), r.deps.Logger(), r.deps.DB(), &sql.TxOptions{}, inner); err != nil {
+		return errors.Wrap(err, "could not TransactWithRetries")
+	}
+	r.deps.Vault().Lock()
+	return nil
+}
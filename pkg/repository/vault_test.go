@@ -0,0 +1,192 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/testhelpers"
+)
+
+func newUnlockedVault(t *testing.T) (*repository.TokenVault, repository.VaultKey) {
+	t.Helper()
+
+	k, err := repository.GenerateVaultKey()
+	require.NoError(t, err)
+
+	v := repository.NewTokenVault()
+	v.Unlock(k)
+	return v, k
+}
+
+func TestTokenVault_SealOpen(t *testing.T) {
+	t.Parallel()
+
+	v, k := newUnlockedVault(t)
+
+	sealed, err := v.Seal(42, "access_token", "secret-token")
+	require.NoError(t, err)
+	assert.True(t, repository.IsSealed(sealed))
+	assert.NotContains(t, sealed, "secret-token")
+
+	again, err := v.Seal(42, "access_token", "secret-token")
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, again, "each seal uses a fresh nonce")
+
+	got, err := v.Open(42, "access_token", sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "secret-token", got)
+
+	_, err = v.Open(43, "access_token", sealed)
+	assert.ErrorIs(t, err, repository.ErrWrongVaultKey, "sealed for another character")
+
+	_, err = v.Open(42, "refresh_token", sealed)
+	assert.ErrorIs(t, err, repository.ErrWrongVaultKey, "sealed for another column")
+
+	other, _ := newUnlockedVault(t)
+	_, err = other.Open(42, "access_token", sealed)
+	assert.ErrorIs(t, err, repository.ErrWrongVaultKey)
+
+	same := repository.NewTokenVault()
+	same.Unlock(k)
+	got, err = same.Open(42, "access_token", sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "secret-token", got)
+}
+
+func TestTokenVault_Locked(t *testing.T) {
+	t.Parallel()
+
+	v, _ := newUnlockedVault(t)
+	sealed, err := v.Seal(1, "access_token", "secret")
+	require.NoError(t, err)
+
+	v.Lock()
+	assert.True(t, v.Locked())
+
+	_, err = v.Seal(1, "access_token", "secret")
+	assert.ErrorIs(t, err, repository.ErrVaultLocked)
+
+	_, err = v.Open(1, "access_token", sealed)
+	assert.ErrorIs(t, err, repository.ErrVaultLocked)
+
+	got, err := v.Open(1, "access_token", "plain-from-before-the-vault")
+	require.NoError(t, err)
+	assert.Equal(t, "plain-from-before-the-vault", got)
+
+	var nilVault *repository.TokenVault
+	assert.True(t, nilVault.Locked())
+	_, err = nilVault.Seal(1, "access_token", "secret")
+	assert.ErrorIs(t, err, repository.ErrVaultLocked)
+}
+
+func TestDeriveVaultKey(t *testing.T) {
+	t.Parallel()
+
+	// small parameters keep the test fast
+	p := repository.KDFParams{Salt: []byte("0123456789abcdef"), Time: 1, Memory: 64, Threads: 1}
+
+	k1, err := repository.DeriveVaultKey("correct horse", p)
+	require.NoError(t, err)
+	k2, err := repository.DeriveVaultKey("correct horse", p)
+	require.NoError(t, err)
+	assert.Equal(t, k1, k2)
+
+	k3, err := repository.DeriveVaultKey("battery staple", p)
+	require.NoError(t, err)
+	assert.NotEqual(t, k1, k3)
+
+	p.Salt = []byte("fedcba9876543210")
+	k4, err := repository.DeriveVaultKey("correct horse", p)
+	require.NoError(t, err)
+	assert.NotEqual(t, k1, k4)
+
+	_, err = repository.DeriveVaultKey("", p)
+	assert.ErrorIs(t, err, repository.ErrEmptyPassphrase)
+}
+
+func TestKeyFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nested", "token.key")
+
+	_, err := repository.ReadKeyFile(path)
+	assert.ErrorIs(t, err, repository.ErrKeyFileMissing)
+
+	k, err := repository.GenerateVaultKey()
+	require.NoError(t, err)
+	require.NoError(t, repository.WriteKeyFile(path, k))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	if filepath.Separator == '/' {
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+
+	got, err := repository.ReadKeyFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, k, got)
+
+	require.NoError(t, os.WriteFile(path, []byte("not a key"), 0o600))
+	_, err = repository.ReadKeyFile(path)
+	assert.ErrorIs(t, err, repository.ErrBadKeyFile)
+}
+
+func TestParseVaultMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		want    repository.VaultMode
+		wantErr error
+	}{
+		{"", repository.VaultModeKeyFile, nil},
+		{"keyfile", repository.VaultModeKeyFile, nil},
+		{"Passphrase", repository.VaultModePassphrase, nil},
+		{"plaintext", "", repository.ErrUnknownVaultMode},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := repository.ParseVaultMode(tt.in)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAppSqliteRepository_VaultKeyChanged(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	src := repository.KeySource{Mode: repository.VaultModeKeyFile, KeyFile: filepath.Join(t.TempDir(), "vault.key")}
+
+	gui := newSeededDeps(t, seed{characters: 1})
+	repo := repository.NewAppData(gui)
+	require.NoError(t, repo.UnlockVault(ctx, src))
+	_, err := repo.UpsertToken(ctx, 1, "access", "refresh", "Bearer", time.Now(), nil)
+	require.NoError(t, err)
+
+	// the CLI shares the database, but not the vault
+	cli := gui
+	cli.TestDependencies = testhelpers.NewTestDependencies(t)
+	cliRepo := repository.NewAppData(cli)
+	require.NoError(t, cliRepo.UnlockVault(ctx, src))
+	require.NoError(t, cliRepo.RotateVault(ctx, src))
+
+	_, err = repo.UpsertToken(ctx, 1, "stale", "stale", "Bearer", time.Now(), nil)
+	assert.ErrorIs(t, err, repository.ErrVaultKeyChanged)
+	assert.True(t, gui.Vault().Locked(), "nothing more is sealed with the old key")
+
+	tok, err := cliRepo.GetTokenForCharacter(ctx, 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "access", tok.AccessToken)
+}
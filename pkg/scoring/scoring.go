@@ -0,0 +1,192 @@
+// Package scoring measures how far characters are from meeting a tag's
+// required skill levels, in trained levels and in skill points.
+package scoring
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+// skillPointsPerRank is the SP for a rank 1 skill at each level; a skill's SP
+// at a level is this times its rank
+var skillPointsPerRank = [6]int64{0, 250, 1415, 8000, 45255, 256000}
+
+// SkillPoints is the total SP for a skill of the given rank trained to level
+func SkillPoints(rank, level int64) int64 {
+	if level <= 0 {
+		return 0
+	}
+	return rank * skillPointsPerRank[min(level, 5)]
+}
+
+// FormatSP shortens a skill point count for display, e.g. 1.25M or 45.3k
+func FormatSP(sp int64) string {
+	switch {
+	case sp >= 1_000_000:
+		return fmt.Sprintf("%.2fM", float64(sp)/1_000_000)
+	case sp >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(sp)/1_000)
+	default:
+		return fmt.Sprintf("%d", sp)
+	}
+}
+
+// Ranks maps skill IDs to their training time multiplier. Skills that are
+// missing are treated as rank 1.
+type Ranks map[int64]int64
+
+func (r Ranks) Rank(skillID int64) int64 {
+	if rank, ok := r[skillID]; ok && rank > 0 {
+		return rank
+	}
+	return 1
+}
+
+// LoadRanks looks up the rank of every skill used by the given tags,
+// including skills they inherit
+func LoadRanks(ctx context.Context, static repository.StaticData, tags []*repository.TagDBData) (Ranks, error) {
+	seen := map[int64]bool{}
+	skillIDs := make([]int64, 0)
+	for _, t := range tags {
+		for _, sk := range t.AllSkills() {
+			if !seen[sk.SkillID] {
+				seen[sk.SkillID] = true
+				skillIDs = append(skillIDs, sk.SkillID)
+			}
+		}
+	}
+
+	ranks := make(Ranks, len(skillIDs))
+	if len(skillIDs) == 0 {
+		return ranks, nil
+	}
+
+	rows, err := static.BatchGetSkillRanks(ctx, skillIDs, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not BatchGetSkillRanks")
+	}
+	for _, row := range rows {
+		ranks[row.SkillID] = row.SkillRank
+	}
+
+	return ranks, nil
+}
+
+// SkillGap is one tag skill a character has not trained far enough
+type SkillGap struct {
+	SkillID int64
+	// CurrentLevel is -1 if the skill is not injected
+	CurrentLevel int64
+	TargetLevel  int64
+	SPNeeded     int64
+}
+
+// Progress is a character's progress toward a tag's required levels. Levels
+// and SP only count up to the required level of each skill.
+type Progress struct {
+	LevelsTrained  int64
+	LevelsRequired int64
+	SPTrained      int64
+	SPRequired     int64
+	Missing        []SkillGap
+}
+
+func (p Progress) Qualified() bool {
+	return len(p.Missing) == 0
+}
+
+func (p Progress) SPNeeded() int64 {
+	return p.SPRequired - p.SPTrained
+}
+
+// Percent is progress by SP, from 0 to 100
+func (p Progress) Percent() float64 {
+	if p.Qualified() {
+		return 100
+	}
+	if p.SPRequired == 0 { // only missing injections
+		return 0
+	}
+	return 100 * float64(p.SPTrained) / float64(p.SPRequired)
+}
+
+// TagProgress scores a character against a tag's flattened required levels.
+// Recommended-only skills are not counted.
+func TagProgress(char *repository.CharacterDBData, tag *repository.TagDBData, ranks Ranks) Progress {
+	charSkills := make(map[int64]int64, len(char.Skills))
+	for _, sk := range char.Skills {
+		charSkills[sk.SkillID] = sk.SkillLevel
+	}
+
+	var p Progress
+	for _, sk := range tag.AllSkills() {
+		if sk.SkillLevel == 0 && sk.RecommendedLevel > 0 {
+			continue
+		}
+
+		rank := ranks.Rank(sk.SkillID)
+		cur, ok := charSkills[sk.SkillID]
+		if !ok {
+			cur = -1
+		}
+		trained := max(min(cur, sk.SkillLevel), 0)
+
+		p.LevelsRequired += sk.SkillLevel
+		p.LevelsTrained += trained
+		p.SPRequired += SkillPoints(rank, sk.SkillLevel)
+		p.SPTrained += SkillPoints(rank, trained)
+
+		if cur < sk.SkillLevel {
+			p.Missing = append(p.Missing, SkillGap{
+				SkillID:      sk.SkillID,
+				CurrentLevel: cur,
+				TargetLevel:  sk.SkillLevel,
+				SPNeeded:     SkillPoints(rank, sk.SkillLevel) - SkillPoints(rank, trained),
+			})
+		}
+	}
+
+	return p
+}
+
+// CharacterProgress pairs a character with its progress toward one tag
+type CharacterProgress struct {
+	Character *repository.CharacterDBData
+	Progress  Progress
+}
+
+// RankCharacters orders characters by how close they are to qualifying for
+// the tag: least SP still needed first, then fewest missing skills, then name.
+// Characters that already qualify are left out unless includeQualified is set.
+func RankCharacters(chars []*repository.CharacterDBData, tag *repository.TagDBData, ranks Ranks, includeQualified bool) []CharacterProgress {
+	ranked := make([]CharacterProgress, 0, len(chars))
+	for _, c := range chars {
+		if c == nil || c.Character.ID == 0 {
+			continue
+		}
+
+		p := TagProgress(c, tag, ranks)
+		if p.Qualified() && !includeQualified {
+			continue
+		}
+		ranked = append(ranked, CharacterProgress{Character: c, Progress: p})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i].Progress, ranked[j].Progress
+		if a.SPNeeded() != b.SPNeeded() {
+			return a.SPNeeded() < b.SPNeeded()
+		}
+		if len(a.Missing) != len(b.Missing) {
+			return len(a.Missing) < len(b.Missing)
+		}
+		return ranked[i].Character.Character.Name < ranked[j].Character.Character.Name
+	})
+
+	return ranked
+}
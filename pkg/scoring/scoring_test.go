@@ -0,0 +1,108 @@
+package scoring_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kava-forge/eve-alts/pkg/repository"
+	"github.com/kava-forge/eve-alts/pkg/scoring"
+	"github.com/kava-forge/eve-alts/pkg/testhelpers"
+)
+
+func TestSkillPoints(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, int64(0), scoring.SkillPoints(3, 0))
+	assert.Equal(t, int64(250), scoring.SkillPoints(1, 1))
+	assert.Equal(t, int64(24000), scoring.SkillPoints(3, 3))
+	assert.Equal(t, int64(256000*8), scoring.SkillPoints(8, 5))
+}
+
+func TestFormatSP(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "250", scoring.FormatSP(250))
+	assert.Equal(t, "45.3k", scoring.FormatSP(45255))
+	assert.Equal(t, "1.28M", scoring.FormatSP(1280000))
+}
+
+func newChar(id int64, name string, skills map[int64]int64) *repository.CharacterDBData {
+	c := &repository.CharacterDBData{Character: repository.Character{ID: id, Name: name}}
+	for sid, lvl := range skills {
+		c.Skills = append(c.Skills, repository.CharacterSkill{CharacterID: id, SkillID: sid, SkillLevel: lvl})
+	}
+	return c
+}
+
+func TestTagProgress(t *testing.T) {
+	t.Parallel()
+
+	tag := &repository.TagDBData{
+		Tag: repository.Tag{ID: 1},
+		Skills: []repository.TagSkill{
+			{TagID: 1, SkillID: 100, SkillLevel: 4},
+			{TagID: 1, SkillID: 101, SkillLevel: 3},
+			{TagID: 1, SkillID: 102, RecommendedLevel: 5}, // not counted
+		},
+	}
+	ranks := scoring.Ranks{100: 2, 101: 5}
+
+	p := scoring.TagProgress(newChar(1, "a", map[int64]int64{100: 2}), tag, ranks)
+	assert.Equal(t, scoring.Progress{
+		LevelsTrained:  2,
+		LevelsRequired: 7,
+		SPTrained:      2 * 1415,
+		SPRequired:     2*45255 + 5*8000,
+		Missing: []scoring.SkillGap{
+			{SkillID: 100, CurrentLevel: 2, TargetLevel: 4, SPNeeded: 2 * (45255 - 1415)},
+			{SkillID: 101, CurrentLevel: -1, TargetLevel: 3, SPNeeded: 5 * 8000},
+		},
+	}, p)
+	assert.InDelta(t, 100*float64(2830)/float64(130510), p.Percent(), 0.001)
+
+	p = scoring.TagProgress(newChar(2, "b", map[int64]int64{100: 5, 101: 3}), tag, ranks)
+	assert.True(t, p.Qualified())
+	assert.Equal(t, float64(100), p.Percent())
+}
+
+func TestRankCharacters(t *testing.T) {
+	t.Parallel()
+
+	tag := &repository.TagDBData{
+		Tag:    repository.Tag{ID: 1},
+		Skills: []repository.TagSkill{{TagID: 1, SkillID: 100, SkillLevel: 5}, {TagID: 1, SkillID: 101, SkillLevel: 1}},
+	}
+
+	far := newChar(1, "Far", nil)
+	near := newChar(2, "Close", map[int64]int64{100: 4, 101: 1})
+	closer := newChar(3, "Closer", map[int64]int64{100: 5})
+	done := newChar(4, "Done", map[int64]int64{100: 5, 101: 1})
+
+	ranked := scoring.RankCharacters([]*repository.CharacterDBData{far, near, done, closer}, tag, scoring.Ranks{}, false)
+	names := make([]string, 0, len(ranked))
+	for _, cp := range ranked {
+		names = append(names, cp.Character.Character.Name)
+	}
+	assert.Equal(t, []string{"Closer", "Close", "Far"}, names)
+
+	assert.Len(t, scoring.RankCharacters([]*repository.CharacterDBData{far, done}, tag, scoring.Ranks{}, true), 2)
+}
+
+func TestLoadRanks(t *testing.T) {
+	t.Parallel()
+
+	deps := testhelpers.NewTestDependencies(t)
+	deps.TestStaticRepo.BatchGetSkillRanksReturns([]repository.BatchGetSkillRanksRow{{SkillID: 100, SkillRank: 3}}, nil)
+
+	ranks, err := scoring.LoadRanks(context.Background(), deps.StaticRepo(), []*repository.TagDBData{{
+		Skills: []repository.TagSkill{{SkillID: 100, SkillLevel: 1}, {SkillID: 101, SkillLevel: 1}},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), ranks.Rank(100))
+	assert.Equal(t, int64(1), ranks.Rank(101))
+
+	_, ids, _ := deps.TestStaticRepo.BatchGetSkillRanksArgsForCall(0)
+	assert.Equal(t, []int64{100, 101}, ids)
+}
@@ -0,0 +1,118 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	//nolint:depguard,staticcheck // joins the exporter and file close errors
+	"github.com/hashicorp/go-multierror"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+	"github.com/kava-forge/eve-alts/lib/telemetry"
+)
+
+// Exporter selects where spans are sent
+type Exporter string
+
+const (
+	// ExporterNone drops every span, and is used when no exporter is configured
+	ExporterNone Exporter = "none"
+	// ExporterOTLPHTTP sends spans to an OTLP receiver over HTTP
+	ExporterOTLPHTTP Exporter = "otlp-http"
+	// ExporterOTLPGRPC sends spans to an OTLP receiver over gRPC
+	ExporterOTLPGRPC Exporter = "otlp-grpc"
+	// ExporterFile writes spans as JSON lines to a size-rotated file
+	ExporterFile Exporter = "file"
+)
+
+// Exporters lists every exporter
+func Exporters() []Exporter {
+	return []Exporter{ExporterNone, ExporterOTLPHTTP, ExporterOTLPGRPC, ExporterFile}
+}
+
+// ParseExporter checks a configured exporter name; empty means none
+func ParseExporter(s string) (Exporter, error) {
+	if s == "" {
+		return ExporterNone, nil
+	}
+	for _, e := range Exporters() {
+		if string(e) == strings.ToLower(s) {
+			return e, nil
+		}
+	}
+	return "", errors.Wrap(ErrBadExporter, "exporter must be none, otlp-http, otlp-grpc or file", "exporter", s)
+}
+
+// DefaultEndpoint is the usual local OTLP receiver address for the exporter
+func (e Exporter) DefaultEndpoint() string {
+	switch e {
+	case ExporterOTLPHTTP:
+		return "localhost:4318"
+	case ExporterOTLPGRPC:
+		return "localhost:4317"
+	default:
+		return ""
+	}
+}
+
+// newSpanExporter builds the configured exporter, or nil for none
+func newSpanExporter(ctx context.Context, opts Options) (telemetry.SpanExporter, error) {
+	switch opts.TraceExporter {
+	case "", ExporterNone:
+		return nil, nil
+	case ExporterOTLPHTTP:
+		httpOpts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(opts.OTLPEndpoint)}
+		if opts.OTLPInsecure {
+			httpOpts = append(httpOpts, otlptracehttp.WithInsecure())
+		}
+		exp, err := otlptracehttp.New(ctx, httpOpts...)
+		return exp, errors.Wrap(err, "could not create otlp http exporter", "endpoint", opts.OTLPEndpoint)
+	case ExporterOTLPGRPC:
+		grpcOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.OTLPEndpoint)}
+		if opts.OTLPInsecure {
+			grpcOpts = append(grpcOpts, otlptracegrpc.WithInsecure())
+		}
+		exp, err := otlptracegrpc.New(ctx, grpcOpts...)
+		return exp, errors.Wrap(err, "could not create otlp grpc exporter", "endpoint", opts.OTLPEndpoint)
+	case ExporterFile:
+		if opts.TraceFile == "" {
+			return nil, errors.Wrap(ErrBadExporter, "the file exporter needs a file")
+		}
+		w := &lumberjack.Logger{
+			Filename:   opts.TraceFile,
+			MaxSize:    100,
+			MaxBackups: 3,
+			MaxAge:     28,
+			LocalTime:  true,
+		}
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(w))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create file exporter", "path", opts.TraceFile)
+		}
+		return &fileExporter{Exporter: exp, file: w}, nil
+	default:
+		return nil, errors.Wrap(ErrBadExporter, "unknown trace exporter", "exporter", string(opts.TraceExporter))
+	}
+}
+
+// fileExporter closes its file once the last spans are written
+type fileExporter struct {
+	*stdouttrace.Exporter
+	file io.Closer
+}
+
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	var errs error
+	if err := e.Exporter.Shutdown(ctx); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err := e.file.Close(); err != nil {
+		errs = multierror.Append(errs, errors.Wrap(err, "could not close trace file"))
+	}
+	return errs
+}
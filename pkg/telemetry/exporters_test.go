@@ -0,0 +1,204 @@
+package telemetry_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/kava-forge/eve-alts/lib/json"
+
+	"github.com/kava-forge/eve-alts/pkg/telemetry"
+	"github.com/kava-forge/eve-alts/pkg/testhelpers"
+)
+
+// receiver collects the names of the spans sent to a local OTLP endpoint
+type receiver struct {
+	coltracepb.UnimplementedTraceServiceServer
+
+	mu    sync.Mutex
+	names []string
+}
+
+func (r *receiver) add(req *coltracepb.ExportTraceServiceRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rs := range req.GetResourceSpans() {
+		for _, ss := range rs.GetScopeSpans() {
+			for _, sp := range ss.GetSpans() {
+				r.names = append(r.names, sp.GetName())
+			}
+		}
+	}
+}
+
+func (r *receiver) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.names...)
+}
+
+func (r *receiver) Export(_ context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	r.add(req)
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil || req.URL.Path != "/v1/traces" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var msg coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &msg); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.add(&msg)
+
+	out, _ := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(out)
+}
+
+// emit makes one span and shuts the telemeter down, flushing it
+func emit(t *testing.T, opts telemetry.Options) {
+	t.Helper()
+
+	ctx := context.Background()
+	tel, _, err := telemetry.NewTelemeter(ctx, testhelpers.NewTestDependencies(t), "test", "v0", "test", opts)
+	require.NoError(t, err)
+
+	_, span := telemetry.StartSpan(ctx, tel, "test", "Exported")
+	span.End()
+
+	require.NoError(t, tel.Shutdown(ctx))
+}
+
+func TestNewTelemeter_OTLPHTTP(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		probability float64
+		want        []string
+	}{
+		{name: "sampled", probability: 1, want: []string{"Exported"}},
+		{name: "not sampled", probability: 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rcv := &receiver{}
+			srv := httptest.NewServer(rcv)
+			t.Cleanup(srv.Close)
+
+			emit(t, telemetry.Options{
+				TraceExporter:    telemetry.ExporterOTLPHTTP,
+				OTLPEndpoint:     strings.TrimPrefix(srv.URL, "http://"),
+				OTLPInsecure:     true,
+				TraceProbability: tt.probability,
+			})
+
+			assert.Equal(t, tt.want, rcv.Names())
+		})
+	}
+}
+
+func TestNewTelemeter_OTLPGRPC(t *testing.T) {
+	t.Parallel()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	rcv := &receiver{}
+	srv := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(srv, rcv)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	emit(t, telemetry.Options{
+		TraceExporter:    telemetry.ExporterOTLPGRPC,
+		OTLPEndpoint:     lis.Addr().String(),
+		OTLPInsecure:     true,
+		TraceProbability: 1,
+	})
+
+	assert.Equal(t, []string{"Exported"}, rcv.Names())
+}
+
+func TestNewTelemeter_File(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "traces.json")
+	emit(t, telemetry.Options{
+		TraceExporter:    telemetry.ExporterFile,
+		TraceFile:        path,
+		TraceProbability: 1,
+	})
+
+	fh, err := os.Open(path)
+	require.NoError(t, err)
+	defer fh.Close()
+
+	var names []string
+	sc := bufio.NewScanner(fh)
+	for sc.Scan() {
+		var sp struct {
+			Name string `json:"Name"`
+		}
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &sp))
+		names = append(names, sp.Name)
+	}
+	require.NoError(t, sc.Err())
+	assert.Equal(t, []string{"Exported"}, names)
+}
+
+func TestNewTelemeter_None(t *testing.T) {
+	t.Parallel()
+
+	emit(t, telemetry.Options{TraceProbability: 1})
+	emit(t, telemetry.Options{TraceExporter: telemetry.ExporterNone, TraceProbability: 1})
+}
+
+func TestParseExporter(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		in      string
+		want    telemetry.Exporter
+		wantErr bool
+	}{
+		{in: "", want: telemetry.ExporterNone},
+		{in: "OTLP-HTTP", want: telemetry.ExporterOTLPHTTP},
+		{in: "otlp-grpc", want: telemetry.ExporterOTLPGRPC},
+		{in: "file", want: telemetry.ExporterFile},
+		{in: "jaeger", wantErr: true},
+	} {
+		got, err := telemetry.ParseExporter(tt.in)
+		if tt.wantErr {
+			assert.ErrorIs(t, err, telemetry.ErrBadExporter, tt.in)
+			continue
+		}
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+}
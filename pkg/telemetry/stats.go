@@ -1,23 +1,223 @@
 package telemetry
 
 import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
 	"github.com/kava-forge/eve-alts/lib/telemetry"
 )
 
+// Stats holds the app's own metrics. Every recording method is safe to call
+// on a nil *Stats, so code under test does not need one.
 type Stats struct {
+	meter telemetry.Meter
+
 	RequestCount telemetry.Int64Counter
+
+	ESIRequests        telemetry.Int64Counter
+	ESIRequestDuration telemetry.Float64Histogram
+	CacheLookups       telemetry.Int64Counter
+	TokenRefreshes     telemetry.Int64Counter
+	TransactionRetries telemetry.Int64Counter
+	TransactionTime    telemetry.Float64Histogram
+	RefreshDuration    telemetry.Float64Histogram
+}
+
+var defaultStats atomic.Pointer[Stats]
+
+// DefaultStats returns the Stats last passed to MakeDefault, or nil, for
+// packages that are only handed a Telemeter
+func DefaultStats() *Stats {
+	return defaultStats.Load()
 }
 
+// MakeDefault makes s the Stats returned by DefaultStats
+func (s *Stats) MakeDefault() {
+	defaultStats.Store(s)
+}
+
+// seconds buckets cover single ESI calls up to full character refreshes
+var secondsBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// transactionBuckets cover a single row write up to a stalled transaction
+var transactionBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
 func NewStats(appName string, telemeter *Telemeter) (*Stats, error) {
 	meter := telemeter.Meter(appName)
 
 	var err error
-	stats := &Stats{}
+	stats := &Stats{meter: meter}
 
 	stats.RequestCount, err = meter.Int64Counter("request_ct")
 	if err != nil {
 		return stats, err
 	}
 
+	stats.ESIRequests, err = meter.Int64Counter("esi_requests",
+		otelmetric.WithDescription("ESI requests by endpoint and response status"))
+	if err != nil {
+		return stats, err
+	}
+
+	stats.ESIRequestDuration, err = meter.Float64Histogram("esi_request_duration",
+		otelmetric.WithDescription("ESI request latency by endpoint"),
+		otelmetric.WithUnit("s"),
+		otelmetric.WithExplicitBucketBoundaries(secondsBuckets...))
+	if err != nil {
+		return stats, err
+	}
+
+	stats.CacheLookups, err = meter.Int64Counter("cache_lookups",
+		otelmetric.WithDescription("Cache lookups by cache and hit or miss"))
+	if err != nil {
+		return stats, err
+	}
+
+	stats.TokenRefreshes, err = meter.Int64Counter("token_refreshes",
+		otelmetric.WithDescription("ESI access token refreshes by result"))
+	if err != nil {
+		return stats, err
+	}
+
+	stats.TransactionRetries, err = meter.Int64Counter("db_transaction_retries",
+		otelmetric.WithDescription("Database transaction attempts after the first"))
+	if err != nil {
+		return stats, err
+	}
+
+	stats.TransactionTime, err = meter.Float64Histogram("db_transaction_duration",
+		otelmetric.WithDescription("Time a database transaction is open, from begin to commit or rollback, by result"),
+		otelmetric.WithUnit("s"),
+		otelmetric.WithExplicitBucketBoundaries(transactionBuckets...))
+	if err != nil {
+		return stats, err
+	}
+
+	stats.RefreshDuration, err = meter.Float64Histogram("character_refresh_duration",
+		otelmetric.WithDescription("Time to refresh a character from ESI, by character and result"),
+		otelmetric.WithUnit("s"),
+		otelmetric.WithExplicitBucketBoundaries(secondsBuckets...))
+	if err != nil {
+		return stats, err
+	}
+
 	return stats, nil
 }
+
+func result(err error) attribute.KeyValue {
+	if err != nil {
+		return attribute.String("result", "error")
+	}
+	return attribute.String("result", "ok")
+}
+
+// ESIRequest records one ESI call. status is 0 when no response came back.
+func (s *Stats) ESIRequest(ctx context.Context, endpoint string, status int, took time.Duration) {
+	if s == nil {
+		return
+	}
+
+	code := "error"
+	if status != 0 {
+		code = strconv.Itoa(status)
+	}
+
+	s.ESIRequests.Add(ctx, 1, otelmetric.WithAttributes(attribute.String("endpoint", endpoint), attribute.String("status", code)))
+	s.ESIRequestDuration.Record(ctx, took.Seconds(), otelmetric.WithAttributes(attribute.String("endpoint", endpoint)))
+}
+
+// CacheLookup records whether a lookup in the named cache was a hit
+func (s *Stats) CacheLookup(ctx context.Context, cache string, hit bool) {
+	if s == nil {
+		return
+	}
+
+	res := "miss"
+	if hit {
+		res = "hit"
+	}
+	s.CacheLookups.Add(ctx, 1, otelmetric.WithAttributes(attribute.String("cache", cache), attribute.String("result", res)))
+}
+
+// TokenRefresh records an access token refresh, failed if err is not nil
+func (s *Stats) TokenRefresh(ctx context.Context, err error) {
+	if s == nil {
+		return
+	}
+	s.TokenRefreshes.Add(ctx, 1, otelmetric.WithAttributes(result(err)))
+}
+
+// TransactionRetry records that a transaction is being attempted again
+func (s *Stats) TransactionRetry(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	s.TransactionRetries.Add(ctx, 1)
+}
+
+// Transaction records how long one transaction attempt held the database
+func (s *Stats) Transaction(ctx context.Context, took time.Duration, err error) {
+	if s == nil {
+		return
+	}
+	s.TransactionTime.Record(ctx, took.Seconds(), otelmetric.WithAttributes(result(err)))
+}
+
+// CharacterRefresh records how long refreshing a character took
+func (s *Stats) CharacterRefresh(ctx context.Context, character string, took time.Duration, err error) {
+	if s == nil {
+		return
+	}
+	s.RefreshDuration.Record(ctx, took.Seconds(), otelmetric.WithAttributes(attribute.String("character", character), result(err)))
+}
+
+// Roster is a snapshot of the gauges read on each metrics scrape
+type Roster struct {
+	// TotalSP is keyed by character name
+	TotalSP map[string]int64
+	// Qualified counts the characters matching each role, keyed by role name
+	Qualified map[string]int64
+}
+
+// ObserveRoster registers the character and role gauges, read from load
+// whenever metrics are collected
+func (s *Stats) ObserveRoster(load func(ctx context.Context) (Roster, error)) error {
+	if s == nil {
+		return nil
+	}
+
+	sp, err := s.meter.Int64ObservableGauge("character_total_sp",
+		otelmetric.WithDescription("Total skill points per character"))
+	if err != nil {
+		return errors.Wrap(err, "could not create character_total_sp")
+	}
+
+	qualified, err := s.meter.Int64ObservableGauge("role_qualified_characters",
+		otelmetric.WithDescription("Characters that qualify for each role"))
+	if err != nil {
+		return errors.Wrap(err, "could not create role_qualified_characters")
+	}
+
+	_, err = s.meter.RegisterCallback(func(ctx context.Context, o otelmetric.Observer) error {
+		r, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		for name, v := range r.TotalSP {
+			o.ObserveInt64(sp, v, otelmetric.WithAttributes(attribute.String("character", name)))
+		}
+		for name, v := range r.Qualified {
+			o.ObserveInt64(qualified, v, otelmetric.WithAttributes(attribute.String("role", name)))
+		}
+		return nil
+	}, sp, qualified)
+
+	return errors.Wrap(err, "could not register roster callback")
+}
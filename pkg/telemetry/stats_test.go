@@ -0,0 +1,105 @@
+package telemetry_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/telemetry"
+	"github.com/kava-forge/eve-alts/pkg/testhelpers"
+)
+
+func scrape(t *testing.T, h http.Handler) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+	require.Equal(t, http.StatusOK, rec.Code)
+	return rec.Body.String()
+}
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	deps := testhelpers.NewTestDependencies(t)
+	ctx := context.Background()
+	stats := deps.Stats()
+
+	stats.ESIRequest(ctx, "/characters/{character_id}/skills/", 200, 150*time.Millisecond)
+	stats.ESIRequest(ctx, "/characters/{character_id}/skills/", 0, time.Second)
+	stats.CacheLookup(ctx, "matching", true)
+	stats.CacheLookup(ctx, "matching", false)
+	stats.TokenRefresh(ctx, errors.New("expired"))
+	stats.TransactionRetry(ctx)
+	stats.Transaction(ctx, 4*time.Millisecond, nil)
+	stats.CharacterRefresh(ctx, "Alice", 3*time.Second, nil)
+	require.NoError(t, stats.ObserveRoster(func(context.Context) (telemetry.Roster, error) {
+		return telemetry.Roster{
+			TotalSP:   map[string]int64{"Alice": 50_000_000},
+			Qualified: map[string]int64{"Logi": 2},
+		}, nil
+	}))
+
+	out := scrape(t, deps.StatsHandler())
+	tests := []struct {
+		name   string
+		labels []string
+	}{
+		{"esi_requests_total", []string{`endpoint="/characters/{character_id}/skills/"`, `status="200"`}},
+		{"esi_requests_total", []string{`endpoint="/characters/{character_id}/skills/"`, `status="error"`}},
+		{"esi_request_duration_seconds_count", []string{`endpoint="/characters/{character_id}/skills/"`}},
+		{"cache_lookups_total", []string{`cache="matching"`, `result="hit"`}},
+		{"cache_lookups_total", []string{`cache="matching"`, `result="miss"`}},
+		{"token_refreshes_total", []string{`result="error"`}},
+		{"db_transaction_retries_total", nil},
+		{"db_transaction_duration_seconds_count", []string{`result="ok"`}},
+		{"character_refresh_duration_seconds_count", []string{`character="Alice"`, `result="ok"`}},
+		{"character_total_sp", []string{`character="Alice"`}},
+		{"role_qualified_characters", []string{`role="Logi"`}},
+	}
+	for _, tt := range tests {
+		assert.True(t, hasSeries(out, tt.name, tt.labels...), "missing %s %v", tt.name, tt.labels)
+	}
+}
+
+// hasSeries looks for a sample of the named metric carrying every label
+func hasSeries(out, name string, labels ...string) bool {
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.HasPrefix(line, name+"{") {
+			continue
+		}
+		found := true
+		for _, l := range labels {
+			found = found && strings.Contains(line, l)
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+func TestStats_Nil(t *testing.T) {
+	t.Parallel()
+
+	var stats *telemetry.Stats
+	ctx := context.Background()
+
+	assert.NotPanics(t, func() {
+		stats.ESIRequest(ctx, "/x/", 200, time.Second)
+		stats.CacheLookup(ctx, "matching", true)
+		stats.TokenRefresh(ctx, nil)
+		stats.TransactionRetry(ctx)
+		stats.Transaction(ctx, time.Millisecond, nil)
+		stats.CharacterRefresh(ctx, "Alice", time.Second, nil)
+		assert.NoError(t, stats.ObserveRoster(nil))
+	})
+}
@@ -45,8 +45,16 @@ type dependencies interface {
 
 type Options struct {
 	PrometheusNamespace string
-	JaegerURL           string
-	TraceProbability    float64
+	// TraceExporter is where spans go; empty means nowhere
+	TraceExporter Exporter
+	// OTLPEndpoint is the receiver's host:port for the OTLP exporters
+	OTLPEndpoint string
+	// OTLPInsecure sends to the receiver without TLS
+	OTLPInsecure bool
+	// TraceFile is the file the file exporter writes to
+	TraceFile string
+	// TraceProbability is the fraction of new traces that are sampled
+	TraceProbability float64
 }
 
 type promLogger struct {
@@ -62,12 +70,7 @@ func newPromLogger(logger logging.Logger) promhttp.Logger {
 }
 
 func NewTelemeter(ctx context.Context, deps dependencies, appName, version, instanceID string, opts Options) (*telemetry.Telemeter, http.Handler, error) {
-	// traceExp, err := jaeger.New(jaeger.WithCollectorEndpoint(jaeger.WithEndpoint(opts.JaegerURL)))
-	// if err != nil {
-	// 	return nil, nil, errors.Wrap(err, "could not set up jaeger")
-	// }
-
-	traceExp, err := stdouttrace.New()
+	traceExp, err := newSpanExporter(ctx, opts)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "could not set up tracer")
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	stdhttp "net/http"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -15,6 +16,8 @@ import (
 
 	"github.com/kava-forge/eve-alts/pkg/database"
 	"github.com/kava-forge/eve-alts/pkg/database/databasefakes"
+	"github.com/kava-forge/eve-alts/pkg/notify"
+	"github.com/kava-forge/eve-alts/pkg/repository"
 	"github.com/kava-forge/eve-alts/pkg/repository/repositoryfakes"
 	"github.com/kava-forge/eve-alts/pkg/telemetry"
 )
@@ -24,9 +27,15 @@ type TestDependencies struct {
 	configuredLogger logging.Logger
 	TestDB           *databasefakes.FakeConnection
 	TestStaticDB     *databasefakes.FakeConnection
+	TestSnapshots    database.Snapshots
 	TestAppRepo      *repositoryfakes.FakeAppData
 	TestStaticRepo   *repositoryfakes.FakeStaticData
 	TestHTTPClient   *httpfakes.FakeClient
+	// TestNotifier is nil, and so disabled, unless a test sets one up
+	TestNotifier *notify.Notifier
+	// TestVault starts locked
+	TestVault       *repository.TokenVault
+	TestVaultSource repository.KeySource
 
 	TestTelemetry *telemetry.Telemeter
 	TestStats     *telemetry.Stats
@@ -51,7 +60,7 @@ var skipKeys = map[string]bool{
 	"http.scheme":          true,
 }
 
-func NewTestDependencies(t *testing.T) *TestDependencies {
+func NewTestDependencies(t testing.TB) *TestDependencies {
 	t.Helper()
 
 	ctx := context.Background()
@@ -110,12 +119,32 @@ func NewTestDependencies(t *testing.T) *TestDependencies {
 	deps.TestDB = &databasefakes.FakeConnection{}
 	deps.TestDB.BeginTxReturns(&databasefakes.FakeTx{}, nil)
 
+	deps.TestStaticDB = &databasefakes.FakeConnection{}
+	deps.TestSnapshots = database.Snapshots{Dir: filepath.Join(t.TempDir(), "backups")}
+	deps.TestAppRepo = &repositoryfakes.FakeAppData{}
+	deps.TestStaticRepo = &repositoryfakes.FakeStaticData{}
+	deps.TestVault = repository.NewTokenVault()
+	deps.TestVaultSource = repository.KeySource{
+		Mode:    repository.VaultModeKeyFile,
+		KeyFile: filepath.Join(t.TempDir(), "token.key"),
+	}
+
 	return deps
 }
 
-func (d *TestDependencies) Telemetry() *telemetry.Telemeter { return d.TestTelemetry }
-func (d *TestDependencies) Stats() *telemetry.Stats         { return d.TestStats }
-func (d *TestDependencies) DB() database.Connection         { return d.TestDB }
-func (d *TestDependencies) Logger() logging.Logger          { return d.configuredLogger }
-func (d *TestDependencies) StatsHandler() stdhttp.Handler   { return d.statsHandler }
-func (d *TestDependencies) HTTPClient() http.Client         { return d.TestHTTPClient }
+func (d *TestDependencies) Telemetry() *telemetry.Telemeter   { return d.TestTelemetry }
+func (d *TestDependencies) Stats() *telemetry.Stats           { return d.TestStats }
+func (d *TestDependencies) DB() database.Connection           { return d.TestDB }
+func (d *TestDependencies) StaticDB() database.Connection     { return d.TestStaticDB }
+func (d *TestDependencies) Snapshots() database.Snapshots     { return d.TestSnapshots }
+func (d *TestDependencies) AppRepo() repository.AppData       { return d.TestAppRepo }
+func (d *TestDependencies) StaticRepo() repository.StaticData { return d.TestStaticRepo }
+func (d *TestDependencies) Logger() logging.Logger            { return d.configuredLogger }
+func (d *TestDependencies) StatsHandler() stdhttp.Handler     { return d.statsHandler }
+func (d *TestDependencies) HTTPClient() http.Client           { return d.TestHTTPClient }
+func (d *TestDependencies) Notifier() *notify.Notifier        { return d.TestNotifier }
+func (d *TestDependencies) Vault() *repository.TokenVault     { return d.TestVault }
+
+// VaultSource is a key file in the test's temp directory, unless a test
+// sets TestVaultSource
+func (d *TestDependencies) VaultSource() repository.KeySource { return d.TestVaultSource }
@@ -0,0 +1,104 @@
+// Package roster builds random rosters for matching tests and benchmarks. It
+// stays apart from testhelpers so the packages testhelpers depends on can use
+// it in their own tests.
+package roster
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand"
+
+	"github.com/kava-forge/eve-alts/pkg/operators"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+// rosterSkills is how many distinct skills a random roster draws from
+const rosterSkills = 300
+
+// Random builds a reproducible roster of characters, tags and roles for
+// matching tests and benchmarks. Roles nest groups of every operator and some
+// carry conditions; a few tags and roles are deleted (ID 0).
+func Random(seed int64, numChars, numTags, numRoles int) ([]*repository.CharacterDBData, []*repository.TagDBData, []*repository.RoleDBData) {
+	rng := rand.New(rand.NewSource(seed)) //nolint:gosec // test data
+
+	chars := make([]*repository.CharacterDBData, 0, numChars)
+	for i := range numChars {
+		c := &repository.CharacterDBData{
+			Character: repository.Character{
+				ID:      int64(i + 1),
+				Name:    fmt.Sprintf("Character %d", i+1),
+				TotalSp: rng.Int63n(100_000_000),
+				Omega:   sql.NullBool{Bool: rng.Intn(4) != 0, Valid: rng.Intn(10) != 0},
+			},
+			Corporation: repository.Corporation{ID: int64(rng.Intn(5) + 1)},
+		}
+		for sk := range rosterSkills {
+			if rng.Intn(3) == 0 {
+				continue
+			}
+			c.Skills = append(c.Skills, repository.CharacterSkill{CharacterID: c.Character.ID, SkillID: int64(sk + 1), SkillLevel: int64(rng.Intn(6))})
+		}
+		chars = append(chars, c)
+	}
+
+	tags := make([]*repository.TagDBData, 0, numTags)
+	for i := range numTags {
+		t := &repository.TagDBData{Tag: repository.Tag{ID: int64(i + 1), Name: fmt.Sprintf("Tag %d", i+1)}}
+		for range rng.Intn(8) + 1 {
+			sk := repository.TagSkill{TagID: t.Tag.ID, SkillID: int64(rng.Intn(rosterSkills) + 1), SkillLevel: int64(rng.Intn(4) + 1)}
+			switch rng.Intn(4) {
+			case 0:
+				sk.RecommendedLevel = min(sk.SkillLevel+1, 5)
+			case 1:
+				sk.SkillLevel, sk.RecommendedLevel = 0, int64(rng.Intn(5)+1)
+			}
+			t.Skills = append(t.Skills, sk)
+		}
+		if i > 0 && rng.Intn(4) == 0 {
+			t.Inherited = append(t.Inherited, tags[rng.Intn(i)].Skills...)
+		}
+		tags = append(tags, t)
+	}
+	for i := 0; i < numTags; i += 17 {
+		tags[i].Tag.ID = 0
+	}
+
+	ops := operators.OperatorValues()
+	var group func(depth int) *repository.RoleExpr
+	group = func(depth int) *repository.RoleExpr {
+		g := repository.NewRoleGroup(ops[rng.Intn(len(ops))])
+		for range rng.Intn(4) + 1 {
+			if depth < 2 && rng.Intn(3) == 0 {
+				g.Children = append(g.Children, group(depth+1))
+			} else {
+				g.Children = append(g.Children, repository.NewRoleLeaf(int64(rng.Intn(numTags)+1)))
+			}
+		}
+		if g.Operator == operators.OperatorAtleast {
+			g.MinCount = int64(rng.Intn(len(g.Children)) + 1)
+		}
+		return g
+	}
+
+	roles := make([]*repository.RoleDBData, 0, numRoles)
+	for i := range numRoles {
+		r := &repository.RoleDBData{
+			Role: repository.Role{ID: int64(i + 1), Name: fmt.Sprintf("Role %d", i+1)},
+			Expr: group(0),
+		}
+		switch rng.Intn(5) {
+		case 0:
+			r.Conditions = append(r.Conditions, repository.RoleCondition{Kind: "corporation", Value: int64(rng.Intn(5) + 1)})
+		case 1:
+			r.Conditions = append(r.Conditions, repository.RoleCondition{Kind: "min_sp", Value: rng.Int63n(100_000_000)})
+		case 2:
+			r.Conditions = append(r.Conditions, repository.RoleCondition{Kind: "omega"})
+		}
+		roles = append(roles, r)
+	}
+	for i := 0; i < numRoles; i += 11 {
+		roles[i].Role.ID = 0
+	}
+
+	return chars, tags, roles
+}
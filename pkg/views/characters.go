@@ -0,0 +1,48 @@
+package views
+
+import (
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+type Org struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Ticker string `json:"ticker"`
+}
+
+type Character struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Corporation Org    `json:"corporation"`
+	Alliance    *Org   `json:"alliance,omitempty"`
+	TotalSP     int64  `json:"total_sp"`
+	// Omega is null until the character has been refreshed
+	Omega  *bool `json:"omega"`
+	Skills int   `json:"skills"`
+}
+
+func NewCharacter(c *repository.CharacterDBData) Character {
+	cv := Character{
+		ID:          c.Character.ID,
+		Name:        c.Character.Name,
+		Corporation: Org{ID: c.Corporation.ID, Name: c.Corporation.Name, Ticker: c.Corporation.Ticker},
+		TotalSP:     c.Character.TotalSp,
+		Skills:      len(c.Skills),
+	}
+	if c.Alliance.ID.Valid {
+		cv.Alliance = &Org{ID: c.Alliance.ID.Int64, Name: c.Alliance.Name.String, Ticker: c.Alliance.Ticker.String}
+	}
+	if c.Character.Omega.Valid {
+		omega := c.Character.Omega.Bool
+		cv.Omega = &omega
+	}
+	return cv
+}
+
+func NewCharacters(chars []*repository.CharacterDBData) []Character {
+	list := make([]Character, 0, len(chars))
+	for _, c := range chars {
+		list = append(list, NewCharacter(c))
+	}
+	return list
+}
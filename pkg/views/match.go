@@ -0,0 +1,34 @@
+package views
+
+import (
+	"github.com/kava-forge/eve-alts/pkg/matching"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+// Match lists the characters that qualify for a role
+type Match struct {
+	Role       Ref   `json:"role"`
+	Characters []Ref `json:"characters"`
+}
+
+// NewMatch evaluates role against the roster. results may be nil, in which
+// case the roster is compiled just for this role.
+func NewMatch(results *matching.Results, chars []*repository.CharacterDBData, tags []*repository.TagDBData, roles []*repository.RoleDBData, role *repository.RoleDBData) Match {
+	if results == nil {
+		results = matching.Compile(chars, tags, roles)
+	}
+
+	byID := make(map[int64]*repository.CharacterDBData, len(chars))
+	for _, c := range chars {
+		byID[c.Character.ID] = c
+	}
+
+	m := Match{
+		Role:       Ref{ID: role.Role.ID, Name: role.Role.Name},
+		Characters: []Ref{},
+	}
+	for _, id := range results.Filter(nil, matching.TagMissing, []int64{role.Role.ID}) {
+		m.Characters = append(m.Characters, Ref{ID: id, Name: byID[id].Character.Name})
+	}
+	return m
+}
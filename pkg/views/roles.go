@@ -0,0 +1,80 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/kava-forge/eve-alts/pkg/bundles"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+type Condition struct {
+	Kind  string `json:"kind"`
+	Value int64  `json:"value,omitempty"`
+	Label string `json:"label,omitempty"`
+}
+
+// Expr is one node of a role's expression: a tag, or a group of nodes
+type Expr struct {
+	Operator string `json:"operator,omitempty"`
+	Count    int64  `json:"count,omitempty"`
+	Tag      string `json:"tag,omitempty"`
+	Children []Expr `json:"children,omitempty"`
+}
+
+type Role struct {
+	ID         int64       `json:"id"`
+	Name       string      `json:"name"`
+	Label      string      `json:"label"`
+	Color      string      `json:"color"`
+	Tags       []string    `json:"tags"`
+	Conditions []Condition `json:"conditions"`
+	Expr       *Expr       `json:"expression"`
+}
+
+func newExpr(e *repository.RoleExpr, tagNames map[int64]string) Expr {
+	if e.IsLeaf() {
+		name, ok := tagNames[e.TagID]
+		if !ok {
+			name = fmt.Sprintf("tag #%d", e.TagID)
+		}
+		return Expr{Tag: name}
+	}
+
+	ev := Expr{Operator: e.Operator.String(), Count: e.MinCount}
+	for _, c := range e.Children {
+		ev.Children = append(ev.Children, newExpr(c, tagNames))
+	}
+	return ev
+}
+
+func NewRole(r *repository.RoleDBData) Role {
+	tagNames := make(map[int64]string, len(r.Tags))
+	rv := Role{
+		ID:         r.Role.ID,
+		Name:       r.Role.Name,
+		Label:      r.Role.Label,
+		Color:      bundles.EncodeColor(r.Color()),
+		Tags:       make([]string, 0, len(r.Tags)),
+		Conditions: make([]Condition, 0, len(r.Conditions)),
+	}
+	for _, t := range r.Tags {
+		tagNames[t.ID] = t.Name
+		rv.Tags = append(rv.Tags, t.Name)
+	}
+	for _, c := range r.Conditions {
+		rv.Conditions = append(rv.Conditions, Condition{Kind: c.Kind, Value: c.Value, Label: c.Label})
+	}
+	if r.Expr != nil {
+		ev := newExpr(r.Expr, tagNames)
+		rv.Expr = &ev
+	}
+	return rv
+}
+
+func NewRoles(roles []*repository.RoleDBData) []Role {
+	list := make([]Role, 0, len(roles))
+	for _, r := range roles {
+		list = append(list, NewRole(r))
+	}
+	return list
+}
@@ -0,0 +1,114 @@
+package views
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/bundles"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+type Tag struct {
+	ID       int64    `json:"id"`
+	Name     string   `json:"name"`
+	Color    string   `json:"color"`
+	Skills   int      `json:"skills"`
+	Includes []string `json:"includes"`
+}
+
+type TagSkill struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Level       int64  `json:"level"`
+	Recommended int64  `json:"recommended,omitempty"`
+	// From names the included tag an inherited skill comes from
+	From string `json:"from,omitempty"`
+}
+
+type TagDetail struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Color     string     `json:"color"`
+	Includes  []string   `json:"includes"`
+	Skills    []TagSkill `json:"skills"`
+	Inherited []TagSkill `json:"inherited"`
+}
+
+func includeNames(t *repository.TagDBData) []string {
+	names := make([]string, 0, len(t.Includes))
+	for _, inc := range t.Includes {
+		names = append(names, inc.Name)
+	}
+	return names
+}
+
+func NewTag(t *repository.TagDBData) Tag {
+	return Tag{
+		ID:       t.Tag.ID,
+		Name:     t.Tag.Name,
+		Color:    bundles.EncodeColor(t.Color()),
+		Skills:   len(t.AllSkills()),
+		Includes: includeNames(t),
+	}
+}
+
+func NewTags(tags []*repository.TagDBData) []Tag {
+	list := make([]Tag, 0, len(tags))
+	for _, t := range tags {
+		list = append(list, NewTag(t))
+	}
+	return list
+}
+
+// LoadTagDetail describes one tag's skills by name, naming the tag among
+// allTags that each inherited skill comes from
+func LoadTagDetail(ctx context.Context, static repository.StaticData, tag *repository.TagDBData, allTags []*repository.TagDBData) (TagDetail, error) {
+	all := tag.AllSkills()
+	skillIDs := make([]int64, 0, len(all))
+	for _, sk := range all {
+		skillIDs = append(skillIDs, sk.SkillID)
+	}
+	nameMap := map[int64]string{}
+	if len(skillIDs) > 0 {
+		rows, err := static.BatchGetSkillNames(ctx, skillIDs, nil)
+		if err != nil {
+			return TagDetail{}, errors.Wrap(err, "could not fetch skill names")
+		}
+		for _, row := range rows {
+			nameMap[row.SkillID] = row.SkillName
+		}
+	}
+
+	tagNames := make(map[int64]string, len(allTags))
+	for _, t := range allTags {
+		tagNames[t.Tag.ID] = t.Tag.Name
+	}
+
+	skill := func(sk repository.TagSkill, from string) TagSkill {
+		return TagSkill{ID: sk.SkillID, Name: nameMap[sk.SkillID], Level: sk.SkillLevel, Recommended: sk.RecommendedLevel, From: from}
+	}
+
+	detail := TagDetail{
+		ID:        tag.Tag.ID,
+		Name:      tag.Tag.Name,
+		Color:     bundles.EncodeColor(tag.Color()),
+		Includes:  includeNames(tag),
+		Skills:    make([]TagSkill, 0, len(tag.Skills)),
+		Inherited: make([]TagSkill, 0, len(tag.Inherited)),
+	}
+	for _, sk := range tag.Skills {
+		detail.Skills = append(detail.Skills, skill(sk, ""))
+	}
+	for _, sk := range tag.Inherited {
+		detail.Inherited = append(detail.Inherited, skill(sk, tagNames[sk.TagID]))
+	}
+	byName := func(s []TagSkill) func(i, j int) bool {
+		return func(i, j int) bool { return s[i].Name < s[j].Name }
+	}
+	sort.SliceStable(detail.Skills, byName(detail.Skills))
+	sort.SliceStable(detail.Inherited, byName(detail.Inherited))
+
+	return detail, nil
+}
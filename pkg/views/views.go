@@ -0,0 +1,90 @@
+// Package views shapes characters, tags, roles and match results for the
+// headless interfaces. The CLI's --json output and the local API both print
+// these types, so scripts can switch between them.
+package views
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kava-forge/eve-alts/lib/errors"
+
+	"github.com/kava-forge/eve-alts/pkg/database"
+	"github.com/kava-forge/eve-alts/pkg/repository"
+)
+
+var ErrNotFound = errors.New("not found")
+
+// Ref names a character, tag or role
+type Ref struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// MatchesRef checks an "id or name" reference against an item. Names match
+// case-insensitively.
+func MatchesRef(ref string, id int64, name string) bool {
+	if n, err := strconv.ParseInt(ref, 10, 64); err == nil && n == id {
+		return true
+	}
+	return strings.EqualFold(ref, name)
+}
+
+// LoadCharacters returns every character, sorted by name
+func LoadCharacters(ctx context.Context, repo repository.AppData) ([]*repository.CharacterDBData, error) {
+	chars, err := repo.GetAllCharacters(ctx, nil)
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return nil, errors.Wrap(err, "could not GetAllCharacters")
+	}
+	sort.SliceStable(chars, func(i, j int) bool { return chars[i].Character.Name < chars[j].Character.Name })
+	return chars, nil
+}
+
+// LoadTags returns every tag, sorted by name
+func LoadTags(ctx context.Context, repo repository.AppData) ([]*repository.TagDBData, error) {
+	tags, err := repo.GetAllTags(ctx, nil)
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return nil, errors.Wrap(err, "could not GetAllTags")
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].Tag.Name < tags[j].Tag.Name })
+	return tags, nil
+}
+
+// LoadRoles returns every role, sorted by name
+func LoadRoles(ctx context.Context, repo repository.AppData) ([]*repository.RoleDBData, error) {
+	roles, err := repo.GetAllRoles(ctx, nil)
+	if err != nil && !errors.Is(err, database.ErrNoRows) {
+		return nil, errors.Wrap(err, "could not GetAllRoles")
+	}
+	sort.SliceStable(roles, func(i, j int) bool { return roles[i].Role.Name < roles[j].Role.Name })
+	return roles, nil
+}
+
+func FindCharacter(chars []*repository.CharacterDBData, ref string) (*repository.CharacterDBData, error) {
+	for _, c := range chars {
+		if MatchesRef(ref, c.Character.ID, c.Character.Name) {
+			return c, nil
+		}
+	}
+	return nil, errors.Wrap(ErrNotFound, "no such character", "character", ref)
+}
+
+func FindTag(tags []*repository.TagDBData, ref string) (*repository.TagDBData, error) {
+	for _, t := range tags {
+		if MatchesRef(ref, t.Tag.ID, t.Tag.Name) {
+			return t, nil
+		}
+	}
+	return nil, errors.Wrap(ErrNotFound, "no such tag", "tag", ref)
+}
+
+func FindRole(roles []*repository.RoleDBData, ref string) (*repository.RoleDBData, error) {
+	for _, r := range roles {
+		if MatchesRef(ref, r.Role.ID, r.Role.Name) {
+			return r, nil
+		}
+	}
+	return nil, errors.Wrap(ErrNotFound, "no such role", "role", ref)
+}
@@ -16,4 +16,12 @@ CREATE TABLE IF NOT EXISTS trnTranslations (
 
 CREATE INDEX IF NOT EXISTS "idx_translations_by_name" 
 ON trnTranslations ("tcID", "languageID", LOWER("text"))
-WHERE "tcID" = 8;
\ No newline at end of file
+WHERE "tcID" = 8;
+
+CREATE TABLE IF NOT EXISTS dgmTypeAttributes (
+        "typeID" INTEGER NOT NULL,
+        "attributeID" INTEGER NOT NULL,
+        "valueInt" INTEGER,
+        "valueFloat" FLOAT,
+        PRIMARY KEY ("typeID", "attributeID")
+);